@@ -1,32 +1,82 @@
 package apply
 
 import (
+	"github.com/naoto24kawa/mcpjson/cmd/cmdutil"
 	"github.com/naoto24kawa/mcpjson/cmd/profile"
 	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/diff"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/mcpjson"
+	"github.com/naoto24kawa/mcpjson/internal/server"
 	"github.com/naoto24kawa/mcpjson/internal/utils"
+	"github.com/spf13/cobra"
 )
 
-func Execute(args []string) {
-	profileName, argsOffset := utils.ParseProfileName(args, config.DefaultProfileName)
-	var targetPath string
+// NewCommand returns the apply command
+func NewCommand() *cobra.Command {
+	var targetPath, clientName, format, mode, conflict string
+	var dryRun, viaProxy, showSecrets bool
 
-	for i := argsOffset; i < len(args); i++ {
-		switch args[i] {
-		case "--to", "-t":
-			var err error
-			targetPath, i, err = utils.ParseFlag(args, i, "--to")
-			utils.HandleArgumentError(err)
-		}
-	}
-
-	if targetPath == "" {
-		targetPath = config.GetDefaultMCPPath()
-	}
+	cmd := &cobra.Command{
+		Use:               "apply [profile]",
+		Short:             i18n.T("help.apply.short"),
+		Long:              i18n.T("help.apply.long", config.DefaultProfileName),
+		Args:              cmdutil.MaximumArgs(1),
+		ValidArgsFunction: cmdutil.CompleteProfiles(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := cmdutil.LoadConfig()
+			if err != nil {
+				return err
+			}
+			profileName, err := cmdutil.ProfileArg(cfg, args)
+			if err != nil {
+				return err
+			}
+			applyMode, err := mcpjson.ParseApplyMode(mode)
+			if err != nil {
+				return utils.ArgumentError(err)
+			}
+			conflictPolicy, err := mcpjson.ParseConflictPolicy(conflict)
+			if err != nil {
+				return utils.ArgumentError(err)
+			}
+			var client server.ClientAdapter
+			if clientName != "" {
+				if client, err = server.LookupClient(clientName); err != nil {
+					return utils.ArgumentError(err)
+				}
+			}
 
-	utils.HandleArgumentError(utils.ValidateName(profileName, "プロファイル"))
+			targets, err := profile.Targets(cfg, targetPath, client)
+			if err != nil {
+				return err
+			}
 
-	cfg, err := config.New()
-	utils.HandleEnvironmentError(err)
+			for _, target := range targets {
+				if dryRun {
+					err = profile.DryRun(cfg, profileName, target, client, format, applyMode, conflictPolicy, viaProxy, showSecrets)
+				} else {
+					err = profile.ApplyWithMode(cfg, profileName, target, client, applyMode, conflictPolicy, viaProxy)
+				}
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
 
-	utils.HandleGeneralError(profile.Apply(cfg, profileName, targetPath))
+	cmd.Flags().StringVarP(&targetPath, "to", "t", "", i18n.T("help.apply.to"))
+	cmd.Flags().StringVar(&clientName, "client", "", i18n.T("help.apply.client"))
+	cmd.Flags().BoolVarP(&dryRun, "dry-run", "n", false, i18n.T("help.apply.dry_run"))
+	cmd.Flags().StringVarP(&mode, "mode", "m", string(mcpjson.ModeReplace), i18n.T("help.apply.mode"))
+	cmd.Flags().StringVar(&conflict, "conflict", string(mcpjson.PolicyFail), i18n.T("help.apply.conflict"))
+	cmd.Flags().BoolVar(&viaProxy, "via-proxy", false, i18n.T("help.apply.via_proxy"))
+	cmd.Flags().StringVarP(&format, "format", "f", diff.FormatText, i18n.T("help.apply.format"))
+	cmd.Flags().BoolVar(&showSecrets, "show-secrets", false, i18n.T("help.apply.show_secrets"))
+	_ = cmd.RegisterFlagCompletionFunc("client", cmdutil.CompleteValues(server.ClientNames()...))
+	_ = cmd.RegisterFlagCompletionFunc("mode", cmdutil.CompleteValues(string(mcpjson.ModeReplace), string(mcpjson.ModeMerge), string(mcpjson.ModeUpdateOnly)))
+	_ = cmd.RegisterFlagCompletionFunc("conflict", cmdutil.CompleteValues(string(mcpjson.PolicyProfileWins), string(mcpjson.PolicyFileWins), string(mcpjson.PolicyFail)))
+	_ = cmd.RegisterFlagCompletionFunc("format", cmdutil.CompleteValues(diff.FormatText, diff.FormatJSON))
+	return cmd
 }
@@ -2,6 +2,7 @@ package apply
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/naoto24kawa/mcpjson/internal/config"
@@ -15,6 +16,18 @@ func setupTestEnvironment(t *testing.T) (string, *config.Config, func()) {
 	return testutil.SetupIsolatedTestEnvironment(t)
 }
 
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+}
+
 func TestExecute(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -85,6 +98,9 @@ func TestExecute(t *testing.T) {
 			_, cfg, cleanup := setupTestEnvironment(t)
 			defer cleanup()
 
+			// The default target is ./.mcp.json, so never run inside the package directory
+			chdir(t, t.TempDir())
+
 			// Setup test data
 			profileName := tt.setup(cfg)
 
@@ -97,7 +113,11 @@ func TestExecute(t *testing.T) {
 				args = []string{profileName, "--to", customPath}
 			}
 
-			Execute(args)
+			cmd := NewCommand()
+			cmd.SetArgs(args)
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("コマンドの実行に失敗: %v", err)
+			}
 
 			var outputPath string
 			if len(args) >= 3 && (args[len(args)-2] == "--to" || args[len(args)-2] == "-t") {
@@ -114,3 +134,106 @@ func TestExecute(t *testing.T) {
 		})
 	}
 }
+
+func TestExecute_Project(t *testing.T) {
+	tempDir, cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	profileManager := profile.NewManager(cfg.ProfilesDir)
+	_ = server.NewManager(cfg.ServersDir).SaveManual("db", "python", []string{"db.py"}, nil, false)
+	_ = profileManager.Create("web", "")
+	_ = profileManager.AddServer("web", "db", "db", nil)
+
+	projectDir := filepath.Join(tempDir, "repo")
+	workDir := filepath.Join(projectDir, "src")
+	templatesDir := filepath.Join(projectDir, ".mcp", "servers")
+	for _, dir := range []string{workDir, templatesDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = server.NewManager(templatesDir).SaveManual("db", "node", []string{"${profile.url}"}, nil, false)
+	project := `{
+		"profile": "web",
+		"targets": [".mcp.json", ".cursor-mcp.json"],
+		"templates": ".mcp/servers",
+		"vars": {"url": "postgres://localhost/app"}
+	}`
+	if err := os.WriteFile(filepath.Join(projectDir, config.ProjectFileName), []byte(project), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	chdir(t, workDir)
+
+	cmd := NewCommand()
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("コマンドの実行に失敗: %v", err)
+	}
+
+	for _, name := range []string{".mcp.json", ".cursor-mcp.json"} {
+		doc, err := server.LoadMCPDocument(filepath.Join(projectDir, name))
+		if err != nil {
+			t.Fatalf("%s の読み込みに失敗: %v", name, err)
+		}
+		db, ok, err := doc.Server("db")
+		if err != nil || !ok {
+			t.Fatalf("%s にサーバー db がありません: %v", name, err)
+		}
+		if db.Command != "node" || len(db.Args) != 1 || db.Args[0] != "postgres://localhost/app" {
+			t.Errorf("%s: server = %+v, want the project template with the project variable", name, db)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(workDir, ".mcp.json")); !os.IsNotExist(err) {
+		t.Errorf("./.mcp.json should not be written inside a project with targets")
+	}
+}
+
+func TestExecute_Client(t *testing.T) {
+	tempDir, cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	serverManager := server.NewManager(cfg.ServersDir)
+	profileManager := profile.NewManager(cfg.ProfilesDir)
+	_ = serverManager.SaveManual("db", "python", []string{"db.py"}, nil, false)
+	_ = profileManager.Create("web", "")
+	_ = profileManager.AddServer("web", "db", "db", nil)
+
+	chdir(t, tempDir)
+
+	vscodePath := filepath.Join(tempDir, "vscode-mcp.json")
+	tests := []struct {
+		name    string
+		args    []string
+		path    string
+		client  string
+		wantErr bool
+	}{
+		{name: "既定のパスへ書き込む", args: []string{"web", "--client", "cursor"}, path: filepath.Join(tempDir, ".cursor", "mcp.json"), client: server.ClientCursor},
+		{name: "--to と併用", args: []string{"web", "--client", "vscode", "--to", vscodePath}, path: vscodePath, client: server.ClientVSCode},
+		{name: "未対応のクライアント", args: []string{"web", "--client", "emacs"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewCommand()
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			client, _ := server.LookupClient(tt.client)
+			doc, err := server.LoadMCPDocumentFor(tt.path, client)
+			if err != nil {
+				t.Fatalf("%s の読み込みに失敗: %v", tt.path, err)
+			}
+			if db, ok, _ := doc.Server("db"); !ok || db.Command != "python" {
+				t.Errorf("%s: server db = %+v, %v", tt.path, db, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package bundle
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/naoto24kawa/mcpjson/cmd/cmdutil"
+	"github.com/naoto24kawa/mcpjson/internal/bundle"
+	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/history"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/interaction"
+	"github.com/naoto24kawa/mcpjson/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+const defaultAllBundleName = "mcpjson-bundle.tar.gz"
+
+// NewExportCommand returns the export command
+func NewExportCommand() *cobra.Command {
+	var opts bundle.ExportOptions
+	var outputPath string
+
+	cmd := &cobra.Command{
+		Use:               "export <profile>... | --all",
+		Short:             i18n.T("help.export.short"),
+		Long:              i18n.T("help.export.long"),
+		ValidArgsFunction: cmdutil.CompleteProfiles(-1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !opts.All && len(args) == 0 {
+				if cmd.Flags().NFlag() == 0 {
+					return cmd.Help()
+				}
+				return utils.ArgumentError(i18n.Errorf("bundle.need_profile_or_all"))
+			}
+			if err := cmdutil.ValidateNames(i18n.T("kind.profile"), args...); err != nil {
+				return err
+			}
+			opts.Profiles = args
+			if outputPath == "" {
+				outputPath = defaultAllBundleName
+				if !opts.All {
+					outputPath = opts.Profiles[0] + ".tar.gz"
+				}
+			}
+
+			cfg, err := cmdutil.LoadConfig()
+			if err != nil {
+				return err
+			}
+			return Export(cfg, opts, outputPath)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&opts.All, "all", "a", false, i18n.T("help.export.all"))
+	cmd.Flags().StringVarP(&outputPath, "output", "o", "", i18n.T("help.export.output"))
+	return cmd
+}
+
+// NewImportCommand returns the import command
+func NewImportCommand() *cobra.Command {
+	var onConflictFlag string
+
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: i18n.T("help.import.short"),
+		Long:  i18n.T("help.import.long"),
+		Args:  cmdutil.MaximumArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				if cmd.Flags().NFlag() == 0 {
+					return cmd.Help()
+				}
+				return utils.ArgumentError(i18n.Errorf("bundle.no_import_file"))
+			}
+
+			var onConflict bundle.Action
+			if cmd.Flags().Changed("on-conflict") {
+				var err error
+				if onConflict, err = bundle.ParseAction(onConflictFlag); err != nil {
+					return utils.ArgumentError(err)
+				}
+			}
+
+			cfg, err := cmdutil.LoadConfig()
+			if err != nil {
+				return err
+			}
+			return Import(cfg, args[0], onConflict)
+		},
+	}
+
+	cmd.Flags().StringVar(&onConflictFlag, "on-conflict", "", i18n.T("help.import.on_conflict"))
+	_ = cmd.RegisterFlagCompletionFunc("on-conflict", cmdutil.CompleteValues(string(bundle.ActionRename), string(bundle.ActionSkip), string(bundle.ActionOverwrite)))
+	return cmd
+}
+
+// Export writes a bundle to outputPath
+func Export(cfg *config.Config, opts bundle.ExportOptions, outputPath string) error {
+	b, err := bundle.Export(cfg, opts)
+	if err != nil {
+		return err
+	}
+
+	tmpPath := outputPath + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return i18n.Errorf("cmd.file_create_failed", err)
+	}
+	if err := b.Write(file); err != nil {
+		file.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(tmpPath)
+		return i18n.Errorf("cmd.file_save_failed", err)
+	}
+	if err := os.Rename(tmpPath, outputPath); err != nil {
+		os.Remove(tmpPath)
+		return i18n.Errorf("cmd.file_save_failed", err)
+	}
+
+	fmt.Println(i18n.T("bundle.exported",
+		outputPath, len(b.Manifest.Profiles), len(b.Manifest.Templates), len(b.Manifest.Groups)))
+	printSecrets(i18n.T("bundle.secrets_replaced"), b.Manifest.Secrets)
+	return nil
+}
+
+// Import reads the bundle at bundlePath into the store. Collisions are
+// resolved with onConflict, or interactively when it is empty.
+func Import(cfg *config.Config, bundlePath string, onConflict bundle.Action) error {
+	file, err := os.Open(bundlePath)
+	if err != nil {
+		return i18n.Errorf("cmd.file_read_failed", err)
+	}
+	defer file.Close()
+
+	b, err := bundle.Read(file)
+	if err != nil {
+		return err
+	}
+
+	opts := bundle.ImportOptions{Resolve: func(c bundle.Collision) (bundle.Action, error) {
+		if onConflict != "" {
+			return onConflict, nil
+		}
+		if !interaction.IsInteractive() {
+			return "", i18n.Errorf("bundle.collision",
+				c.Kind.Label(), c.Name, bundle.ActionRename, bundle.ActionSkip, bundle.ActionOverwrite)
+		}
+		choice := interaction.Choose(i18n.T("bundle.collision_prompt", c.Kind.Label(), c.Name),
+			[]string{string(bundle.ActionRename), string(bundle.ActionSkip), string(bundle.ActionOverwrite)}, string(bundle.ActionRename))
+		return bundle.Action(choice), nil
+	}}
+
+	var result *bundle.ImportResult
+	paths := []string{cfg.ProfilesDir, cfg.ServersDir, cfg.GroupsDir}
+	err = history.Run(cfg, "import "+bundlePath, paths, func() error {
+		var err error
+		result, err = b.Import(cfg, opts)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	imported := 0
+	for _, item := range result.Items {
+		switch {
+		case item.Identical && item.As != "":
+			fmt.Println(i18n.T("bundle.item_identical_copy", item.Kind.Label(), item.Name, item.As))
+		case item.Identical:
+			fmt.Println(i18n.T("bundle.item_identical", item.Kind.Label(), item.Name))
+		case item.Action == bundle.ActionSkip:
+			fmt.Println(i18n.T("bundle.item_skipped", item.Kind.Label(), item.Name))
+		case item.Action == bundle.ActionRename:
+			fmt.Println(i18n.T("bundle.item_renamed", item.Kind.Label(), item.Name, item.As))
+			imported++
+		case item.Action == bundle.ActionOverwrite:
+			fmt.Println(i18n.T("bundle.item_overwritten", item.Kind.Label(), item.Name))
+			imported++
+		default:
+			fmt.Printf("  + %s '%s'\n", item.Kind.Label(), item.Name)
+			imported++
+		}
+	}
+	fmt.Println(i18n.T("bundle.imported", bundlePath, imported))
+	printSecrets(i18n.T("bundle.set_secrets"), b.Manifest.Secrets)
+	return nil
+}
+
+func printSecrets(message string, secrets []string) {
+	if len(secrets) == 0 {
+		return
+	}
+	fmt.Printf("%s:\n", message)
+	for _, name := range secrets {
+		fmt.Printf("  %s\n", name)
+	}
+}
@@ -0,0 +1,124 @@
+// Package cmdutil holds the pieces shared by the commands of the CLI:
+// loading the configuration, validating positional arguments and the
+// flags that several commands have in common.
+package cmdutil
+
+import (
+	"strings"
+
+	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/output"
+	"github.com/naoto24kawa/mcpjson/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// groupAnnotation marks commands that only hold subcommands
+const groupAnnotation = "mcpjson/group"
+
+// LoadConfig loads the configuration, reporting a failure as an
+// environment error
+func LoadConfig() (*config.Config, error) {
+	cfg, err := config.New()
+	if err != nil {
+		return nil, utils.EnvironmentError(i18n.Errorf("cmd.config_init_failed", err))
+	}
+	return cfg, nil
+}
+
+// ProfileArg returns the profile named by the first positional argument,
+// or the default profile of cfg when it is omitted
+func ProfileArg(cfg *config.Config, args []string) (string, error) {
+	name, _ := utils.ParseProfileName(args, cfg.DefaultProfile())
+	if err := ValidateNames(i18n.T("kind.profile"), name); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// ValidateNames checks each name with utils.ValidateName
+func ValidateNames(kind string, names ...string) error {
+	for _, name := range names {
+		if err := utils.ValidateName(name, kind); err != nil {
+			return utils.ArgumentError(err)
+		}
+	}
+	return nil
+}
+
+// AddForceFlag registers the --force flag that skips confirmation prompts
+func AddForceFlag(cmd *cobra.Command, force *bool, shorthand string) {
+	cmd.Flags().BoolVarP(force, "force", shorthand, false, i18n.T("help.flag.force"))
+}
+
+// AddOutputFlag registers the --output/-o flag of list and detail commands
+func AddOutputFlag(cmd *cobra.Command, format *string, defaultFormat output.Format) {
+	cmd.Flags().StringVarP(format, "output", "o", string(defaultFormat), i18n.T("help.flag.output"))
+	_ = cmd.RegisterFlagCompletionFunc("output", CompleteValues(string(output.FormatTable), string(output.FormatJSON), string(output.FormatYAML)))
+}
+
+// ParseOutput parses the value of the --output flag
+func ParseOutput(value string) (output.Format, error) {
+	format, err := output.ParseFormat(value)
+	if err != nil {
+		return "", utils.ArgumentError(err)
+	}
+	return format, nil
+}
+
+// ExactArgs requires exactly n positional arguments. missing is reported
+// when there are fewer.
+func ExactArgs(n int, missing error) cobra.PositionalArgs {
+	return RangeArgs(n, n, missing)
+}
+
+// MinimumArgs requires at least n positional arguments
+func MinimumArgs(n int, missing error) cobra.PositionalArgs {
+	return RangeArgs(n, -1, missing)
+}
+
+// MaximumArgs allows at most n positional arguments
+func MaximumArgs(n int) cobra.PositionalArgs {
+	return RangeArgs(0, n, nil)
+}
+
+// RangeArgs requires between min and max positional arguments. A negative
+// max allows any number.
+func RangeArgs(min, max int, missing error) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) < min {
+			return utils.ArgumentError(missing)
+		}
+		if max >= 0 && len(args) > max {
+			return utils.ArgumentError(i18n.Errorf("cmd.unexpected_args", strings.Join(args[max:], " "), cmd.CommandPath()))
+		}
+		return nil
+	}
+}
+
+// NewGroup returns a command that only holds subcommands. Without a
+// subcommand it shows its help; an unknown subcommand is an error.
+func NewGroup(use, short string) *cobra.Command {
+	return &cobra.Command{
+		Use:         use,
+		Short:       short,
+		Args:        cobra.ArbitraryArgs,
+		Annotations: map[string]string{groupAnnotation: "true"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return cmd.Help()
+			}
+			return UnknownCommand(cmd, args[0])
+		},
+	}
+}
+
+// IsGroup reports whether cmd was created by NewGroup
+func IsGroup(cmd *cobra.Command) bool {
+	return cmd.Annotations[groupAnnotation] == "true"
+}
+
+// UnknownCommand reports name as an unknown subcommand of cmd
+func UnknownCommand(cmd *cobra.Command, name string) error {
+	return utils.ArgumentError(i18n.Errorf("cmd.unknown_command", cmd.CommandPath()+" "+name, cmd.CommandPath()))
+}
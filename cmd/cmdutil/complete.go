@@ -0,0 +1,98 @@
+package cmdutil
+
+import (
+	"strings"
+
+	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/server"
+	"github.com/spf13/cobra"
+)
+
+// defaultMCPFile is the MCP config file commands read when no path is given
+const defaultMCPFile = "./.mcp.json"
+
+// CompleteProfiles completes the first n positional arguments with profile
+// names. A negative n completes every argument.
+func CompleteProfiles(n int) cobra.CompletionFunc {
+	return completeStoreNames(n, (*config.Config).ProfileDirs, cobra.ShellCompDirectiveNoFileComp)
+}
+
+// CompleteProfilesOrFiles is CompleteProfiles for arguments that also
+// accept a file path, so the shell falls back to file names
+func CompleteProfilesOrFiles(n int) cobra.CompletionFunc {
+	return completeStoreNames(n, (*config.Config).ProfileDirs, cobra.ShellCompDirectiveDefault)
+}
+
+// CompleteTemplates completes the first n positional arguments with server
+// template names. A negative n completes every argument.
+func CompleteTemplates(n int) cobra.CompletionFunc {
+	return completeStoreNames(n, (*config.Config).ServerDirs, cobra.ShellCompDirectiveNoFileComp)
+}
+
+// CompleteServersIn completes server names defined in the MCP config file
+// given by the flag, or ./.mcp.json when the flag is empty
+func CompleteServersIn(flag string) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		path, _ := cmd.Flags().GetString(flag)
+		if path == "" {
+			path = defaultMCPFile
+		}
+		doc, err := server.LoadMCPDocument(path)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return withPrefix(doc.ServerNames(), toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// CompleteStores completes the names of the stores reads fall through
+func CompleteStores(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	cfg, err := config.NewReadOnly()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names := make([]string, 0, len(cfg.Stores))
+	for _, store := range cfg.Stores {
+		names = append(names, store.Name)
+	}
+	return withPrefix(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// CompleteValues completes a flag with a fixed set of values
+func CompleteValues(values ...string) cobra.CompletionFunc {
+	return cobra.FixedCompletions(values, cobra.ShellCompDirectiveNoFileComp)
+}
+
+// completeStoreNames lists the names in the store directories. Only the
+// directory entries are read so completion stays fast on large stores.
+func completeStoreNames(n int, dirs func(*config.Config) []string, directive cobra.ShellCompDirective) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		if n >= 0 && len(args) >= n {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		cfg, err := completionConfig(cmd)
+		if err != nil {
+			return nil, directive
+		}
+		return withPrefix(config.ListNames(dirs(cfg)...), toComplete), directive
+	}
+}
+
+// completionConfig locates the stores without creating any directory.
+// The root command selects the --store store before running a command,
+// but not before completing one, so the flag is applied here.
+func completionConfig(cmd *cobra.Command) (*config.Config, error) {
+	store, _ := cmd.Flags().GetString("store")
+	config.UseStore(store)
+	return config.NewReadOnly()
+}
+
+func withPrefix(names []string, prefix string) []cobra.Completion {
+	result := make([]cobra.Completion, 0, len(names))
+	for _, name := range names {
+		if strings.HasPrefix(name, prefix) {
+			result = append(result, name)
+		}
+	}
+	return result
+}
@@ -0,0 +1,200 @@
+package cmdutil
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/testutil"
+	"github.com/spf13/cobra"
+)
+
+func writeStoreFiles(t *testing.T, dir string, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCompleteStoreNames(t *testing.T) {
+	_, cfg, cleanup := testutil.SetupIsolatedTestEnvironment(t)
+	defer cleanup()
+
+	writeStoreFiles(t, cfg.ProfilesDir, "work.jsonc", "web.jsonc", "home.jsonc", "notes.txt")
+	writeStoreFiles(t, cfg.ServersDir, "github.jsonc", "filesystem.jsonc")
+	if err := os.Mkdir(filepath.Join(cfg.ProfilesDir, "dir.jsonc"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		complete      cobra.CompletionFunc
+		args          []string
+		toComplete    string
+		want          []string
+		wantDirective cobra.ShellCompDirective
+	}{
+		{
+			name:          "プロファイル名",
+			complete:      CompleteProfiles(1),
+			want:          []string{"home", "web", "work"},
+			wantDirective: cobra.ShellCompDirectiveNoFileComp,
+		},
+		{
+			name:          "前方一致",
+			complete:      CompleteProfiles(1),
+			toComplete:    "w",
+			want:          []string{"web", "work"},
+			wantDirective: cobra.ShellCompDirectiveNoFileComp,
+		},
+		{
+			name:          "補完する引数の数を超えた",
+			complete:      CompleteProfiles(1),
+			args:          []string{"work"},
+			wantDirective: cobra.ShellCompDirectiveNoFileComp,
+		},
+		{
+			name:          "引数の数に制限なし",
+			complete:      CompleteProfiles(-1),
+			args:          []string{"work", "web"},
+			toComplete:    "h",
+			want:          []string{"home"},
+			wantDirective: cobra.ShellCompDirectiveNoFileComp,
+		},
+		{
+			name:          "ファイルも補完",
+			complete:      CompleteProfilesOrFiles(2),
+			toComplete:    "ho",
+			want:          []string{"home"},
+			wantDirective: cobra.ShellCompDirectiveDefault,
+		},
+		{
+			name:          "サーバーテンプレート名",
+			complete:      CompleteTemplates(1),
+			want:          []string{"filesystem", "github"},
+			wantDirective: cobra.ShellCompDirectiveNoFileComp,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, directive := tt.complete(&cobra.Command{}, tt.args, tt.toComplete)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("completions = %v, want %v", got, tt.want)
+			}
+			if directive != tt.wantDirective {
+				t.Errorf("directive = %v, want %v", directive, tt.wantDirective)
+			}
+		})
+	}
+}
+
+func TestCompleteStoreNames_ReadOnly(t *testing.T) {
+	tempDir, _, cleanup := testutil.SetupIsolatedTestEnvironment(t)
+	defer cleanup()
+
+	home := filepath.Join(tempDir, "home")
+	team := filepath.Join(tempDir, "team")
+	if err := os.MkdirAll(filepath.Join(team, config.ProfilesDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeStoreFiles(t, filepath.Join(team, config.ProfilesDir), "shared.jsonc")
+	if err := os.MkdirAll(home, 0755); err != nil {
+		t.Fatal(err)
+	}
+	settings := fmt.Sprintf(`{"stores": [{"name": "team", "path": %q}]}`, team)
+	if err := os.WriteFile(filepath.Join(home, config.SettingsFileName), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(config.EnvHome, home)
+
+	// 同じコマンドラインで指定した --store のストアから補完すること
+	cmd := &cobra.Command{}
+	cmd.Flags().String("store", "", "")
+	if err := cmd.Flags().Set("store", "team"); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := CompleteProfiles(1)(cmd, nil, "")
+	if strings.Join(got, ",") != "shared" {
+		t.Errorf("completions = %v, want [shared]", got)
+	}
+
+	// 補完ではストアのディレクトリを作らないこと
+	for _, dir := range []string{
+		filepath.Join(home, config.ProfilesDir),
+		filepath.Join(home, config.ServersDir),
+		filepath.Join(team, config.ServersDir),
+		filepath.Join(team, config.GroupsDir),
+	} {
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			t.Errorf("completion created %s", dir)
+		}
+	}
+}
+
+func TestCompleteServersIn(t *testing.T) {
+	dir := t.TempDir()
+	mcpPath := filepath.Join(dir, "mcp.json")
+	content := `{
+  // comment
+  "mcpServers": {"github": {"command": "gh"}, "filesystem": {"command": "fs"}, "git": {"command": "git"}}
+}`
+	if err := os.WriteFile(mcpPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		from       string
+		toComplete string
+		want       []string
+	}{
+		{name: "ファイル内のサーバー名", from: mcpPath, want: []string{"github", "filesystem", "git"}},
+		{name: "前方一致", from: mcpPath, toComplete: "gi", want: []string{"github", "git"}},
+		{name: "存在しないファイル", from: filepath.Join(dir, "missing.json")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			cmd.Flags().String("from", "", "")
+			if err := cmd.Flags().Set("from", tt.from); err != nil {
+				t.Fatal(err)
+			}
+
+			got, directive := CompleteServersIn("from")(cmd, nil, tt.toComplete)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("completions = %v, want %v", got, tt.want)
+			}
+			if directive != cobra.ShellCompDirectiveNoFileComp {
+				t.Errorf("directive = %v, want NoFileComp", directive)
+			}
+		})
+	}
+}
+
+func BenchmarkCompleteProfiles(b *testing.B) {
+	dir := b.TempDir()
+	b.Setenv("HOME", dir)
+	cfg, err := config.New()
+	if err != nil {
+		b.Fatal(err)
+	}
+	for i := 0; i < 500; i++ {
+		path := filepath.Join(cfg.ProfilesDir, fmt.Sprintf("profile-%03d.jsonc", i))
+		if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	complete := CompleteProfiles(1)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		complete(&cobra.Command{}, nil, "profile-4")
+	}
+}
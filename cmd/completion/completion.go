@@ -0,0 +1,38 @@
+package completion
+
+import (
+	"github.com/naoto24kawa/mcpjson/cmd/cmdutil"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// shells lists the supported shells in the order they are completed
+var shells = []string{"bash", "zsh", "fish"}
+
+// NewCommand returns the completion command
+func NewCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "completion <bash|zsh|fish>",
+		Short: i18n.T("help.completion.short"),
+		Long:  i18n.T("help.completion.long"),
+		Example: `  source <(mcpjson completion bash)
+  mcpjson completion zsh > "${fpath[1]}/_mcpjson"
+  mcpjson completion fish > ~/.config/fish/completions/mcpjson.fish`,
+		Args:              cmdutil.ExactArgs(1, i18n.Error("completion.no_shell")),
+		ValidArgsFunction: cmdutil.CompleteValues(shells...),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, out := cmd.Root(), cmd.OutOrStdout()
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletionV2(out, true)
+			case "zsh":
+				return root.GenZshCompletion(out)
+			case "fish":
+				return root.GenFishCompletion(out, true)
+			default:
+				return utils.ArgumentError(i18n.Errorf("completion.unsupported_shell", args[0]))
+			}
+		},
+	}
+}
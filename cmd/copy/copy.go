@@ -1,35 +1,40 @@
 package copy
 
 import (
-	"fmt"
-	"os"
-
+	"github.com/naoto24kawa/mcpjson/cmd/cmdutil"
 	"github.com/naoto24kawa/mcpjson/cmd/profile"
 	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
 	"github.com/naoto24kawa/mcpjson/internal/utils"
+	"github.com/spf13/cobra"
 )
 
-func Execute(args []string) {
-	sourceName, destName, argsOffset, err := utils.ParseRenameArgs(args, config.DefaultProfileName)
-	if err != nil {
-		fmt.Fprintln(os.Stderr, "エラー:", err)
-		os.Exit(utils.ExitArgumentError)
-	}
-	force := false
-
-	for i := argsOffset; i < len(args); i++ {
-		switch args[i] {
-		case "--force", "-f":
-			force = true
-		}
+// NewCommand returns the copy command
+func NewCommand() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:               "copy [source] <destination>",
+		Short:             i18n.T("help.copy.short"),
+		Long:              i18n.T("help.copy.long", config.DefaultProfileName),
+		Args:              cmdutil.RangeArgs(1, 2, i18n.Error("copy.no_destination")),
+		ValidArgsFunction: cmdutil.CompleteProfiles(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := cmdutil.LoadConfig()
+			if err != nil {
+				return err
+			}
+			sourceName, destName, _, err := utils.ParseRenameArgs(args, cfg.DefaultProfile())
+			if err != nil {
+				return utils.ArgumentError(err)
+			}
+			if err := cmdutil.ValidateNames(i18n.T("kind.profile"), sourceName, destName); err != nil {
+				return err
+			}
+			return profile.Copy(cfg, sourceName, destName, force)
+		},
 	}
 
-	utils.HandleArgumentError(utils.ValidateName(sourceName, "プロファイル"))
-
-	utils.HandleArgumentError(utils.ValidateName(destName, "プロファイル"))
-
-	cfg, err := config.New()
-	utils.HandleEnvironmentError(err)
-
-	utils.HandleGeneralError(profile.Copy(cfg, sourceName, destName, force))
+	cmdutil.AddForceFlag(cmd, &force, "f")
+	return cmd
 }
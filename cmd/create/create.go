@@ -1,28 +1,36 @@
 package create
 
 import (
+	"github.com/naoto24kawa/mcpjson/cmd/cmdutil"
 	"github.com/naoto24kawa/mcpjson/cmd/profile"
 	"github.com/naoto24kawa/mcpjson/internal/config"
-	"github.com/naoto24kawa/mcpjson/internal/utils"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/spf13/cobra"
 )
 
-func Execute(args []string) {
-	profileName, argsOffset := utils.ParseProfileName(args, config.DefaultProfileName)
+// NewCommand returns the create command
+func NewCommand() *cobra.Command {
 	var templateName string
 
-	for i := argsOffset; i < len(args); i++ {
-		switch args[i] {
-		case "--template", "-t":
-			var err error
-			templateName, i, err = utils.ParseFlag(args, i, "--template")
-			utils.HandleArgumentError(err)
-		}
+	cmd := &cobra.Command{
+		Use:   "create [profile]",
+		Short: i18n.T("help.create.short"),
+		Long:  i18n.T("help.create.long", config.DefaultProfileName),
+		Args:  cmdutil.MaximumArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := cmdutil.LoadConfig()
+			if err != nil {
+				return err
+			}
+			profileName, err := cmdutil.ProfileArg(cfg, args)
+			if err != nil {
+				return err
+			}
+			return profile.Create(cfg, profileName, templateName)
+		},
 	}
 
-	utils.HandleArgumentError(utils.ValidateName(profileName, "プロファイル"))
-
-	cfg, err := config.New()
-	utils.HandleEnvironmentError(err)
-
-	utils.HandleGeneralError(profile.Create(cfg, profileName, templateName))
+	cmd.Flags().StringVarP(&templateName, "template", "t", "", i18n.T("help.create.template"))
+	_ = cmd.RegisterFlagCompletionFunc("template", cmdutil.CompleteProfiles(-1))
+	return cmd
 }
@@ -3,40 +3,18 @@ package create
 import (
 	"fmt"
 	"math/rand"
-	"os"
 	"testing"
 	"time"
 
 	"github.com/naoto24kawa/mcpjson/internal/config"
 	"github.com/naoto24kawa/mcpjson/internal/profile"
 	"github.com/naoto24kawa/mcpjson/internal/server"
+	"github.com/naoto24kawa/mcpjson/internal/testutil"
 )
 
 func setupTestEnvironment(t *testing.T) (string, func()) {
 	t.Helper()
-
-	tempDir, err := os.MkdirTemp("", "create_test")
-	if err != nil {
-		t.Fatalf("一時ディレクトリの作成に失敗: %v", err)
-	}
-
-	origXDGConfigHome := os.Getenv("XDG_CONFIG_HOME")
-	os.Setenv("XDG_CONFIG_HOME", tempDir)
-
-	cleanup := func() {
-		os.Setenv("XDG_CONFIG_HOME", origXDGConfigHome)
-		os.RemoveAll(tempDir)
-	}
-
-	cfg, err := config.New()
-	if err != nil {
-		cleanup()
-		t.Fatalf("設定の作成に失敗: %v", err)
-	}
-
-	os.MkdirAll(cfg.ProfilesDir, 0755)
-	os.MkdirAll(cfg.ServersDir, 0755)
-
+	tempDir, _, cleanup := testutil.SetupIsolatedTestEnvironment(t)
 	return tempDir, cleanup
 }
 
@@ -85,7 +63,11 @@ func TestExecute(t *testing.T) {
 
 			tt.setup(cfg)
 
-			Execute(tt.args)
+			cmd := NewCommand()
+			cmd.SetArgs(tt.args)
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("コマンドの実行に失敗: %v", err)
+			}
 
 			profileManager := profile.NewManager(cfg.ProfilesDir)
 			profileName := config.DefaultProfileName
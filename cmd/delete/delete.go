@@ -1,26 +1,36 @@
 package delete
 
 import (
+	"github.com/naoto24kawa/mcpjson/cmd/cmdutil"
 	"github.com/naoto24kawa/mcpjson/cmd/profile"
 	"github.com/naoto24kawa/mcpjson/internal/config"
-	"github.com/naoto24kawa/mcpjson/internal/utils"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/spf13/cobra"
 )
 
-func Execute(args []string) {
-	profileName, argsOffset := utils.ParseProfileName(args, config.DefaultProfileName)
-	force := false
+// NewCommand returns the delete command
+func NewCommand() *cobra.Command {
+	var force bool
 
-	for i := argsOffset; i < len(args); i++ {
-		switch args[i] {
-		case "--force", "-f":
-			force = true
-		}
+	cmd := &cobra.Command{
+		Use:               "delete [profile]",
+		Short:             i18n.T("help.delete.short"),
+		Long:              i18n.T("help.delete.long", config.DefaultProfileName),
+		Args:              cmdutil.MaximumArgs(1),
+		ValidArgsFunction: cmdutil.CompleteProfiles(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := cmdutil.LoadConfig()
+			if err != nil {
+				return err
+			}
+			profileName, err := cmdutil.ProfileArg(cfg, args)
+			if err != nil {
+				return err
+			}
+			return profile.Delete(cfg, profileName, force)
+		},
 	}
 
-	utils.HandleArgumentError(utils.ValidateName(profileName, "プロファイル"))
-
-	cfg, err := config.New()
-	utils.HandleEnvironmentError(err)
-
-	utils.HandleGeneralError(profile.Delete(cfg, profileName, force))
+	cmdutil.AddForceFlag(cmd, &force, "f")
+	return cmd
 }
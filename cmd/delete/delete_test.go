@@ -1,38 +1,16 @@
 package delete
 
 import (
-	"os"
 	"testing"
 
 	"github.com/naoto24kawa/mcpjson/internal/config"
 	"github.com/naoto24kawa/mcpjson/internal/profile"
+	"github.com/naoto24kawa/mcpjson/internal/testutil"
 )
 
 func setupTestEnvironment(t *testing.T) (string, func()) {
 	t.Helper()
-
-	tempDir, err := os.MkdirTemp("", "delete_test")
-	if err != nil {
-		t.Fatalf("一時ディレクトリの作成に失敗: %v", err)
-	}
-
-	origXDGConfigHome := os.Getenv("XDG_CONFIG_HOME")
-	os.Setenv("XDG_CONFIG_HOME", tempDir)
-
-	cleanup := func() {
-		os.Setenv("XDG_CONFIG_HOME", origXDGConfigHome)
-		os.RemoveAll(tempDir)
-	}
-
-	cfg, err := config.New()
-	if err != nil {
-		cleanup()
-		t.Fatalf("設定の作成に失敗: %v", err)
-	}
-
-	os.MkdirAll(cfg.ProfilesDir, 0755)
-	os.MkdirAll(cfg.ServersDir, 0755)
-
+	tempDir, _, cleanup := testutil.SetupIsolatedTestEnvironment(t)
 	return tempDir, cleanup
 }
 
@@ -88,7 +66,11 @@ func TestExecute(t *testing.T) {
 
 			tt.setup(cfg)
 
-			Execute(tt.args)
+			cmd := NewCommand()
+			cmd.SetArgs(tt.args)
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("コマンドの実行に失敗: %v", err)
+			}
 
 			profileManager := profile.NewManager(cfg.ProfilesDir)
 			profileName := config.DefaultProfileName
@@ -115,7 +97,11 @@ func TestExecuteWithMultipleFlags(t *testing.T) {
 	profileManager := profile.NewManager(cfg.ProfilesDir)
 	profileManager.Create("test-profile3", "")
 
-	Execute([]string{"test-profile3", "--force", "-f"})
+	cmd := NewCommand()
+	cmd.SetArgs([]string{"test-profile3", "--force", "-f"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("コマンドの実行に失敗: %v", err)
+	}
 
 	if _, err := profileManager.Load("test-profile3"); err == nil {
 		t.Error("プロファイル 'test-profile3' が削除されませんでした")
@@ -1,45 +1,114 @@
 package detail
 
 import (
-	"encoding/json"
-	"fmt"
 	"os"
-	"path/filepath"
 
+	"github.com/naoto24kawa/mcpjson/cmd/cmdutil"
+	cmdprofile "github.com/naoto24kawa/mcpjson/cmd/profile"
 	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/output"
 	"github.com/naoto24kawa/mcpjson/internal/profile"
 	"github.com/naoto24kawa/mcpjson/internal/utils"
+	"github.com/spf13/cobra"
 )
 
-func Execute(args []string) error {
-	if len(args) == 0 {
-		return fmt.Errorf("使用方法: mcpconfig detail <プロファイル名>")
+// NewCommand returns the detail command
+func NewCommand() *cobra.Command {
+	var resolved, tools bool
+	var formatFlag string
+
+	cmd := &cobra.Command{
+		Use:               "detail <profile>",
+		Short:             i18n.T("help.detail.short"),
+		Long:              i18n.T("help.detail.long"),
+		Args:              cmdutil.ExactArgs(1, i18n.Error("detail.no_profile")),
+		ValidArgsFunction: cmdutil.CompleteProfiles(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := cmdutil.ParseOutput(formatFlag)
+			if err != nil {
+				return err
+			}
+
+			if tools {
+				cfg, err := cmdutil.LoadConfig()
+				if err != nil {
+					return err
+				}
+				return showTools(cfg, args[0], format)
+			}
+			if resolved {
+				return showResolvedProfile(args[0], format)
+			}
+			return showProfileDetail(args[0], format)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&resolved, "resolved", "r", false, i18n.T("help.detail.resolved"))
+	cmd.Flags().BoolVar(&tools, "tools", false, i18n.T("help.detail.tools"))
+	cmd.MarkFlagsMutuallyExclusive("resolved", "tools")
+	cmdutil.AddOutputFlag(cmd, &formatFlag, output.FormatJSON)
+	return cmd
+}
+
+// showTools prints the tools the servers of the profile expose
+func showTools(cfg *config.Config, profileName string, format output.Format) error {
+	serverManager, err := cmdprofile.NewServerManager(cfg)
+	if err != nil {
+		return err
+	}
+	surface, err := cmdprofile.NewProfileManager(cfg).Tools(profileName, serverManager)
+	if err != nil {
+		return err
+	}
+
+	if format.IsStructured() {
+		return output.Write(os.Stdout, format, surface)
+	}
+	return profile.WriteTools(os.Stdout, surface)
+}
+
+// showResolvedProfile prints the profile with its extends chain flattened,
+// including the profile each server came from
+func showResolvedProfile(profileName string, format output.Format) error {
+	if !format.IsStructured() {
+		return utils.ArgumentError(i18n.Errorf("detail.resolved_format", output.FormatJSON, output.FormatYAML))
+	}
+
+	cfg, err := cmdutil.LoadConfig()
+	if err != nil {
+		return err
 	}
 
-	return showProfileDetail(args[0])
+	profileManager := profile.NewManager(cfg.ProfilesDir)
+	profileManager.UseConfig(cfg)
+	resolved, err := profileManager.Resolve(profileName)
+	if err != nil {
+		return err
+	}
+
+	return output.Write(os.Stdout, format, resolved)
 }
 
-func showProfileDetail(profileName string) error {
-	cfg, err := config.New()
+func showProfileDetail(profileName string, format output.Format) error {
+	cfg, err := cmdutil.LoadConfig()
 	if err != nil {
-		return fmt.Errorf("設定の初期化に失敗しました: %v", err)
+		return err
 	}
 
-	profilePath := filepath.Join(cfg.ProfilesDir, profileName+config.FileExtension)
+	profilePath := config.LookupPath(cfg.ProfileDirs(), cfg.ProfilesDir, profileName)
 	if _, err := os.Stat(profilePath); os.IsNotExist(err) {
-		return fmt.Errorf("プロファイル '%s' が見つかりません", profileName)
+		return i18n.Errorf("profile.not_found", profileName)
 	}
 
 	var targetProfile profile.Profile
 	if err := utils.LoadJSON(profilePath, &targetProfile); err != nil {
-		return fmt.Errorf("プロファイルの読み込みに失敗しました: %v", err)
+		return i18n.Errorf("detail.load_failed", err)
 	}
 
-	jsonData, err := json.MarshalIndent(targetProfile, "", "  ")
-	if err != nil {
-		return fmt.Errorf("JSONの生成に失敗しました: %v", err)
+	if !format.IsStructured() {
+		profile.NewManager(cfg.ProfilesDir).WriteDetails(os.Stdout, &targetProfile)
+		return nil
 	}
-
-	fmt.Println(string(jsonData))
-	return nil
+	return output.Write(os.Stdout, format, targetProfile)
 }
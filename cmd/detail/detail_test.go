@@ -1,39 +1,18 @@
 package detail
 
 import (
-	"os"
 	"strings"
 	"testing"
 
 	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/output"
 	"github.com/naoto24kawa/mcpjson/internal/profile"
+	"github.com/naoto24kawa/mcpjson/internal/testutil"
 )
 
 func setupTestEnvironment(t *testing.T) func() {
 	t.Helper()
-
-	tempDir, err := os.MkdirTemp("", "detail_test")
-	if err != nil {
-		t.Fatalf("一時ディレクトリの作成に失敗: %v", err)
-	}
-
-	origXDGConfigHome := os.Getenv("XDG_CONFIG_HOME")
-	os.Setenv("XDG_CONFIG_HOME", tempDir)
-
-	cleanup := func() {
-		os.Setenv("XDG_CONFIG_HOME", origXDGConfigHome)
-		os.RemoveAll(tempDir)
-	}
-
-	cfg, err := config.New()
-	if err != nil {
-		cleanup()
-		t.Fatalf("設定の作成に失敗: %v", err)
-	}
-
-	_ = os.MkdirAll(cfg.ProfilesDir, 0755)
-	_ = os.MkdirAll(cfg.ServersDir, 0755)
-
+	_, _, cleanup := testutil.SetupIsolatedTestEnvironment(t)
 	return cleanup
 }
 
@@ -51,6 +30,15 @@ func TestExecute(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "不明なオプション",
+			args: []string{"test-profile", "--unknown"},
+			setup: func(cfg *config.Config) {
+				profileManager := profile.NewManager(cfg.ProfilesDir)
+				_ = profileManager.Create("test-profile", "テスト用プロファイル")
+			},
+			wantErr: true,
+		},
 		{
 			name: "存在しないプロファイル",
 			args: []string{"non-existent"},
@@ -58,6 +46,15 @@ func TestExecute(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "--output=yaml 形式のオプション",
+			args: []string{"test-profile", "--output=yaml"},
+			setup: func(cfg *config.Config) {
+				profileManager := profile.NewManager(cfg.ProfilesDir)
+				_ = profileManager.Create("test-profile", "テスト用プロファイル")
+			},
+			wantErr: false,
+		},
 		{
 			name: "正常なプロファイル詳細表示",
 			args: []string{"test-profile"},
@@ -81,7 +78,9 @@ func TestExecute(t *testing.T) {
 
 			tt.setup(cfg)
 
-			err = Execute(tt.args)
+			cmd := NewCommand()
+			cmd.SetArgs(tt.args)
+			err = cmd.Execute()
 
 			if tt.wantErr && err == nil {
 				t.Error("エラーが期待されましたが発生しませんでした")
@@ -105,12 +104,12 @@ func TestShowProfileDetail(t *testing.T) {
 	profileManager := profile.NewManager(cfg.ProfilesDir)
 	_ = profileManager.Create("test-detail", "詳細テスト用")
 
-	err = showProfileDetail("test-detail")
+	err = showProfileDetail("test-detail", output.FormatJSON)
 	if err != nil {
 		t.Errorf("showProfileDetail() error = %v", err)
 	}
 
-	err = showProfileDetail("non-existent")
+	err = showProfileDetail("non-existent", output.FormatJSON)
 	if err == nil {
 		t.Error("存在しないプロファイルでエラーが発生しませんでした")
 	}
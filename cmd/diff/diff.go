@@ -0,0 +1,63 @@
+package diff
+
+import (
+	"os"
+
+	"github.com/naoto24kawa/mcpjson/cmd/cmdutil"
+	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/diff"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/spf13/cobra"
+)
+
+// NewCommand returns the diff command
+func NewCommand() *cobra.Command {
+	var format string
+	var jsonOutput, showSecrets bool
+
+	cmd := &cobra.Command{
+		Use:   "diff <from> <to>",
+		Short: i18n.T("help.diff.short"),
+		Long:  i18n.T("help.diff.long"),
+		Example: `  mcpjson diff build:work ~/.mcp.json
+  mcpjson diff profile:frontend profile:backend --format json`,
+		Args:              cmdutil.ExactArgs(2, i18n.Error("diff.usage")),
+		ValidArgsFunction: cmdutil.CompleteProfilesOrFiles(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if jsonOutput {
+				format = diff.FormatJSON
+			}
+
+			cfg, err := cmdutil.LoadConfig()
+			if err != nil {
+				return err
+			}
+			return Run(cfg, args[0], args[1], format, showSecrets)
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", diff.FormatText, i18n.T("help.diff.format"))
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, i18n.T("help.diff.json"))
+	cmd.Flags().BoolVar(&showSecrets, "show-secrets", false, i18n.T("help.diff.show_secrets"))
+	_ = cmd.RegisterFlagCompletionFunc("format", cmdutil.CompleteValues(diff.FormatText, diff.FormatJSON))
+	return cmd
+}
+
+// Run compares the two sources and writes the result to stdout. Env and
+// header values are hidden unless showSecrets is set.
+func Run(cfg *config.Config, fromSpec, toSpec, format string, showSecrets bool) error {
+	from, err := diff.LoadSource(cfg, fromSpec)
+	if err != nil {
+		return err
+	}
+	to, err := diff.LoadSource(cfg, toSpec)
+	if err != nil {
+		return err
+	}
+
+	result := diff.Compare(from.Label, from.Servers, to.Label, to.Servers)
+	if !showSecrets {
+		result.Redact()
+	}
+	return result.Write(os.Stdout, format)
+}
@@ -0,0 +1,73 @@
+package doctor
+
+import (
+	"context"
+	"os"
+
+	"github.com/naoto24kawa/mcpjson/cmd/cmdutil"
+	cmdprofile "github.com/naoto24kawa/mcpjson/cmd/profile"
+	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/doctor"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/output"
+	"github.com/naoto24kawa/mcpjson/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// NewCommand returns the doctor command
+func NewCommand() *cobra.Command {
+	var formatFlag string
+
+	cmd := &cobra.Command{
+		Use:               "doctor [profile]",
+		Short:             i18n.T("help.doctor.short"),
+		Long:              i18n.T("help.doctor.long", config.DefaultProfileName),
+		Args:              cmdutil.MaximumArgs(1),
+		ValidArgsFunction: cmdutil.CompleteProfiles(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := cmdutil.ParseOutput(formatFlag)
+			if err != nil {
+				return err
+			}
+
+			cfg, err := cmdutil.LoadConfig()
+			if err != nil {
+				return err
+			}
+			profileName, err := cmdutil.ProfileArg(cfg, args)
+			if err != nil {
+				return err
+			}
+			return runDoctor(cmd.Context(), cfg, profileName, format)
+		},
+	}
+
+	cmdutil.AddOutputFlag(cmd, &formatFlag, output.FormatTable)
+	return cmd
+}
+
+// runDoctor launches every server the profile would write and reports
+// whether each answers the MCP handshake
+func runDoctor(ctx context.Context, cfg *config.Config, profileName string, format output.Format) error {
+	serverManager, err := cmdprofile.NewServerManager(cfg)
+	if err != nil {
+		return err
+	}
+	mcpConfig, err := cmdprofile.NewProfileManager(cfg).Build(profileName, serverManager)
+	if err != nil {
+		return err
+	}
+	return WriteResults(doctor.CheckAll(ctx, mcpConfig.McpServers), format)
+}
+
+// WriteResults prints the results of the checks, failing with the
+// server error exit code when any of them failed
+func WriteResults(results []doctor.Result, format output.Format) error {
+	if err := doctor.Write(os.Stdout, format, results); err != nil {
+		return err
+	}
+	if failed := doctor.Failed(results); failed > 0 {
+		return utils.WithExitCode(i18n.Errorf("doctor.failed", failed), utils.ExitServerError)
+	}
+	return nil
+}
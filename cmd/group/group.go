@@ -1,39 +1,48 @@
 package group
 
 import (
-	"fmt"
 	"os"
 
-	"github.com/naoto24kawa/mcpjson/internal/config"
-	"github.com/naoto24kawa/mcpjson/internal/utils"
+	"github.com/naoto24kawa/mcpjson/cmd/cmdutil"
+	"github.com/naoto24kawa/mcpjson/internal/group"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/output"
+	"github.com/spf13/cobra"
 )
 
-func Execute(cfg *config.Config, args []string) {
-	if len(args) == 0 {
-		PrintUsage()
-		os.Exit(0)
-	}
-
-	subCmd := args[0]
-
-	switch subCmd {
-	case "list":
-		fmt.Println("グループ機能は現在開発中です")
-	default:
-		fmt.Fprintf(os.Stderr, "エラー: 不明なサブコマンド 'group %s'\n", subCmd)
-		PrintUsage()
-		os.Exit(utils.ExitGeneralError)
-	}
+// NewCommand returns the group command with its subcommands
+func NewCommand() *cobra.Command {
+	cmd := cmdutil.NewGroup("group", i18n.T("help.group.short"))
+	cmd.Long = i18n.T("help.group.long")
+	cmd.AddCommand(newListCommand())
+	return cmd
 }
 
-func PrintUsage() {
-	fmt.Println(`mcpjson group - グループ管理
-
-使用方法:
-  mcpjson group <サブコマンド> [オプション]
-
-サブコマンド:
-  list [--detail]                                    グループ一覧表示
+func newListCommand() *cobra.Command {
+	var detail bool
+	var formatFlag string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: i18n.T("help.group_list.short"),
+		Args:  cmdutil.MaximumArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := cmdutil.ParseOutput(formatFlag)
+			if err != nil {
+				return err
+			}
+
+			cfg, err := cmdutil.LoadConfig()
+			if err != nil {
+				return err
+			}
+			groupManager := group.NewManager(cfg.GroupsDir)
+			groupManager.UseConfig(cfg)
+			return groupManager.ListWithFormat(os.Stdout, detail, format)
+		},
+	}
 
-注意: グループ機能は現在開発中です`)
+	cmd.Flags().BoolVarP(&detail, "detail", "d", false, i18n.T("help.flag.detail"))
+	cmdutil.AddOutputFlag(cmd, &formatFlag, output.FormatTable)
+	return cmd
 }
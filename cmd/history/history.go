@@ -0,0 +1,221 @@
+package history
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/naoto24kawa/mcpjson/cmd/cmdutil"
+	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/filelock"
+	"github.com/naoto24kawa/mcpjson/internal/gitstore"
+	"github.com/naoto24kawa/mcpjson/internal/history"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+const timeFormat = "2006-01-02 15:04:05"
+
+// NewCommand returns the history command with its subcommands
+func NewCommand() *cobra.Command {
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: i18n.T("help.history.short"),
+		Long:  i18n.T("help.history.long"),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				return cmdutil.UnknownCommand(cmd, args[0])
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if limit < 0 {
+				return utils.ArgumentError(i18n.Errorf("history.invalid_limit", strconv.Itoa(limit)))
+			}
+
+			cfg, err := cmdutil.LoadConfig()
+			if err != nil {
+				return err
+			}
+			return List(cfg, limit)
+		},
+	}
+	cmd.Flags().IntVarP(&limit, "limit", "n", 0, i18n.T("help.history.limit"))
+
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "show <id>",
+			Short: i18n.T("help.history_show.short"),
+			Args:  cmdutil.ExactArgs(1, i18n.Error("history.no_id")),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				id, err := parseID(args[0])
+				if err != nil {
+					return utils.ArgumentError(err)
+				}
+
+				cfg, err := cmdutil.LoadConfig()
+				if err != nil {
+					return err
+				}
+				return Show(cfg, id)
+			},
+		},
+		&cobra.Command{
+			Use:   "prune",
+			Short: i18n.T("help.history_prune.short"),
+			Args:  cmdutil.MaximumArgs(0),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				cfg, err := cmdutil.LoadConfig()
+				if err != nil {
+					return err
+				}
+				return Prune(cfg)
+			},
+		},
+	)
+	return cmd
+}
+
+// NewUndoCommand returns the undo command
+func NewUndoCommand() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "undo [id]",
+		Short: i18n.T("help.undo.short"),
+		Long:  i18n.T("help.undo.long"),
+		Args:  cmdutil.MaximumArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id := 0
+			if len(args) > 0 {
+				var err error
+				if id, err = parseID(args[0]); err != nil {
+					return utils.ArgumentError(err)
+				}
+			}
+
+			cfg, err := cmdutil.LoadConfig()
+			if err != nil {
+				return err
+			}
+			return Undo(cfg, id, force)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&force, "force", "f", false, i18n.T("help.undo.force"))
+	return cmd
+}
+
+// List prints the recorded operations, newest first. limit 0 prints all.
+func List(cfg *config.Config, limit int) error {
+	journal, err := history.Open(cfg)
+	if err != nil {
+		return err
+	}
+	entries, err := journal.List()
+	if err != nil {
+		return err
+	}
+
+	if len(entries) == 0 {
+		fmt.Println(i18n.T("history.empty"))
+		return nil
+	}
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	for _, entry := range entries {
+		status := ""
+		if entry.UndoneAt != nil {
+			status = i18n.T("history.undone_mark")
+		}
+		fmt.Println(i18n.T("history.list_line",
+			entry.ID, entry.CreatedAt.Local().Format(timeFormat), entry.Operation, len(entry.Files), status))
+	}
+	return nil
+}
+
+// Show prints the files recorded by an entry
+func Show(cfg *config.Config, id int) error {
+	journal, err := history.Open(cfg)
+	if err != nil {
+		return err
+	}
+	entry, err := journal.Get(id)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("#%d %s\n", entry.ID, entry.Operation)
+	fmt.Println(i18n.T("history.show_time", entry.CreatedAt.Local().Format(timeFormat)))
+	if entry.UndoneAt != nil {
+		fmt.Println(i18n.T("history.show_undone", entry.UndoneAt.Local().Format(timeFormat)))
+	}
+	fmt.Println(i18n.T("history.show_files"))
+	for _, file := range entry.Files {
+		switch {
+		case !file.Existed:
+			fmt.Println(i18n.T("history.file_created", file.Path))
+		case file.After == "":
+			fmt.Println(i18n.T("history.file_deleted", file.Path))
+		default:
+			fmt.Println(i18n.T("history.file_modified", file.Path))
+		}
+	}
+	return nil
+}
+
+// Undo restores the files of an entry. id 0 undoes the latest operation.
+func Undo(cfg *config.Config, id int, force bool) error {
+	journal, err := history.Open(cfg)
+	if err != nil {
+		return err
+	}
+
+	// 復元と同じロックの中でコミットする
+	unlock, err := filelock.LockStore(cfg.ProfilesDir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	entry, err := journal.Undo(id, force)
+	if err != nil {
+		return err
+	}
+
+	if err := gitstore.AutoCommit(cfg.BaseDir, fmt.Sprintf("undo #%d (%s)", entry.ID, entry.Operation)); err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T("history.commit_failed", err))
+	}
+
+	fmt.Println(i18n.T("history.undo_done", entry.ID, entry.Operation, len(entry.Files)))
+	return nil
+}
+
+// Prune removes entries beyond the configured retention
+func Prune(cfg *config.Config) error {
+	journal, err := history.Open(cfg)
+	if err != nil {
+		return err
+	}
+	removed, err := journal.Prune()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(i18n.T("history.pruned", removed))
+	return nil
+}
+
+func parseID(s string) (int, error) {
+	id, err := strconv.Atoi(strings.TrimPrefix(s, "#"))
+	if err != nil || id <= 0 {
+		return 0, i18n.Errorf("history.invalid_id", s)
+	}
+	return id, nil
+}
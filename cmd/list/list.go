@@ -1,32 +1,37 @@
 package list
 
 import (
-	"fmt"
-	"os"
-
+	"github.com/naoto24kawa/mcpjson/cmd/cmdutil"
 	"github.com/naoto24kawa/mcpjson/cmd/profile"
-	"github.com/naoto24kawa/mcpjson/internal/config"
-	"github.com/naoto24kawa/mcpjson/internal/utils"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/output"
+	"github.com/spf13/cobra"
 )
 
-func Execute(args []string) {
-	detail := false
+// NewCommand returns the list command
+func NewCommand() *cobra.Command {
+	var detail bool
+	var formatFlag string
 
-	for _, arg := range args {
-		switch arg {
-		case "--detail", "-d":
-			detail = true
-		}
-	}
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: i18n.T("help.list.short"),
+		Args:  cmdutil.MaximumArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := cmdutil.ParseOutput(formatFlag)
+			if err != nil {
+				return err
+			}
 
-	cfg, err := config.New()
-	if err != nil {
-		fmt.Fprintln(os.Stderr, "エラー:", err)
-		os.Exit(utils.ExitEnvironment)
+			cfg, err := cmdutil.LoadConfig()
+			if err != nil {
+				return err
+			}
+			return profile.ListWithFormat(cfg, detail, format)
+		},
 	}
 
-	if err := profile.List(cfg, detail); err != nil {
-		fmt.Fprintln(os.Stderr, "エラー:", err)
-		os.Exit(utils.ExitGeneralError)
-	}
+	cmd.Flags().BoolVarP(&detail, "detail", "d", false, i18n.T("help.flag.detail"))
+	cmdutil.AddOutputFlag(cmd, &formatFlag, output.FormatTable)
+	return cmd
 }
@@ -1,38 +1,16 @@
 package list
 
 import (
-	"os"
 	"testing"
 
 	"github.com/naoto24kawa/mcpjson/internal/config"
 	"github.com/naoto24kawa/mcpjson/internal/profile"
+	"github.com/naoto24kawa/mcpjson/internal/testutil"
 )
 
 func setupTestEnvironment(t *testing.T) func() {
 	t.Helper()
-
-	tempDir, err := os.MkdirTemp("", "list_test")
-	if err != nil {
-		t.Fatalf("一時ディレクトリの作成に失敗: %v", err)
-	}
-
-	origXDGConfigHome := os.Getenv("XDG_CONFIG_HOME")
-	os.Setenv("XDG_CONFIG_HOME", tempDir)
-
-	cleanup := func() {
-		os.Setenv("XDG_CONFIG_HOME", origXDGConfigHome)
-		os.RemoveAll(tempDir)
-	}
-
-	cfg, err := config.New()
-	if err != nil {
-		cleanup()
-		t.Fatalf("設定の作成に失敗: %v", err)
-	}
-
-	_ = os.MkdirAll(cfg.ProfilesDir, 0755)
-	_ = os.MkdirAll(cfg.ServersDir, 0755)
-
+	_, _, cleanup := testutil.SetupIsolatedTestEnvironment(t)
 	return cleanup
 }
 
@@ -92,7 +70,11 @@ func TestExecute(t *testing.T) {
 				}
 			}()
 
-			Execute(tt.args)
+			cmd := NewCommand()
+			cmd.SetArgs(tt.args)
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("コマンドの実行に失敗: %v", err)
+			}
 		})
 	}
 }
@@ -0,0 +1,35 @@
+package mcpserve
+
+import (
+	"github.com/naoto24kawa/mcpjson/cmd/cmdutil"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/spf13/cobra"
+)
+
+// NewCommand returns the mcp-serve command
+func NewCommand() *cobra.Command {
+	var allow []string
+
+	cmd := &cobra.Command{
+		Use:   "mcp-serve",
+		Short: i18n.T("help.mcp_serve.short"),
+		Long:  i18n.T("help.mcp_serve.long"),
+		Example: `  mcpjson mcp-serve
+  mcpjson mcp-serve --allow list_profiles,diff_profile,apply_profile`,
+		Args: cmdutil.MaximumArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := cmdutil.LoadConfig()
+			if err != nil {
+				return err
+			}
+			// Without --allow the allow-list of the settings is used
+			if !cmd.Flags().Changed("allow") {
+				allow = nil
+			}
+			return serve(cmd.Context(), cfg, cmd.Root().Version, allow)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&allow, "allow", nil, i18n.T("help.mcp_serve.allow"))
+	return cmd
+}
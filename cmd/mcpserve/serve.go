@@ -0,0 +1,272 @@
+package mcpserve
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	cmdprofile "github.com/naoto24kawa/mcpjson/cmd/profile"
+	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/diff"
+	"github.com/naoto24kawa/mcpjson/internal/group"
+	"github.com/naoto24kawa/mcpjson/internal/history"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/mcpjson"
+	"github.com/naoto24kawa/mcpjson/internal/mcpserve"
+	"github.com/naoto24kawa/mcpjson/internal/output"
+	"github.com/naoto24kawa/mcpjson/internal/server"
+	"github.com/naoto24kawa/mcpjson/internal/utils"
+)
+
+// serve runs mcpjson as an MCP server on stdin and stdout, offering the
+// allowed tools. A nil allow-list is read from the settings.
+func serve(ctx context.Context, cfg *config.Config, version string, allow []string) error {
+	if allow == nil {
+		settings, err := cfg.LoadSettings()
+		if err != nil {
+			return err
+		}
+		allow = settings.MCPServe.Allow
+	}
+	tools, err := mcpserve.Allow(serveTools(cfg), allow)
+	if err != nil {
+		return utils.ArgumentError(err)
+	}
+
+	names := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		names = append(names, tool.Name)
+	}
+	fmt.Fprintln(os.Stderr, i18n.T("mcpserve.started", strings.Join(names, ", ")))
+
+	return mcpserve.NewServer("mcpjson", version, tools).ServeStdio(ctx)
+}
+
+// serveTools returns every tool mcp-serve can offer, backed by the stores
+// of cfg
+func serveTools(cfg *config.Config) []mcpserve.Tool {
+	applyProperties := map[string]interface{}{
+		"profile":  stringArg(i18n.T("mcpserve.arg.profile", config.DefaultProfileName)),
+		"path":     stringArg(i18n.T("mcpserve.arg.path")),
+		"client":   enumArg(i18n.T("mcpserve.arg.client"), server.ClientNames()...),
+		"mode":     enumArg(i18n.T("mcpserve.arg.mode"), string(mcpjson.ModeReplace), string(mcpjson.ModeMerge), string(mcpjson.ModeUpdateOnly)),
+		"conflict": enumArg(i18n.T("mcpserve.arg.conflict"), string(mcpjson.PolicyProfileWins), string(mcpjson.PolicyFileWins), string(mcpjson.PolicyFail)),
+	}
+
+	return []mcpserve.Tool{
+		{
+			Name:        "list_profiles",
+			Description: i18n.T("mcpserve.tool.list_profiles"),
+			ReadOnly:    true,
+			Handler: func(ctx context.Context, args json.RawMessage) (string, error) {
+				var buf bytes.Buffer
+				err := cmdprofile.NewProfileManager(cfg).ListWithFormat(&buf, false, output.FormatJSON)
+				return buf.String(), err
+			},
+		},
+		{
+			Name:        "list_templates",
+			Description: i18n.T("mcpserve.tool.list_templates"),
+			ReadOnly:    true,
+			Handler: func(ctx context.Context, args json.RawMessage) (string, error) {
+				serverManager := server.NewManager(cfg.ServersDir)
+				serverManager.UseConfig(cfg)
+				var buf bytes.Buffer
+				err := serverManager.ListWithFormat(&buf, false, output.FormatJSON)
+				return buf.String(), err
+			},
+		},
+		{
+			Name:        "list_groups",
+			Description: i18n.T("mcpserve.tool.list_groups"),
+			ReadOnly:    true,
+			Handler: func(ctx context.Context, args json.RawMessage) (string, error) {
+				groupManager := group.NewManager(cfg.GroupsDir)
+				groupManager.UseConfig(cfg)
+				var buf bytes.Buffer
+				err := groupManager.ListWithFormat(&buf, false, output.FormatJSON)
+				return buf.String(), err
+			},
+		},
+		{
+			Name:        "diff_profile",
+			Description: i18n.T("mcpserve.tool.diff_profile"),
+			InputSchema: map[string]interface{}{"properties": applyProperties},
+			ReadOnly:    true,
+			Handler: func(ctx context.Context, args json.RawMessage) (string, error) {
+				request, err := parseApplyArgs(cfg, "diff_profile", args)
+				if err != nil {
+					return "", err
+				}
+				diffs, err := planApply(cfg, request)
+				if err != nil {
+					return "", err
+				}
+				return toolJSON(diffs)
+			},
+		},
+		{
+			Name:        "apply_profile",
+			Description: i18n.T("mcpserve.tool.apply_profile"),
+			InputSchema: map[string]interface{}{"properties": applyProperties},
+			Destructive: true,
+			Handler: func(ctx context.Context, args json.RawMessage) (string, error) {
+				request, err := parseApplyArgs(cfg, "apply_profile", args)
+				if err != nil {
+					return "", err
+				}
+				diffs, err := planApply(cfg, request)
+				if err != nil {
+					return "", err
+				}
+				for _, target := range request.targets {
+					if err := cmdprofile.ApplyWithMode(cfg, request.profile, target, request.client, request.mode, request.conflict, false); err != nil {
+						return "", err
+					}
+				}
+				return toolJSON(diffs)
+			},
+		},
+		{
+			Name:        "add_server_to_config",
+			Description: i18n.T("mcpserve.tool.add_server_to_config"),
+			InputSchema: map[string]interface{}{
+				"properties": map[string]interface{}{
+					"template": stringArg(i18n.T("mcpserve.arg.template")),
+					"name":     stringArg(i18n.T("mcpserve.arg.name")),
+					"path":     stringArg(i18n.T("mcpserve.arg.server_path")),
+				},
+				"required": []string{"template"},
+			},
+			// path には任意のファイルを指定できるため、確認を求める
+			Destructive: true,
+			Handler: func(ctx context.Context, args json.RawMessage) (string, error) {
+				var request struct {
+					Template string `json:"template"`
+					Name     string `json:"name"`
+					Path     string `json:"path"`
+				}
+				if err := decodeArgs("add_server_to_config", args, &request); err != nil {
+					return "", err
+				}
+				if err := utils.ValidateName(request.Template, i18n.T("kind.template")); err != nil {
+					return "", err
+				}
+				if request.Name == "" {
+					request.Name = request.Template
+				}
+				if request.Path == "" {
+					request.Path = "./.mcp.json"
+				}
+
+				serverManager, err := cmdprofile.NewServerManager(cfg)
+				if err != nil {
+					return "", err
+				}
+				operation := fmt.Sprintf("server add %s --to %s", request.Template, request.Path)
+				err = history.Run(cfg, operation, []string{request.Path}, func() error {
+					return serverManager.AddToMCPConfig(request.Path, request.Template, request.Name, nil)
+				})
+				if err != nil {
+					return "", err
+				}
+				return i18n.T("server.added_to_mcp", request.Name, request.Path), nil
+			},
+		},
+	}
+}
+
+// applyRequest is what diff_profile and apply_profile were asked to do
+type applyRequest struct {
+	profile  string
+	targets  []string
+	client   server.ClientAdapter
+	mode     mcpjson.ApplyMode
+	conflict mcpjson.ConflictPolicy
+}
+
+// parseApplyArgs reads the arguments of diff_profile and apply_profile,
+// filling in the defaults of the apply command
+func parseApplyArgs(cfg *config.Config, tool string, args json.RawMessage) (*applyRequest, error) {
+	var raw struct {
+		Profile  string `json:"profile"`
+		Path     string `json:"path"`
+		Client   string `json:"client"`
+		Mode     string `json:"mode"`
+		Conflict string `json:"conflict"`
+	}
+	if err := decodeArgs(tool, args, &raw); err != nil {
+		return nil, err
+	}
+
+	request := &applyRequest{profile: raw.Profile, mode: mcpjson.ModeReplace, conflict: mcpjson.PolicyFail}
+	if request.profile == "" {
+		request.profile = cfg.DefaultProfile()
+	}
+	if err := utils.ValidateName(request.profile, i18n.T("kind.profile")); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if raw.Mode != "" {
+		if request.mode, err = mcpjson.ParseApplyMode(raw.Mode); err != nil {
+			return nil, err
+		}
+	}
+	if raw.Conflict != "" {
+		if request.conflict, err = mcpjson.ParseConflictPolicy(raw.Conflict); err != nil {
+			return nil, err
+		}
+	}
+	if raw.Client != "" {
+		if request.client, err = server.LookupClient(raw.Client); err != nil {
+			return nil, err
+		}
+	}
+	if request.targets, err = cmdprofile.Targets(cfg, raw.Path, request.client); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// planApply returns what applying the request would change in each
+// target. Env and header values are redacted since they are handed to a
+// model.
+func planApply(cfg *config.Config, request *applyRequest) ([]*diff.Result, error) {
+	diffs := make([]*diff.Result, 0, len(request.targets))
+	for _, target := range request.targets {
+		diffResult, err := cmdprofile.Plan(cfg, request.profile, target, request.client, request.mode, request.conflict, false)
+		if err != nil {
+			return nil, err
+		}
+		diffResult.Redact()
+		diffs = append(diffs, diffResult)
+	}
+	return diffs, nil
+}
+
+func decodeArgs(tool string, args json.RawMessage, v interface{}) error {
+	if err := json.Unmarshal(args, v); err != nil {
+		return i18n.Errorf("mcpserve.invalid_arguments", tool, err)
+	}
+	return nil
+}
+
+func toolJSON(v interface{}) (string, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func stringArg(description string) map[string]interface{} {
+	return map[string]interface{}{"type": "string", "description": description}
+}
+
+func enumArg(description string, values ...string) map[string]interface{} {
+	return map[string]interface{}{"type": "string", "description": description, "enum": values}
+}
@@ -0,0 +1,171 @@
+package mcpserve
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/mcpjson"
+	"github.com/naoto24kawa/mcpjson/internal/mcpserve"
+	"github.com/naoto24kawa/mcpjson/internal/profile"
+	"github.com/naoto24kawa/mcpjson/internal/server"
+)
+
+func TestServeTools(t *testing.T) {
+	baseDir := t.TempDir()
+	cfg := &config.Config{
+		BaseDir:     baseDir,
+		ProfilesDir: filepath.Join(baseDir, "profiles"),
+		ServersDir:  filepath.Join(baseDir, "servers"),
+		GroupsDir:   filepath.Join(baseDir, "groups"),
+	}
+	for _, dir := range []string{cfg.ProfilesDir, cfg.ServersDir, cfg.GroupsDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("ディレクトリ作成に失敗: %v", err)
+		}
+	}
+
+	serverManager := server.NewManager(cfg.ServersDir)
+	if err := serverManager.SaveManual("git", "uvx", []string{"mcp-server-git"}, map[string]string{"TOKEN": "secret-value"}, false); err != nil {
+		t.Fatalf("SaveManual() failed: %v", err)
+	}
+	profileManager := profile.NewManager(cfg.ProfilesDir)
+	if err := profileManager.Create("work", ""); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	if err := profileManager.AddServer("work", "git", "", nil); err != nil {
+		t.Fatalf("AddServer() failed: %v", err)
+	}
+
+	tools := map[string]mcpserve.Tool{}
+	for _, tool := range serveTools(cfg) {
+		tools[tool.Name] = tool
+	}
+	call := func(name string, args string) (string, error) {
+		t.Helper()
+		return tools[name].Handler(context.Background(), json.RawMessage(args))
+	}
+	targetPath := filepath.Join(baseDir, ".mcp.json")
+
+	t.Run("プロファイル一覧", func(t *testing.T) {
+		text, err := call("list_profiles", `{}`)
+		if err != nil || !strings.Contains(text, `"name": "work"`) {
+			t.Errorf("list_profiles = %s, %v", text, err)
+		}
+	})
+
+	t.Run("差分は値を伏せてファイルを変更しない", func(t *testing.T) {
+		text, err := call("diff_profile", `{"profile": "work", "path": "`+targetPath+`"}`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(text, "env.TOKEN") || strings.Contains(text, "secret-value") {
+			t.Errorf("diff_profile = %s, want the env key without its value", text)
+		}
+		if _, err := os.Stat(targetPath); !os.IsNotExist(err) {
+			t.Error("diff_profile must not write the target")
+		}
+	})
+
+	t.Run("不正な引数", func(t *testing.T) {
+		if _, err := call("apply_profile", `{"profile": "../work", "path": "`+targetPath+`"}`); err == nil {
+			t.Error("apply_profile accepted an invalid profile name")
+		}
+		if _, err := call("apply_profile", `{"profile": "work", "mode": "overwrite"}`); err == nil {
+			t.Error("apply_profile accepted an unknown mode")
+		}
+	})
+
+	t.Run("適用", func(t *testing.T) {
+		if _, err := call("apply_profile", `{"profile": "work", "path": "`+targetPath+`", "confirm": true}`); err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(targetPath)
+		if err != nil || !strings.Contains(string(data), "mcp-server-git") {
+			t.Errorf("target after apply_profile = %s, %v", data, err)
+		}
+	})
+
+	t.Run("サーバーの追加", func(t *testing.T) {
+		path := filepath.Join(baseDir, "other.json")
+		// 任意のファイルに書き込めるため確認を求める
+		if !tools["add_server_to_config"].Destructive {
+			t.Error("add_server_to_config is not destructive")
+		}
+		if _, err := call("add_server_to_config", `{"template": "git", "name": "repo", "path": "`+path+`", "confirm": true}`); err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil || !strings.Contains(string(data), `"repo"`) {
+			t.Errorf("target after add_server_to_config = %s, %v", data, err)
+		}
+	})
+}
+
+func TestServeTools_ConcurrentWrites(t *testing.T) {
+	baseDir := t.TempDir()
+	cfg := &config.Config{
+		BaseDir:     baseDir,
+		ProfilesDir: filepath.Join(baseDir, "profiles"),
+		ServersDir:  filepath.Join(baseDir, "servers"),
+		GroupsDir:   filepath.Join(baseDir, "groups"),
+	}
+	for _, dir := range []string{cfg.ProfilesDir, cfg.ServersDir, cfg.GroupsDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("ディレクトリ作成に失敗: %v", err)
+		}
+	}
+	if err := server.NewManager(cfg.ServersDir).SaveManual("git", "uvx", []string{"mcp-server-git"}, nil, false); err != nil {
+		t.Fatalf("SaveManual() failed: %v", err)
+	}
+
+	// 同じファイルへの追加が同時に届いても、どちらも書き込まれる
+	targetPath := filepath.Join(baseDir, ".mcp.json")
+	var requests strings.Builder
+	names := []string{"a", "b", "c", "d"}
+	for i, name := range names {
+		fmt.Fprintf(&requests, `{"jsonrpc": "2.0", "id": %d, "method": "tools/call", "params": {"name": "add_server_to_config", "arguments": {"template": "git", "name": %q, "path": %q, "confirm": true}}}`+"\n", i+1, name, targetPath)
+	}
+	var output bytes.Buffer
+	srv := mcpserve.NewServer("mcpjson", "test", serveTools(cfg))
+	captureStdout(func() {
+		if err := srv.Serve(context.Background(), strings.NewReader(requests.String()), &output); err != nil {
+			t.Errorf("Serve() failed: %v", err)
+		}
+	})
+	if strings.Contains(output.String(), `"isError":true`) {
+		t.Fatalf("add_server_to_config failed:\n%s", output.String())
+	}
+
+	mcpConfig, err := mcpjson.NewMCPConfigManager().Load(targetPath)
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	for _, name := range names {
+		if _, ok := mcpConfig.McpServers[name]; !ok {
+			t.Errorf("server %s is missing from %v", name, mcpConfig.McpServers)
+		}
+	}
+}
+
+func captureStdout(fn func()) string {
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	fn()
+
+	w.Close()
+	os.Stdout = old
+
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	return buf.String()
+}
@@ -3,34 +3,38 @@ package path
 import (
 	"fmt"
 
-	"github.com/spf13/cobra"
-
+	"github.com/naoto24kawa/mcpjson/cmd/cmdutil"
 	"github.com/naoto24kawa/mcpjson/cmd/profile"
-	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/spf13/cobra"
 )
 
-var PathCmd = &cobra.Command{
-	Use:   "path [profile_name]",
-	Short: "プロファイルファイルのパスを表示します",
-	Long:  "指定されたプロファイルファイルの絶対パスを表示します。プロファイル名を省略した場合はデフォルトプロファイルのパスを表示します。",
-	Args:  cobra.MaximumNArgs(1),
-	RunE: func(cmd *cobra.Command, args []string) error {
-		profileName := config.DefaultProfileName
-		if len(args) > 0 {
-			profileName = args[0]
-		}
+// NewCommand returns the path command
+func NewCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:               "path [profile]",
+		Short:             i18n.T("help.path.short"),
+		Long:              i18n.T("help.path.long"),
+		Args:              cmdutil.MaximumArgs(1),
+		ValidArgsFunction: cmdutil.CompleteProfiles(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := cmdutil.LoadConfig()
+			if err != nil {
+				return err
+			}
 
-		cfg, err := config.New()
-		if err != nil {
-			return fmt.Errorf("設定の読み込みに失敗しました: %w", err)
-		}
+			profileName := cfg.DefaultProfile()
+			if len(args) > 0 {
+				profileName = args[0]
+			}
 
-		profilePath, err := profile.GetProfilePath(cfg, profileName)
-		if err != nil {
-			return fmt.Errorf("プロファイルパスの取得に失敗しました: %w", err)
-		}
+			profilePath, err := profile.GetProfilePath(cfg, profileName)
+			if err != nil {
+				return i18n.Errorf("path.get_failed", err)
+			}
 
-		cmd.Print(profilePath)
-		return nil
-	},
+			fmt.Fprint(cmd.OutOrStdout(), profilePath)
+			return nil
+		},
+	}
 }
@@ -44,11 +44,12 @@ func TestPathCmd_DefaultProfile(t *testing.T) {
 
 	// Act
 	var output bytes.Buffer
-	PathCmd.SetOut(&output)
-	PathCmd.SetErr(&output) // Also set error output to capture errors
-	PathCmd.SetArgs([]string{})
+	pathCmd := NewCommand()
+	pathCmd.SetOut(&output)
+	pathCmd.SetErr(&output) // Also set error output to capture errors
+	pathCmd.SetArgs([]string{})
 
-	err := PathCmd.Execute()
+	err := pathCmd.Execute()
 
 	// Assert
 	if err != nil {
@@ -113,11 +114,12 @@ func TestPathCmd_SpecificProfile(t *testing.T) {
 			// Act
 			var output bytes.Buffer
 			var errOutput bytes.Buffer
-			PathCmd.SetOut(&output)
-			PathCmd.SetErr(&errOutput)
-			PathCmd.SetArgs([]string{actualProfileName})
+			pathCmd := NewCommand()
+			pathCmd.SetOut(&output)
+			pathCmd.SetErr(&errOutput)
+			pathCmd.SetArgs([]string{actualProfileName})
 
-			err := PathCmd.Execute()
+			err := pathCmd.Execute()
 
 			// Assert
 			if tt.expectError {
@@ -171,7 +173,7 @@ func TestPathCmd_ArgumentValidation(t *testing.T) {
 			name:        "too many arguments",
 			args:        []string{"profile1", "profile2"},
 			expectError: true,
-			errorMsg:    "accepts at most 1 arg",
+			errorMsg:    "profile2",
 		},
 		{
 			name:        "empty string argument",
@@ -204,11 +206,12 @@ func TestPathCmd_ArgumentValidation(t *testing.T) {
 			// Act
 			var output bytes.Buffer
 			var errOutput bytes.Buffer
-			PathCmd.SetOut(&output)
-			PathCmd.SetErr(&errOutput)
-			PathCmd.SetArgs(actualArgs)
+			pathCmd := NewCommand()
+			pathCmd.SetOut(&output)
+			pathCmd.SetErr(&errOutput)
+			pathCmd.SetArgs(actualArgs)
 
-			err := PathCmd.Execute()
+			err := pathCmd.Execute()
 
 			// Assert
 			if tt.expectError {
@@ -236,10 +239,11 @@ func TestPathCmd_OutputFormat(t *testing.T) {
 
 	// Act
 	var output bytes.Buffer
-	PathCmd.SetOut(&output)
-	PathCmd.SetArgs([]string{profileName})
+	pathCmd := NewCommand()
+	pathCmd.SetOut(&output)
+	pathCmd.SetArgs([]string{profileName})
 
-	err := PathCmd.Execute()
+	err := pathCmd.Execute()
 
 	// Assert
 	if err != nil {
@@ -271,11 +275,12 @@ func TestPathCmd_ConfigError(t *testing.T) {
 	// Act
 	var output bytes.Buffer
 	var errOutput bytes.Buffer
-	PathCmd.SetOut(&output)
-	PathCmd.SetErr(&errOutput)
-	PathCmd.SetArgs([]string{"test-profile"})
+	pathCmd := NewCommand()
+	pathCmd.SetOut(&output)
+	pathCmd.SetErr(&errOutput)
+	pathCmd.SetArgs([]string{"test-profile"})
 
-	err := PathCmd.Execute()
+	err := pathCmd.Execute()
 
 	// Assert
 	if err == nil {
@@ -336,10 +341,11 @@ func TestPathCmd_ValidationEdgeCases(t *testing.T) {
 
 			// Act
 			var output bytes.Buffer
-			PathCmd.SetOut(&output)
-			PathCmd.SetArgs([]string{actualProfileName})
+			pathCmd := NewCommand()
+			pathCmd.SetOut(&output)
+			pathCmd.SetArgs([]string{actualProfileName})
 
-			err := PathCmd.Execute()
+			err := pathCmd.Execute()
 
 			// Assert
 			if tt.expectError {
@@ -372,10 +378,11 @@ func TestPathCmd_Integration(t *testing.T) {
 	for _, profileName := range actualProfiles {
 		t.Run("profile_"+profileName, func(t *testing.T) {
 			var output bytes.Buffer
-			PathCmd.SetOut(&output)
-			PathCmd.SetArgs([]string{profileName})
+			pathCmd := NewCommand()
+			pathCmd.SetOut(&output)
+			pathCmd.SetArgs([]string{profileName})
 
-			err := PathCmd.Execute()
+			err := pathCmd.Execute()
 			if err != nil {
 				t.Errorf("Failed to get path for profile '%s': %v", profileName, err)
 			}
@@ -407,10 +414,11 @@ func BenchmarkPathCmd_Execute(b *testing.B) {
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		var output bytes.Buffer
-		PathCmd.SetOut(&output)
-		PathCmd.SetArgs([]string{"bench-profile"})
+		pathCmd := NewCommand()
+		pathCmd.SetOut(&output)
+		pathCmd.SetArgs([]string{"bench-profile"})
 
-		err := PathCmd.Execute()
+		err := pathCmd.Execute()
 		if err != nil {
 			b.Fatalf("Benchmark failed: %v", err)
 		}
@@ -433,10 +441,11 @@ func BenchmarkPathCmd_DefaultProfile(b *testing.B) {
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		var output bytes.Buffer
-		PathCmd.SetOut(&output)
-		PathCmd.SetArgs([]string{})
+		pathCmd := NewCommand()
+		pathCmd.SetOut(&output)
+		pathCmd.SetArgs([]string{})
 
-		err := PathCmd.Execute()
+		err := pathCmd.Execute()
 		if err != nil {
 			b.Fatalf("Benchmark failed: %v", err)
 		}
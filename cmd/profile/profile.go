@@ -1,23 +1,134 @@
 package profile
 
 import (
+	"fmt"
+	"os"
+	"strings"
+
 	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/diff"
+	"github.com/naoto24kawa/mcpjson/internal/history"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/mcpjson"
+	"github.com/naoto24kawa/mcpjson/internal/output"
 	"github.com/naoto24kawa/mcpjson/internal/profile"
+	"github.com/naoto24kawa/mcpjson/internal/provenance"
+	"github.com/naoto24kawa/mcpjson/internal/secret"
 	"github.com/naoto24kawa/mcpjson/internal/server"
+	"github.com/naoto24kawa/mcpjson/internal/utils"
 )
 
 func Apply(cfg *config.Config, profileName, targetPath string) error {
+	return ApplyWithMode(cfg, profileName, targetPath, nil, mcpjson.ModeReplace, mcpjson.PolicyFail, false)
+}
+
+// ApplyWithMode applies the profile combining it with the servers already
+// in targetPath according to mode and conflict. The file is written in the
+// client's format; a nil client writes a plain .mcp.json. With viaProxy a
+// single 'mcpjson proxy' entry is written in place of the servers.
+func ApplyWithMode(cfg *config.Config, profileName, targetPath string, client server.ClientAdapter, mode mcpjson.ApplyMode, conflict mcpjson.ConflictPolicy, viaProxy bool) error {
+	profileManager := NewProfileManager(cfg)
+	serverManager, err := NewServerManager(cfg)
+	if err != nil {
+		return err
+	}
+
+	operation := fmt.Sprintf("apply %s --to %s", profileName, targetPath)
+	return history.Run(cfg, operation, []string{targetPath, cfg.StateDir()}, func() error {
+		return profileManager.ApplyWithOptions(profileName, targetPath, serverManager, applyOptions(cfg, client, mode, conflict, viaProxy))
+	})
+}
+
+// Targets returns the files apply writes to: targetPath when given, else
+// the client's own file, else the targets of the project
+func Targets(cfg *config.Config, targetPath string, client server.ClientAdapter) ([]string, error) {
+	switch {
+	case targetPath != "":
+		return []string{targetPath}, nil
+	case client != nil:
+		path, err := client.DefaultPath()
+		if err != nil {
+			return nil, utils.EnvironmentError(err)
+		}
+		return []string{path}, nil
+	}
+	return cfg.DefaultTargets(), nil
+}
+
+// DryRun prints what applying the profile would change in targetPath
+// without writing it. Env and header values, sensitive flags and URL
+// parameters are hidden unless showSecrets is set, since they hold
+// expanded secrets.
+func DryRun(cfg *config.Config, profileName, targetPath string, client server.ClientAdapter, format string, mode mcpjson.ApplyMode, conflict mcpjson.ConflictPolicy, viaProxy, showSecrets bool) error {
+	diffResult, err := Plan(cfg, profileName, targetPath, client, mode, conflict, viaProxy)
+	if err != nil {
+		return err
+	}
+	if !showSecrets {
+		diffResult.Redact()
+	}
+	return diffResult.Write(os.Stdout, format)
+}
+
+// Plan returns what applying the profile would change in targetPath.
+// The values are not redacted.
+func Plan(cfg *config.Config, profileName, targetPath string, client server.ClientAdapter, mode mcpjson.ApplyMode, conflict mcpjson.ConflictPolicy, viaProxy bool) (*diff.Result, error) {
+	profileManager := NewProfileManager(cfg)
+	serverManager, err := NewServerManager(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	current, result, err := profileManager.Plan(profileName, targetPath, serverManager, applyOptions(cfg, client, mode, conflict, viaProxy))
+	if err != nil {
+		return nil, err
+	}
+	return diff.Compare(targetPath, current.McpServers, "build:"+profileName, result.Servers), nil
+}
+
+func applyOptions(cfg *config.Config, client server.ClientAdapter, mode mcpjson.ApplyMode, conflict mcpjson.ConflictPolicy, viaProxy bool) profile.ApplyOptions {
+	return profile.ApplyOptions{
+		Mode:     mode,
+		Conflict: conflict,
+		State:    provenance.NewStore(cfg.StateDir()),
+		Client:   client,
+		ViaProxy: viaProxy,
+	}
+}
+
+// NewProfileManager returns a profile manager whose reads fall through
+// every store
+func NewProfileManager(cfg *config.Config) *profile.Manager {
 	profileManager := profile.NewManager(cfg.ProfilesDir)
+	profileManager.UseConfig(cfg)
+	return profileManager
+}
+
+// NewServerManager returns a server manager that resolves secrets with
+// the configured provider and reads the templates and variables of the
+// current project
+func NewServerManager(cfg *config.Config) (*server.Manager, error) {
 	serverManager := server.NewManager(cfg.ServersDir)
+	serverManager.UseConfig(cfg)
 
-	return profileManager.Apply(profileName, targetPath, serverManager)
+	provider, err := secret.Open(cfg)
+	if err != nil {
+		return nil, err
+	}
+	serverManager.SetSecretProvider(provider)
+	return serverManager, nil
 }
 
-func Save(cfg *config.Config, profileName, fromPath string, force bool) error {
+// Save creates a profile from the servers in fromPath, read in the
+// client's format. A nil client reads a plain .mcp.json.
+func Save(cfg *config.Config, profileName, fromPath string, client server.ClientAdapter, force bool) error {
 	profileManager := profile.NewManager(cfg.ProfilesDir)
 	serverManager := server.NewManager(cfg.ServersDir)
 
-	return profileManager.Save(profileName, fromPath, serverManager, force)
+	paths := []string{cfg.ProfilesDir, cfg.ServersDir}
+	return history.Run(cfg, "save "+profileName, paths, func() error {
+		return profileManager.SaveFrom(profileName, fromPath, client, serverManager, force)
+	})
 }
 
 func Create(cfg *config.Config, profileName, templateName string) error {
@@ -25,38 +136,55 @@ func Create(cfg *config.Config, profileName, templateName string) error {
 
 	description := ""
 	if templateName != "" {
-		description = "テンプレート " + templateName + " から作成"
+		description = i18n.T("profile.created_from_template", templateName)
 	}
 
-	return profileManager.Create(profileName, description)
+	return history.Run(cfg, "create "+profileName, []string{cfg.ProfilesDir}, func() error {
+		return profileManager.Create(profileName, description)
+	})
 }
 
 func List(cfg *config.Config, detail bool) error {
-	profileManager := profile.NewManager(cfg.ProfilesDir)
-	return profileManager.List(detail)
+	return ListWithFormat(cfg, detail, output.FormatTable)
+}
+
+func ListWithFormat(cfg *config.Config, detail bool, format output.Format) error {
+	profileManager := NewProfileManager(cfg)
+	return profileManager.ListWithFormat(os.Stdout, detail, format)
 }
 
 func Delete(cfg *config.Config, profileName string, force bool) error {
 	profileManager := profile.NewManager(cfg.ProfilesDir)
-	return profileManager.Delete(profileName, force)
+	return history.Run(cfg, "delete "+profileName, []string{cfg.ProfilesDir}, func() error {
+		return profileManager.Delete(profileName, force)
+	})
 }
 
 func Rename(cfg *config.Config, oldName, newName string, force bool) error {
 	profileManager := profile.NewManager(cfg.ProfilesDir)
-	return profileManager.Rename(oldName, newName, force)
+	operation := fmt.Sprintf("rename %s %s", oldName, newName)
+	return history.Run(cfg, operation, []string{cfg.ProfilesDir}, func() error {
+		return profileManager.Rename(oldName, newName, force)
+	})
 }
 
 func Copy(cfg *config.Config, sourceName, destName string, force bool) error {
-	profileManager := profile.NewManager(cfg.ProfilesDir)
-	return profileManager.Copy(sourceName, destName, force)
+	profileManager := NewProfileManager(cfg)
+	operation := fmt.Sprintf("copy %s %s", sourceName, destName)
+	return history.Run(cfg, operation, []string{cfg.ProfilesDir}, func() error {
+		return profileManager.Copy(sourceName, destName, force)
+	})
 }
 
 func Merge(cfg *config.Config, destName string, sourceNames []string, force bool) error {
-	profileManager := profile.NewManager(cfg.ProfilesDir)
-	return profileManager.Merge(destName, sourceNames, force)
+	profileManager := NewProfileManager(cfg)
+	operation := fmt.Sprintf("merge %s %s", destName, strings.Join(sourceNames, " "))
+	return history.Run(cfg, operation, []string{cfg.ProfilesDir}, func() error {
+		return profileManager.Merge(destName, sourceNames, force)
+	})
 }
 
 func GetProfilePath(cfg *config.Config, profileName string) (string, error) {
-	profileManager := profile.NewManager(cfg.ProfilesDir)
+	profileManager := NewProfileManager(cfg)
 	return profileManager.GetProfilePath(profileName)
 }
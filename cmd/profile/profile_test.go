@@ -1,16 +1,24 @@
 package profile
 
 import (
+	"bytes"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/history"
+	"github.com/naoto24kawa/mcpjson/internal/mcpjson"
+	"github.com/naoto24kawa/mcpjson/internal/profile"
+	"github.com/naoto24kawa/mcpjson/internal/server"
 )
 
 func TestCreate(t *testing.T) {
 	tempDir := t.TempDir()
 	cfg := &config.Config{
+		BaseDir:     tempDir,
 		ProfilesDir: tempDir,
 		ServersDir:  tempDir,
 	}
@@ -56,6 +64,7 @@ func TestCreate(t *testing.T) {
 func TestList(t *testing.T) {
 	tempDir := t.TempDir()
 	cfg := &config.Config{
+		BaseDir:     tempDir,
 		ProfilesDir: tempDir,
 		ServersDir:  tempDir,
 	}
@@ -96,6 +105,7 @@ func TestList(t *testing.T) {
 func TestDelete(t *testing.T) {
 	tempDir := t.TempDir()
 	cfg := &config.Config{
+		BaseDir:     tempDir,
 		ProfilesDir: tempDir,
 		ServersDir:  tempDir,
 	}
@@ -139,6 +149,7 @@ func TestDelete(t *testing.T) {
 func TestRename(t *testing.T) {
 	tempDir := t.TempDir()
 	cfg := &config.Config{
+		BaseDir:     tempDir,
 		ProfilesDir: tempDir,
 		ServersDir:  tempDir,
 	}
@@ -181,3 +192,133 @@ func TestRename(t *testing.T) {
 		})
 	}
 }
+
+func captureStdout(fn func()) string {
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	fn()
+
+	w.Close()
+	os.Stdout = old
+
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	return buf.String()
+}
+
+func TestDryRun(t *testing.T) {
+	baseDir := t.TempDir()
+	cfg := &config.Config{
+		BaseDir:     baseDir,
+		ProfilesDir: filepath.Join(baseDir, "profiles"),
+		ServersDir:  filepath.Join(baseDir, "servers"),
+	}
+	for _, dir := range []string{cfg.ProfilesDir, cfg.ServersDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("ディレクトリ作成に失敗: %v", err)
+		}
+	}
+
+	serverManager := server.NewManager(cfg.ServersDir)
+	if err := serverManager.SaveManual("git", "uvx", []string{"mcp-server-git"}, map[string]string{"GITHUB_TOKEN": "ghp_plain"}, false); err != nil {
+		t.Fatalf("SaveManual() failed: %v", err)
+	}
+	profileManager := profile.NewManager(cfg.ProfilesDir)
+	if err := profileManager.Create("work", ""); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	if err := profileManager.AddServer("work", "git", "", nil); err != nil {
+		t.Fatalf("AddServer() failed: %v", err)
+	}
+
+	targetPath := filepath.Join(baseDir, ".mcp.json")
+	original := []byte(`{"mcpServers": {"old": {"command": "node"}}}`)
+	if err := os.WriteFile(targetPath, original, 0644); err != nil {
+		t.Fatalf("MCP設定ファイルの作成に失敗: %v", err)
+	}
+
+	var err error
+	output := captureStdout(func() {
+		err = DryRun(cfg, "work", targetPath, nil, "json", mcpjson.ModeMerge, mcpjson.PolicyFail, false, false)
+	})
+	if err != nil {
+		t.Fatalf("DryRun() failed: %v", err)
+	}
+	if strings.Contains(output, "ghp_plain") {
+		t.Errorf("DryRun() must hide env values by default:\n%s", output)
+	}
+
+	output = captureStdout(func() {
+		err = DryRun(cfg, "work", targetPath, nil, "json", mcpjson.ModeMerge, mcpjson.PolicyFail, false, true)
+	})
+	if err != nil {
+		t.Fatalf("DryRun() failed: %v", err)
+	}
+	if !strings.Contains(output, "ghp_plain") {
+		t.Errorf("DryRun() with showSecrets must print env values:\n%s", output)
+	}
+
+	data, err := os.ReadFile(targetPath)
+	if err != nil {
+		t.Fatalf("MCP設定ファイルの読み込みに失敗: %v", err)
+	}
+	if string(data) != string(original) {
+		t.Errorf("DryRun() must not modify the target:\n%s", data)
+	}
+}
+
+func TestApplyWithMode_Undo(t *testing.T) {
+	baseDir := t.TempDir()
+	cfg := &config.Config{
+		BaseDir:     baseDir,
+		ProfilesDir: filepath.Join(baseDir, "profiles"),
+		ServersDir:  filepath.Join(baseDir, "servers"),
+	}
+	for _, dir := range []string{cfg.ProfilesDir, cfg.ServersDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("ディレクトリ作成に失敗: %v", err)
+		}
+	}
+
+	serverManager := server.NewManager(cfg.ServersDir)
+	if err := serverManager.SaveManual("git", "uvx", []string{"mcp-server-git"}, nil, false); err != nil {
+		t.Fatalf("SaveManual() failed: %v", err)
+	}
+	profileManager := profile.NewManager(cfg.ProfilesDir)
+	if err := profileManager.Create("work", ""); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	if err := profileManager.AddServer("work", "git", "", nil); err != nil {
+		t.Fatalf("AddServer() failed: %v", err)
+	}
+
+	// 状態ディレクトリのないストアに適用した操作も取り消せる
+	if _, err := os.Stat(cfg.StateDir()); !os.IsNotExist(err) {
+		t.Fatalf("state directory already exists: %v", err)
+	}
+	targetPath := filepath.Join(baseDir, "project", ".mcp.json")
+	var err error
+	captureStdout(func() {
+		err = ApplyWithMode(cfg, "work", targetPath, nil, mcpjson.ModeReplace, mcpjson.PolicyFail, false)
+	})
+	if err != nil {
+		t.Fatalf("ApplyWithMode() failed: %v", err)
+	}
+
+	journal, err := history.Open(cfg)
+	if err != nil {
+		t.Fatalf("history.Open() failed: %v", err)
+	}
+	entry, err := journal.Undo(0, false)
+	if err != nil {
+		t.Fatalf("Undo() failed: %v", err)
+	}
+	if !strings.HasPrefix(entry.Operation, "apply work") {
+		t.Errorf("Undo() = %s, want the apply", entry.Operation)
+	}
+	if _, err := os.Stat(targetPath); !os.IsNotExist(err) {
+		t.Error("apply で作成されたファイルが削除されていません")
+	}
+}
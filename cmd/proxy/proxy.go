@@ -0,0 +1,82 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/naoto24kawa/mcpjson/cmd/cmdutil"
+	cmdprofile "github.com/naoto24kawa/mcpjson/cmd/profile"
+	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/mcpserve"
+	"github.com/naoto24kawa/mcpjson/internal/proxy"
+	"github.com/spf13/cobra"
+)
+
+// NewCommand returns the proxy command
+func NewCommand() *cobra.Command {
+	var projectDir string
+
+	cmd := &cobra.Command{
+		Use:   "proxy [profile]",
+		Short: i18n.T("help.proxy.short"),
+		Long:  i18n.T("help.proxy.long", config.DefaultProfileName),
+		Example: `  mcpjson proxy work
+  mcpjson apply work --via-proxy
+  mcpjson proxy work --project ~/src/app`,
+		Args:              cmdutil.MaximumArgs(1),
+		ValidArgsFunction: cmdutil.CompleteProfiles(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// クライアントは任意のディレクトリで起動するため、プロジェクトを明示できるようにする
+			if projectDir != "" {
+				if err := os.Chdir(projectDir); err != nil {
+					return i18n.Errorf("proxy.project_dir_failed", projectDir, err)
+				}
+			}
+			cfg, err := cmdutil.LoadConfig()
+			if err != nil {
+				return err
+			}
+			profileName, err := cmdutil.ProfileArg(cfg, args)
+			if err != nil {
+				return err
+			}
+			return runProxy(cmd.Context(), cfg, profileName, cmd.Root().Version)
+		},
+	}
+
+	cmd.Flags().StringVar(&projectDir, "project", "", i18n.T("help.proxy.project"))
+	return cmd
+}
+
+// runProxy serves the enabled servers of the profile behind one MCP server
+// on stdin and stdout
+func runProxy(ctx context.Context, cfg *config.Config, profileName, version string) error {
+	profileManager := cmdprofile.NewProfileManager(cfg)
+	serverManager, err := cmdprofile.NewServerManager(cfg)
+	if err != nil {
+		return err
+	}
+	mcpConfig, err := profileManager.Build(profileName, serverManager)
+	if err != nil {
+		return err
+	}
+	resolved, err := profileManager.Resolve(profileName)
+	if err != nil {
+		return err
+	}
+
+	var upstreams []proxy.Upstream
+	for _, ref := range resolved.ServerRefs() {
+		if mcpServer, ok := mcpConfig.McpServers[ref.Name]; ok {
+			upstreams = append(upstreams, proxy.Upstream{Name: ref.Name, Server: mcpServer, Tools: ref.Overrides.Tools})
+		}
+	}
+
+	p := proxy.Start(ctx, upstreams, version, os.Stderr)
+	defer p.Close()
+	fmt.Fprintln(os.Stderr, i18n.T("proxy.started", profileName, len(p.Tools())))
+
+	return mcpserve.NewServer(proxy.ServerName, version, p.Tools()).ServeStdio(ctx)
+}
@@ -1,35 +1,40 @@
 package rename
 
 import (
-	"fmt"
-	"os"
-
+	"github.com/naoto24kawa/mcpjson/cmd/cmdutil"
 	"github.com/naoto24kawa/mcpjson/cmd/profile"
 	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
 	"github.com/naoto24kawa/mcpjson/internal/utils"
+	"github.com/spf13/cobra"
 )
 
-func Execute(args []string) {
-	oldName, newName, argsOffset, err := utils.ParseRenameArgs(args, config.DefaultProfileName)
-	if err != nil {
-		fmt.Fprintln(os.Stderr, "エラー:", err)
-		os.Exit(utils.ExitArgumentError)
-	}
-	force := false
-
-	for i := argsOffset; i < len(args); i++ {
-		switch args[i] {
-		case "--force", "-f":
-			force = true
-		}
+// NewCommand returns the rename command
+func NewCommand() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:               "rename [old-name] <new-name>",
+		Short:             i18n.T("help.rename.short"),
+		Long:              i18n.T("help.rename.long", config.DefaultProfileName),
+		Args:              cmdutil.RangeArgs(1, 2, i18n.Error("utils.rename_missing_name")),
+		ValidArgsFunction: cmdutil.CompleteProfiles(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := cmdutil.LoadConfig()
+			if err != nil {
+				return err
+			}
+			oldName, newName, _, err := utils.ParseRenameArgs(args, cfg.DefaultProfile())
+			if err != nil {
+				return utils.ArgumentError(err)
+			}
+			if err := cmdutil.ValidateNames(i18n.T("kind.profile"), oldName, newName); err != nil {
+				return err
+			}
+			return profile.Rename(cfg, oldName, newName, force)
+		},
 	}
 
-	utils.HandleArgumentError(utils.ValidateName(oldName, "プロファイル"))
-
-	utils.HandleArgumentError(utils.ValidateName(newName, "プロファイル"))
-
-	cfg, err := config.New()
-	utils.HandleEnvironmentError(err)
-
-	utils.HandleGeneralError(profile.Rename(cfg, oldName, newName, force))
+	cmdutil.AddForceFlag(cmd, &force, "f")
+	return cmd
 }
@@ -2,122 +2,103 @@ package reset
 
 import (
 	"fmt"
-	"os"
 
+	"github.com/naoto24kawa/mcpjson/cmd/cmdutil"
 	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/history"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
 	"github.com/naoto24kawa/mcpjson/internal/interaction"
 	"github.com/naoto24kawa/mcpjson/internal/profile"
 	"github.com/naoto24kawa/mcpjson/internal/server"
-	"github.com/naoto24kawa/mcpjson/internal/utils"
+	"github.com/spf13/cobra"
 )
 
-func Execute(cfg *config.Config, args []string) {
-	if len(args) == 0 {
-		PrintUsage()
-		os.Exit(0)
-	}
-
-	cmd := args[0]
-	cmdArgs := args[1:]
+// NewCommand returns the reset command with its subcommands
+func NewCommand() *cobra.Command {
+	cmd := cmdutil.NewGroup("reset", i18n.T("help.reset.short"))
+	cmd.Example = `  mcpjson reset all
+  mcpjson reset profiles --force
+  mcpjson reset servers`
+	cmd.AddCommand(
+		newSubcommand("all", i18n.T("help.reset_all.short"), resetAll),
+		newSubcommand("profiles", i18n.T("help.reset_profiles.short"), resetProfiles),
+		newSubcommand("servers", i18n.T("help.reset_servers.short"), resetServers),
+	)
+	return cmd
+}
 
+func newSubcommand(use, short string, reset func(force bool) error) *cobra.Command {
 	var force bool
-	for i, arg := range cmdArgs {
-		if arg == "--force" || arg == "-f" {
-			force = true
-			_ = append(cmdArgs[:i], cmdArgs[i+1:]...)
-			break
-		}
-	}
 
-	switch cmd {
-	case "all":
-		if err := resetAll(force); err != nil {
-			fmt.Fprintln(os.Stderr, "エラー:", err)
-			os.Exit(utils.ExitGeneralError)
-		}
-	case "profiles":
-		if err := resetProfiles(force); err != nil {
-			fmt.Fprintln(os.Stderr, "エラー:", err)
-			os.Exit(utils.ExitGeneralError)
-		}
-	case "servers":
-		if err := resetServers(force); err != nil {
-			fmt.Fprintln(os.Stderr, "エラー:", err)
-			os.Exit(utils.ExitGeneralError)
-		}
-	default:
-		fmt.Fprintf(os.Stderr, "エラー: 不明なリセットコマンド '%s'\n", cmd)
-		PrintUsage()
-		os.Exit(utils.ExitGeneralError)
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		Args:  cmdutil.MaximumArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return reset(force)
+		},
 	}
-}
 
-func PrintUsage() {
-	fmt.Println(`mcpconfig reset - 開発用設定のリセット
-
-使用方法:
-  mcpconfig reset <サブコマンド> [オプション]
-
-サブコマンド:
-  all       すべての設定をリセット (プロファイル + サーバーテンプレート)
-  profiles  すべてのプロファイルを削除
-  servers   すべてのサーバーテンプレートを削除
-
-オプション:
-  --force, -f  確認なしで実行
-
-例:
-  mcpconfig reset all                すべての設定をリセット
-  mcpconfig reset profiles --force   確認なしですべてのプロファイルを削除
-  mcpconfig reset servers            すべてのサーバーテンプレートを削除`)
+	cmdutil.AddForceFlag(cmd, &force, "f")
+	return cmd
 }
 
 func resetAll(force bool) error {
-	cfg, err := config.New()
+	cfg, err := cmdutil.LoadConfig()
 	if err != nil {
-		return fmt.Errorf("設定の初期化に失敗しました: %w", err)
+		return err
 	}
 
 	if !force {
-		fmt.Println("以下の設定がすべて削除されます:")
-		fmt.Println("  - すべてのプロファイル")
-		fmt.Println("  - すべてのサーバーテンプレート")
+		fmt.Println(i18n.T("reset.all_list"))
+		fmt.Println(i18n.T("reset.all_profiles"))
+		fmt.Println(i18n.T("reset.all_templates"))
 		fmt.Println()
 
-		if !interaction.Confirm("本当にすべての設定をリセットしますか？") {
-			fmt.Println("リセットをキャンセルしました")
+		if !interaction.Confirm(i18n.T("reset.confirm_all")) {
+			fmt.Println(i18n.T("common.reset_cancelled"))
 			return nil
 		}
 	}
 
-	if err := resetProfilesWithConfig(cfg, true); err != nil {
-		fmt.Printf("プロファイルのリセットに失敗しました: %v\n", err)
-	}
+	err = history.Run(cfg, "reset all", []string{cfg.ProfilesDir, cfg.ServersDir}, func() error {
+		if err := resetProfilesWithConfig(cfg, true); err != nil {
+			fmt.Println(i18n.T("reset.profiles_failed", err))
+		}
 
-	if err := resetServersWithConfig(cfg, true); err != nil {
-		fmt.Printf("サーバーテンプレートのリセットに失敗しました: %v\n", err)
+		if err := resetServersWithConfig(cfg, true); err != nil {
+			fmt.Println(i18n.T("reset.templates_failed", err))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	fmt.Println("すべての設定をリセットしました")
+	fmt.Println(i18n.T("reset.all_done"))
 	return nil
 }
 
 func resetProfiles(force bool) error {
-	cfg, err := config.New()
+	cfg, err := cmdutil.LoadConfig()
 	if err != nil {
-		return fmt.Errorf("設定の初期化に失敗しました: %w", err)
+		return err
 	}
 
-	return resetProfilesWithConfig(cfg, force)
+	return history.Run(cfg, "reset profiles", []string{cfg.ProfilesDir}, func() error {
+		return resetProfilesWithConfig(cfg, force)
+	})
 }
 
 func resetServers(force bool) error {
-	cfg, err := config.New()
+	cfg, err := cmdutil.LoadConfig()
 	if err != nil {
-		return fmt.Errorf("設定の初期化に失敗しました: %w", err)
+		return err
 	}
 
-	return resetServersWithConfig(cfg, force)
+	return history.Run(cfg, "reset servers", []string{cfg.ServersDir}, func() error {
+		return resetServersWithConfig(cfg, force)
+	})
 }
 
 func resetProfilesWithConfig(cfg *config.Config, force bool) error {
@@ -1,39 +1,17 @@
 package reset
 
 import (
-	"os"
 	"testing"
 
 	"github.com/naoto24kawa/mcpjson/internal/config"
 	"github.com/naoto24kawa/mcpjson/internal/profile"
 	"github.com/naoto24kawa/mcpjson/internal/server"
+	"github.com/naoto24kawa/mcpjson/internal/testutil"
 )
 
 func setupTestEnvironment(t *testing.T) (*config.Config, func()) {
 	t.Helper()
-
-	tempDir, err := os.MkdirTemp("", "reset_test")
-	if err != nil {
-		t.Fatalf("一時ディレクトリの作成に失敗: %v", err)
-	}
-
-	origXDGConfigHome := os.Getenv("XDG_CONFIG_HOME")
-	os.Setenv("XDG_CONFIG_HOME", tempDir)
-
-	cleanup := func() {
-		os.Setenv("XDG_CONFIG_HOME", origXDGConfigHome)
-		os.RemoveAll(tempDir)
-	}
-
-	cfg, err := config.New()
-	if err != nil {
-		cleanup()
-		t.Fatalf("設定の作成に失敗: %v", err)
-	}
-
-	_ = os.MkdirAll(cfg.ProfilesDir, 0755)
-	_ = os.MkdirAll(cfg.ServersDir, 0755)
-
+	_, cfg, cleanup := testutil.SetupIsolatedTestEnvironment(t)
 	return cfg, cleanup
 }
 
@@ -78,13 +56,11 @@ func TestExecute(t *testing.T) {
 
 			tt.setup(cfg)
 
-			defer func() {
-				if r := recover(); r != nil {
-					t.Errorf("予期しないパニック: %v", r)
-				}
-			}()
-
-			Execute(cfg, tt.args)
+			cmd := NewCommand()
+			cmd.SetArgs(tt.args)
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("reset %v failed: %v", tt.args, err)
+			}
 		})
 	}
 }
@@ -3,189 +3,246 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/naoto24kawa/mcpjson/cmd/apply"
+	"github.com/naoto24kawa/mcpjson/cmd/bundle"
+	"github.com/naoto24kawa/mcpjson/cmd/cmdutil"
+	"github.com/naoto24kawa/mcpjson/cmd/completion"
 	"github.com/naoto24kawa/mcpjson/cmd/copy"
 	"github.com/naoto24kawa/mcpjson/cmd/create"
 	"github.com/naoto24kawa/mcpjson/cmd/delete"
 	"github.com/naoto24kawa/mcpjson/cmd/detail"
+	"github.com/naoto24kawa/mcpjson/cmd/diff"
+	"github.com/naoto24kawa/mcpjson/cmd/doctor"
 	"github.com/naoto24kawa/mcpjson/cmd/group"
+	"github.com/naoto24kawa/mcpjson/cmd/history"
 	"github.com/naoto24kawa/mcpjson/cmd/list"
+	"github.com/naoto24kawa/mcpjson/cmd/mcpserve"
 	"github.com/naoto24kawa/mcpjson/cmd/merge"
 	"github.com/naoto24kawa/mcpjson/cmd/path"
+	"github.com/naoto24kawa/mcpjson/cmd/proxy"
 	"github.com/naoto24kawa/mcpjson/cmd/rename"
 	"github.com/naoto24kawa/mcpjson/cmd/reset"
 	"github.com/naoto24kawa/mcpjson/cmd/save"
+	"github.com/naoto24kawa/mcpjson/cmd/secret"
 	"github.com/naoto24kawa/mcpjson/cmd/server"
+	"github.com/naoto24kawa/mcpjson/cmd/sync"
 	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
 	"github.com/naoto24kawa/mcpjson/internal/utils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var Version = "v0.2.5"
 
-type CommandRouter struct{}
-
+// Execute runs the command line and exits with the code attached to the
+// error the command returned
 func Execute() {
-	router := &CommandRouter{}
-
-	if len(os.Args) == 1 {
-		printUsage()
-		os.Exit(0)
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T("common.error_prefix"), err)
+		os.Exit(utils.ExitCode(err))
 	}
-
-	cmd := os.Args[1]
-	args := os.Args[2:]
-
-	router.Route(cmd, args)
 }
 
-func (r *CommandRouter) Route(cmd string, args []string) {
-	switch cmd {
-	case "help", "-h", "--help":
-		r.handleHelp()
-	case "version", "-v", "--version":
-		r.handleVersion()
-	case "apply":
-		apply.Execute(args)
-	case "save":
-		save.Execute(args)
-	case "create":
-		create.Execute(args)
-	case "list":
-		list.Execute(args)
-	case "delete":
-		delete.Execute(args)
-	case "rename":
-		rename.Execute(args)
-	case "copy":
-		copy.Execute(args)
-	case "merge":
-		merge.Execute(args)
-	case "detail":
-		r.handleDetail(args)
-	case "server":
-		r.handleServer(args)
-	case "group":
-		r.handleGroup(args)
-	case "reset":
-		r.handleReset(args)
-	case "path":
-		r.handlePath(args)
-	default:
-		r.handleUnknownCommand(cmd)
+func run(osArgs []string) error {
+	args, lang, err := osArgs, "", error(nil)
+	// Completion requests keep --lang so that its value can be completed
+	if len(args) == 0 || args[0] != cobra.ShellCompRequestCmd {
+		if args, lang, err = extractLangFlag(osArgs); err != nil {
+			return utils.ArgumentError(err)
+		}
 	}
-}
-
-func (r *CommandRouter) handleHelp() {
-	printUsage()
-	os.Exit(0)
-}
+	locale, err := i18n.Detect(lang)
+	if err != nil {
+		return utils.ArgumentError(err)
+	}
+	i18n.SetLocale(locale)
 
-func (r *CommandRouter) handleVersion() {
-	fmt.Printf("mcpconfig version %s\n", Version)
-	os.Exit(0)
+	root := NewRootCommand()
+	root.SetArgs(args)
+	return root.Execute()
 }
 
-func (r *CommandRouter) handleDetail(args []string) {
-	if err := detail.Execute(args); err != nil {
-		fmt.Fprintln(os.Stderr, "エラー:", err)
-		os.Exit(utils.ExitGeneralError)
+// extractLangFlag removes the global --lang flag from the arguments so it
+// can be given before or after the command. Arguments after "--" and the
+// values of other flags are left alone, so that e.g. "--env --lang" keeps
+// its value.
+func extractLangFlag(args []string) ([]string, string, error) {
+	// 値を取るフラグを知るため、ロケールを選ぶ前のコマンドツリーで解釈する
+	cmd, _, _ := NewRootCommand().Find(args)
+
+	rest := make([]string, 0, len(args))
+	lang := ""
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--":
+			return append(rest, args[i:]...), lang, nil
+		case args[i] == "--lang":
+			value, next, err := utils.ParseFlag(args, i, "--lang")
+			if err != nil {
+				return nil, "", err
+			}
+			lang = value
+			i = next
+		case strings.HasPrefix(args[i], "--lang="):
+			lang = strings.TrimPrefix(args[i], "--lang=")
+		default:
+			rest = append(rest, args[i])
+			if takesValue(cmd, args[i]) && i+1 < len(args) {
+				i++
+				rest = append(rest, args[i])
+			}
+		}
 	}
+	return rest, lang, nil
 }
 
-func (r *CommandRouter) handlePath(args []string) {
-	path.PathCmd.SetArgs(args)
-	if err := path.PathCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, "エラー:", err)
-		os.Exit(utils.ExitGeneralError)
+// takesValue reports whether arg is a flag of cmd whose value is the
+// next argument
+func takesValue(cmd *cobra.Command, arg string) bool {
+	if cmd == nil || len(arg) < 2 || arg[0] != '-' || strings.Contains(arg, "=") {
+		return false
 	}
-}
 
-func (r *CommandRouter) handleUnknownCommand(cmd string) {
-	fmt.Fprintf(os.Stderr, "エラー: 不明なコマンド '%s'\n", cmd)
-	printUsage()
-	os.Exit(utils.ExitGeneralError)
+	var flag *pflag.Flag
+	switch {
+	case strings.HasPrefix(arg, "--"):
+		name := arg[2:]
+		if flag = cmd.Flags().Lookup(name); flag == nil {
+			flag = cmd.InheritedFlags().Lookup(name)
+		}
+	case len(arg) == 2:
+		if flag = cmd.Flags().ShorthandLookup(arg[1:]); flag == nil {
+			flag = cmd.InheritedFlags().ShorthandLookup(arg[1:])
+		}
+	}
+	return flag != nil && flag.NoOptDefVal == ""
 }
 
-func printUsage() {
-	fmt.Printf(`mcpconfig - MCP設定ファイル管理ツール
-
-使用方法:
-  mcpconfig <コマンド> [オプション] [引数]
-
-コマンド:
-  apply [プロファイル名] --to <パス>         プロファイルを指定パスに適用 (デフォルト: %s)
-  save [プロファイル名] --from <パス>        現在の設定をプロファイルとして保存 (デフォルト: %s)
-  create [プロファイル名]                    新規プロファイルを作成 (デフォルト: %s)
-  list [--detail]                           プロファイル一覧を表示
-  delete [プロファイル名]                    プロファイルを削除 (デフォルト: %s)
-  rename [現在の名前] <新しい名前>           プロファイル名を変更 (デフォルト: %s)
-  copy [コピー元] <コピー先>                 プロファイルをコピー (デフォルト: %s)
-  merge <合成先> <ソース1> [ソース2]...      複数のプロファイルを合成
-  path [プロファイル名]                      プロファイルファイルのパスを表示 (デフォルト: %s)
-  detail <プロファイル名>                    プロファイルの詳細を表示
-  server <サブコマンド>                      MCPサーバー管理
-  group <サブコマンド>                       サーバーグループ管理
-  reset <サブコマンド>                       開発用設定のリセット
-
-注意: []で囲まれた引数は省略可能で、省略時はデフォルトプロファイル名 '%s' が使用されます
-
-グローバルオプション:
-  --help, -h      ヘルプを表示
-  --version, -v   バージョンを表示
-
-詳細は 'mcpconfig help <コマンド>' で確認してください`,
-		config.DefaultProfileName,
-		config.DefaultProfileName,
-		config.DefaultProfileName,
-		config.DefaultProfileName,
-		config.DefaultProfileName,
-		config.DefaultProfileName,
-		config.DefaultProfileName,
-		config.DefaultProfileName)
+// NewRootCommand returns the whole command tree. Help texts are looked up
+// while it is built, so the locale has to be selected first.
+func NewRootCommand() *cobra.Command {
+	root := cmdutil.NewGroup("mcpjson", i18n.T("help.root.short"))
+	root.Long = i18n.T("help.root.long", config.DefaultProfileName)
+	root.Version = Version
+	root.SilenceErrors = true
+	root.SilenceUsage = true
+	root.CompletionOptions.DisableDefaultCmd = true
+	root.SetVersionTemplate("mcpconfig version {{.Version}}\n")
+	root.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return utils.ArgumentError(i18n.Errorf("cmd.invalid_flag", err, cmd.CommandPath()))
+	})
+
+	root.PersistentFlags().String("lang", "", i18n.T("help.flag.lang"))
+	root.PersistentFlags().String("store", "", i18n.T("help.flag.store"))
+	_ = root.RegisterFlagCompletionFunc("store", cmdutil.CompleteStores)
+	root.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		store, _ := cmd.Flags().GetString("store")
+		config.UseStore(store)
+	}
+	_ = root.RegisterFlagCompletionFunc("lang", cmdutil.CompleteValues(string(i18n.Japanese), string(i18n.English)))
+	root.PersistentFlags().BoolP("help", "h", false, i18n.T("help.flag.help"))
+	root.Flags().BoolP("version", "v", false, i18n.T("help.flag.version"))
+
+	root.AddCommand(
+		apply.NewCommand(),
+		save.NewCommand(),
+		create.NewCommand(),
+		list.NewCommand(),
+		delete.NewCommand(),
+		rename.NewCommand(),
+		copy.NewCommand(),
+		merge.NewCommand(),
+		path.NewCommand(),
+		detail.NewCommand(),
+		diff.NewCommand(),
+		doctor.NewCommand(),
+		mcpserve.NewCommand(),
+		proxy.NewCommand(),
+		server.NewCommand(),
+		group.NewCommand(),
+		secret.NewCommand(),
+		bundle.NewExportCommand(),
+		bundle.NewImportCommand(),
+		sync.NewCommand(),
+		history.NewCommand(),
+		history.NewUndoCommand(),
+		reset.NewCommand(),
+		completion.NewCommand(),
+		newVersionCommand(),
+	)
+	root.SetHelpCommand(newHelpCommand())
+
+	cobra.AddTemplateFunc("isGroup", cmdutil.IsGroup)
+	root.SetUsageTemplate(usageTemplate())
+	return root
 }
 
-func (r *CommandRouter) handleServer(args []string) {
-	if len(args) == 0 {
-		server.PrintUsage()
-		os.Exit(0)
+func newVersionCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "version",
+		Short: i18n.T("help.version.short"),
+		Args:  cmdutil.MaximumArgs(0),
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Fprintf(cmd.OutOrStdout(), "mcpconfig version %s\n", Version)
+		},
 	}
+}
 
-	cfg, err := config.New()
-	if err != nil {
-		fmt.Fprintln(os.Stderr, "エラー:", err)
-		os.Exit(utils.ExitEnvironment)
+// newHelpCommand replaces cobra's help command so that its description
+// follows the selected language
+func newHelpCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "help [command]",
+		Short: i18n.T("help.help.short"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target, rest, err := cmd.Root().Find(args)
+			if err != nil {
+				return err
+			}
+			if len(rest) > 0 {
+				return cmdutil.UnknownCommand(target, rest[0])
+			}
+			return target.Help()
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+			target, _, err := cmd.Root().Find(args)
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			var names []cobra.Completion
+			for _, sub := range target.Commands() {
+				if sub.IsAvailableCommand() && strings.HasPrefix(sub.Name(), toComplete) {
+					names = append(names, cobra.CompletionWithDesc(sub.Name(), sub.Short))
+				}
+			}
+			return names, cobra.ShellCompDirectiveNoFileComp
+		},
 	}
-
-	server.Execute(cfg, args)
 }
 
-func (r *CommandRouter) handleGroup(args []string) {
-	if len(args) == 0 {
-		group.PrintUsage()
-		os.Exit(0)
-	}
+// usageTemplate is cobra's usage template with the headings taken from
+// the message catalog. Commands that only hold subcommands do not show a
+// usage line of their own.
+func usageTemplate() string {
+	return i18n.T("help.heading.usage") + `{{if and .Runnable (not (isGroup .))}}
+  {{.UseLine}}{{end}}{{if .HasAvailableSubCommands}}
+  {{.CommandPath}} [command]{{end}}{{if .HasExample}}
 
-	cfg, err := config.New()
-	if err != nil {
-		fmt.Fprintln(os.Stderr, "エラー:", err)
-		os.Exit(utils.ExitEnvironment)
-	}
+` + i18n.T("help.heading.examples") + `
+{{.Example}}{{end}}{{if .HasAvailableSubCommands}}
 
-	group.Execute(cfg, args)
-}
+` + i18n.T("help.heading.commands") + `{{range .Commands}}{{if (or .IsAvailableCommand (eq .Name "help"))}}
+  {{rpad .Name .NamePadding }} {{.Short}}{{end}}{{end}}{{end}}{{if .HasAvailableLocalFlags}}
 
-func (r *CommandRouter) handleReset(args []string) {
-	if len(args) == 0 {
-		reset.PrintUsage()
-		os.Exit(0)
-	}
+` + i18n.T("help.heading.flags") + `
+{{.LocalFlags.FlagUsages | trimTrailingWhitespaces}}{{end}}{{if .HasAvailableInheritedFlags}}
 
-	cfg, err := config.New()
-	if err != nil {
-		fmt.Fprintln(os.Stderr, "エラー:", err)
-		os.Exit(utils.ExitEnvironment)
-	}
+` + i18n.T("help.heading.global_flags") + `
+{{.InheritedFlags.FlagUsages | trimTrailingWhitespaces}}{{end}}{{if .HasAvailableSubCommands}}
 
-	reset.Execute(cfg, args)
+` + i18n.T("help.more", "{{.CommandPath}}") + `{{end}}
+`
 }
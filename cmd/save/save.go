@@ -4,56 +4,78 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/naoto24kawa/mcpjson/cmd/cmdutil"
 	"github.com/naoto24kawa/mcpjson/cmd/profile"
 	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/server"
 	"github.com/naoto24kawa/mcpjson/internal/utils"
+	"github.com/spf13/cobra"
 )
 
-// findMCPConfigFile searches for MCP configuration file in default locations
-func findMCPConfigFile() (string, error) {
-	localPath := config.GetDefaultMCPPath()
-	if _, err := os.Stat(localPath); err == nil {
-		return localPath, nil
+// findMCPConfigFile searches for MCP configuration file in the default
+// targets, then in the well-known locations
+func findMCPConfigFile(cfg *config.Config) (string, error) {
+	for _, path := range cfg.DefaultTargets() {
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
 	}
 
 	foundPath := config.FindMCPConfigPath()
 	if foundPath == "" {
-		return "", fmt.Errorf("MCP設定ファイルが見つかりません\n使用方法: mcpconfig save [プロファイル名] --from <パス>")
+		return "", i18n.Errorf("save.no_mcp_file")
 	}
 
-	fmt.Printf("MCP設定ファイルを自動検出しました: %s\n", foundPath)
+	fmt.Println(i18n.T("save.detected", foundPath))
 	return foundPath, nil
 }
 
-func Execute(args []string) {
-	profileName, argsOffset := utils.ParseProfileName(args, config.DefaultProfileName)
-	var fromPath string
-	force := false
-
-	for i := argsOffset; i < len(args); i++ {
-		switch args[i] {
-		case "--from", "-f":
-			var err error
-			fromPath, i, err = utils.ParseFlag(args, i, "--from")
-			utils.HandleArgumentError(err)
-		case "--force", "-F":
-			force = true
-		}
-	}
+// NewCommand returns the save command
+func NewCommand() *cobra.Command {
+	var fromPath, clientName string
+	var force bool
 
-	if fromPath == "" {
-		var err error
-		fromPath, err = findMCPConfigFile()
-		if err != nil {
-			fmt.Fprintln(os.Stderr, "エラー:", err)
-			os.Exit(utils.ExitArgumentError)
-		}
-	}
-
-	utils.HandleArgumentError(utils.ValidateName(profileName, "プロファイル"))
+	cmd := &cobra.Command{
+		Use:               "save [profile]",
+		Short:             i18n.T("help.save.short"),
+		Long:              i18n.T("help.save.long", config.DefaultProfileName),
+		Args:              cmdutil.MaximumArgs(1),
+		ValidArgsFunction: cmdutil.CompleteProfiles(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := cmdutil.LoadConfig()
+			if err != nil {
+				return err
+			}
+			profileName, err := cmdutil.ProfileArg(cfg, args)
+			if err != nil {
+				return err
+			}
+			var client server.ClientAdapter
+			if clientName != "" {
+				if client, err = server.LookupClient(clientName); err != nil {
+					return utils.ArgumentError(err)
+				}
+			}
 
-	cfg, err := config.New()
-	utils.HandleEnvironmentError(err)
+			switch {
+			case fromPath != "":
+			case client != nil:
+				if fromPath, err = client.DefaultPath(); err != nil {
+					return utils.EnvironmentError(err)
+				}
+			default:
+				if fromPath, err = findMCPConfigFile(cfg); err != nil {
+					return utils.ArgumentError(err)
+				}
+			}
+			return profile.Save(cfg, profileName, fromPath, client, force)
+		},
+	}
 
-	utils.HandleGeneralError(profile.Save(cfg, profileName, fromPath, force))
+	cmd.Flags().StringVarP(&fromPath, "from", "f", "", i18n.T("help.save.from"))
+	cmd.Flags().StringVar(&clientName, "from-client", "", i18n.T("help.save.from_client"))
+	cmdutil.AddForceFlag(cmd, &force, "F")
+	_ = cmd.RegisterFlagCompletionFunc("from-client", cmdutil.CompleteValues(server.ClientNames()...))
+	return cmd
 }
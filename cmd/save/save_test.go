@@ -4,37 +4,19 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/profile"
 	"github.com/naoto24kawa/mcpjson/internal/server"
+	"github.com/naoto24kawa/mcpjson/internal/testutil"
 )
 
 func setupTestEnvironment(t *testing.T) (string, func()) {
 	t.Helper()
-
-	tempDir, err := os.MkdirTemp("", "save_test")
-	if err != nil {
-		t.Fatalf("一時ディレクトリの作成に失敗: %v", err)
-	}
-
-	origXDGConfigHome := os.Getenv("XDG_CONFIG_HOME")
-	os.Setenv("XDG_CONFIG_HOME", tempDir)
-
-	cleanup := func() {
-		os.Setenv("XDG_CONFIG_HOME", origXDGConfigHome)
-		os.RemoveAll(tempDir)
-	}
-
-	cfg, err := config.New()
-	if err != nil {
-		cleanup()
-		t.Fatalf("設定の作成に失敗: %v", err)
-	}
-
-	_ = os.MkdirAll(cfg.ProfilesDir, 0755)
-	_ = os.MkdirAll(cfg.ServersDir, 0755)
-
+	tempDir, _, cleanup := testutil.SetupIsolatedTestEnvironment(t)
 	return tempDir, cleanup
 }
 
@@ -114,7 +96,50 @@ func TestExecute(t *testing.T) {
 				}
 			}()
 
-			Execute(tt.args(tempDir))
+			cmd := NewCommand()
+			cmd.SetArgs(tt.args(tempDir))
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("コマンドの実行に失敗: %v", err)
+			}
 		})
 	}
 }
+
+func TestExecute_FromClient(t *testing.T) {
+	tempDir, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	vscodeConfig := `{
+  "servers": {
+    "docs": {"type": "http", "url": "https://example.com/mcp"},
+    "git": {"type": "stdio", "command": "uvx", "args": ["mcp-server-git"]}
+  }
+}`
+	mcpPath := filepath.Join(tempDir, "mcp.json")
+	if err := os.WriteFile(mcpPath, []byte(vscodeConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := NewCommand()
+	cmd.SetArgs([]string{"vscode", "--from", mcpPath, "--from-client", "vscode"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("コマンドの実行に失敗: %v", err)
+	}
+
+	cfg, err := config.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved, err := profile.NewManager(cfg.ProfilesDir).Load("vscode")
+	if err != nil {
+		t.Fatalf("プロファイルの読み込みに失敗: %v", err)
+	}
+	names := []string{}
+	for _, ref := range saved.Servers {
+		names = append(names, ref.Name)
+	}
+	sort.Strings(names)
+	if strings.Join(names, ",") != "docs,git" {
+		t.Errorf("saved servers = %v, want docs and git", names)
+	}
+}
@@ -0,0 +1,193 @@
+package secret
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/naoto24kawa/mcpjson/cmd/cmdutil"
+	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/history"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/interaction"
+	"github.com/naoto24kawa/mcpjson/internal/secret"
+	"github.com/spf13/cobra"
+)
+
+// NewCommand returns the secret command with its subcommands
+func NewCommand() *cobra.Command {
+	cmd := cmdutil.NewGroup("secret", i18n.T("help.secret.short"))
+	cmd.Long = i18n.T("help.secret.long")
+
+	var force bool
+	rm := &cobra.Command{
+		Use:   "rm <name>",
+		Short: i18n.T("help.secret_rm.short"),
+		Args:  cmdutil.ExactArgs(1, i18n.Error("secret.cmd_no_name")),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := cmdutil.LoadConfig()
+			if err != nil {
+				return err
+			}
+			return Remove(cfg, args[0], force)
+		},
+	}
+	cmdutil.AddForceFlag(rm, &force, "f")
+
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "set <name> [value]",
+			Short: i18n.T("help.secret_set.short"),
+			Long:  i18n.T("help.secret_set.long"),
+			Args:  cmdutil.RangeArgs(1, 2, i18n.Error("secret.cmd_no_name")),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				cfg, err := cmdutil.LoadConfig()
+				if err != nil {
+					return err
+				}
+				return Set(cfg, args, os.Stdin)
+			},
+		},
+		&cobra.Command{
+			Use:   "get <name>",
+			Short: i18n.T("help.secret_get.short"),
+			Args:  cmdutil.ExactArgs(1, i18n.Error("secret.cmd_no_name")),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				cfg, err := cmdutil.LoadConfig()
+				if err != nil {
+					return err
+				}
+				return Get(cfg, args[0])
+			},
+		},
+		&cobra.Command{
+			Use:   "list",
+			Short: i18n.T("help.secret_list.short"),
+			Args:  cmdutil.MaximumArgs(0),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				cfg, err := cmdutil.LoadConfig()
+				if err != nil {
+					return err
+				}
+				return List(cfg)
+			},
+		},
+		rm,
+	)
+	return cmd
+}
+
+// Set stores a secret. The value is read from stdin unless it is given
+// as the second argument, so it does not have to appear in shell history.
+func Set(cfg *config.Config, args []string, stdin io.Reader) error {
+	name := args[0]
+	if err := secret.ValidateName(name); err != nil {
+		return err
+	}
+
+	store, err := secret.OpenStore(cfg)
+	if err != nil {
+		return err
+	}
+
+	var value string
+	if len(args) > 1 {
+		value = args[1]
+	} else {
+		if interaction.IsInteractive() {
+			fmt.Print(i18n.T("secret.prompt", name))
+		}
+		if value, err = readValue(stdin); err != nil {
+			return err
+		}
+	}
+	if value == "" {
+		return i18n.Errorf("secret.empty_value")
+	}
+
+	return history.Run(cfg, "secret set "+name, []string{vaultPath(cfg)}, func() error {
+		if err := store.Set(name, value); err != nil {
+			return err
+		}
+		fmt.Println(i18n.T("secret.saved", name))
+		return nil
+	})
+}
+
+// Get prints the value of a secret
+func Get(cfg *config.Config, name string) error {
+	provider, err := secret.Open(cfg)
+	if err != nil {
+		return err
+	}
+
+	value, err := provider.Get(name)
+	if err != nil {
+		return i18n.Errorf("secret.get_failed", name, err)
+	}
+	fmt.Println(value)
+	return nil
+}
+
+// List prints the names of the stored secrets
+func List(cfg *config.Config) error {
+	store, err := secret.OpenStore(cfg)
+	if err != nil {
+		return err
+	}
+
+	names, err := store.List()
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		fmt.Println(i18n.T("secret.none"))
+		return nil
+	}
+	for _, name := range names {
+		fmt.Println(name)
+	}
+	return nil
+}
+
+// Remove deletes a secret, asking for confirmation unless force is set
+func Remove(cfg *config.Config, name string, force bool) error {
+	store, err := secret.OpenStore(cfg)
+	if err != nil {
+		return err
+	}
+
+	if !force && !interaction.Confirm(i18n.T("secret.confirm_delete", name)) {
+		fmt.Println(i18n.T("common.delete_cancelled"))
+		return nil
+	}
+
+	return history.Run(cfg, "secret rm "+name, []string{vaultPath(cfg)}, func() error {
+		deleted, err := store.Delete(name)
+		if err != nil {
+			return err
+		}
+		if !deleted {
+			return i18n.Errorf("secret.named_not_found", name)
+		}
+		fmt.Println(i18n.T("secret.deleted", name))
+		return nil
+	})
+}
+
+// vaultPath is the file the vault keeps the encrypted secrets in. The
+// keyfile is left out of the journal so that undo never removes it.
+func vaultPath(cfg *config.Config) string {
+	return filepath.Join(cfg.SecretsDir(), secret.VaultFile)
+}
+
+func readValue(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", i18n.Errorf("secret.read_value_failed", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -0,0 +1,126 @@
+package secret
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/history"
+)
+
+func captureStdout(fn func()) string {
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	fn()
+
+	w.Close()
+	os.Stdout = old
+
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	return buf.String()
+}
+
+func TestSecretCommands(t *testing.T) {
+	cfg := &config.Config{BaseDir: t.TempDir()}
+
+	var err error
+	captureStdout(func() {
+		err = Set(cfg, []string{"github-token"}, strings.NewReader("ghp_stdin\n"))
+	})
+	if err != nil {
+		t.Fatalf("Set() from stdin failed: %v", err)
+	}
+	captureStdout(func() {
+		err = Set(cfg, []string{"openai-key", "sk-arg"}, strings.NewReader(""))
+	})
+	if err != nil {
+		t.Fatalf("Set() with value failed: %v", err)
+	}
+
+	out := captureStdout(func() { err = Get(cfg, "github-token") })
+	if err != nil || out != "ghp_stdin\n" {
+		t.Errorf("Get() = %q, %v", out, err)
+	}
+
+	out = captureStdout(func() { err = List(cfg) })
+	if err != nil || out != "github-token\nopenai-key\n" {
+		t.Errorf("List() = %q, %v", out, err)
+	}
+
+	captureStdout(func() { err = Remove(cfg, "openai-key", true) })
+	if err != nil {
+		t.Fatalf("Remove() failed: %v", err)
+	}
+	captureStdout(func() { err = Remove(cfg, "openai-key", true) })
+	if err == nil {
+		t.Error("Remove() of a missing secret expected error")
+	}
+}
+
+func TestSet_EmptyValue(t *testing.T) {
+	cfg := &config.Config{BaseDir: t.TempDir()}
+
+	var err error
+	captureStdout(func() {
+		err = Set(cfg, []string{"token"}, strings.NewReader(""))
+	})
+	if err == nil || !strings.Contains(err.Error(), "値が空") {
+		t.Errorf("Set() error = %v, want empty value error", err)
+	}
+}
+
+func TestSet_ReadOnlyProvider(t *testing.T) {
+	cfg := &config.Config{BaseDir: t.TempDir()}
+	settings := `{"secrets": {"provider": "exec", "command": ["pass", "show"]}}`
+	if err := os.WriteFile(cfg.SettingsPath(), []byte(settings), 0644); err != nil {
+		t.Fatalf("failed to write settings: %v", err)
+	}
+
+	if err := Set(cfg, []string{"token", "value"}, strings.NewReader("")); err == nil {
+		t.Error("Set() with the exec provider expected error")
+	}
+}
+
+func TestSecretCommands_Undo(t *testing.T) {
+	cfg := &config.Config{BaseDir: t.TempDir()}
+
+	var err error
+	captureStdout(func() { err = Set(cfg, []string{"token", "first"}, strings.NewReader("")) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	captureStdout(func() { err = Set(cfg, []string{"token", "second"}, strings.NewReader("")) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	captureStdout(func() { err = Remove(cfg, "token", true) })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	journal, err := history.Open(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := journal.List()
+	if err != nil || len(entries) != 3 {
+		t.Fatalf("List() = %d entries, %v, want 3", len(entries), err)
+	}
+
+	// rm と2回目の set を取り消すと最初の値に戻ること
+	for i := 0; i < 2; i++ {
+		if _, err := journal.Undo(0, false); err != nil {
+			t.Fatalf("Undo() failed: %v", err)
+		}
+	}
+	out := captureStdout(func() { err = Get(cfg, "token") })
+	if err != nil || out != "first\n" {
+		t.Errorf("Get() after undo = %q, %v, want first", out, err)
+	}
+}
@@ -2,71 +2,244 @@ package add
 
 import (
 	"fmt"
-	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 
+	"github.com/naoto24kawa/mcpjson/cmd/cmdutil"
 	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/history"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/profile"
+	"github.com/naoto24kawa/mcpjson/internal/secret"
 	"github.com/naoto24kawa/mcpjson/internal/server"
 	"github.com/naoto24kawa/mcpjson/internal/utils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
-func Execute(cfg *config.Config, args []string) {
-	if len(args) < 1 {
-		fmt.Fprintln(os.Stderr, "エラー: テンプレート名が指定されていません")
-		os.Exit(utils.ExitArgumentError)
-	}
-
-	templateName := args[0]
-	var mcpConfigPath, serverName, envStr string
+// options holds the parsed command line of `server add`
+type options struct {
+	templateName string
+	target       string
+	serverName   string
+	envStr       string
+	overrides    profile.ServerOverrides
+	update       bool
+	// hasOverrides is set when an option other than --env was given;
+	// those options only apply to profiles
+	hasOverrides bool
+}
 
-	for i := 1; i < len(args); i++ {
-		switch args[i] {
-		case "--to", "-t":
-			if i+1 >= len(args) {
-				fmt.Fprintln(os.Stderr, "エラー: --to オプションに値が指定されていません")
-				os.Exit(utils.ExitArgumentError)
-			}
-			mcpConfigPath = args[i+1]
-			i++
-		case "--as", "-a":
-			if i+1 >= len(args) {
-				fmt.Fprintln(os.Stderr, "エラー: --as オプションに値が指定されていません")
-				os.Exit(utils.ExitArgumentError)
+// NewCommand returns the server add command
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add <template>",
+		Short: i18n.T("help.server_add.short"),
+		Long:  i18n.T("help.server_add.long"),
+		Example: `  mcpjson server add github --to work --as gh
+  mcpjson server add github --to ./.mcp.json --env GITHUB_TOKEN=xxx
+  mcpjson server add fs --to work --args-append /work --timeout 30`,
+		Args:              cmdutil.ExactArgs(1, i18n.Error("server.cmd_no_template")),
+		ValidArgsFunction: cmdutil.CompleteTemplates(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := parseOptions(cmd, args[0])
+			if err != nil {
+				return utils.ArgumentError(err)
 			}
-			serverName = args[i+1]
-			i++
-		case "--env", "-e":
-			if i+1 >= len(args) {
-				fmt.Fprintln(os.Stderr, "エラー: --env オプションに値が指定されていません")
-				os.Exit(utils.ExitArgumentError)
+
+			cfg, err := cmdutil.LoadConfig()
+			if err != nil {
+				return err
 			}
-			envStr = args[i+1]
-			i++
+			return run(cfg, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringP("to", "t", "", i18n.T("help.server_add.to"))
+	flags.StringP("as", "a", "", i18n.T("help.server_add.as"))
+	flags.StringP("env", "e", "", i18n.T("help.server.env"))
+	flags.StringP("command", "c", "", i18n.T("help.server_add.command"))
+	flags.String("args", "", i18n.T("help.server_add.args"))
+	flags.String("args-append", "", i18n.T("help.server_add.args_append"))
+	flags.String("args-prepend", "", i18n.T("help.server_add.args_prepend"))
+	flags.String("timeout", "", i18n.T("help.server_add.timeout"))
+	flags.String("transport", "", i18n.T("help.server_add.transport"))
+	flags.String("env-file", "", i18n.T("help.server_add.env_file"))
+	flags.String("allow-tools", "", i18n.T("help.server_add.allow_tools"))
+	flags.String("deny-tools", "", i18n.T("help.server_add.deny_tools"))
+	flags.Bool("disabled", false, i18n.T("help.server_add.disabled"))
+	flags.Bool("enabled", false, i18n.T("help.server_add.enabled"))
+	flags.BoolP("update", "U", false, i18n.T("help.server_add.update"))
+	cmd.MarkFlagsMutuallyExclusive("disabled", "enabled")
+	_ = cmd.RegisterFlagCompletionFunc("to", cmdutil.CompleteProfilesOrFiles(-1))
+	return cmd
+}
+
+func run(cfg *config.Config, opts *options) error {
+	envOverrides := make(map[string]string)
+	if opts.envStr != "" {
+		parsedEnv, err := utils.ParseEnvVars(opts.envStr)
+		if err != nil {
+			return utils.ArgumentError(err)
 		}
+		envOverrides = parsedEnv
+	}
+
+	serverManager := server.NewManager(cfg.ServersDir)
+	serverManager.UseConfig(cfg)
+
+	profileName, ok, err := profileTarget(cfg, opts.target)
+	if err != nil {
+		return utils.ArgumentError(err)
+	}
+	if ok {
+		opts.overrides.Env = envOverrides
+		return addToProfile(cfg, serverManager, profileName, opts)
+	}
+
+	if opts.hasOverrides || opts.update {
+		return utils.ArgumentError(i18n.Errorf("server.overrides_profile_only"))
 	}
 
 	// --to が未指定の場合、デフォルトで ./.mcp.json を使用
+	mcpConfigPath := opts.target
 	if mcpConfigPath == "" {
 		mcpConfigPath = "./.mcp.json"
 	}
 
-	if err := utils.ValidateName(templateName, "サーバーテンプレート"); err != nil {
-		fmt.Fprintln(os.Stderr, "エラー:", err)
-		os.Exit(utils.ExitArgumentError)
+	provider, err := secret.Open(cfg)
+	if err != nil {
+		return err
 	}
+	serverManager.SetSecretProvider(provider)
 
-	envOverrides := make(map[string]string)
-	if envStr != "" {
-		parsedEnv, err := utils.ParseEnvVars(envStr)
-		if err != nil {
-			fmt.Fprintln(os.Stderr, "エラー:", err)
-			os.Exit(utils.ExitArgumentError)
+	operation := fmt.Sprintf("server add %s --to %s", opts.templateName, mcpConfigPath)
+	return history.Run(cfg, operation, []string{mcpConfigPath}, func() error {
+		return serverManager.AddToMCPConfig(mcpConfigPath, opts.templateName, opts.serverName, envOverrides)
+	})
+}
+
+// parseOptions reads the flags of cmd. Only the override flags that were
+// given on the command line end up in the overrides.
+func parseOptions(cmd *cobra.Command, templateName string) (*options, error) {
+	if err := utils.ValidateName(templateName, i18n.T("kind.template")); err != nil {
+		return nil, err
+	}
+
+	flags := cmd.Flags()
+	opts := &options{templateName: templateName}
+	opts.target, _ = flags.GetString("to")
+	opts.serverName, _ = flags.GetString("as")
+	opts.envStr, _ = flags.GetString("env")
+	opts.update, _ = flags.GetBool("update")
+
+	flags.Visit(func(flag *pflag.Flag) {
+		value := flag.Value.String()
+		switch flag.Name {
+		case "command":
+			opts.overrides.Command = value
+		case "args":
+			opts.overrides.Args = nonNil(utils.ParseArgs(value))
+		case "args-append":
+			opts.overrides.ArgsAppend = nonNil(utils.ParseArgs(value))
+		case "args-prepend":
+			opts.overrides.ArgsPrepend = nonNil(utils.ParseArgs(value))
+		case "timeout":
+			if timeout, err := strconv.Atoi(value); err == nil && timeout > 0 {
+				opts.overrides.Timeout = &timeout
+			}
+		case "transport":
+			opts.overrides.TransportType = &value
+		case "env-file":
+			opts.overrides.EnvFile = &value
+		case "disabled", "enabled":
+			enabled := (flag.Name == "enabled") == (value == "true")
+			opts.overrides.Enabled = &enabled
+		case "allow-tools", "deny-tools":
+			if opts.overrides.Tools == nil {
+				opts.overrides.Tools = &profile.ToolFilter{}
+			}
+			if flag.Name == "allow-tools" {
+				opts.overrides.Tools.Allow = splitList(value)
+			} else {
+				opts.overrides.Tools.Deny = splitList(value)
+			}
+		default:
+			return
 		}
-		envOverrides = parsedEnv
+		opts.hasOverrides = true
+	})
+
+	if timeout := flags.Lookup("timeout"); timeout.Changed && opts.overrides.Timeout == nil {
+		return nil, i18n.Errorf("server.invalid_timeout", timeout.Value.String())
+	}
+	if err := opts.overrides.Tools.Validate(); err != nil {
+		return nil, err
 	}
+	return opts, nil
+}
 
-	serverManager := server.NewManager(cfg.ServersDir)
-	if err := serverManager.AddToMCPConfig(mcpConfigPath, templateName, serverName, envOverrides); err != nil {
-		fmt.Fprintln(os.Stderr, "エラー:", err)
-		os.Exit(utils.ExitGeneralError)
+// profileTarget reports whether the --to value names a profile of any
+// store rather than an MCP config file path. A bare name that is neither is an
+// error, so that a mistyped profile name does not create a file.
+func profileTarget(cfg *config.Config, target string) (string, bool, error) {
+	if target == "" || isPathLike(target) {
+		return "", false, nil
+	}
+	if utils.ValidateName(target, i18n.T("kind.profile")) == nil &&
+		utils.FileExists(config.LookupPath(cfg.ProfileDirs(), cfg.ProfilesDir, target)) {
+		return target, true, nil
+	}
+	return "", false, i18n.Errorf("server.target_ambiguous", target)
+}
+
+// isPathLike reports whether target is written as a file path
+func isPathLike(target string) bool {
+	if strings.ContainsAny(target, `/\`) || strings.HasPrefix(target, ".") || strings.HasPrefix(target, "~") {
+		return true
+	}
+	switch strings.ToLower(filepath.Ext(target)) {
+	case ".json", ".jsonc":
+		return true
+	}
+	return false
+}
+
+func addToProfile(cfg *config.Config, serverManager *server.Manager, profileName string, opts *options) error {
+	exists, err := serverManager.Exists(opts.templateName)
+	if err != nil {
+		return i18n.Errorf("group.server_check_failed", err)
+	}
+	if !exists {
+		return i18n.Errorf("common.template_not_found", opts.templateName)
+	}
+
+	profileManager := profile.NewManager(cfg.ProfilesDir)
+	profileManager.UseConfig(cfg)
+	operation := fmt.Sprintf("server add %s --to %s", opts.templateName, profileName)
+	return history.Run(cfg, operation, []string{cfg.ProfilesDir}, func() error {
+		return profileManager.AddServerWithOverrides(profileName, opts.templateName, opts.serverName, opts.overrides, opts.update)
+	})
+}
+
+// splitList splits a comma-separated list. An empty value gives an empty,
+// non-nil list so that it clears the setting.
+func splitList(value string) []string {
+	list := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// nonNil keeps an explicitly empty argument list distinct from "not set"
+func nonNil(args []string) []string {
+	if args == nil {
+		return []string{}
 	}
+	return args
 }
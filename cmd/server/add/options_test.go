@@ -0,0 +1,196 @@
+package add
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/profile"
+	"github.com/naoto24kawa/mcpjson/internal/server"
+)
+
+func TestParseOptions(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		wantErr      bool
+		validateOpts func(*testing.T, *options)
+	}{
+		{
+			name: "ファイルへの追加",
+			args: []string{"git", "--to", "./.mcp.json", "--as", "my-git", "--env", "A=1"},
+			validateOpts: func(t *testing.T, opts *options) {
+				if opts.target != "./.mcp.json" || opts.serverName != "my-git" || opts.envStr != "A=1" {
+					t.Errorf("unexpected options: %+v", opts)
+				}
+				if opts.hasOverrides {
+					t.Error("hasOverrides should be false without override options")
+				}
+			},
+		},
+		{
+			name: "上書きオプション",
+			args: []string{"fs", "--to", "work", "--command", "bunx", "--args", "-y,pkg",
+				"--args-append", "/work", "--args-prepend", "--inspect", "--timeout", "90",
+				"--transport", "stdio", "--env-file", ".env", "--disabled", "--update"},
+			validateOpts: func(t *testing.T, opts *options) {
+				o := opts.overrides
+				if o.Command != "bunx" || !reflect.DeepEqual(o.Args, []string{"-y", "pkg"}) {
+					t.Errorf("command/args = %q %v", o.Command, o.Args)
+				}
+				if !reflect.DeepEqual(o.ArgsAppend, []string{"/work"}) || !reflect.DeepEqual(o.ArgsPrepend, []string{"--inspect"}) {
+					t.Errorf("argsAppend/argsPrepend = %v %v", o.ArgsAppend, o.ArgsPrepend)
+				}
+				if o.Timeout == nil || *o.Timeout != 90 || *o.TransportType != "stdio" || *o.EnvFile != ".env" {
+					t.Errorf("timeout/transport/envFile not parsed: %+v", o)
+				}
+				if o.Enabled == nil || *o.Enabled || !opts.update || !opts.hasOverrides {
+					t.Errorf("flags not parsed: %+v", opts)
+				}
+			},
+		},
+		{
+			name: "ツールの絞り込み",
+			args: []string{"github", "--to", "work", "--allow-tools", "search_*, get_issue", "--deny-tools", ""},
+			validateOpts: func(t *testing.T, opts *options) {
+				want := &profile.ToolFilter{Allow: []string{"search_*", "get_issue"}, Deny: []string{}}
+				if !reflect.DeepEqual(opts.overrides.Tools, want) || !opts.hasOverrides {
+					t.Errorf("tools = %+v, want %+v", opts.overrides.Tools, want)
+				}
+			},
+		},
+		{
+			name:    "不正なツールのパターン",
+			args:    []string{"github", "--to", "work", "--deny-tools", "[delete"},
+			wantErr: true,
+		},
+		{
+			name:    "不正なタイムアウト",
+			args:    []string{"fs", "--timeout", "abc"},
+			wantErr: true,
+		},
+		{
+			name:    "値のないオプション",
+			args:    []string{"fs", "--args-append"},
+			wantErr: true,
+		},
+		{
+			name:    "不明なオプション",
+			args:    []string{"fs", "--unknown"},
+			wantErr: true,
+		},
+		{
+			name: "--flag=value 形式",
+			args: []string{"fs", "--to=work", "--timeout=30", "--enabled"},
+			validateOpts: func(t *testing.T, opts *options) {
+				o := opts.overrides
+				if opts.target != "work" || o.Timeout == nil || *o.Timeout != 30 || o.Enabled == nil || !*o.Enabled {
+					t.Errorf("unexpected options: %+v", opts)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := parseCommandLine(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.validateOpts != nil {
+				tt.validateOpts(t, opts)
+			}
+		})
+	}
+}
+
+// parseCommandLine parses args with the flags of the server add command
+func parseCommandLine(args []string) (*options, error) {
+	cmd := NewCommand()
+	if err := cmd.ParseFlags(args); err != nil {
+		return nil, err
+	}
+	return parseOptions(cmd, cmd.Flags().Arg(0))
+}
+
+func TestProfileTarget(t *testing.T) {
+	cfg, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	if err := profile.NewManager(cfg.ProfilesDir).Create("work", ""); err != nil {
+		t.Fatalf("Failed to create profile: %v", err)
+	}
+	// team はチームのストアにだけある
+	team := config.Store{Name: "team", Dir: t.TempDir()}
+	cfg.Stores = []config.Store{team, {Name: config.StorePersonal, Dir: filepath.Dir(cfg.ProfilesDir)}}
+	if err := os.MkdirAll(team.ProfilesDir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := profile.NewManager(team.ProfilesDir()).Create("team", ""); err != nil {
+		t.Fatalf("Failed to create profile: %v", err)
+	}
+
+	tests := []struct {
+		target  string
+		want    bool
+		wantErr bool
+	}{
+		{"work", true, false},
+		{"missing", false, true},
+		{"not a name", false, true},
+		{"", false, false},
+		{"./work", false, false},
+		{"work.json", false, false},
+		{".mcp.json", false, false},
+		{filepath.Join(cfg.ProfilesDir, "work"), false, false},
+		{"team", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			_, got, err := profileTarget(cfg, tt.target)
+			if got != tt.want || (err != nil) != tt.wantErr {
+				t.Errorf("profileTarget(%q) = %v, %v, want %v (error: %v)", tt.target, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAddToProfile(t *testing.T) {
+	cfg, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	templateName := createTestTemplate(t, cfg, "fs")
+	if err := profile.NewManager(cfg.ProfilesDir).Create("work", ""); err != nil {
+		t.Fatalf("Failed to create profile: %v", err)
+	}
+
+	opts, err := parseCommandLine([]string{templateName, "--to", "work", "--args-append", "/work"})
+	if err != nil {
+		t.Fatalf("parseOptions() failed: %v", err)
+	}
+
+	serverManager := server.NewManager(cfg.ServersDir)
+	if err := addToProfile(cfg, serverManager, "work", opts); err != nil {
+		t.Fatalf("addToProfile() failed: %v", err)
+	}
+
+	p, err := profile.NewManager(cfg.ProfilesDir).Load("work")
+	if err != nil {
+		t.Fatalf("Failed to load profile: %v", err)
+	}
+	if len(p.Servers) != 1 || !reflect.DeepEqual(p.Servers[0].Overrides.ArgsAppend, []string{"/work"}) {
+		t.Errorf("server not added with overrides: %+v", p.Servers)
+	}
+
+	missing := &options{templateName: "missing-template"}
+	if err := addToProfile(cfg, serverManager, "work", missing); err == nil {
+		t.Error("addToProfile() expected error for missing template")
+	}
+
+	if _, err := os.Stat(filepath.Join(cfg.ProfilesDir, "work"+config.FileExtension)); err != nil {
+		t.Errorf("profile file missing: %v", err)
+	}
+}
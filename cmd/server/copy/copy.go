@@ -2,66 +2,43 @@ package copy
 
 import (
 	"fmt"
-	"os"
 
-	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/cmd/cmdutil"
+	"github.com/naoto24kawa/mcpjson/internal/history"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
 	"github.com/naoto24kawa/mcpjson/internal/server"
-	"github.com/naoto24kawa/mcpjson/internal/utils"
+	"github.com/spf13/cobra"
 )
 
-func Execute(cfg *config.Config, args []string) {
-	if len(args) < 2 {
-		fmt.Fprintf(os.Stderr, "エラー: コピー元とコピー先のサーバー名を指定してください\n")
-		printUsage()
-		os.Exit(utils.ExitGeneralError)
+// NewCommand returns the server copy command
+func NewCommand() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:               "copy <source> <destination>",
+		Short:             i18n.T("help.server_copy.short"),
+		Long:              i18n.T("help.server_copy.long"),
+		Args:              cmdutil.ExactArgs(2, i18n.Error("server.copy_usage")),
+		ValidArgsFunction: cmdutil.CompleteTemplates(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			srcName, destName := args[0], args[1]
+			if err := cmdutil.ValidateNames(i18n.T("kind.template"), srcName, destName); err != nil {
+				return err
+			}
+
+			cfg, err := cmdutil.LoadConfig()
+			if err != nil {
+				return err
+			}
+
+			serverManager := server.NewManager(cfg.ServersDir)
+			serverManager.UseConfig(cfg)
+			return history.Run(cfg, fmt.Sprintf("server copy %s %s", srcName, destName), []string{cfg.ServersDir}, func() error {
+				return serverManager.Copy(srcName, destName, force)
+			})
+		},
 	}
 
-	srcName := args[0]
-	destName := args[1]
-	force := false
-
-	// オプション解析
-	for i := 2; i < len(args); i++ {
-		switch args[i] {
-		case "--force", "-f":
-			force = true
-		default:
-			fmt.Fprintf(os.Stderr, "エラー: 不明なオプション '%s'\n", args[i])
-			printUsage()
-			os.Exit(utils.ExitGeneralError)
-		}
-	}
-
-	if err := utils.ValidateName(srcName, "サーバーテンプレート"); err != nil {
-		fmt.Fprintln(os.Stderr, "エラー:", err)
-		os.Exit(utils.ExitArgumentError)
-	}
-
-	if err := utils.ValidateName(destName, "サーバーテンプレート"); err != nil {
-		fmt.Fprintln(os.Stderr, "エラー:", err)
-		os.Exit(utils.ExitArgumentError)
-	}
-
-	serverManager := server.NewManager(cfg.ServersDir)
-	if err := serverManager.Copy(srcName, destName, force); err != nil {
-		fmt.Fprintf(os.Stderr, "エラー: %v\n", err)
-		os.Exit(utils.ExitGeneralError)
-	}
-}
-
-func printUsage() {
-	fmt.Println(`mcpjson server copy - サーバーテンプレートをコピー
-
-使用方法:
-  mcpjson server copy <コピー元サーバー名> <コピー先サーバー名> [--force]
-
-引数:
-  <コピー元サーバー名>    コピー元のサーバーテンプレート名
-  <コピー先サーバー名>    コピー先のサーバーテンプレート名
-
-オプション:
-  --force, -f           既存サーバーがある場合に確認なしで上書き
-
-説明:
-  既存のサーバーテンプレートを別名でコピーします。元のサーバーテンプレートはそのまま残ります。`)
+	cmdutil.AddForceFlag(cmd, &force, "f")
+	return cmd
 }
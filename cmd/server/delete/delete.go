@@ -1,41 +1,45 @@
 package delete
 
 import (
-	"fmt"
-	"os"
-
-	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/cmd/cmdutil"
+	"github.com/naoto24kawa/mcpjson/internal/history"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
 	"github.com/naoto24kawa/mcpjson/internal/profile"
 	"github.com/naoto24kawa/mcpjson/internal/server"
-	"github.com/naoto24kawa/mcpjson/internal/utils"
+	"github.com/spf13/cobra"
 )
 
-func Execute(cfg *config.Config, args []string) {
-	if len(args) < 1 {
-		fmt.Fprintln(os.Stderr, "エラー: テンプレート名が指定されていません")
-		os.Exit(utils.ExitArgumentError)
-	}
-
-	templateName := args[0]
-	force := false
-
-	for i := 1; i < len(args); i++ {
-		switch args[i] {
-		case "--force", "-f":
-			force = true
-		}
+// NewCommand returns the server delete command
+func NewCommand() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:               "delete <template>",
+		Short:             i18n.T("help.server_delete.short"),
+		Long:              i18n.T("help.server_delete.long"),
+		Args:              cmdutil.ExactArgs(1, i18n.Error("server.cmd_no_template")),
+		ValidArgsFunction: cmdutil.CompleteTemplates(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			templateName := args[0]
+			if err := cmdutil.ValidateNames(i18n.T("kind.template"), templateName); err != nil {
+				return err
+			}
+
+			cfg, err := cmdutil.LoadConfig()
+			if err != nil {
+				return err
+			}
+
+			serverManager := server.NewManager(cfg.ServersDir)
+			profileManager := profile.NewManager(cfg.ProfilesDir)
+
+			paths := []string{cfg.ServersDir, cfg.ProfilesDir}
+			return history.Run(cfg, "server delete "+templateName, paths, func() error {
+				return serverManager.Delete(templateName, force, profileManager)
+			})
+		},
 	}
 
-	if err := utils.ValidateName(templateName, "サーバーテンプレート"); err != nil {
-		fmt.Fprintln(os.Stderr, "エラー:", err)
-		os.Exit(utils.ExitArgumentError)
-	}
-
-	serverManager := server.NewManager(cfg.ServersDir)
-	profileManager := profile.NewManager(cfg.ProfilesDir)
-
-	if err := serverManager.Delete(templateName, force, profileManager); err != nil {
-		fmt.Fprintln(os.Stderr, "エラー:", err)
-		os.Exit(utils.ExitGeneralError)
-	}
+	cmdutil.AddForceFlag(cmd, &force, "f")
+	return cmd
 }
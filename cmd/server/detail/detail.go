@@ -1,46 +1,58 @@
 package detail
 
 import (
-	"encoding/json"
-	"fmt"
 	"os"
-	"path/filepath"
 
+	"github.com/naoto24kawa/mcpjson/cmd/cmdutil"
 	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/output"
 	"github.com/naoto24kawa/mcpjson/internal/server"
 	"github.com/naoto24kawa/mcpjson/internal/utils"
+	"github.com/spf13/cobra"
 )
 
-func Execute(cfg *config.Config, args []string) {
-	if len(args) == 0 {
-		fmt.Fprintf(os.Stderr, "エラー: サーバー名を指定してください\n")
-		fmt.Println("使用方法: mcpconfig server detail <サーバー名>")
-		os.Exit(utils.ExitGeneralError)
+// NewCommand returns the server detail command
+func NewCommand() *cobra.Command {
+	var formatFlag string
+
+	cmd := &cobra.Command{
+		Use:               "detail <template>",
+		Short:             i18n.T("help.server_detail.short"),
+		Args:              cmdutil.ExactArgs(1, i18n.Error("server.detail_no_name")),
+		ValidArgsFunction: cmdutil.CompleteTemplates(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := cmdutil.ParseOutput(formatFlag)
+			if err != nil {
+				return err
+			}
+
+			cfg, err := cmdutil.LoadConfig()
+			if err != nil {
+				return err
+			}
+			return showServerDetail(cfg, args[0], format)
+		},
 	}
 
-	serverName := args[0]
-	if err := showServerDetail(cfg, serverName); err != nil {
-		fmt.Fprintln(os.Stderr, "エラー:", err)
-		os.Exit(utils.ExitGeneralError)
-	}
+	cmdutil.AddOutputFlag(cmd, &formatFlag, output.FormatJSON)
+	return cmd
 }
 
-func showServerDetail(cfg *config.Config, serverName string) error {
-	templatePath := filepath.Join(cfg.ServersDir, serverName+config.FileExtension)
+func showServerDetail(cfg *config.Config, serverName string, format output.Format) error {
+	templatePath := config.LookupPath(cfg.ServerDirs(), cfg.ServersDir, serverName)
 	if _, err := os.Stat(templatePath); os.IsNotExist(err) {
-		return fmt.Errorf("サーバーテンプレート '%s' が見つかりません", serverName)
+		return i18n.Errorf("common.template_not_found", serverName)
 	}
 
 	var targetTemplate server.ServerTemplate
 	if err := utils.LoadJSON(templatePath, &targetTemplate); err != nil {
-		return fmt.Errorf("サーバーテンプレートの読み込みに失敗しました: %v", err)
+		return i18n.Errorf("server.detail_load_failed", err)
 	}
 
-	jsonData, err := json.MarshalIndent(targetTemplate, "", "  ")
-	if err != nil {
-		return fmt.Errorf("JSONの生成に失敗しました: %v", err)
+	if !format.IsStructured() {
+		server.NewTemplateDisplay(cfg.ServersDir).WriteDetail(os.Stdout, &targetTemplate)
+		return nil
 	}
-
-	fmt.Println(string(jsonData))
-	return nil
+	return output.Write(os.Stdout, format, targetTemplate)
 }
@@ -0,0 +1,74 @@
+package inspect
+
+import (
+	"context"
+	"os"
+
+	"github.com/naoto24kawa/mcpjson/cmd/cmdutil"
+	cmdprofile "github.com/naoto24kawa/mcpjson/cmd/profile"
+	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/doctor"
+	"github.com/naoto24kawa/mcpjson/internal/history"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/output"
+	"github.com/naoto24kawa/mcpjson/internal/server"
+	"github.com/naoto24kawa/mcpjson/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// NewCommand returns the server inspect command
+func NewCommand() *cobra.Command {
+	var formatFlag string
+
+	cmd := &cobra.Command{
+		Use:               "inspect <template>",
+		Short:             i18n.T("help.server_inspect.short"),
+		Long:              i18n.T("help.server_inspect.long"),
+		Args:              cmdutil.ExactArgs(1, i18n.Error("server.inspect_no_name")),
+		ValidArgsFunction: cmdutil.CompleteTemplates(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := cmdutil.ParseOutput(formatFlag)
+			if err != nil {
+				return err
+			}
+
+			cfg, err := cmdutil.LoadConfig()
+			if err != nil {
+				return err
+			}
+			return inspectServer(cmd.Context(), cfg, args[0], format)
+		},
+	}
+
+	cmdutil.AddOutputFlag(cmd, &formatFlag, output.FormatTable)
+	return cmd
+}
+
+// inspectServer lists what the template's server offers and caches it in the
+// template
+func inspectServer(ctx context.Context, cfg *config.Config, templateName string, format output.Format) error {
+	serverManager, err := cmdprofile.NewServerManager(cfg)
+	if err != nil {
+		return err
+	}
+	mcpServer, err := serverManager.Build(templateName)
+	if err != nil {
+		return err
+	}
+	capabilities, err := doctor.Inspect(ctx, mcpServer)
+	if err != nil {
+		return utils.WithExitCode(i18n.Errorf("server.inspect_failed", templateName, err), utils.ExitServerError)
+	}
+	err = history.Run(cfg, "server inspect "+templateName, []string{cfg.ServersDir}, func() error {
+		return serverManager.SaveCapabilities(templateName, capabilities)
+	})
+	if err != nil {
+		return err
+	}
+
+	if format.IsStructured() {
+		return output.Write(os.Stdout, format, capabilities)
+	}
+	server.WriteCapabilities(os.Stdout, templateName, capabilities)
+	return nil
+}
@@ -0,0 +1,210 @@
+package install
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/naoto24kawa/mcpjson/cmd/cmdutil"
+	"github.com/naoto24kawa/mcpjson/internal/catalog"
+	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/history"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/interaction"
+	"github.com/naoto24kawa/mcpjson/internal/secret"
+	"github.com/naoto24kawa/mcpjson/internal/server"
+	"github.com/naoto24kawa/mcpjson/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// options holds the flags of `server install`
+type options struct {
+	templateName string
+	env          string
+	catalogs     []string
+	force        bool
+}
+
+// NewCommand returns the server install command
+func NewCommand() *cobra.Command {
+	var opts options
+
+	cmd := &cobra.Command{
+		Use:   "install <catalog-id>",
+		Short: i18n.T("help.server_install.short"),
+		Long:  i18n.T("help.server_install.long"),
+		Example: `  mcpjson server install github
+  mcpjson server install github --as gh --env GITHUB_TOKEN='${secret:github-token}'`,
+		Args: cmdutil.ExactArgs(1, i18n.Error("catalog.install_no_id")),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := cmdutil.LoadConfig()
+			if err != nil {
+				return err
+			}
+			input := &envInput{interactive: interaction.IsInteractive(), in: bufio.NewReader(os.Stdin), out: os.Stdout}
+			return run(cmd.Context(), cfg, args[0], &opts, input)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&opts.templateName, "as", "a", "", i18n.T("help.server_install.as"))
+	flags.StringVarP(&opts.env, "env", "e", "", i18n.T("help.server.env"))
+	flags.StringArrayVar(&opts.catalogs, "catalog", nil, i18n.T("help.server_search.catalog"))
+	cmdutil.AddForceFlag(cmd, &opts.force, "F")
+	return cmd
+}
+
+func run(ctx context.Context, cfg *config.Config, id string, opts *options, input *envInput) error {
+	index, err := catalog.Open(ctx, cfg, opts.catalogs, os.Stderr)
+	if err != nil {
+		return err
+	}
+	entry, err := index.Find(id)
+	if err != nil {
+		return err
+	}
+	if err := entry.Server(nil).Validate(); err != nil {
+		return i18n.Errorf("common.server_invalid", id, err)
+	}
+
+	templateName := opts.templateName
+	if templateName == "" {
+		templateName = entry.ID
+	}
+	if err := cmdutil.ValidateNames(i18n.T("kind.template"), templateName); err != nil {
+		return err
+	}
+	given := map[string]string{}
+	if opts.env != "" {
+		if given, err = utils.ParseEnvVars(opts.env); err != nil {
+			return utils.ArgumentError(err)
+		}
+	}
+
+	// Ask before prompting for the values, which may be stored as secrets
+	serverManager := server.NewManager(cfg.ServersDir)
+	exists, err := serverManager.Exists(templateName)
+	if err != nil {
+		return err
+	}
+	if exists && !opts.force && !interaction.ConfirmOverwrite(i18n.T("kind.template"), templateName) {
+		return i18n.Errorf("common.overwrite_cancelled")
+	}
+
+	store, err := secret.OpenStore(cfg)
+	if errors.Is(err, secret.ErrReadOnly) {
+		store = nil
+	} else if err != nil {
+		return err
+	}
+	input.store = store
+
+	env, err := input.collect(entry, templateName, given)
+	if err != nil {
+		return err
+	}
+
+	var description *string
+	if entry.Description != "" {
+		description = &entry.Description
+	}
+	err = history.Run(cfg, "server install "+id, []string{cfg.ServersDir}, func() error {
+		return serverManager.SaveWithDescription(templateName, description, entry.Server(env))
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Println(i18n.T("catalog.installed", id, templateName))
+	return nil
+}
+
+// envInput collects the environment of an installed server, asking for
+// the required values that were not given on the command line
+type envInput struct {
+	interactive bool
+	in          *bufio.Reader
+	out         io.Writer
+	// store keeps the secret values. When nil the secret provider is read
+	// only, and the secrets have to be defined there.
+	store secret.Store
+}
+
+// collect returns the given values, the defaults of the optional variables
+// and the answers for the required ones. Secret answers are stored as
+// secrets named <template>/<variable> and referenced by a placeholder.
+func (e *envInput) collect(entry *catalog.Entry, templateName string, given map[string]string) (map[string]string, error) {
+	env := make(map[string]string, len(given))
+	for name, value := range given {
+		env[name] = value
+	}
+
+	var missing []string
+	for _, v := range entry.Env {
+		if _, ok := env[v.Name]; ok {
+			continue
+		}
+		if !v.Required {
+			if v.Default != "" {
+				env[v.Name] = v.Default
+			}
+			continue
+		}
+
+		secretName := templateName + "/" + v.Name
+		if v.Secret && e.store == nil {
+			env[v.Name] = "${secret:" + secretName + "}"
+			fmt.Fprintln(e.out, i18n.T("catalog.define_secret", secretName, v.Name))
+			continue
+		}
+		if !e.interactive {
+			missing = append(missing, v.Name)
+			continue
+		}
+
+		value, err := e.ask(v)
+		if err != nil {
+			return nil, err
+		}
+		if v.Secret {
+			if err := e.store.Set(secretName, value); err != nil {
+				return nil, err
+			}
+			fmt.Fprintln(e.out, i18n.T("secret.saved", secretName))
+			value = "${secret:" + secretName + "}"
+		}
+		env[v.Name] = value
+	}
+
+	if len(missing) > 0 {
+		return nil, utils.ArgumentError(i18n.Errorf("catalog.env_missing", strings.Join(missing, ", ")))
+	}
+	return env, nil
+}
+
+func (e *envInput) ask(v catalog.EnvVar) (string, error) {
+	label := v.Name
+	if v.Description != "" {
+		label += " (" + v.Description + ")"
+	}
+	if v.Default != "" {
+		label += " [" + v.Default + "]"
+	}
+	fmt.Fprint(e.out, label+": ")
+
+	line, err := e.in.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", i18n.Errorf("secret.read_value_failed", err)
+	}
+	value := strings.TrimSpace(line)
+	if value == "" {
+		value = v.Default
+	}
+	if value == "" {
+		return "", i18n.Errorf("catalog.env_empty", v.Name)
+	}
+	return value, nil
+}
@@ -0,0 +1,137 @@
+package install
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/naoto24kawa/mcpjson/internal/catalog"
+	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/secret"
+	"github.com/naoto24kawa/mcpjson/internal/server"
+)
+
+func TestEnvInput_Collect(t *testing.T) {
+	entry := &catalog.Entry{ID: "github", Command: "npx", Env: []catalog.EnvVar{
+		{Name: "GITHUB_TOKEN", Required: true, Secret: true},
+		{Name: "GITHUB_HOST", Required: true, Default: "github.com"},
+		{Name: "LOG_LEVEL", Default: "info"},
+		{Name: "PROXY"},
+	}}
+
+	tests := []struct {
+		name        string
+		interactive bool
+		readOnly    bool
+		given       map[string]string
+		answers     string
+		want        map[string]string
+		wantSecret  string
+		wantErr     string
+	}{
+		{
+			name:        "入力した値",
+			interactive: true,
+			answers:     "ghp_xxx\n\n",
+			want:        map[string]string{"GITHUB_TOKEN": "${secret:gh/GITHUB_TOKEN}", "GITHUB_HOST": "github.com", "LOG_LEVEL": "info"},
+			wantSecret:  "ghp_xxx",
+		},
+		{
+			name:  "--env で指定",
+			given: map[string]string{"GITHUB_TOKEN": "${secret:token}", "GITHUB_HOST": "ghe.example.com", "PROXY": "http://proxy"},
+			want:  map[string]string{"GITHUB_TOKEN": "${secret:token}", "GITHUB_HOST": "ghe.example.com", "LOG_LEVEL": "info", "PROXY": "http://proxy"},
+		},
+		{
+			name:    "非対話で未指定",
+			given:   map[string]string{"GITHUB_HOST": "github.com"},
+			wantErr: "GITHUB_TOKEN",
+		},
+		{
+			name:     "読み取り専用のバックエンド",
+			readOnly: true,
+			given:    map[string]string{"GITHUB_HOST": "github.com"},
+			want:     map[string]string{"GITHUB_TOKEN": "${secret:gh/GITHUB_TOKEN}", "GITHUB_HOST": "github.com", "LOG_LEVEL": "info"},
+		},
+		{
+			name:        "空の入力",
+			interactive: true,
+			answers:     "\n",
+			wantErr:     "GITHUB_TOKEN",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vault := secret.NewVault(t.TempDir())
+			input := &envInput{interactive: tt.interactive, in: bufio.NewReader(strings.NewReader(tt.answers)), out: io.Discard}
+			if !tt.readOnly {
+				input.store = vault
+			}
+
+			env, err := input.collect(entry, "gh", tt.given)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("collect() error = %v, want it to mention %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(env, tt.want) {
+				t.Errorf("collect() = %v, want %v", env, tt.want)
+			}
+			if tt.wantSecret != "" {
+				if value, err := vault.Get("gh/GITHUB_TOKEN"); err != nil || value != tt.wantSecret {
+					t.Errorf("secret = %q, %v, want %q", value, err, tt.wantSecret)
+				}
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	baseDir := t.TempDir()
+	cfg := &config.Config{BaseDir: baseDir, ServersDir: filepath.Join(baseDir, "servers")}
+	if err := os.MkdirAll(cfg.ServersDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	catalogPath := filepath.Join(baseDir, "catalog.json")
+	content := `{"servers": [
+		{"id": "fetch", "description": "Fetches web pages", "command": "uvx", "args": ["mcp-server-fetch"],
+		 "env": [{"name": "USER_AGENT", "required": true}]},
+		{"id": "broken", "type": "websocket", "url": "wss://example.com/mcp"}
+	]}`
+	if err := os.WriteFile(catalogPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	input := &envInput{in: bufio.NewReader(strings.NewReader("")), out: io.Discard}
+	opts := &options{templateName: "web", env: "USER_AGENT=mcpjson", catalogs: []string{catalogPath}, force: true}
+	if err := run(context.Background(), cfg, "fetch", opts, input); err != nil {
+		t.Fatalf("run() failed: %v", err)
+	}
+
+	template, err := server.NewManager(cfg.ServersDir).Load("web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if template.Description == nil || *template.Description != "Fetches web pages" {
+		t.Errorf("Description = %v", template.Description)
+	}
+	want := server.ServerConfig{Command: "uvx", Args: []string{"mcp-server-fetch"}, Env: map[string]string{"USER_AGENT": "mcpjson"}}
+	if !reflect.DeepEqual(template.ServerConfig, want) {
+		t.Errorf("ServerConfig = %+v, want %+v", template.ServerConfig, want)
+	}
+
+	for _, id := range []string{"broken", "unknown"} {
+		if err := run(context.Background(), cfg, id, &options{catalogs: []string{catalogPath}}, input); err == nil {
+			t.Errorf("run(%s) should fail", id)
+		}
+	}
+}
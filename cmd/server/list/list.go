@@ -1,27 +1,41 @@
 package list
 
 import (
-	"fmt"
 	"os"
 
-	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/cmd/cmdutil"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/output"
 	"github.com/naoto24kawa/mcpjson/internal/server"
-	"github.com/naoto24kawa/mcpjson/internal/utils"
+	"github.com/spf13/cobra"
 )
 
-func Execute(cfg *config.Config, args []string) {
-	detail := false
+// NewCommand returns the server list command
+func NewCommand() *cobra.Command {
+	var detail bool
+	var formatFlag string
 
-	for _, arg := range args {
-		switch arg {
-		case "--detail", "-d":
-			detail = true
-		}
-	}
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: i18n.T("help.server_list.short"),
+		Args:  cmdutil.MaximumArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := cmdutil.ParseOutput(formatFlag)
+			if err != nil {
+				return err
+			}
 
-	serverManager := server.NewManager(cfg.ServersDir)
-	if err := serverManager.List(detail); err != nil {
-		fmt.Fprintln(os.Stderr, "エラー:", err)
-		os.Exit(utils.ExitGeneralError)
+			cfg, err := cmdutil.LoadConfig()
+			if err != nil {
+				return err
+			}
+			serverManager := server.NewManager(cfg.ServersDir)
+			serverManager.UseConfig(cfg)
+			return serverManager.ListWithFormat(os.Stdout, detail, format)
+		},
 	}
+
+	cmd.Flags().BoolVarP(&detail, "detail", "d", false, i18n.T("help.flag.detail"))
+	cmdutil.AddOutputFlag(cmd, &formatFlag, output.FormatTable)
+	return cmd
 }
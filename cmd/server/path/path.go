@@ -2,41 +2,36 @@ package path
 
 import (
 	"fmt"
-	"os"
 
-	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/cmd/cmdutil"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
 	"github.com/naoto24kawa/mcpjson/internal/server"
-	"github.com/naoto24kawa/mcpjson/internal/utils"
+	"github.com/spf13/cobra"
 )
 
-func Execute(cfg *config.Config, args []string) {
-	if len(args) != 1 {
-		fmt.Fprintf(os.Stderr, "エラー: サーバーテンプレート名を指定してください\n")
-		printUsage()
-		os.Exit(utils.ExitGeneralError)
+// NewCommand returns the server path command
+func NewCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:               "path <template>",
+		Short:             i18n.T("help.server_path.short"),
+		Long:              i18n.T("help.server_path.long"),
+		Args:              cmdutil.ExactArgs(1, i18n.Error("server.path_no_name")),
+		ValidArgsFunction: cmdutil.CompleteTemplates(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := cmdutil.LoadConfig()
+			if err != nil {
+				return err
+			}
+
+			serverManager := server.NewManager(cfg.ServersDir)
+			serverManager.UseConfig(cfg)
+			templatePath, err := serverManager.GetTemplatePath(args[0])
+			if err != nil {
+				return i18n.Errorf("server.path_failed", err)
+			}
+
+			fmt.Fprint(cmd.OutOrStdout(), templatePath)
+			return nil
+		},
 	}
-
-	templateName := args[0]
-
-	serverManager := server.NewManager(cfg.ServersDir)
-	templatePath, err := serverManager.GetTemplatePath(templateName)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "エラー: サーバーテンプレートパスの取得に失敗しました: %v\n", err)
-		os.Exit(utils.ExitGeneralError)
-	}
-
-	fmt.Print(templatePath)
-}
-
-func printUsage() {
-	fmt.Println(`mcpjson server path - サーバーテンプレートパス表示
-
-使用方法:
-  mcpjson server path <サーバーテンプレート名>
-
-引数:
-  <サーバーテンプレート名>    パスを表示するサーバーテンプレート名
-
-説明:
-  指定されたサーバーテンプレートファイルの絶対パスを表示します。`)
 }
@@ -2,42 +2,39 @@ package remove
 
 import (
 	"fmt"
-	"os"
 
-	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/cmd/cmdutil"
+	"github.com/naoto24kawa/mcpjson/internal/history"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
 	"github.com/naoto24kawa/mcpjson/internal/server"
-	"github.com/naoto24kawa/mcpjson/internal/utils"
+	"github.com/spf13/cobra"
 )
 
-func Execute(cfg *config.Config, args []string) {
-	if len(args) < 1 {
-		fmt.Fprintln(os.Stderr, "エラー: サーバー名が指定されていません")
-		os.Exit(utils.ExitArgumentError)
-	}
-
-	serverName := args[0]
+// NewCommand returns the server remove command
+func NewCommand() *cobra.Command {
 	var mcpConfigPath string
 
-	for i := 1; i < len(args); i++ {
-		switch args[i] {
-		case "--from", "-f":
-			if i+1 >= len(args) {
-				fmt.Fprintln(os.Stderr, "エラー: --from オプションに値が指定されていません")
-				os.Exit(utils.ExitArgumentError)
+	cmd := &cobra.Command{
+		Use:               "remove <server>",
+		Short:             i18n.T("help.server_remove.short"),
+		Args:              cmdutil.ExactArgs(1, i18n.Error("server.remove_no_name")),
+		ValidArgsFunction: cmdutil.CompleteServersIn("from"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			serverName := args[0]
+
+			cfg, err := cmdutil.LoadConfig()
+			if err != nil {
+				return err
 			}
-			mcpConfigPath = args[i+1]
-			i++
-		}
-	}
 
-	// --from が未指定の場合、デフォルトで ./.mcp.json を使用
-	if mcpConfigPath == "" {
-		mcpConfigPath = "./.mcp.json"
+			serverManager := server.NewManager(cfg.ServersDir)
+			operation := fmt.Sprintf("server remove %s --from %s", serverName, mcpConfigPath)
+			return history.Run(cfg, operation, []string{mcpConfigPath}, func() error {
+				return serverManager.RemoveFromMCPConfig(mcpConfigPath, serverName)
+			})
+		},
 	}
 
-	serverManager := server.NewManager(cfg.ServersDir)
-	if err := serverManager.RemoveFromMCPConfig(mcpConfigPath, serverName); err != nil {
-		fmt.Fprintln(os.Stderr, "エラー:", err)
-		os.Exit(utils.ExitGeneralError)
-	}
+	cmd.Flags().StringVarP(&mcpConfigPath, "from", "f", "./.mcp.json", i18n.T("help.server_remove.from"))
+	return cmd
 }
@@ -2,43 +2,42 @@ package rename
 
 import (
 	"fmt"
-	"os"
 
-	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/cmd/cmdutil"
+	"github.com/naoto24kawa/mcpjson/internal/history"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
 	"github.com/naoto24kawa/mcpjson/internal/server"
-	"github.com/naoto24kawa/mcpjson/internal/utils"
+	"github.com/spf13/cobra"
 )
 
-func Execute(cfg *config.Config, args []string) {
-	if len(args) < 2 {
-		fmt.Fprintln(os.Stderr, "エラー: テンプレート名が指定されていません")
-		os.Exit(utils.ExitArgumentError)
+// NewCommand returns the server rename command
+func NewCommand() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:               "rename <old-name> <new-name>",
+		Short:             i18n.T("help.server_rename.short"),
+		Args:              cmdutil.ExactArgs(2, i18n.Error("server.rename_no_names")),
+		ValidArgsFunction: cmdutil.CompleteTemplates(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			oldName, newName := args[0], args[1]
+			if err := cmdutil.ValidateNames(i18n.T("kind.template"), oldName, newName); err != nil {
+				return err
+			}
+
+			cfg, err := cmdutil.LoadConfig()
+			if err != nil {
+				return err
+			}
+
+			serverManager := server.NewManager(cfg.ServersDir)
+			paths := []string{cfg.ServersDir, cfg.ProfilesDir}
+			return history.Run(cfg, fmt.Sprintf("server rename %s %s", oldName, newName), paths, func() error {
+				return serverManager.Rename(oldName, newName, force)
+			})
+		},
 	}
 
-	oldName := args[0]
-	newName := args[1]
-	force := false
-
-	for i := 2; i < len(args); i++ {
-		switch args[i] {
-		case "--force", "-f":
-			force = true
-		}
-	}
-
-	if err := utils.ValidateName(oldName, "サーバーテンプレート"); err != nil {
-		fmt.Fprintln(os.Stderr, "エラー:", err)
-		os.Exit(utils.ExitArgumentError)
-	}
-
-	if err := utils.ValidateName(newName, "サーバーテンプレート"); err != nil {
-		fmt.Fprintln(os.Stderr, "エラー:", err)
-		os.Exit(utils.ExitArgumentError)
-	}
-
-	serverManager := server.NewManager(cfg.ServersDir)
-	if err := serverManager.Rename(oldName, newName, force); err != nil {
-		fmt.Fprintln(os.Stderr, "エラー:", err)
-		os.Exit(utils.ExitGeneralError)
-	}
+	cmdutil.AddForceFlag(cmd, &force, "f")
+	return cmd
 }
@@ -1,124 +1,127 @@
 package save
 
 import (
-	"fmt"
-	"os"
-
+	"github.com/naoto24kawa/mcpjson/cmd/cmdutil"
 	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/history"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
 	"github.com/naoto24kawa/mcpjson/internal/server"
 	"github.com/naoto24kawa/mcpjson/internal/utils"
+	"github.com/spf13/cobra"
 )
 
-func Execute(cfg *config.Config, args []string) {
-	if len(args) < 1 {
-		fmt.Fprintln(os.Stderr, "エラー: テンプレート名が指定されていません")
-		os.Exit(utils.ExitArgumentError)
-	}
-
-	templateName := args[0]
-	var serverName, fromPath, command, argsStr, envStr, envFile string
-	force := false
+// NewCommand returns the server save command
+func NewCommand() *cobra.Command {
+	var opts options
 
-	for i := 1; i < len(args); i++ {
-		switch args[i] {
-		case "--server", "-s":
-			if i+1 >= len(args) {
-				fmt.Fprintln(os.Stderr, "エラー: --server オプションに値が指定されていません")
-				os.Exit(utils.ExitArgumentError)
-			}
-			serverName = args[i+1]
-			i++
-		case "--from", "-f":
-			if i+1 >= len(args) {
-				fmt.Fprintln(os.Stderr, "エラー: --from オプションに値が指定されていません")
-				os.Exit(utils.ExitArgumentError)
-			}
-			fromPath = args[i+1]
-			i++
-		case "--command", "-c":
-			if i+1 >= len(args) {
-				fmt.Fprintln(os.Stderr, "エラー: --command オプションに値が指定されていません")
-				os.Exit(utils.ExitArgumentError)
-			}
-			command = args[i+1]
-			i++
-		case "--args", "-a":
-			if i+1 >= len(args) {
-				fmt.Fprintln(os.Stderr, "エラー: --args オプションに値が指定されていません")
-				os.Exit(utils.ExitArgumentError)
-			}
-			argsStr = args[i+1]
-			i++
-		case "--env", "-e":
-			if i+1 >= len(args) {
-				fmt.Fprintln(os.Stderr, "エラー: --env オプションに値が指定されていません")
-				os.Exit(utils.ExitArgumentError)
-			}
-			envStr = args[i+1]
-			i++
-		case "--env-file":
-			if i+1 >= len(args) {
-				fmt.Fprintln(os.Stderr, "エラー: --env-file オプションに値が指定されていません")
-				os.Exit(utils.ExitArgumentError)
+	cmd := &cobra.Command{
+		Use:   "save <template>",
+		Short: i18n.T("help.server_save.short"),
+		Long:  i18n.T("help.server_save.long"),
+		Example: `  mcpjson server save github --server github --from ./.mcp.json
+  mcpjson server save fs --command npx --args "-y,@modelcontextprotocol/server-filesystem"
+  mcpjson server save remote --url https://example.com/mcp --header "Authorization: Bearer ${secret:token}"`,
+		Args: cmdutil.ExactArgs(1, i18n.Error("server.cmd_no_template")),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := cmdutil.LoadConfig()
+			if err != nil {
+				return err
 			}
-			envFile = args[i+1]
-			i++
-		case "--force", "-F":
-			force = true
-		}
+			return run(cfg, args[0], &opts)
+		},
 	}
 
-	if err := utils.ValidateName(templateName, "サーバーテンプレート"); err != nil {
-		fmt.Fprintln(os.Stderr, "エラー:", err)
-		os.Exit(utils.ExitArgumentError)
+	flags := cmd.Flags()
+	flags.StringVarP(&opts.serverName, "server", "s", "", i18n.T("help.server_save.server"))
+	flags.StringVarP(&opts.fromPath, "from", "f", "", i18n.T("help.server_save.from"))
+	flags.StringVarP(&opts.command, "command", "c", "", i18n.T("help.server_save.command"))
+	flags.StringVarP(&opts.args, "args", "a", "", i18n.T("help.server.args"))
+	flags.StringVarP(&opts.env, "env", "e", "", i18n.T("help.server.env"))
+	flags.StringVar(&opts.envFile, "env-file", "", i18n.T("help.server.env_file"))
+	flags.StringVar(&opts.serverType, "type", "", i18n.T("help.server_save.type"))
+	flags.StringVarP(&opts.url, "url", "u", "", i18n.T("help.server_save.url"))
+	flags.StringArrayVarP(&opts.headers, "header", "H", nil, i18n.T("help.server_save.header"))
+	cmdutil.AddForceFlag(cmd, &opts.force, "F")
+	_ = cmd.RegisterFlagCompletionFunc("server", cmdutil.CompleteServersIn("from"))
+	return cmd
+}
+
+// options holds the flags of `server save`
+type options struct {
+	serverName string
+	fromPath   string
+	command    string
+	args       string
+	env        string
+	envFile    string
+	serverType string
+	url        string
+	headers    []string
+	force      bool
+}
+
+func run(cfg *config.Config, templateName string, opts *options) error {
+	if err := cmdutil.ValidateNames(i18n.T("kind.template"), templateName); err != nil {
+		return err
 	}
 
 	// --from が未指定の場合、デフォルトで ./.mcp.json を使用
-	if fromPath == "" && serverName != "" {
+	fromPath := opts.fromPath
+	if fromPath == "" && opts.serverName != "" {
 		fromPath = "./.mcp.json"
 	}
 
 	serverManager := server.NewManager(cfg.ServersDir)
+	record := func(fn func() error) error {
+		return history.Run(cfg, "server save "+templateName, []string{cfg.ServersDir}, fn)
+	}
 
-	if fromPath != "" && serverName != "" {
-		if err := serverManager.SaveFromFile(templateName, serverName, fromPath, force); err != nil {
-			fmt.Fprintln(os.Stderr, "エラー:", err)
-			os.Exit(utils.ExitGeneralError)
+	isRemote := opts.url != "" || len(opts.headers) > 0 || (opts.serverType != "" && opts.serverType != server.ServerTypeStdio)
+	if isRemote && (opts.command != "" || opts.args != "") {
+		return utils.ArgumentError(i18n.Errorf("server.remote_and_command"))
+	}
+
+	switch {
+	case fromPath != "" && opts.serverName != "":
+		return record(func() error {
+			return serverManager.SaveFromFile(templateName, opts.serverName, fromPath, opts.force)
+		})
+	case isRemote:
+		headers, err := utils.ParseHeaders(opts.headers)
+		if err != nil {
+			return utils.ArgumentError(err)
 		}
-	} else if command != "" || argsStr != "" || envStr != "" || envFile != "" {
+		return record(func() error {
+			return serverManager.SaveRemote(templateName, opts.serverType, opts.url, headers, opts.force)
+		})
+	case opts.command != "" || opts.args != "" || opts.env != "" || opts.envFile != "":
 		env := make(map[string]string)
 
-		if envFile != "" {
-			fileEnv, err := utils.LoadEnvFile(envFile)
+		if opts.envFile != "" {
+			fileEnv, err := utils.LoadEnvFile(opts.envFile)
 			if err != nil {
-				fmt.Fprintln(os.Stderr, "エラー:", err)
-				os.Exit(utils.ExitFileError)
+				return utils.WithExitCode(err, utils.ExitFileError)
 			}
 			for k, v := range fileEnv {
 				env[k] = v
 			}
 		}
 
-		if envStr != "" {
-			parsedEnv, err := utils.ParseEnvVars(envStr)
+		if opts.env != "" {
+			parsedEnv, err := utils.ParseEnvVars(opts.env)
 			if err != nil {
-				fmt.Fprintln(os.Stderr, "エラー:", err)
-				os.Exit(utils.ExitArgumentError)
+				return utils.ArgumentError(err)
 			}
 			for k, v := range parsedEnv {
 				env[k] = v
 			}
 		}
 
-		parsedArgs := utils.ParseArgs(argsStr)
-
-		if err := serverManager.SaveManual(templateName, command, parsedArgs, env, force); err != nil {
-			fmt.Fprintln(os.Stderr, "エラー:", err)
-			os.Exit(utils.ExitGeneralError)
-		}
-	} else {
-		fmt.Fprintln(os.Stderr, "エラー: 設定ファイルからの保存には --server と --from が必要です")
-		fmt.Fprintln(os.Stderr, "手動作成には --command が必要です")
-		os.Exit(utils.ExitArgumentError)
+		parsedArgs := utils.ParseArgs(opts.args)
+		return record(func() error {
+			return serverManager.SaveManual(templateName, opts.command, parsedArgs, env, opts.force)
+		})
+	default:
+		return utils.ArgumentError(i18n.Errorf("server.save_needs_source"))
 	}
 }
@@ -0,0 +1,72 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/naoto24kawa/mcpjson/cmd/cmdutil"
+	"github.com/naoto24kawa/mcpjson/internal/catalog"
+	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// NewCommand returns the server search command
+func NewCommand() *cobra.Command {
+	var catalogs []string
+	var formatFlag string
+
+	cmd := &cobra.Command{
+		Use:   "search [term...]",
+		Short: i18n.T("help.server_search.short"),
+		Long:  i18n.T("help.server_search.long"),
+		Example: `  mcpjson server search github
+  mcpjson server search --catalog ./catalog.json database`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := cmdutil.ParseOutput(formatFlag)
+			if err != nil {
+				return err
+			}
+
+			cfg, err := cmdutil.LoadConfig()
+			if err != nil {
+				return err
+			}
+			return run(cmd.Context(), cfg, strings.Join(args, " "), catalogs, format, os.Stdout)
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&catalogs, "catalog", nil, i18n.T("help.server_search.catalog"))
+	cmdutil.AddOutputFlag(cmd, &formatFlag, output.FormatTable)
+	return cmd
+}
+
+func run(ctx context.Context, cfg *config.Config, term string, catalogs []string, format output.Format, w io.Writer) error {
+	index, err := catalog.Open(ctx, cfg, catalogs, os.Stderr)
+	if err != nil {
+		return err
+	}
+
+	entries := index.Search(term)
+	if format.IsStructured() {
+		return output.Write(w, format, entries)
+	}
+	if len(entries) == 0 {
+		fmt.Fprintln(w, i18n.T("catalog.no_match", term))
+		return nil
+	}
+
+	table := output.NewTable(i18n.T("catalog.column_id"), i18n.T("catalog.column_description"), i18n.T("server.column_command"), i18n.T("catalog.column_env"))
+	for _, entry := range entries {
+		description := entry.Description
+		if description == "" {
+			description = entry.Name
+		}
+		table.AddRow(entry.ID, description, entry.Target(), strings.Join(entry.RequiredEnv(), ", "))
+	}
+	return table.Write(w)
+}
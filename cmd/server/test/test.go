@@ -0,0 +1,55 @@
+package test
+
+import (
+	"context"
+
+	"github.com/naoto24kawa/mcpjson/cmd/cmdutil"
+	cmddoctor "github.com/naoto24kawa/mcpjson/cmd/doctor"
+	cmdprofile "github.com/naoto24kawa/mcpjson/cmd/profile"
+	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/doctor"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// NewCommand returns the server test command
+func NewCommand() *cobra.Command {
+	var formatFlag string
+
+	cmd := &cobra.Command{
+		Use:               "test <template>",
+		Short:             i18n.T("help.server_test.short"),
+		Args:              cmdutil.ExactArgs(1, i18n.Error("server.test_no_name")),
+		ValidArgsFunction: cmdutil.CompleteTemplates(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := cmdutil.ParseOutput(formatFlag)
+			if err != nil {
+				return err
+			}
+
+			cfg, err := cmdutil.LoadConfig()
+			if err != nil {
+				return err
+			}
+			return testServer(cmd.Context(), cfg, args[0], format)
+		},
+	}
+
+	cmdutil.AddOutputFlag(cmd, &formatFlag, output.FormatTable)
+	return cmd
+}
+
+// testServer launches the server a template describes and reports whether
+// it answers the MCP handshake
+func testServer(ctx context.Context, cfg *config.Config, templateName string, format output.Format) error {
+	serverManager, err := cmdprofile.NewServerManager(cfg)
+	if err != nil {
+		return err
+	}
+	mcpServer, err := serverManager.Build(templateName)
+	if err != nil {
+		return err
+	}
+	return cmddoctor.WriteResults([]doctor.Result{doctor.Check(ctx, templateName, mcpServer)}, format)
+}
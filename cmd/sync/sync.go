@@ -0,0 +1,186 @@
+package sync
+
+import (
+	"fmt"
+
+	"github.com/naoto24kawa/mcpjson/cmd/cmdutil"
+	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/filelock"
+	"github.com/naoto24kawa/mcpjson/internal/gitstore"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// NewCommand returns the sync command with its subcommands
+func NewCommand() *cobra.Command {
+	var preferFlag string
+
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: i18n.T("help.sync.short"),
+		Long:  i18n.T("help.sync.long"),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				return cmdutil.UnknownCommand(cmd, args[0])
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			prefer := gitstore.PreferNone
+			if cmd.Flags().Changed("prefer") {
+				var err error
+				if prefer, err = parsePrefer(preferFlag); err != nil {
+					return utils.ArgumentError(err)
+				}
+			}
+
+			cfg, err := cmdutil.LoadConfig()
+			if err != nil {
+				return err
+			}
+			return Sync(cfg, prefer)
+		},
+	}
+	cmd.Flags().StringVar(&preferFlag, "prefer", "", i18n.T("help.sync.prefer"))
+	_ = cmd.RegisterFlagCompletionFunc("prefer", cmdutil.CompleteValues(string(gitstore.PreferLocal), string(gitstore.PreferRemote)))
+
+	var remote string
+	initCmd := &cobra.Command{
+		Use:   "init",
+		Short: i18n.T("help.sync_init.short"),
+		Args:  cmdutil.MaximumArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := cmdutil.LoadConfig()
+			if err != nil {
+				return err
+			}
+			return Init(cfg, remote)
+		},
+	}
+	initCmd.Flags().StringVarP(&remote, "remote", "r", "", i18n.T("help.sync_init.remote"))
+
+	cmd.AddCommand(
+		initCmd,
+		&cobra.Command{
+			Use:   "remote [url]",
+			Short: i18n.T("help.sync_remote.short"),
+			Args:  cmdutil.MaximumArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				cfg, err := cmdutil.LoadConfig()
+				if err != nil {
+					return err
+				}
+				if len(args) == 0 {
+					return ShowRemote(cfg)
+				}
+				return SetRemote(cfg, args[0])
+			},
+		},
+		&cobra.Command{
+			Use:   "status",
+			Short: i18n.T("help.sync_status.short"),
+			Args:  cmdutil.MaximumArgs(0),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				cfg, err := cmdutil.LoadConfig()
+				if err != nil {
+					return err
+				}
+				return Status(cfg)
+			},
+		},
+	)
+	return cmd
+}
+
+// Init makes the store a git repository, optionally with a remote
+func Init(cfg *config.Config, remote string) error {
+	repo, err := gitstore.Init(cfg.BaseDir)
+	if err != nil {
+		return err
+	}
+	if remote != "" {
+		if err := repo.SetRemote(remote); err != nil {
+			return err
+		}
+	}
+
+	fmt.Println(i18n.T("sync.initialised", cfg.BaseDir))
+	fmt.Println(i18n.T("sync.auto_commit"))
+	return nil
+}
+
+// SetRemote sets the repository that sync pulls from and pushes to
+func SetRemote(cfg *config.Config, url string) error {
+	if err := gitstore.Open(cfg.BaseDir).SetRemote(url); err != nil {
+		return err
+	}
+	fmt.Println(i18n.T("sync.remote_set", url))
+	return nil
+}
+
+// ShowRemote prints the configured remote
+func ShowRemote(cfg *config.Config) error {
+	repo := gitstore.Open(cfg.BaseDir)
+	if !repo.IsRepository() {
+		return gitstore.ErrNotRepository
+	}
+	if remote := repo.Remote(); remote != "" {
+		fmt.Println(remote)
+		return nil
+	}
+	fmt.Println(i18n.T("sync.no_remote"))
+	return nil
+}
+
+// Status prints whether the store is a repository and where it syncs to
+func Status(cfg *config.Config) error {
+	repo := gitstore.Open(cfg.BaseDir)
+	if !repo.IsRepository() {
+		fmt.Println(i18n.T("sync.not_managed"))
+		return nil
+	}
+
+	fmt.Println(i18n.T("sync.status_store", cfg.BaseDir))
+	if remote := repo.Remote(); remote != "" {
+		fmt.Println(i18n.T("sync.status_remote", remote))
+	} else {
+		fmt.Println(i18n.T("sync.status_no_remote"))
+	}
+	return nil
+}
+
+// Sync pulls changes from the remote and pushes local ones. The store is
+// locked throughout, so that no other command changes it mid-merge.
+func Sync(cfg *config.Config, prefer gitstore.Prefer) error {
+	unlock, err := filelock.LockStore(cfg.ProfilesDir)
+	if err != nil {
+		return err
+	}
+	result, err := gitstore.Open(cfg.BaseDir).Sync(prefer)
+	unlock()
+	if err != nil {
+		return err
+	}
+
+	if result.Pulled {
+		fmt.Println(i18n.T("sync.pulled"))
+	} else {
+		fmt.Println(i18n.T("sync.up_to_date"))
+	}
+	for _, c := range result.Resolved {
+		fmt.Println(i18n.T("sync.resolved", prefer, c))
+	}
+	if result.Pushed {
+		fmt.Println(i18n.T("sync.pushed"))
+	}
+	return nil
+}
+
+func parsePrefer(s string) (gitstore.Prefer, error) {
+	switch prefer := gitstore.Prefer(s); prefer {
+	case gitstore.PreferLocal, gitstore.PreferRemote:
+		return prefer, nil
+	}
+	return "", i18n.Errorf("sync.invalid_prefer", gitstore.PreferLocal, gitstore.PreferRemote, s)
+}
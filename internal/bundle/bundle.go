@@ -0,0 +1,240 @@
+// Package bundle packs profiles, server templates and groups into a single
+// tar.gz archive and imports such archives into a store.
+//
+// An archive holds a manifest.json and the JSONC files of the store laid
+// out as in the store directory (profiles/, servers/ and groups/), so
+// comments in the files survive the round trip.
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/utils"
+)
+
+const (
+	ManifestName  = "manifest.json"
+	FormatVersion = 1
+
+	// maxFileSize bounds each file read from an archive
+	maxFileSize = 10 << 20
+)
+
+// Kind is the kind of an item in a bundle. Its value is the directory the
+// item is stored in, both in the archive and in the store.
+type Kind string
+
+const (
+	KindProfile  Kind = config.ProfilesDir
+	KindTemplate Kind = config.ServersDir
+	KindGroup    Kind = config.GroupsDir
+)
+
+// kinds lists the kinds in the order they are imported: templates first so
+// that profiles and groups can follow template renames
+var kinds = []Kind{KindTemplate, KindGroup, KindProfile}
+
+// Label returns the user-facing name of the kind
+func (k Kind) Label() string {
+	switch k {
+	case KindProfile:
+		return i18n.T("kind.profile")
+	case KindTemplate:
+		return i18n.T("kind.template")
+	case KindGroup:
+		return i18n.T("kind.group")
+	}
+	return string(k)
+}
+
+// Manifest describes the content of a bundle
+type Manifest struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+	Profiles  []string  `json:"profiles"`
+	Templates []string  `json:"templates"`
+	Groups    []string  `json:"groups"`
+	// Secrets lists the secrets that replaced sensitive values on export.
+	// They have to be set with 'mcpjson secret set' after importing.
+	Secrets []string `json:"secrets,omitempty"`
+}
+
+// Bundle is the content of an archive
+type Bundle struct {
+	Manifest Manifest
+	files    map[Kind]map[string][]byte
+}
+
+func newBundle() *Bundle {
+	b := &Bundle{
+		Manifest: Manifest{Version: FormatVersion},
+		files:    make(map[Kind]map[string][]byte),
+	}
+	for _, kind := range kinds {
+		b.files[kind] = make(map[string][]byte)
+	}
+	return b
+}
+
+// Names returns the names of the items of the given kind, sorted
+func (b *Bundle) Names(kind Kind) []string {
+	names := make([]string, 0, len(b.files[kind]))
+	for name := range b.files[kind] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// File returns the JSONC content of an item
+func (b *Bundle) File(kind Kind, name string) ([]byte, bool) {
+	data, ok := b.files[kind][name]
+	return data, ok
+}
+
+func (b *Bundle) add(kind Kind, name string, data []byte) {
+	b.files[kind][name] = data
+}
+
+func (b *Bundle) updateManifest() {
+	b.Manifest.Profiles = b.Names(KindProfile)
+	b.Manifest.Templates = b.Names(KindTemplate)
+	b.Manifest.Groups = b.Names(KindGroup)
+	sort.Strings(b.Manifest.Secrets)
+}
+
+// Write writes the bundle as a tar.gz archive
+func (b *Bundle) Write(w io.Writer) error {
+	b.updateManifest()
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	manifest, err := json.MarshalIndent(b.Manifest, "", "  ")
+	if err != nil {
+		return i18n.Errorf("bundle.manifest_write_failed", err)
+	}
+	if err := writeTarFile(tw, ManifestName, append(manifest, '\n'), b.Manifest.CreatedAt); err != nil {
+		return err
+	}
+
+	for _, kind := range kinds {
+		for _, name := range b.Names(kind) {
+			if err := writeTarFile(tw, path.Join(string(kind), name+config.FileExtension), b.files[kind][name], b.Manifest.CreatedAt); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return i18n.Errorf("bundle.write_failed", err)
+	}
+	if err := gz.Close(); err != nil {
+		return i18n.Errorf("bundle.write_failed", err)
+	}
+	return nil
+}
+
+func writeTarFile(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return i18n.Errorf("bundle.write_failed", err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return i18n.Errorf("bundle.write_failed", err)
+	}
+	return nil
+}
+
+// Read reads a tar.gz archive written by Write. Entries other than the
+// manifest and the store files are rejected, so that an archive cannot
+// write outside the store.
+func Read(r io.Reader) (*Bundle, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, i18n.Errorf("bundle.read_failed", err)
+	}
+	defer gz.Close()
+
+	b := newBundle()
+	hasManifest := false
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, i18n.Errorf("bundle.read_failed", err)
+		}
+		if header.Typeflag == tar.TypeDir {
+			continue
+		}
+		if header.Typeflag != tar.TypeReg {
+			return nil, i18n.Errorf("bundle.invalid_entry", header.Name)
+		}
+
+		data, err := io.ReadAll(io.LimitReader(tr, maxFileSize+1))
+		if err != nil {
+			return nil, i18n.Errorf("bundle.read_failed", err)
+		}
+		if len(data) > maxFileSize {
+			return nil, i18n.Errorf("bundle.entry_too_large", header.Name)
+		}
+
+		if header.Name == ManifestName {
+			if err := json.Unmarshal(data, &b.Manifest); err != nil {
+				return nil, i18n.Errorf("bundle.manifest_parse_failed", err)
+			}
+			hasManifest = true
+			continue
+		}
+
+		kind, name, err := splitEntryName(header.Name)
+		if err != nil {
+			return nil, err
+		}
+		b.add(kind, name, data)
+	}
+
+	if !hasManifest {
+		return nil, i18n.Errorf("bundle.missing_manifest", ManifestName)
+	}
+	if b.Manifest.Version > FormatVersion {
+		return nil, i18n.Errorf("bundle.unsupported_version", b.Manifest.Version)
+	}
+	return b, nil
+}
+
+func splitEntryName(entry string) (Kind, string, error) {
+	dir, file := path.Split(strings.TrimPrefix(entry, "./"))
+	kind := Kind(strings.TrimSuffix(dir, "/"))
+	name := strings.TrimSuffix(file, config.FileExtension)
+
+	switch kind {
+	case KindProfile, KindTemplate, KindGroup:
+	default:
+		return "", "", i18n.Errorf("bundle.unknown_entry", entry)
+	}
+	if name == file {
+		return "", "", i18n.Errorf("bundle.unknown_entry", entry)
+	}
+	if err := utils.ValidateName(name, kind.Label()); err != nil {
+		return "", "", i18n.Errorf("bundle.entry_failed", entry, err)
+	}
+	return kind, name, nil
+}
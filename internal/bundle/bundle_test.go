@@ -0,0 +1,384 @@
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/filelock"
+	"github.com/naoto24kawa/mcpjson/internal/profile"
+	"github.com/naoto24kawa/mcpjson/internal/server"
+	"github.com/naoto24kawa/mcpjson/internal/utils"
+)
+
+func newTestConfig(t *testing.T) *config.Config {
+	t.Helper()
+	baseDir := t.TempDir()
+	cfg := &config.Config{
+		BaseDir:     baseDir,
+		ProfilesDir: filepath.Join(baseDir, config.ProfilesDir),
+		ServersDir:  filepath.Join(baseDir, config.ServersDir),
+		GroupsDir:   filepath.Join(baseDir, config.GroupsDir),
+	}
+	for _, dir := range []string{cfg.ProfilesDir, cfg.ServersDir, cfg.GroupsDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return cfg
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// setupSourceStore creates a store with a profile extending another one,
+// the templates they use, an unrelated template and a group
+func setupSourceStore(t *testing.T) *config.Config {
+	t.Helper()
+	cfg := newTestConfig(t)
+
+	writeTestFile(t, cfg.GetServerPath("github"), `{
+  // GitHub連携
+  "name": "github",
+  "description": null,
+  "createdAt": "2024-01-01T00:00:00Z",
+  "serverConfig": {
+    "command": "npx",
+    "env": {"GITHUB_TOKEN": "ghp_secret", "LOG_LEVEL": "info"}
+  }
+}`)
+	writeTestFile(t, cfg.GetServerPath("fetch"), `{"name": "fetch", "description": null, "createdAt": "2024-01-01T00:00:00Z", "serverConfig": {"command": "uvx", "args": ["mcp-server-fetch"]}}`)
+	writeTestFile(t, cfg.GetServerPath("unused"), `{"name": "unused", "description": null, "createdAt": "2024-01-01T00:00:00Z", "serverConfig": {"command": "node"}}`)
+	writeTestFile(t, cfg.GetProfilePath("base"), `{"name": "base", "description": "", "createdAt": "2024-01-01T00:00:00Z", "updatedAt": "2024-01-01T00:00:00Z", "servers": [{"name": "fetch", "template": "fetch"}]}`)
+	writeTestFile(t, cfg.GetProfilePath("work"), `{"name": "work", "description": "", "createdAt": "2024-01-01T00:00:00Z", "updatedAt": "2024-01-01T00:00:00Z", "extends": ["base"], "servers": [{"name": "github", "template": "github"}]}`)
+	writeTestFile(t, cfg.GetGroupPath("dev"), `{"name": "dev", "description": null, "createdAt": "2024-01-01T00:00:00Z", "updatedAt": "2024-01-01T00:00:00Z", "servers": ["github", "fetch"]}`)
+	writeTestFile(t, cfg.GetGroupPath("other"), `{"name": "other", "description": null, "createdAt": "2024-01-01T00:00:00Z", "updatedAt": "2024-01-01T00:00:00Z", "servers": ["unused"]}`)
+	return cfg
+}
+
+func roundTrip(t *testing.T, b *Bundle) *Bundle {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := b.Write(&buf); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	read, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	return read
+}
+
+func TestExport_Profile(t *testing.T) {
+	cfg := setupSourceStore(t)
+
+	b, err := Export(cfg, ExportOptions{Profiles: []string{"work"}})
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	b = roundTrip(t, b)
+
+	if got := b.Manifest.Profiles; !reflect.DeepEqual(got, []string{"base", "work"}) {
+		t.Errorf("Profiles = %v, want [base work]", got)
+	}
+	if got := b.Manifest.Templates; !reflect.DeepEqual(got, []string{"fetch", "github"}) {
+		t.Errorf("Templates = %v, want [fetch github]", got)
+	}
+	if got := b.Manifest.Groups; !reflect.DeepEqual(got, []string{"dev"}) {
+		t.Errorf("Groups = %v, want [dev]", got)
+	}
+	if got := b.Manifest.Secrets; !reflect.DeepEqual(got, []string{"github.GITHUB_TOKEN"}) {
+		t.Errorf("Secrets = %v, want [github.GITHUB_TOKEN]", got)
+	}
+
+	data, _ := b.File(KindTemplate, "github")
+	content := string(data)
+	if strings.Contains(content, "ghp_secret") {
+		t.Error("シークレットの値がエクスポートされています")
+	}
+	for _, want := range []string{"${secret:github.GITHUB_TOKEN}", `"LOG_LEVEL": "info"`, "// GitHub連携"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("github テンプレートに %s が含まれていません:\n%s", want, content)
+		}
+	}
+}
+
+func TestExport_MasksArgsAndURL(t *testing.T) {
+	cfg := newTestConfig(t)
+	writeTestFile(t, cfg.GetServerPath("remote"), `{"name": "remote", "description": null, "createdAt": "2024-01-01T00:00:00Z", "serverConfig": {"type": "http", "url": "https://user:pw@example.com/mcp?api_key=k1&mode=fast#top"}}`)
+	writeTestFile(t, cfg.GetServerPath("cli"), `{"name": "cli", "description": null, "createdAt": "2024-01-01T00:00:00Z", "serverConfig": {"command": "npx", "args": ["server", "--token=t1", "--api-key", "k2", "--verbose", "--password", "${env:PASSWORD}"]}}`)
+	writeTestFile(t, cfg.GetProfilePath("work"), `{"name": "work", "description": "", "createdAt": "2024-01-01T00:00:00Z", "updatedAt": "2024-01-01T00:00:00Z", "servers": [{"name": "remote", "template": "remote"}, {"name": "cli", "template": "cli", "overrides": {"argsAppend": ["--secret", "s1"]}}]}`)
+
+	b, err := Export(cfg, ExportOptions{Profiles: []string{"work"}})
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	want := []string{"cli.args.api-key", "cli.args.token", "remote.url.api_key", "remote.url.password", "work.cli.args.secret"}
+	if got := b.Manifest.Secrets; !reflect.DeepEqual(got, want) {
+		t.Errorf("Secrets = %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		kind    Kind
+		name    string
+		want    []string
+		notWant []string
+	}{
+		{KindTemplate, "remote", []string{"https://user:${secret:remote.url.password}@example.com/mcp?api_key=${secret:remote.url.api_key}&mode=fast#top"}, []string{"pw@", "k1"}},
+		{KindTemplate, "cli", []string{`"--token=${secret:cli.args.token}"`, `"--api-key", "${secret:cli.args.api-key}"`, `"--verbose"`, "${env:PASSWORD}"}, []string{"t1", "k2"}},
+		{KindProfile, "work", []string{`"--secret", "${secret:work.cli.args.secret}"`}, []string{"s1"}},
+	}
+	for _, tt := range tests {
+		data, _ := b.File(tt.kind, tt.name)
+		content := string(data)
+		for _, want := range tt.want {
+			if !strings.Contains(content, want) {
+				t.Errorf("%s に %s が含まれていません:\n%s", tt.name, want, content)
+			}
+		}
+		for _, notWant := range tt.notWant {
+			if strings.Contains(content, notWant) {
+				t.Errorf("%s に %s がエクスポートされています:\n%s", tt.name, notWant, content)
+			}
+		}
+	}
+}
+
+func TestExport_All(t *testing.T) {
+	cfg := setupSourceStore(t)
+
+	b, err := Export(cfg, ExportOptions{All: true})
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if got := b.Manifest.Templates; !reflect.DeepEqual(got, []string{"fetch", "github", "unused"}) {
+		t.Errorf("Templates = %v", got)
+	}
+	if got := b.Manifest.Groups; !reflect.DeepEqual(got, []string{"dev", "other"}) {
+		t.Errorf("Groups = %v", got)
+	}
+}
+
+func TestExport_Stores(t *testing.T) {
+	cfg := setupSourceStore(t)
+	team := config.Store{Name: "team", Dir: t.TempDir()}
+	for _, dir := range []string{team.ProfilesDir(), team.ServersDir()} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg.Stores = []config.Store{team, {Name: "personal", Dir: cfg.BaseDir}}
+
+	// チームのストアにだけあるプロファイルとテンプレート
+	writeTestFile(t, filepath.Join(team.ServersDir(), "jira"+config.FileExtension), `{
+  // チーム共通
+  "name": "jira", "description": null, "createdAt": "2024-01-01T00:00:00Z", "serverConfig": {"command": "jira-mcp"}
+}`)
+	writeTestFile(t, filepath.Join(team.ProfilesDir(), "team"+config.FileExtension), `{"name": "team", "description": "", "createdAt": "2024-01-01T00:00:00Z", "updatedAt": "2024-01-01T00:00:00Z", "extends": ["base"], "servers": [{"name": "jira", "template": "jira"}]}`)
+
+	b, err := Export(cfg, ExportOptions{Profiles: []string{"team"}})
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if got := b.Manifest.Templates; !reflect.DeepEqual(got, []string{"fetch", "jira"}) {
+		t.Errorf("Templates = %v, want [fetch jira]", got)
+	}
+	if data, _ := b.File(KindTemplate, "jira"); !strings.Contains(string(data), "// チーム共通") {
+		t.Errorf("jira はチームのストアのファイルから読み込まれていません:\n%s", data)
+	}
+
+	b, err = Export(cfg, ExportOptions{All: true})
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if got := b.Manifest.Profiles; !reflect.DeepEqual(got, []string{"base", "team", "work"}) {
+		t.Errorf("Profiles = %v, want [base team work]", got)
+	}
+}
+
+func TestBundle_Import(t *testing.T) {
+	source := setupSourceStore(t)
+	b, err := Export(source, ExportOptions{Profiles: []string{"work"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b = roundTrip(t, b)
+
+	tests := []struct {
+		name         string
+		action       Action
+		wantTemplate string
+		wantCommand  string
+	}{
+		{name: "名前を変更", action: ActionRename, wantTemplate: "github-2", wantCommand: "docker"},
+		{name: "スキップ", action: ActionSkip, wantTemplate: "github", wantCommand: "docker"},
+		{name: "上書き", action: ActionOverwrite, wantTemplate: "github", wantCommand: "npx"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newTestConfig(t)
+			// github は内容が異なるため衝突し、fetch は同じ設定のため衝突しない
+			writeTestFile(t, cfg.GetServerPath("github"), `{"name": "github", "description": null, "createdAt": "2023-01-01T00:00:00Z", "serverConfig": {"command": "docker"}}`)
+			writeTestFile(t, cfg.GetServerPath("fetch"), `{"name": "fetch", "description": null, "createdAt": "2023-06-01T00:00:00Z", "serverConfig": {"command": "uvx", "args": ["mcp-server-fetch"]}}`)
+
+			var collisions []Collision
+			result, err := b.Import(cfg, ImportOptions{Resolve: func(c Collision) (Action, error) {
+				// 回答を待つ間はストアをロックしないこと
+				if utils.FileExists(filepath.Join(cfg.BaseDir, filelock.StoreLockName)) {
+					t.Error("the store was locked while the collision was resolved")
+				}
+				collisions = append(collisions, c)
+				return tt.action, nil
+			}})
+			if err != nil {
+				t.Fatalf("Import() error = %v", err)
+			}
+			if want := []Collision{{Kind: KindTemplate, Name: "github"}}; !reflect.DeepEqual(collisions, want) {
+				t.Errorf("collisions = %v, want %v", collisions, want)
+			}
+			if len(result.Items) != 5 {
+				t.Errorf("Items = %d, want 5", len(result.Items))
+			}
+
+			p, err := profile.NewManager(cfg.ProfilesDir).Load("work")
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if p.Servers[0].Template != tt.wantTemplate {
+				t.Errorf("work の参照先 = %s, want %s", p.Servers[0].Template, tt.wantTemplate)
+			}
+
+			template, err := server.NewManager(cfg.ServersDir).Load("github")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if template.ServerConfig.Command != tt.wantCommand {
+				t.Errorf("github の command = %s, want %s", template.ServerConfig.Command, tt.wantCommand)
+			}
+
+			if tt.action == ActionRename {
+				renamed, err := server.NewManager(cfg.ServersDir).Load("github-2")
+				if err != nil {
+					t.Fatalf("github-2 が作成されていません: %v", err)
+				}
+				if renamed.Name != "github-2" {
+					t.Errorf("renamed.Name = %s, want github-2", renamed.Name)
+				}
+				data, err := os.ReadFile(cfg.GetGroupPath("dev"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !strings.Contains(string(data), `"github-2"`) {
+					t.Errorf("グループの参照が書き換えられていません:\n%s", data)
+				}
+			}
+		})
+	}
+}
+
+func TestBundle_Import_StoreChangedWhileAsking(t *testing.T) {
+	source := setupSourceStore(t)
+	b, err := Export(source, ExportOptions{Profiles: []string{"work"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b = roundTrip(t, b)
+
+	cfg := newTestConfig(t)
+	writeTestFile(t, cfg.GetServerPath("github"), `{"name": "github", "description": null, "createdAt": "2023-01-01T00:00:00Z", "serverConfig": {"command": "docker"}}`)
+
+	// 回答を待つ間に別のコマンドが fetch を作成した場合
+	_, err = b.Import(cfg, ImportOptions{Resolve: func(c Collision) (Action, error) {
+		writeTestFile(t, cfg.GetServerPath("fetch"), `{"name": "fetch", "description": null, "createdAt": "2023-01-01T00:00:00Z", "serverConfig": {"command": "other"}}`)
+		return ActionOverwrite, nil
+	}})
+	if err == nil || !strings.Contains(err.Error(), "fetch") {
+		t.Fatalf("Import() error = %v, want the new collision reported", err)
+	}
+	template, err := server.NewManager(cfg.ServersDir).Load("github")
+	if err != nil || template.ServerConfig.Command != "docker" {
+		t.Errorf("github was written although the import failed: %+v, %v", template, err)
+	}
+}
+
+func TestBundle_ImportTwice(t *testing.T) {
+	source := setupSourceStore(t)
+	b, err := Export(source, ExportOptions{Profiles: []string{"work"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b = roundTrip(t, b)
+
+	cfg := newTestConfig(t)
+	writeTestFile(t, cfg.GetServerPath("github"), `{"name": "github", "description": null, "createdAt": "2023-01-01T00:00:00Z", "serverConfig": {"command": "docker"}}`)
+	rename := ImportOptions{Resolve: func(Collision) (Action, error) { return ActionRename, nil }}
+	if _, err := b.Import(cfg, rename); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	// 2回目は前回作成した github-2 をそのまま使う
+	result, err := b.Import(cfg, rename)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	for _, item := range result.Items {
+		if !item.Identical {
+			t.Errorf("%s %s was imported again: %+v", item.Kind, item.Name, item)
+		}
+		if item.Kind == KindTemplate && item.Name == "github" && item.As != "github-2" {
+			t.Errorf("github.As = %q, want github-2", item.As)
+		}
+	}
+	if utils.FileExists(cfg.GetServerPath("github-3")) {
+		t.Error("github-3 が作成されています")
+	}
+}
+
+func TestRead(t *testing.T) {
+	manifest := `{"version": 1}`
+	tests := []struct {
+		name    string
+		entries map[string]string
+		wantErr bool
+	}{
+		{name: "正常なアーカイブ", entries: map[string]string{ManifestName: manifest, "servers/a.jsonc": "{}"}},
+		{name: "マニフェストなし", entries: map[string]string{"servers/a.jsonc": "{}"}, wantErr: true},
+		{name: "ストア外へのパス", entries: map[string]string{ManifestName: manifest, "../evil.jsonc": "{}"}, wantErr: true},
+		{name: "不明なディレクトリ", entries: map[string]string{ManifestName: manifest, "secrets/a.jsonc": "{}"}, wantErr: true},
+		{name: "拡張子が異なる", entries: map[string]string{ManifestName: manifest, "servers/a.json": "{}"}, wantErr: true},
+		{name: "新しい形式", entries: map[string]string{ManifestName: `{"version": 99}`}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			gz := gzip.NewWriter(&buf)
+			tw := tar.NewWriter(gz)
+			for name, content := range tt.entries {
+				if err := writeTarFile(tw, name, []byte(content), time.Now()); err != nil {
+					t.Fatal(err)
+				}
+			}
+			tw.Close()
+			gz.Close()
+
+			_, err := Read(&buf)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Read() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
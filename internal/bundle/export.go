@@ -0,0 +1,304 @@
+package bundle
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/group"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/jsonedit"
+	"github.com/naoto24kawa/mcpjson/internal/profile"
+	"github.com/naoto24kawa/mcpjson/internal/sensitive"
+	"github.com/naoto24kawa/mcpjson/internal/server"
+	"github.com/tidwall/jsonc"
+)
+
+// invalidSecretChars matches characters not allowed in secret names
+var invalidSecretChars = regexp.MustCompile(`[^A-Za-z0-9_./-]`)
+
+// ExportOptions selects what Export packs
+type ExportOptions struct {
+	// Profiles are exported together with the profiles they extend and
+	// the templates they reference
+	Profiles []string
+	// All exports every profile, template and group in the store
+	All bool
+}
+
+// Export collects the items selected by opts from the stores of cfg, each
+// item read from the first store that has it. Literal
+// values of sensitive env vars, headers, profile variables, command-line
+// flags and URL query parameters are replaced with ${secret:...}
+// references listed in the manifest.
+func Export(cfg *config.Config, opts ExportOptions) (*Bundle, error) {
+	if !opts.All && len(opts.Profiles) == 0 {
+		return nil, i18n.Errorf("bundle.no_profiles")
+	}
+
+	b := newBundle()
+	b.Manifest.CreatedAt = time.Now().UTC()
+	secrets := make(map[string]bool)
+
+	profileNames := opts.Profiles
+	if opts.All {
+		var err error
+		if profileNames, err = listNames(cfg.ProfileDirs()...); err != nil {
+			return nil, err
+		}
+	}
+
+	profileManager := profile.NewManager(cfg.ProfilesDir)
+	profileManager.UseConfig(cfg)
+	templates := make(map[string]bool)
+	visited := make(map[string]bool)
+	var visit func(name string) error
+	visit = func(name string) error {
+		if visited[name] {
+			return nil
+		}
+		visited[name] = true
+
+		p, err := profileManager.Load(name)
+		if err != nil {
+			return err
+		}
+		for _, ref := range p.Servers {
+			if ref.Template != "" && !ref.Remove {
+				templates[ref.Template] = true
+			}
+		}
+		for _, parent := range p.Extends {
+			if err := visit(parent); err != nil {
+				return err
+			}
+		}
+
+		path := config.LookupPath(cfg.ProfileDirs(), cfg.ProfilesDir, name)
+		data, err := exportProfile(path, p, secrets)
+		if err != nil {
+			return err
+		}
+		b.add(KindProfile, name, data)
+		return nil
+	}
+	for _, name := range profileNames {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.All {
+		names, err := listNames(cfg.ServerDirs()...)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			templates[name] = true
+		}
+	}
+
+	serverManager := server.NewManager(cfg.ServersDir)
+	serverManager.UseConfig(cfg)
+	for _, name := range sortedKeys(templates) {
+		template, err := serverManager.Load(name)
+		if err != nil {
+			return nil, err
+		}
+		path := config.LookupPath(cfg.ServerDirs(), cfg.ServersDir, name)
+		data, err := exportTemplate(path, template, secrets)
+		if err != nil {
+			return nil, err
+		}
+		b.add(KindTemplate, name, data)
+	}
+
+	if err := exportGroups(cfg, b, opts.All, templates); err != nil {
+		return nil, err
+	}
+
+	b.Manifest.Secrets = sortedKeys(secrets)
+	b.updateManifest()
+	return b, nil
+}
+
+// exportGroups adds every group with --all, and otherwise the groups whose
+// servers are all included in the bundle
+func exportGroups(cfg *config.Config, b *Bundle, all bool, templates map[string]bool) error {
+	names, err := listNames(cfg.GroupDirs()...)
+	if err != nil {
+		return err
+	}
+
+	groupManager := group.NewManager(cfg.GroupsDir)
+	groupManager.UseConfig(cfg)
+	for _, name := range names {
+		g, err := groupManager.Load(name)
+		if err != nil {
+			return err
+		}
+
+		included := all || len(g.Servers) > 0
+		for _, serverName := range g.Servers {
+			if !templates[serverName] {
+				included = all
+				break
+			}
+		}
+		if !included {
+			continue
+		}
+
+		data, err := os.ReadFile(config.LookupPath(cfg.GroupDirs(), cfg.GroupsDir, name))
+		if err != nil {
+			return i18n.Errorf("group.load_failed", err)
+		}
+		b.add(KindGroup, name, data)
+	}
+	return nil
+}
+
+func exportProfile(path string, p *profile.Profile, secrets map[string]bool) ([]byte, error) {
+	changed := maskValues(p.Vars, p.Name+".vars.", secrets)
+	for i := range p.Servers {
+		ref := &p.Servers[i]
+		prefix := p.Name + "." + ref.Name + "."
+		for _, masked := range []bool{
+			maskValues(ref.Overrides.Env, prefix, secrets),
+			maskArgs(ref.Overrides.Args, prefix, secrets),
+			maskArgs(ref.Overrides.ArgsPrepend, prefix, secrets),
+			maskArgs(ref.Overrides.ArgsAppend, prefix, secrets),
+		} {
+			changed = changed || masked
+		}
+	}
+	return renderFile(path, p, changed)
+}
+
+func exportTemplate(path string, template *server.ServerTemplate, secrets map[string]bool) ([]byte, error) {
+	prefix := template.Name + "."
+	serverConfig := &template.ServerConfig
+	changed := false
+	for _, masked := range []bool{
+		maskValues(serverConfig.Env, prefix, secrets),
+		maskValues(serverConfig.Headers, prefix, secrets),
+		maskArgs(serverConfig.Args, prefix, secrets),
+		maskURL(&serverConfig.URL, prefix, secrets),
+	} {
+		changed = changed || masked
+	}
+	return renderFile(path, template, changed)
+}
+
+// maskValues replaces literal sensitive values in values with secret
+// references named prefix+key, and reports whether it replaced any
+func maskValues(values map[string]string, prefix string, secrets map[string]bool) bool {
+	return sensitive.Values(values, literalRef(prefix, secrets))
+}
+
+// maskArgs replaces literal values of sensitive flags with secret
+// references named prefix+"args."+flag
+func maskArgs(args []string, prefix string, secrets map[string]bool) bool {
+	return sensitive.Args(args, literalRef(prefix+"args.", secrets))
+}
+
+// maskURL replaces the password and the literal values of sensitive query
+// parameters in *rawURL with secret references named prefix+"url."+name
+func maskURL(rawURL *string, prefix string, secrets map[string]bool) bool {
+	return sensitive.URL(rawURL, literalRef(prefix+"url.", secrets))
+}
+
+// literalRef replaces literal values with references to secrets named
+// prefix+name, leaving values that already are references
+func literalRef(prefix string, secrets map[string]bool) sensitive.Replacer {
+	return func(name, value string) (string, bool) {
+		if !isLiteral(value) {
+			return "", false
+		}
+		return secretRef(prefix+name, secrets), true
+	}
+}
+
+// isLiteral reports whether value is set and is not already a reference
+func isLiteral(value string) bool {
+	return value != "" && !strings.Contains(value, "${")
+}
+
+// secretRef records the secret named name, with characters not allowed in
+// secret names replaced, and returns a reference to it
+func secretRef(name string, secrets map[string]bool) string {
+	name = invalidSecretChars.ReplaceAllString(name, "_")
+	secrets[name] = true
+	return "${secret:" + name + "}"
+}
+
+// renderFile returns the file at path, patched to match v when changed
+// so that its comments are kept
+func renderFile(path string, v interface{}, changed bool) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, i18n.Errorf("history.read_failed", err)
+	}
+	if !changed {
+		return data, nil
+	}
+	return patch(data, v)
+}
+
+// patch rewrites the JSONC document data to match v
+func patch(data []byte, v interface{}) ([]byte, error) {
+	doc, err := jsonedit.Parse(data)
+	if err != nil {
+		// 解析できない場合はコメントを残さずに書き出す
+		out, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(out, '\n'), nil
+	}
+	if err := doc.Patch(v); err != nil {
+		return nil, err
+	}
+	return doc.Bytes(), nil
+}
+
+// decode parses JSONC data into v
+func decode(data []byte, v interface{}) error {
+	return json.Unmarshal(jsonc.ToJSON(data), v)
+}
+
+// listNames returns the names of the JSONC files in dirs, each name once.
+// Missing directories are skipped.
+func listNames(dirs ...string) ([]string, error) {
+	seen := make(map[string]bool)
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, i18n.Errorf("bundle.read_dir_failed", err)
+		}
+		for _, e := range entries {
+			if e.IsDir() || filepath.Ext(e.Name()) != config.FileExtension {
+				continue
+			}
+			seen[strings.TrimSuffix(e.Name(), config.FileExtension)] = true
+		}
+	}
+	return sortedKeys(seen), nil
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,347 @@
+package bundle
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/filelock"
+	"github.com/naoto24kawa/mcpjson/internal/group"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/profile"
+	"github.com/naoto24kawa/mcpjson/internal/server"
+	"github.com/naoto24kawa/mcpjson/internal/utils"
+)
+
+// Action decides what happens to an item whose name is already used in
+// the store
+type Action string
+
+const (
+	ActionRename    Action = "rename"
+	ActionSkip      Action = "skip"
+	ActionOverwrite Action = "overwrite"
+)
+
+// ParseAction validates an action given on the command line
+func ParseAction(s string) (Action, error) {
+	switch action := Action(s); action {
+	case ActionRename, ActionSkip, ActionOverwrite:
+		return action, nil
+	}
+	return "", i18n.Errorf("mcpjson.unknown_conflict", s, ActionRename, ActionSkip, ActionOverwrite)
+}
+
+// Collision is an item of the bundle whose name is already used in the store
+type Collision struct {
+	Kind Kind
+	Name string
+}
+
+// ImportOptions controls Import
+type ImportOptions struct {
+	// Resolve is called for every collision. Items identical to the
+	// existing ones are skipped without calling it.
+	Resolve func(c Collision) (Action, error)
+}
+
+// ImportedItem records what happened to one item of the bundle
+type ImportedItem struct {
+	Kind Kind
+	Name string
+	// As is the name the item was imported as, when renamed, or the name
+	// of the identical copy it was skipped for
+	As     string
+	Action Action
+	// Identical is set for items skipped because the store already had them
+	Identical bool
+}
+
+// ImportResult lists the items of the bundle and what happened to them
+type ImportResult struct {
+	Items []ImportedItem
+}
+
+// Import writes the bundle into the store. Renamed templates and profiles
+// are followed by the imported profiles and groups that refer to them.
+// Either every file is written or none is.
+func (b *Bundle) Import(cfg *config.Config, opts ImportOptions) (*ImportResult, error) {
+	// 衝突の解決を尋ねる間はストアをロックせず、ロック後に同じ回答で計画し直す
+	answers := make(map[Collision]Action)
+	_, err := b.plan(cfg, func(c Collision) (Action, error) {
+		action := ActionRename
+		if opts.Resolve != nil {
+			var err error
+			if action, err = opts.Resolve(c); err != nil {
+				return "", err
+			}
+		}
+		answers[c] = action
+		return action, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	unlock, err := filelock.LockStore(cfg.ProfilesDir)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	p, err := b.plan(cfg, func(c Collision) (Action, error) {
+		action, ok := answers[c]
+		if !ok {
+			return "", i18n.Errorf("bundle.store_changed", c.Kind.Label(), c.Name)
+		}
+		return action, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	renames, writes := p.renames, p.writes
+
+	tx := utils.NewTransaction()
+	defer tx.Rollback()
+
+	for _, kind := range kinds {
+		if err := os.MkdirAll(filepath.Dir(storePath(cfg, kind, "x")), config.DefaultDirPerm); err != nil {
+			return nil, i18n.Errorf("common.mkdir_failed", err)
+		}
+		for _, name := range b.Names(kind) {
+			if !writes[kind][name] {
+				continue
+			}
+			data, err := b.rewrite(kind, name, renames)
+			if err != nil {
+				return nil, i18n.Errorf("bundle.convert_failed", kind.Label(), name, err)
+			}
+			target := name
+			if as, ok := renames[kind][name]; ok {
+				target = as
+			}
+			if err := tx.WriteFile(storePath(cfg, kind, target), data, 0644); err != nil {
+				return nil, i18n.Errorf("bundle.save_failed", kind.Label(), target, err)
+			}
+		}
+	}
+
+	tx.Commit()
+	return p.result, nil
+}
+
+// importPlan is what Import writes: the items with their actions, the
+// names items are imported as and the items that are written
+type importPlan struct {
+	result  *ImportResult
+	renames map[Kind]map[string]string
+	writes  map[Kind]map[string]bool
+}
+
+// plan resolves the collisions with the store and decides what Import
+// writes, without writing anything
+func (b *Bundle) plan(cfg *config.Config, resolve func(c Collision) (Action, error)) (*importPlan, error) {
+	result := &ImportResult{}
+	renames := make(map[Kind]map[string]string)
+	writes := make(map[Kind]map[string]bool)
+
+	// 名前の衝突を解決し、インポート後の名前を決める
+	for _, kind := range kinds {
+		renames[kind] = make(map[string]string)
+		writes[kind] = make(map[string]bool)
+		taken := make(map[string]bool)
+		for _, name := range b.Names(kind) {
+			taken[name] = true
+		}
+
+		for _, name := range b.Names(kind) {
+			item := ImportedItem{Kind: kind, Name: name}
+			path := storePath(cfg, kind, name)
+
+			// 先に解決した名前の変更を反映した内容で比較する
+			incoming, err := b.rewrite(kind, name, renames)
+			if err != nil {
+				return nil, i18n.Errorf("bundle.convert_failed", kind.Label(), name, err)
+			}
+
+			switch {
+			case !utils.FileExists(path):
+				writes[kind][name] = true
+			case identical(kind, incoming, path):
+				item.Action = ActionSkip
+				item.Identical = true
+			default:
+				action, err := resolve(Collision{Kind: kind, Name: name})
+				if err != nil {
+					return nil, err
+				}
+				item.Action = action
+
+				switch action {
+				case ActionRename:
+					// 以前のインポートで作られた同じ内容のコピーがあれば、それを使う
+					copyName, err := b.identicalCopy(cfg, kind, name, renames, taken)
+					if err != nil {
+						return nil, i18n.Errorf("bundle.convert_failed", kind.Label(), name, err)
+					}
+					if copyName != "" {
+						item.As = copyName
+						item.Identical = true
+						renames[kind][name] = copyName
+						break
+					}
+					item.As = freeName(cfg, kind, name, taken)
+					taken[item.As] = true
+					renames[kind][name] = item.As
+					writes[kind][name] = true
+				case ActionOverwrite:
+					writes[kind][name] = true
+				}
+			}
+			result.Items = append(result.Items, item)
+		}
+	}
+	return &importPlan{result: result, renames: renames, writes: writes}, nil
+}
+
+// rewrite returns the item's file with its own name and its references to
+// other items updated for renames
+func (b *Bundle) rewrite(kind Kind, name string, renames map[Kind]map[string]string) ([]byte, error) {
+	data := b.files[kind][name]
+	rename := func(k Kind, n string) string {
+		if as, ok := renames[k][n]; ok {
+			return as
+		}
+		return n
+	}
+
+	switch kind {
+	case KindTemplate:
+		template := &server.ServerTemplate{}
+		if err := decode(data, template); err != nil {
+			return nil, err
+		}
+		if as := rename(kind, name); as != template.Name {
+			template.Name = as
+			return patch(data, template)
+		}
+	case KindGroup:
+		g := &group.Group{}
+		if err := decode(data, g); err != nil {
+			return nil, err
+		}
+		changed := false
+		if as := rename(kind, name); as != g.Name {
+			g.Name = as
+			changed = true
+		}
+		for i, serverName := range g.Servers {
+			if as := rename(KindTemplate, serverName); as != serverName {
+				g.Servers[i] = as
+				changed = true
+			}
+		}
+		if changed {
+			return patch(data, g)
+		}
+	case KindProfile:
+		p := &profile.Profile{}
+		if err := decode(data, p); err != nil {
+			return nil, err
+		}
+		changed := false
+		if as := rename(kind, name); as != p.Name {
+			p.Name = as
+			changed = true
+		}
+		for i, parent := range p.Extends {
+			if as := rename(KindProfile, parent); as != parent {
+				p.Extends[i] = as
+				changed = true
+			}
+		}
+		for i, ref := range p.Servers {
+			if as := rename(KindTemplate, ref.Template); as != ref.Template {
+				p.Servers[i].Template = as
+				changed = true
+			}
+		}
+		if changed {
+			return patch(data, p)
+		}
+	}
+	return data, nil
+}
+
+// identical reports whether the file at path holds the same item as
+// incoming. Templates are compared by their server configuration only, so
+// that the same template created on another machine is not a collision.
+func identical(kind Kind, incoming []byte, path string) bool {
+	existing, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	if bytes.Equal(existing, incoming) {
+		return true
+	}
+	if kind != KindTemplate {
+		return false
+	}
+
+	var a, c server.ServerTemplate
+	if decode(existing, &a) != nil || decode(incoming, &c) != nil {
+		return false
+	}
+	return reflect.DeepEqual(a.ServerConfig, c.ServerConfig)
+}
+
+// identicalCopy returns the "<name>-N" in the store that holds the same
+// item as the bundle's name would when renamed to it, or "" when there is
+// none
+func (b *Bundle) identicalCopy(cfg *config.Config, kind Kind, name string, renames map[Kind]map[string]string, taken map[string]bool) (string, error) {
+	defer delete(renames[kind], name)
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s-%d", name, i)
+		if taken[candidate] {
+			continue
+		}
+		path := storePath(cfg, kind, candidate)
+		if !utils.FileExists(path) {
+			return "", nil
+		}
+
+		renames[kind][name] = candidate
+		incoming, err := b.rewrite(kind, name, renames)
+		if err != nil {
+			return "", err
+		}
+		if identical(kind, incoming, path) {
+			return candidate, nil
+		}
+	}
+}
+
+// freeName returns the first "<name>-N" used neither in the store nor by
+// another item of the bundle
+func freeName(cfg *config.Config, kind Kind, name string, taken map[string]bool) string {
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s-%d", name, i)
+		if !taken[candidate] && !utils.FileExists(storePath(cfg, kind, candidate)) {
+			return candidate
+		}
+	}
+}
+
+func storePath(cfg *config.Config, kind Kind, name string) string {
+	switch kind {
+	case KindProfile:
+		return cfg.GetProfilePath(name)
+	case KindTemplate:
+		return cfg.GetServerPath(name)
+	default:
+		return cfg.GetGroupPath(name)
+	}
+}
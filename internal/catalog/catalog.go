@@ -0,0 +1,214 @@
+// Package catalog reads catalogs: JSON indexes of known MCP servers from
+// which 'server install' creates templates. A catalog is read from a local
+// file or an http(s) URL.
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/server"
+	"github.com/tidwall/jsonc"
+)
+
+const (
+	// FetchTimeout bounds the download of a catalog
+	FetchTimeout = 10 * time.Second
+
+	// maxSize is the largest catalog that is read
+	maxSize = 10 << 20
+)
+
+// Catalog is the contents of a catalog file
+type Catalog struct {
+	Name    string  `json:"name,omitempty"`
+	Servers []Entry `json:"servers"`
+}
+
+// Entry describes a server that can be installed as a template. Stdio
+// servers set Command and Args, remote servers Type and URL.
+type Entry struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Homepage    string   `json:"homepage,omitempty"`
+	Type        string   `json:"type,omitempty"`
+	Command     string   `json:"command,omitempty"`
+	Args        []string `json:"args,omitempty"`
+	URL         string   `json:"url,omitempty"`
+	Env         []EnvVar `json:"env,omitempty"`
+	// Source is the file or URL the entry was read from
+	Source string `json:"source,omitempty"`
+}
+
+// EnvVar is an environment variable the server reads. Secret values are
+// stored in the secret vault rather than in the template.
+type EnvVar struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+	Secret      bool   `json:"secret,omitempty"`
+	Default     string `json:"default,omitempty"`
+}
+
+// Load reads the catalog at source, a file path or an http(s) URL
+func Load(ctx context.Context, source string) (*Catalog, error) {
+	data, err := read(ctx, source)
+	if err != nil {
+		return nil, i18n.Errorf("catalog.read_failed", source, err)
+	}
+
+	catalog := &Catalog{}
+	if err := json.Unmarshal(jsonc.ToJSON(data), catalog); err != nil {
+		return nil, i18n.Errorf("catalog.parse_failed", source, err)
+	}
+	for i := range catalog.Servers {
+		entry := &catalog.Servers[i]
+		if entry.ID == "" {
+			return nil, i18n.Errorf("catalog.entry_no_id", source, i+1)
+		}
+		entry.Source = source
+	}
+	return catalog, nil
+}
+
+func read(ctx context.Context, source string) ([]byte, error) {
+	if !IsURL(source) {
+		return os.ReadFile(source)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, FetchTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, i18n.Errorf("catalog.http_status", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxSize))
+}
+
+// IsURL reports whether source is read over http(s) rather than from a file
+func IsURL(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+// Index is the entries of several catalogs, in the order of their sources
+type Index struct {
+	Entries []Entry
+}
+
+// Open reads the catalogs of the settings file, or sources instead when
+// given
+func Open(ctx context.Context, cfg *config.Config, sources []string, log io.Writer) (*Index, error) {
+	if len(sources) == 0 {
+		var err error
+		if sources, err = cfg.CatalogSources(); err != nil {
+			return nil, err
+		}
+	}
+	return LoadAll(ctx, sources, log)
+}
+
+// LoadAll reads every source. A source that cannot be read is reported on
+// log and skipped, so that one unreachable catalog does not hide the
+// others; it is an error only when none can be read.
+func LoadAll(ctx context.Context, sources []string, log io.Writer) (*Index, error) {
+	if len(sources) == 0 {
+		return nil, i18n.Errorf("catalog.no_sources")
+	}
+
+	index := &Index{Entries: []Entry{}}
+	var lastErr error
+	loaded := 0
+	for _, source := range sources {
+		catalog, err := Load(ctx, source)
+		if err != nil {
+			fmt.Fprintln(log, i18n.T("catalog.skipped", err))
+			lastErr = err
+			continue
+		}
+		loaded++
+		index.Entries = append(index.Entries, catalog.Servers...)
+	}
+	if loaded == 0 {
+		return nil, lastErr
+	}
+	return index, nil
+}
+
+// Search returns the entries whose id, name, description or tags contain
+// every word of term, ignoring case. An empty term matches every entry.
+func (i *Index) Search(term string) []Entry {
+	words := strings.Fields(strings.ToLower(term))
+	matches := []Entry{}
+	for _, entry := range i.Entries {
+		text := strings.ToLower(strings.Join(append([]string{entry.ID, entry.Name, entry.Description}, entry.Tags...), " "))
+		matched := true
+		for _, word := range words {
+			if !strings.Contains(text, word) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			matches = append(matches, entry)
+		}
+	}
+	return matches
+}
+
+// Find returns the entry with the given id. When several catalogs have it
+// the first source wins.
+func (i *Index) Find(id string) (*Entry, error) {
+	for _, entry := range i.Entries {
+		if entry.ID == id {
+			return &entry, nil
+		}
+	}
+	return nil, i18n.Errorf("catalog.not_found", id)
+}
+
+// Server returns the server configuration of the entry with the given
+// environment
+func (e *Entry) Server(env map[string]string) server.MCPServer {
+	mcpServer := server.MCPServer{Type: e.Type, Command: e.Command, Args: e.Args, URL: e.URL}
+	if len(env) > 0 {
+		mcpServer.Env = env
+	}
+	return mcpServer
+}
+
+// Target is the command of a stdio server and the url of a remote one
+func (e *Entry) Target() string {
+	if e.URL != "" {
+		return e.URL
+	}
+	return strings.TrimSpace(e.Command + " " + strings.Join(e.Args, " "))
+}
+
+// RequiredEnv returns the names of the required environment variables
+func (e *Entry) RequiredEnv() []string {
+	names := []string{}
+	for _, v := range e.Env {
+		if v.Required {
+			names = append(names, v.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,144 @@
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testCatalog = `{
+  // コメントも使える
+  "name": "local",
+  "servers": [
+    {"id": "github", "name": "GitHub", "description": "Issues and pull requests", "tags": ["git"],
+     "command": "npx", "args": ["-y", "@modelcontextprotocol/server-github"],
+     "env": [{"name": "GITHUB_TOKEN", "required": true, "secret": true}]},
+    {"id": "fetch", "description": "Fetches web pages", "command": "uvx", "args": ["mcp-server-fetch"]}
+  ]
+}`
+
+func writeCatalog(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "catalog.jsonc")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/catalog.json" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"servers": [{"id": "remote", "type": "http", "url": "https://example.com/mcp"}]}`))
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name    string
+		source  string
+		wantIDs string
+		wantErr string
+	}{
+		{name: "ファイル", source: writeCatalog(t, testCatalog), wantIDs: "github,fetch"},
+		{name: "URL", source: ts.URL + "/catalog.json", wantIDs: "remote"},
+		{name: "URL が見つからない", source: ts.URL + "/missing.json", wantErr: "404"},
+		{name: "存在しないファイル", source: filepath.Join(t.TempDir(), "missing.json"), wantErr: "missing.json"},
+		{name: "不正な JSON", source: writeCatalog(t, `{"servers": [`), wantErr: "catalog.jsonc"},
+		{name: "id のないサーバー", source: writeCatalog(t, `{"servers": [{"command": "npx"}]}`), wantErr: "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			catalog, err := Load(context.Background(), tt.source)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Load() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ids []string
+			for _, entry := range catalog.Servers {
+				ids = append(ids, entry.ID)
+				if entry.Source != tt.source {
+					t.Errorf("Source = %s, want %s", entry.Source, tt.source)
+				}
+			}
+			if got := strings.Join(ids, ","); got != tt.wantIDs {
+				t.Errorf("ids = %s, want %s", got, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestLoadAll(t *testing.T) {
+	first := writeCatalog(t, testCatalog)
+	second := writeCatalog(t, `{"servers": [{"id": "github", "command": "docker"}, {"id": "slack", "command": "npx"}]}`)
+	missing := filepath.Join(t.TempDir(), "missing.json")
+
+	var log bytes.Buffer
+	index, err := LoadAll(context.Background(), []string{first, missing, second}, &log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(log.String(), "missing.json") {
+		t.Errorf("log = %q, want the unreadable catalog reported", log.String())
+	}
+
+	entry, err := index.Find("github")
+	if err != nil || entry.Command != "npx" {
+		t.Errorf("Find() = %+v, %v, want the entry of the first catalog", entry, err)
+	}
+	if _, err := index.Find("unknown"); err == nil {
+		t.Error("Find() should fail for an unknown id")
+	}
+
+	if _, err := LoadAll(context.Background(), []string{missing}, &log); err == nil {
+		t.Error("LoadAll() should fail when no catalog can be read")
+	}
+	if _, err := LoadAll(context.Background(), nil, &log); err == nil {
+		t.Error("LoadAll() should fail without sources")
+	}
+}
+
+func TestIndex_Search(t *testing.T) {
+	catalog, err := Load(context.Background(), writeCatalog(t, testCatalog))
+	if err != nil {
+		t.Fatal(err)
+	}
+	index := &Index{Entries: catalog.Servers}
+
+	tests := []struct {
+		name string
+		term string
+		want string
+	}{
+		{name: "未指定はすべて", term: "", want: "github,fetch"},
+		{name: "大文字小文字を区別しない", term: "PULL", want: "github"},
+		{name: "タグ", term: "git", want: "github"},
+		{name: "すべての語を含む", term: "web fetch", want: "fetch"},
+		{name: "一致なし", term: "database", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ids []string
+			for _, entry := range index.Search(tt.term) {
+				ids = append(ids, entry.ID)
+			}
+			if got := strings.Join(ids, ","); got != tt.want {
+				t.Errorf("Search(%q) = %s, want %s", tt.term, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,9 +1,10 @@
 package config
 
 import (
-	"fmt"
 	"os"
 	"path/filepath"
+
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
 )
 
 const (
@@ -18,31 +19,74 @@ const (
 	DefaultProfileName = "default"
 )
 
+// Config locates the stores. BaseDir and the directories next to it are
+// the store commands write to; reads fall through Stores in order.
 type Config struct {
 	BaseDir     string
 	ProfilesDir string
 	ServersDir  string
 	GroupsDir   string
+	// HomeDir is the personal store. It also holds the settings, secrets,
+	// state and history whichever store is selected.
+	HomeDir string
+	// Store is the name of the store commands write to
+	Store string
+	// Stores are the stores reads fall through: project, the named stores
+	// of the settings file, then personal
+	Stores []Store
+	// Project is the project file found from the working directory, or
+	// nil outside a project
+	Project *Project
 }
 
 func New() (*Config, error) {
-	homeDir, err := os.UserHomeDir()
+	return load(true)
+}
+
+// NewReadOnly is New for callers that only read the stores, such as shell
+// completion: it does not create the store directories.
+func NewReadOnly() (*Config, error) {
+	return load(false)
+}
+
+func load(create bool) (*Config, error) {
+	homeDir, err := PersonalDir()
 	if err != nil {
-		return nil, fmt.Errorf("ホームディレクトリの取得に失敗しました: %w", err)
+		return nil, err
 	}
 
-	baseDir := filepath.Join(homeDir, ConfigDirName)
-
 	cfg := &Config{
-		BaseDir:     baseDir,
-		ProfilesDir: filepath.Join(baseDir, ProfilesDir),
-		ServersDir:  filepath.Join(baseDir, ServersDir),
-		GroupsDir:   filepath.Join(baseDir, GroupsDir),
+		BaseDir:     homeDir,
+		ProfilesDir: filepath.Join(homeDir, ProfilesDir),
+		ServersDir:  filepath.Join(homeDir, ServersDir),
+		GroupsDir:   filepath.Join(homeDir, GroupsDir),
+		HomeDir:     homeDir,
+		Store:       StorePersonal,
+	}
+
+	if create {
+		if err := cfg.ensureDirectories(); err != nil {
+			return nil, err
+		}
+	}
+
+	if wd, err := os.Getwd(); err == nil {
+		if cfg.Project, err = FindProject(wd); err != nil {
+			return nil, err
+		}
 	}
 
-	if err := cfg.ensureDirectories(); err != nil {
+	if cfg.Stores, err = cfg.loadStores(); err != nil {
 		return nil, err
 	}
+	if err := cfg.selectStore(selectedStore); err != nil {
+		return nil, err
+	}
+	if create {
+		if err := cfg.ensureDirectories(); err != nil {
+			return nil, err
+		}
+	}
 
 	return cfg, nil
 }
@@ -52,7 +96,7 @@ func (c *Config) ensureDirectories() error {
 
 	for _, dir := range dirs {
 		if err := os.MkdirAll(dir, DefaultDirPerm); err != nil {
-			return fmt.Errorf("ディレクトリの作成に失敗しました %s: %w", dir, err)
+			return i18n.Errorf("config.mkdir_failed", dir, err)
 		}
 	}
 
@@ -17,6 +17,8 @@ func TestNew(t *testing.T) {
 
 	os.Setenv("HOME", tempDir)
 	os.Setenv("USERPROFILE", tempDir)
+	t.Setenv(EnvHome, "")
+	t.Setenv(EnvXDGConfigHome, "")
 
 	defer func() {
 		os.Setenv("HOME", originalHome)
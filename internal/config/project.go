@@ -0,0 +1,142 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/tidwall/jsonc"
+)
+
+// ProjectFileName is the project file looked up from the working directory
+// towards the filesystem root
+const ProjectFileName = ".mcpjson" + FileExtension
+
+// Project is a .mcpjson.jsonc file that scopes mcpjson to a repository.
+// Relative paths in it are relative to the directory holding the file.
+type Project struct {
+	// Path is the location of the project file
+	Path string `json:"-"`
+	// Profile is used when a command is given no profile name
+	Profile string `json:"profile,omitempty"`
+	// Targets are the MCP config files apply writes when --to is omitted
+	Targets []string `json:"targets,omitempty"`
+	// Templates is a directory of server templates that shadow the
+	// global templates of the same name
+	Templates string `json:"templates,omitempty"`
+	// Store is a directory laid out like the personal store whose
+	// profiles and templates take precedence over every other store
+	Store string `json:"store,omitempty"`
+	// Vars are values for ${profile.name} placeholders. They take
+	// precedence over the variables defined in the profile.
+	Vars map[string]string `json:"vars,omitempty"`
+}
+
+// FindProject looks for the project file in dir and its parents. It
+// returns nil when there is none.
+func FindProject(dir string) (*Project, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		path := filepath.Join(dir, ProjectFileName)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return LoadProject(path)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// LoadProject reads the project file at path
+func LoadProject(path string) (*Project, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, i18n.Errorf("config.project_read_failed", path, err)
+	}
+
+	project := &Project{}
+	if err := json.Unmarshal(jsonc.ToJSON(data), project); err != nil {
+		return nil, i18n.Errorf("config.project_parse_failed", path, err)
+	}
+	if project.Path, err = filepath.Abs(path); err != nil {
+		return nil, err
+	}
+
+	if project.Templates != "" {
+		if info, err := os.Stat(project.TemplatesDir()); err != nil || !info.IsDir() {
+			return nil, i18n.Errorf("config.project_templates_not_dir", project.TemplatesDir(), path)
+		}
+	}
+	for _, target := range project.Targets {
+		if target == "" {
+			return nil, i18n.Errorf("config.project_empty_target", path)
+		}
+	}
+
+	return project, nil
+}
+
+// Dir returns the directory that holds the project file
+func (p *Project) Dir() string {
+	return filepath.Dir(p.Path)
+}
+
+// TemplatesDir returns the absolute path of the project template
+// directory, or "" when the project has none
+func (p *Project) TemplatesDir() string {
+	if p.Templates == "" {
+		return ""
+	}
+	return p.resolve(p.Templates)
+}
+
+// StoreDir returns the absolute path of the project store, or "" when the
+// project has none
+func (p *Project) StoreDir() string {
+	if p.Store == "" {
+		return ""
+	}
+	return p.resolve(p.Store)
+}
+
+// TargetPaths returns the absolute paths of the project's targets
+func (p *Project) TargetPaths() []string {
+	paths := make([]string, 0, len(p.Targets))
+	for _, target := range p.Targets {
+		paths = append(paths, p.resolve(target))
+	}
+	return paths
+}
+
+func (p *Project) resolve(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(p.Dir(), path)
+}
+
+// DefaultProfile returns the profile used when a command is given no
+// profile name: the project's profile, or "default"
+func (c *Config) DefaultProfile() string {
+	if c.Project != nil && c.Project.Profile != "" {
+		return c.Project.Profile
+	}
+	return DefaultProfileName
+}
+
+// DefaultTargets returns the MCP config files apply writes when --to is
+// omitted: the project's targets, or ./.mcp.json
+func (c *Config) DefaultTargets() []string {
+	if c.Project != nil && len(c.Project.Targets) > 0 {
+		return c.Project.TargetPaths()
+	}
+	return []string{GetDefaultMCPPath()}
+}
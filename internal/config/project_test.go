@@ -0,0 +1,133 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeProject(t *testing.T, dir, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, ProjectFileName)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFindProject(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "src", "pkg")
+	if err := os.MkdirAll(filepath.Join(root, ".mcp", "servers"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeProject(t, root, `{
+		// プロジェクトの既定値
+		"profile": "web",
+		"targets": [".mcp.json", "/abs/.cursor/mcp.json"],
+		"templates": ".mcp/servers",
+		"vars": {"db": "postgres://localhost/app"},
+	}`)
+
+	project, err := FindProject(nested)
+	if err != nil {
+		t.Fatalf("FindProject() error = %v", err)
+	}
+	if project == nil {
+		t.Fatal("FindProject() returned nil, want the project in the parent directory")
+	}
+
+	if project.Dir() != root {
+		t.Errorf("Dir() = %q, want %q", project.Dir(), root)
+	}
+	if project.Profile != "web" {
+		t.Errorf("Profile = %q, want %q", project.Profile, "web")
+	}
+	wantTargets := []string{filepath.Join(root, ".mcp.json"), "/abs/.cursor/mcp.json"}
+	if got := project.TargetPaths(); strings.Join(got, ",") != strings.Join(wantTargets, ",") {
+		t.Errorf("TargetPaths() = %v, want %v", got, wantTargets)
+	}
+	if got, want := project.TemplatesDir(), filepath.Join(root, ".mcp", "servers"); got != want {
+		t.Errorf("TemplatesDir() = %q, want %q", got, want)
+	}
+	if project.Vars["db"] != "postgres://localhost/app" {
+		t.Errorf("Vars = %v", project.Vars)
+	}
+}
+
+func TestFindProject_NotFound(t *testing.T) {
+	project, err := FindProject(t.TempDir())
+	if err != nil {
+		t.Fatalf("FindProject() error = %v", err)
+	}
+	if project != nil {
+		t.Errorf("FindProject() = %+v, want nil", project)
+	}
+}
+
+func TestLoadProject_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "不正なJSON", content: `{"profile": }`, wantErr: ProjectFileName},
+		{name: "存在しないテンプレートディレクトリ", content: `{"templates": "missing"}`, wantErr: "missing"},
+		{name: "空のターゲット", content: `{"targets": [""]}`, wantErr: ProjectFileName},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeProject(t, t.TempDir(), tt.content)
+			_, err := LoadProject(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadProject() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_Defaults(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name        string
+		project     *Project
+		wantProfile string
+		wantTargets []string
+	}{
+		{
+			name:        "プロジェクトなし",
+			wantProfile: DefaultProfileName,
+			wantTargets: []string{GetDefaultMCPPath()},
+		},
+		{
+			name:        "空のプロジェクト",
+			project:     &Project{Path: filepath.Join(dir, ProjectFileName)},
+			wantProfile: DefaultProfileName,
+			wantTargets: []string{GetDefaultMCPPath()},
+		},
+		{
+			name:        "プロジェクトの既定値",
+			project:     &Project{Path: filepath.Join(dir, ProjectFileName), Profile: "web", Targets: []string{".mcp.json"}},
+			wantProfile: "web",
+			wantTargets: []string{filepath.Join(dir, ".mcp.json")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Project: tt.project}
+			if got := cfg.DefaultProfile(); got != tt.wantProfile {
+				t.Errorf("DefaultProfile() = %q, want %q", got, tt.wantProfile)
+			}
+			if got := cfg.DefaultTargets(); strings.Join(got, ",") != strings.Join(tt.wantTargets, ",") {
+				t.Errorf("DefaultTargets() = %v, want %v", got, tt.wantTargets)
+			}
+		})
+	}
+}
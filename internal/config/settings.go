@@ -0,0 +1,141 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/tidwall/jsonc"
+)
+
+const (
+	SettingsFileName = "settings" + FileExtension
+	SecretsDir       = "secrets"
+	StateDir         = "state"
+	HistoryDir       = ".history"
+
+	SecretProviderVault = "vault"
+	SecretProviderExec  = "exec"
+
+	DefaultHistoryMaxEntries = 50
+	DefaultHistoryMaxAgeDays = 30
+)
+
+// Settings holds user preferences read from settings.jsonc in the base directory
+type Settings struct {
+	Secrets  SecretSettings   `json:"secrets"`
+	History  HistorySettings  `json:"history"`
+	Stores   []StoreSettings  `json:"stores,omitempty"`
+	MCPServe MCPServeSettings `json:"mcpServe"`
+	// Catalogs are the files and http(s) URLs of the server catalogs
+	// 'server search' and 'server install' read
+	Catalogs []string `json:"catalogs,omitempty"`
+}
+
+// StoreSettings names a shared store, such as a clone of a team
+// repository. A relative Path is relative to the personal store.
+type StoreSettings struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+// SecretSettings selects the backend that resolves ${secret:name} placeholders.
+// Provider is "vault" (the default) or "exec"; Command is the command run by
+// the exec provider, where "{name}" is replaced with the secret name.
+type SecretSettings struct {
+	Provider string   `json:"provider,omitempty"`
+	Command  []string `json:"command,omitempty"`
+}
+
+// MCPServeSettings controls which tools 'mcp-serve' offers. Allow lists
+// tool names, "*" allows every tool; an empty list only allows the tools
+// that do not change any file.
+type MCPServeSettings struct {
+	Allow []string `json:"allow,omitempty"`
+}
+
+// HistorySettings controls how long backups taken before destructive
+// operations are kept. An entry is pruned once there are more than
+// MaxEntries newer entries or it is older than MaxAgeDays; a negative
+// value disables that limit.
+type HistorySettings struct {
+	MaxEntries int `json:"maxEntries,omitempty"`
+	MaxAgeDays int `json:"maxAgeDays,omitempty"`
+}
+
+// SettingsPath returns the path of the settings file
+func (c *Config) SettingsPath() string {
+	return filepath.Join(c.homeDir(), SettingsFileName)
+}
+
+// SecretsDir returns the directory that holds the secret vault
+func (c *Config) SecretsDir() string {
+	return filepath.Join(c.homeDir(), SecretsDir)
+}
+
+// StateDir returns the directory for state mcpjson keeps between runs,
+// such as which servers it wrote to each MCP config file
+func (c *Config) StateDir() string {
+	return filepath.Join(c.homeDir(), StateDir)
+}
+
+// HistoryDir returns the directory of the operation journal
+func (c *Config) HistoryDir() string {
+	return filepath.Join(c.homeDir(), HistoryDir)
+}
+
+// CatalogSources returns the catalogs of the settings file. A relative
+// path is relative to the personal store, like the path of a store.
+func (c *Config) CatalogSources() ([]string, error) {
+	settings, err := c.LoadSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make([]string, 0, len(settings.Catalogs))
+	for _, source := range settings.Catalogs {
+		if !strings.Contains(source, "://") {
+			source = expandHome(source)
+			if !filepath.IsAbs(source) {
+				source = filepath.Join(c.homeDir(), source)
+			}
+		}
+		sources = append(sources, source)
+	}
+	return sources, nil
+}
+
+// LoadSettings reads the settings file. A missing file yields the defaults.
+func (c *Config) LoadSettings() (*Settings, error) {
+	settings := &Settings{}
+
+	data, err := os.ReadFile(c.SettingsPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			settings.applyDefaults()
+			return settings, nil
+		}
+		return nil, i18n.Errorf("config.settings_read_failed", err)
+	}
+
+	if err := json.Unmarshal(jsonc.ToJSON(data), settings); err != nil {
+		return nil, i18n.Errorf("config.settings_parse_failed", c.SettingsPath(), err)
+	}
+	settings.applyDefaults()
+
+	return settings, nil
+}
+
+func (s *Settings) applyDefaults() {
+	if s.Secrets.Provider == "" {
+		s.Secrets.Provider = SecretProviderVault
+	}
+	if s.History.MaxEntries == 0 {
+		s.History.MaxEntries = DefaultHistoryMaxEntries
+	}
+	if s.History.MaxAgeDays == 0 {
+		s.History.MaxAgeDays = DefaultHistoryMaxAgeDays
+	}
+}
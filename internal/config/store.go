@@ -0,0 +1,231 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+)
+
+const (
+	// EnvHome overrides the location of the personal store
+	EnvHome = "MCPJSON_HOME"
+	// EnvXDGConfigHome is the XDG base directory for configuration
+	EnvXDGConfigHome = "XDG_CONFIG_HOME"
+	// XDGDirName is the name of the personal store under the XDG base directory
+	XDGDirName = "mcpjson"
+
+	// StorePersonal is the store in the user's home directory
+	StorePersonal = "personal"
+	// StoreProject is the store declared by the project file
+	StoreProject = "project"
+)
+
+// selectedStore is the store commands write to, set by the --store flag
+var selectedStore string
+
+// UseStore selects the store that configurations created afterwards write
+// to. An empty name selects the personal store.
+func UseStore(name string) {
+	selectedStore = name
+}
+
+// Store is a directory holding profiles, server templates and groups
+type Store struct {
+	Name string
+	Dir  string
+}
+
+// ProfilesDir returns the profile directory of the store
+func (s Store) ProfilesDir() string {
+	return filepath.Join(s.Dir, ProfilesDir)
+}
+
+// ServersDir returns the server template directory of the store
+func (s Store) ServersDir() string {
+	return filepath.Join(s.Dir, ServersDir)
+}
+
+// GroupsDir returns the group directory of the store
+func (s Store) GroupsDir() string {
+	return filepath.Join(s.Dir, GroupsDir)
+}
+
+// PersonalDir returns the location of the personal store. MCPJSON_HOME
+// takes precedence; otherwise $XDG_CONFIG_HOME/mcpjson is used when it
+// exists, or when XDG_CONFIG_HOME is set and there is no ~/.mcpconfig.
+func PersonalDir() (string, error) {
+	if dir := os.Getenv(EnvHome); dir != "" {
+		return filepath.Abs(expandHome(dir))
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", i18n.Errorf("config.home_dir_failed", err)
+	}
+
+	xdgHome := os.Getenv(EnvXDGConfigHome)
+	xdgDir := filepath.Join(homeDir, ".config", XDGDirName)
+	if xdgHome != "" {
+		xdgDir = filepath.Join(xdgHome, XDGDirName)
+	}
+	if dirExists(xdgDir) {
+		return xdgDir, nil
+	}
+
+	legacyDir := filepath.Join(homeDir, ConfigDirName)
+	if xdgHome == "" || dirExists(legacyDir) {
+		return legacyDir, nil
+	}
+	return xdgDir, nil
+}
+
+// loadStores returns the stores reads fall through: the project store,
+// the named stores of the settings file in order, then the personal store
+func (c *Config) loadStores() ([]Store, error) {
+	stores := []Store{}
+	if c.Project != nil && c.Project.Store != "" {
+		stores = append(stores, Store{Name: StoreProject, Dir: c.Project.StoreDir()})
+	}
+
+	settings, err := c.LoadSettings()
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{StoreProject: true, StorePersonal: true}
+	for _, s := range settings.Stores {
+		if s.Name == "" || s.Path == "" {
+			return nil, i18n.Errorf("config.store_incomplete", c.SettingsPath())
+		}
+		if seen[s.Name] {
+			return nil, i18n.Errorf("config.store_duplicate", s.Name, c.SettingsPath())
+		}
+		seen[s.Name] = true
+
+		dir := expandHome(s.Path)
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(c.homeDir(), dir)
+		}
+		stores = append(stores, Store{Name: s.Name, Dir: dir})
+	}
+
+	return append(stores, Store{Name: StorePersonal, Dir: c.homeDir()}), nil
+}
+
+// selectStore points the store directories of c at the named store
+func (c *Config) selectStore(name string) error {
+	if name == "" {
+		name = StorePersonal
+	}
+	for _, store := range c.Stores {
+		if store.Name == name {
+			c.Store = store.Name
+			c.BaseDir = store.Dir
+			c.ProfilesDir = store.ProfilesDir()
+			c.ServersDir = store.ServersDir()
+			c.GroupsDir = store.GroupsDir()
+			return nil
+		}
+	}
+
+	names := make([]string, 0, len(c.Stores))
+	for _, store := range c.Stores {
+		names = append(names, store.Name)
+	}
+	return i18n.Errorf("config.unknown_store", name, strings.Join(names, ", "))
+}
+
+// ProfileDirs returns the profile directories in lookup order
+func (c *Config) ProfileDirs() []string {
+	return c.storeDirs(Store.ProfilesDir, c.ProfilesDir)
+}
+
+// ServerDirs returns the server template directories in lookup order. The
+// project's template directory comes before every store.
+func (c *Config) ServerDirs() []string {
+	dirs := c.storeDirs(Store.ServersDir, c.ServersDir)
+	if c.Project != nil && c.Project.Templates != "" {
+		dirs = append([]string{c.Project.TemplatesDir()}, dirs...)
+	}
+	return dirs
+}
+
+// GroupDirs returns the group directories in lookup order
+func (c *Config) GroupDirs() []string {
+	return c.storeDirs(Store.GroupsDir, c.GroupsDir)
+}
+
+func (c *Config) storeDirs(dir func(Store) string, fallback string) []string {
+	if len(c.Stores) == 0 {
+		return []string{fallback}
+	}
+	dirs := make([]string, 0, len(c.Stores))
+	for _, store := range c.Stores {
+		dirs = append(dirs, dir(store))
+	}
+	return dirs
+}
+
+// homeDir returns the personal store, which also holds the settings,
+// secrets, state and history whichever store is selected
+func (c *Config) homeDir() string {
+	if c.HomeDir != "" {
+		return c.HomeDir
+	}
+	return c.BaseDir
+}
+
+// ListNames returns the sorted names of the .jsonc files in dirs. A name
+// found in several directories is listed once; missing directories are
+// skipped.
+func ListNames(dirs ...string) []string {
+	seen := map[string]bool{}
+	names := []string{}
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			if entry.IsDir() || !strings.HasSuffix(name, FileExtension) {
+				continue
+			}
+			name = strings.TrimSuffix(name, FileExtension)
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupPath returns the path of the named file in the first of dirs that
+// has it, or its path in fallback when none does
+func LookupPath(dirs []string, fallback, name string) string {
+	for _, dir := range dirs {
+		path := filepath.Join(dir, name+FileExtension)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return filepath.Join(fallback, name+FileExtension)
+}
+
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[1:])
+		}
+	}
+	return path
+}
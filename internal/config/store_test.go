@@ -0,0 +1,184 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPersonalDir(t *testing.T) {
+	tests := []struct {
+		name   string
+		env    map[string]string
+		mkdirs []string
+		want   string
+	}{
+		{
+			name: "MCPJSON_HOME を優先",
+			env:  map[string]string{EnvHome: "store", EnvXDGConfigHome: "xdg"},
+			want: "store",
+		},
+		{
+			name: "既定は ~/.mcpconfig",
+			want: "home/.mcpconfig",
+		},
+		{
+			name:   "~/.config/mcpjson が存在する",
+			mkdirs: []string{"home/.config/mcpjson"},
+			want:   "home/.config/mcpjson",
+		},
+		{
+			name: "XDG_CONFIG_HOME のみ設定",
+			env:  map[string]string{EnvXDGConfigHome: "xdg"},
+			want: "xdg/mcpjson",
+		},
+		{
+			name:   "XDG_CONFIG_HOME より既存の ~/.mcpconfig を優先",
+			env:    map[string]string{EnvXDGConfigHome: "xdg"},
+			mkdirs: []string{"home/.mcpconfig"},
+			want:   "home/.mcpconfig",
+		},
+		{
+			name:   "既存の XDG ディレクトリを優先",
+			env:    map[string]string{EnvXDGConfigHome: "xdg"},
+			mkdirs: []string{"home/.mcpconfig", "xdg/mcpjson"},
+			want:   "xdg/mcpjson",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			t.Setenv("HOME", filepath.Join(root, "home"))
+			t.Setenv(EnvHome, "")
+			t.Setenv(EnvXDGConfigHome, "")
+			for key, value := range tt.env {
+				t.Setenv(key, filepath.Join(root, value))
+			}
+			for _, dir := range tt.mkdirs {
+				if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := PersonalDir()
+			if err != nil {
+				t.Fatalf("PersonalDir() error = %v", err)
+			}
+			if want := filepath.Join(root, tt.want); got != want {
+				t.Errorf("PersonalDir() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestNew_Stores(t *testing.T) {
+	root := t.TempDir()
+	home := filepath.Join(root, "personal")
+	t.Setenv(EnvHome, home)
+	if err := os.MkdirAll(home, 0755); err != nil {
+		t.Fatal(err)
+	}
+	settings := `{
+		"stores": [
+			// チーム共有のリポジトリ
+			{"name": "team", "path": "../team"}
+		]
+	}`
+	if err := os.WriteFile(filepath.Join(home, SettingsFileName), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+	team := filepath.Join(root, "team")
+
+	tests := []struct {
+		name      string
+		store     string
+		wantStore string
+		wantBase  string
+		wantErr   bool
+	}{
+		{name: "既定は personal", wantStore: StorePersonal, wantBase: home},
+		{name: "名前付きストア", store: "team", wantStore: "team", wantBase: team},
+		{name: "存在しないストア", store: "unknown", wantErr: true},
+		{name: "プロジェクト外の project", store: StoreProject, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			UseStore(tt.store)
+			defer UseStore("")
+
+			cfg, err := New()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if cfg.Store != tt.wantStore || cfg.BaseDir != tt.wantBase {
+				t.Errorf("store = %s (%s), want %s (%s)", cfg.Store, cfg.BaseDir, tt.wantStore, tt.wantBase)
+			}
+			if cfg.ProfilesDir != filepath.Join(tt.wantBase, ProfilesDir) {
+				t.Errorf("ProfilesDir = %q", cfg.ProfilesDir)
+			}
+			if _, err := os.Stat(cfg.ProfilesDir); err != nil {
+				t.Errorf("profiles directory of the selected store was not created: %v", err)
+			}
+			if cfg.SettingsPath() != filepath.Join(home, SettingsFileName) {
+				t.Errorf("SettingsPath() = %q, want it in the personal store", cfg.SettingsPath())
+			}
+
+			wantDirs := []string{filepath.Join(team, ProfilesDir), filepath.Join(home, ProfilesDir)}
+			if got := cfg.ProfileDirs(); strings.Join(got, ",") != strings.Join(wantDirs, ",") {
+				t.Errorf("ProfileDirs() = %v, want %v", got, wantDirs)
+			}
+		})
+	}
+}
+
+func TestConfig_ServerDirs(t *testing.T) {
+	project := &Project{Path: "/repo/" + ProjectFileName, Templates: "templates", Store: ".mcpjson"}
+	cfg := &Config{Project: project, HomeDir: "/home"}
+	stores, err := cfg.loadStores()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Stores = stores
+
+	want := []string{"/repo/templates", "/repo/.mcpjson/servers", "/home/servers"}
+	if got := cfg.ServerDirs(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("ServerDirs() = %v, want %v", got, want)
+	}
+}
+
+func TestListNames(t *testing.T) {
+	first := t.TempDir()
+	second := t.TempDir()
+	for dir, names := range map[string][]string{
+		first:  {"web.jsonc", "notes.txt"},
+		second: {"web.jsonc", "api.jsonc"},
+	} {
+		for _, name := range names {
+			if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	got := ListNames(first, second, filepath.Join(first, "missing"))
+	if want := "api,web"; strings.Join(got, ",") != want {
+		t.Errorf("ListNames() = %v, want %s", got, want)
+	}
+
+	if got := LookupPath([]string{first, second}, second, "api"); got != filepath.Join(second, "api.jsonc") {
+		t.Errorf("LookupPath() = %q", got)
+	}
+	if got := LookupPath([]string{first, second}, second, "web"); got != filepath.Join(first, "web.jsonc") {
+		t.Errorf("LookupPath() = %q", got)
+	}
+	if got := LookupPath([]string{first, second}, second, "none"); got != filepath.Join(second, "none.jsonc") {
+		t.Errorf("LookupPath() = %q", got)
+	}
+}
@@ -0,0 +1,324 @@
+// Package diff compares sets of MCP server configurations, such as a
+// profile, a live MCP config file or the result of applying a profile.
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/sensitive"
+	"github.com/naoto24kawa/mcpjson/internal/server"
+)
+
+// Kind is the kind of change made to a server
+type Kind string
+
+const (
+	Added   Kind = "added"
+	Removed Kind = "removed"
+	Changed Kind = "changed"
+)
+
+// Output formats accepted by Write
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// redacted replaces values hidden by Redact
+const redacted = "***"
+
+// FieldChange is a change of a single field. Env and header entries are
+// reported per key as "env.KEY" and "headers.KEY". Old or New is nil when
+// the field is not set on that side.
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old,omitempty"`
+	New   interface{} `json:"new,omitempty"`
+}
+
+// ServerChange describes how one server differs between the two sides
+type ServerChange struct {
+	Name   string        `json:"name"`
+	Kind   Kind          `json:"kind"`
+	Fields []FieldChange `json:"fields"`
+}
+
+// Result is the difference between two sets of servers
+type Result struct {
+	From    string         `json:"from"`
+	To      string         `json:"to"`
+	Changes []ServerChange `json:"changes"`
+}
+
+// Compare returns the changes needed to turn from into to.
+// from and to are labels used in the output.
+func Compare(from string, fromServers map[string]server.MCPServer, to string, toServers map[string]server.MCPServer) *Result {
+	result := &Result{From: from, To: to, Changes: []ServerChange{}}
+
+	names := make(map[string]bool)
+	for name := range fromServers {
+		names[name] = true
+	}
+	for name := range toServers {
+		names[name] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	for _, name := range sorted {
+		oldServer, inFrom := fromServers[name]
+		newServer, inTo := toServers[name]
+
+		switch {
+		case !inFrom:
+			result.Changes = append(result.Changes, ServerChange{
+				Name: name, Kind: Added, Fields: compareServers(server.MCPServer{}, newServer),
+			})
+		case !inTo:
+			result.Changes = append(result.Changes, ServerChange{
+				Name: name, Kind: Removed, Fields: compareServers(oldServer, server.MCPServer{}),
+			})
+		default:
+			if fields := compareServers(oldServer, newServer); len(fields) > 0 {
+				result.Changes = append(result.Changes, ServerChange{Name: name, Kind: Changed, Fields: fields})
+			}
+		}
+	}
+
+	return result
+}
+
+// HasChanges reports whether the two sides differ
+func (r *Result) HasChanges() bool {
+	return len(r.Changes) > 0
+}
+
+// Count returns the number of servers with the given kind of change
+func (r *Result) Count(kind Kind) int {
+	count := 0
+	for _, c := range r.Changes {
+		if c.Kind == kind {
+			count++
+		}
+	}
+	return count
+}
+
+// Redact hides the values of env and header entries, which often hold
+// expanded secrets, keeping which entries changed. In args, the command
+// and the URL only the values of sensitive flags, the URL password and
+// sensitive query parameters are hidden.
+func (r *Result) Redact() {
+	for i := range r.Changes {
+		for j := range r.Changes[i].Fields {
+			field := &r.Changes[i].Fields[j]
+			field.Old = redactValue(field.Field, field.Old)
+			field.New = redactValue(field.Field, field.New)
+		}
+	}
+}
+
+// redactValue returns value with the secrets it may hold hidden
+func redactValue(field string, value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	if strings.HasPrefix(field, "env.") || strings.HasPrefix(field, "headers.") {
+		return redacted
+	}
+
+	switch v := value.(type) {
+	case []string:
+		// 比較元のサーバーの引数を書き換えないように複製する
+		args := append([]string(nil), v...)
+		sensitive.Args(args, hide)
+		return args
+	case string:
+		switch field {
+		case "command":
+			sensitive.CommandLine(&v, hide)
+		case "url":
+			sensitive.URL(&v, hide)
+		}
+		return v
+	}
+	return value
+}
+
+// hide is a sensitive.Replacer that replaces every value with redacted
+func hide(name, value string) (string, bool) {
+	return redacted, true
+}
+
+// WriteJSON writes the result as indented JSON
+func (r *Result) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WriteText writes the result in a unified-diff-like format: one hunk per
+// server, with removed values prefixed by "-" and added values by "+"
+func (r *Result) WriteText(w io.Writer) error {
+	if !r.HasChanges() {
+		_, err := fmt.Fprintln(w, i18n.T("diff.no_changes", r.From, r.To))
+		return err
+	}
+
+	ew := &errWriter{w: w}
+	ew.printf("--- %s\n", r.From)
+	ew.printf("+++ %s\n", r.To)
+
+	for _, change := range r.Changes {
+		switch change.Kind {
+		case Added:
+			ew.printf("%s\n", i18n.T("diff.hunk_added", change.Name))
+		case Removed:
+			ew.printf("%s\n", i18n.T("diff.hunk_removed", change.Name))
+		default:
+			ew.printf("@@ %s @@\n", change.Name)
+		}
+		for _, field := range change.Fields {
+			if field.Old != nil {
+				ew.printf("-  %s: %s\n", field.Field, formatValue(field.Old))
+			}
+			if field.New != nil {
+				ew.printf("+  %s: %s\n", field.Field, formatValue(field.New))
+			}
+		}
+	}
+
+	ew.printf("%s\n", i18n.T("diff.summary", r.Count(Added), r.Count(Removed), r.Count(Changed)))
+	return ew.err
+}
+
+// Write writes the result in the given format ("text" or "json")
+func (r *Result) Write(w io.Writer, format string) error {
+	switch format {
+	case "", FormatText:
+		return r.WriteText(w)
+	case FormatJSON:
+		return r.WriteJSON(w)
+	default:
+		return i18n.Errorf("diff.unknown_format", format, FormatText, FormatJSON)
+	}
+}
+
+func compareServers(a, b server.MCPServer) []FieldChange {
+	var fields []FieldChange
+
+	add := func(field string, old, new interface{}) {
+		if !reflect.DeepEqual(old, new) {
+			fields = append(fields, FieldChange{Field: field, Old: old, New: new})
+		}
+	}
+
+	add("type", optString(a.Type), optString(b.Type))
+	add("command", optString(a.Command), optString(b.Command))
+	add("args", optArgs(a.Args), optArgs(b.Args))
+	fields = append(fields, compareMaps("env", a.Env, b.Env)...)
+	add("url", optString(a.URL), optString(b.URL))
+	fields = append(fields, compareMaps("headers", a.Headers, b.Headers)...)
+	add("timeout", optInt(a.Timeout), optInt(b.Timeout))
+	add("envFile", optStringPtr(a.EnvFile), optStringPtr(b.EnvFile))
+	add("transportType", optStringPtr(a.TransportType), optStringPtr(b.TransportType))
+
+	return fields
+}
+
+func compareMaps(prefix string, a, b map[string]string) []FieldChange {
+	keys := make(map[string]bool)
+	for k := range a {
+		keys[k] = true
+	}
+	for k := range b {
+		keys[k] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var fields []FieldChange
+	for _, k := range sorted {
+		oldValue, inA := a[k]
+		newValue, inB := b[k]
+		if inA && inB && oldValue == newValue {
+			continue
+		}
+
+		change := FieldChange{Field: prefix + "." + k}
+		if inA {
+			change.Old = oldValue
+		}
+		if inB {
+			change.New = newValue
+		}
+		fields = append(fields, change)
+	}
+	return fields
+}
+
+// The opt helpers return nil for unset values so that they compare equal
+// and are omitted from the output.
+
+func optString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func optStringPtr(s *string) interface{} {
+	if s == nil {
+		return nil
+	}
+	return *s
+}
+
+func optInt(i *int) interface{} {
+	if i == nil {
+		return nil
+	}
+	return *i
+}
+
+func optArgs(args []string) interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+	return args
+}
+
+func formatValue(v interface{}) string {
+	var b strings.Builder
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Sprint(v)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) printf(format string, args ...interface{}) {
+	if ew.err != nil {
+		return
+	}
+	_, ew.err = fmt.Fprintf(ew.w, format, args...)
+}
@@ -0,0 +1,140 @@
+package diff
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/profile"
+	"github.com/naoto24kawa/mcpjson/internal/secret"
+	"github.com/naoto24kawa/mcpjson/internal/server"
+	"github.com/naoto24kawa/mcpjson/internal/utils"
+)
+
+// Source kinds accepted as "<kind>:<name>" by LoadSource
+const (
+	SourceProfile  = "profile"
+	SourceBuild    = "build"
+	SourceTemplate = "template"
+	SourceFile     = "file"
+)
+
+// Source is one side of a comparison
+type Source struct {
+	Label   string
+	Servers map[string]server.MCPServer
+}
+
+// LoadSource loads the servers described by spec:
+//
+//	profile:NAME   the profile's servers with placeholders left unexpanded
+//	build:NAME     what `apply NAME` writes (placeholders and secrets resolved)
+//	template:NAME  a single server template
+//	file:PATH      a live MCP config file
+//
+// Without a prefix, spec is a file when it looks like a path or exists,
+// and a profile otherwise.
+func LoadSource(cfg *config.Config, spec string) (*Source, error) {
+	kind, name := splitSpec(spec)
+	if name == "" {
+		return nil, i18n.Errorf("diff.empty_source", spec)
+	}
+
+	switch kind {
+	case SourceProfile:
+		serverManager := server.NewManager(cfg.ServersDir)
+		serverManager.UseConfig(cfg)
+		mcpConfig, err := newProfileManager(cfg).Compose(name, serverManager)
+		if err != nil {
+			return nil, err
+		}
+		return &Source{Label: spec, Servers: mcpConfig.McpServers}, nil
+	case SourceBuild:
+		serverManager := server.NewManager(cfg.ServersDir)
+		serverManager.UseConfig(cfg)
+		provider, err := secret.Open(cfg)
+		if err != nil {
+			return nil, err
+		}
+		serverManager.SetSecretProvider(provider)
+
+		mcpConfig, err := newProfileManager(cfg).Build(name, serverManager)
+		if err != nil {
+			return nil, err
+		}
+		return &Source{Label: spec, Servers: mcpConfig.McpServers}, nil
+	case SourceTemplate:
+		serverManager := server.NewManager(cfg.ServersDir)
+		serverManager.UseConfig(cfg)
+		template, err := serverManager.Load(name)
+		if err != nil {
+			return nil, err
+		}
+		return &Source{Label: spec, Servers: map[string]server.MCPServer{name: template.ServerConfig}}, nil
+	case SourceFile:
+		servers, err := LoadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		return &Source{Label: name, Servers: servers}, nil
+	default:
+		return nil, i18n.Errorf("diff.unknown_kind",
+			kind, SourceProfile, SourceBuild, SourceTemplate, SourceFile)
+	}
+}
+
+// LoadFile returns the servers of an MCP config file. A missing file has
+// no servers, so that it can be compared with what apply would write.
+func LoadFile(path string) (map[string]server.MCPServer, error) {
+	if !utils.FileExists(path) {
+		return map[string]server.MCPServer{}, nil
+	}
+
+	doc, err := server.LoadMCPDocument(path)
+	if err != nil {
+		return nil, i18n.Errorf("common.mcp_read_failed", err)
+	}
+	mcpConfig, err := doc.Config()
+	if err != nil {
+		return nil, i18n.Errorf("common.mcp_read_failed", err)
+	}
+	return mcpConfig.McpServers, nil
+}
+
+func splitSpec(spec string) (string, string) {
+	if kind, name, ok := strings.Cut(spec, ":"); ok {
+		switch kind {
+		case SourceFile:
+			return kind, expandHome(name)
+		case SourceProfile, SourceBuild, SourceTemplate:
+			return kind, name
+		}
+	}
+
+	if strings.ContainsAny(spec, `/\`) || strings.HasPrefix(spec, ".") || strings.HasPrefix(spec, "~") {
+		return SourceFile, expandHome(spec)
+	}
+	if _, err := os.Stat(spec); err == nil {
+		return SourceFile, spec
+	}
+	return SourceProfile, spec
+}
+
+// newProfileManager returns a profile manager whose reads fall through
+// every store
+func newProfileManager(cfg *config.Config) *profile.Manager {
+	profileManager := profile.NewManager(cfg.ProfilesDir)
+	profileManager.UseConfig(cfg)
+	return profileManager
+}
+
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[1:])
+		}
+	}
+	return path
+}
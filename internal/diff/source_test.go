@@ -0,0 +1,105 @@
+package diff
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/profile"
+	"github.com/naoto24kawa/mcpjson/internal/server"
+)
+
+func TestSplitSpec(t *testing.T) {
+	tests := []struct {
+		spec     string
+		wantKind string
+		wantName string
+	}{
+		{"profile:work", SourceProfile, "work"},
+		{"build:work", SourceBuild, "work"},
+		{"template:git", SourceTemplate, "git"},
+		{"file:mcp.json", SourceFile, "mcp.json"},
+		{"./.mcp.json", SourceFile, "./.mcp.json"},
+		{"work", SourceProfile, "work"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			kind, name := splitSpec(tt.spec)
+			if kind != tt.wantKind || name != tt.wantName {
+				t.Errorf("splitSpec(%q) = %s, %s; want %s, %s", tt.spec, kind, name, tt.wantKind, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestLoadSource(t *testing.T) {
+	baseDir := t.TempDir()
+	cfg := &config.Config{
+		BaseDir:     baseDir,
+		ProfilesDir: filepath.Join(baseDir, "profiles"),
+		ServersDir:  filepath.Join(baseDir, "servers"),
+	}
+	for _, dir := range []string{cfg.ProfilesDir, cfg.ServersDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+	}
+
+	serverManager := server.NewManager(cfg.ServersDir)
+	if err := serverManager.SaveManual("git", "uvx", []string{"mcp-server-git"}, map[string]string{"ROOT": "${profile.root}"}, false); err != nil {
+		t.Fatalf("SaveManual() failed: %v", err)
+	}
+	profileManager := profile.NewManager(cfg.ProfilesDir)
+	if err := profileManager.Create("work", ""); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	if err := profileManager.AddServer("work", "git", "", nil); err != nil {
+		t.Fatalf("AddServer() failed: %v", err)
+	}
+
+	mcpPath := filepath.Join(baseDir, "mcp.json")
+	if err := os.WriteFile(mcpPath, []byte(`{"mcpServers": {"git": {"command": "uvx"}}}`), 0644); err != nil {
+		t.Fatalf("failed to write MCP file: %v", err)
+	}
+
+	tests := []struct {
+		spec    string
+		wantEnv string
+		wantErr bool
+	}{
+		{"profile:work", "${profile.root}", false},
+		{"build:work", "", true}, // ${profile.root} は未定義
+		{"template:git", "${profile.root}", false},
+		{mcpPath, "", false},
+		{"profile:missing", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			source, err := LoadSource(cfg, tt.spec)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("LoadSource() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadSource() failed: %v", err)
+			}
+			git, ok := source.Servers["git"]
+			if !ok {
+				t.Fatalf("servers = %v, want git", source.Servers)
+			}
+			if git.Env["ROOT"] != tt.wantEnv {
+				t.Errorf("ROOT = %q, want %q", git.Env["ROOT"], tt.wantEnv)
+			}
+		})
+	}
+
+	missing, err := LoadSource(cfg, "file:"+filepath.Join(baseDir, "missing.json"))
+	if err != nil || len(missing.Servers) != 0 {
+		t.Errorf("a missing file should compare as empty: %v, %v", missing, err)
+	}
+}
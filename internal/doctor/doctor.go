@@ -0,0 +1,233 @@
+// Package doctor checks that MCP servers start and answer the MCP
+// handshake, so that a broken template is found before an IDE loads it.
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/mcpclient"
+	"github.com/naoto24kawa/mcpjson/internal/server"
+)
+
+const (
+	StatusOK      = "ok"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped"
+
+	// maxStderr is how many trailing bytes of stderr are kept
+	maxStderr = 4096
+)
+
+// Result is the outcome of checking one server
+type Result struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	// LatencyMS is the time from launch until the tools were listed
+	LatencyMS       int64  `json:"latencyMs"`
+	ServerName      string `json:"serverName,omitempty"`
+	ServerVersion   string `json:"serverVersion,omitempty"`
+	ProtocolVersion string `json:"protocolVersion,omitempty"`
+	Tools           int    `json:"tools"`
+	Error           string `json:"error,omitempty"`
+	// Stderr is the tail of what the server wrote to stderr, kept when
+	// the check failed
+	Stderr string `json:"stderr,omitempty"`
+}
+
+// CheckAll checks the servers concurrently and returns the results in
+// name order
+func CheckAll(ctx context.Context, servers map[string]server.MCPServer) []Result {
+	names := make([]string, 0, len(servers))
+	for name := range servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	results := make([]Result, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			results[i] = Check(ctx, name, servers[name])
+		}(i, name)
+	}
+	wg.Wait()
+	return results
+}
+
+// Check launches a stdio server, sends initialize and tools/list, and
+// stops it again. Remote servers are skipped.
+func Check(ctx context.Context, name string, mcpServer server.MCPServer) Result {
+	result := Result{Name: name, Status: StatusFailed}
+	if mcpServer.IsRemote() {
+		result.Status = StatusSkipped
+		result.Error = i18n.T("doctor.remote_skipped", mcpServer.ResolvedType())
+		return result
+	}
+	if err := mcpServer.Validate(); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	var info *mcpclient.InitializeResult
+	var tools []json.RawMessage
+	start := time.Now()
+	stderr, err := withSession(ctx, mcpServer, func(ctx context.Context, c *mcpclient.Client) error {
+		var err error
+		if info, err = c.Initialize(ctx, "mcpjson", clientVersion()); err != nil || !info.Has("tools") {
+			return err
+		}
+		tools, err = c.List(ctx, "tools/list", "tools")
+		return err
+	})
+	result.LatencyMS = time.Since(start).Milliseconds()
+	if err != nil {
+		result.Error = err.Error()
+		result.Stderr = stderr
+		return result
+	}
+
+	result.Status = StatusOK
+	result.ServerName = info.ServerInfo.Name
+	result.ServerVersion = info.ServerInfo.Version
+	result.ProtocolVersion = info.ProtocolVersion
+	result.Tools = len(tools)
+	return result
+}
+
+// Inspect launches a stdio server and lists the tools, prompts and
+// resources it offers
+func Inspect(ctx context.Context, mcpServer server.MCPServer) (*server.Capabilities, error) {
+	if mcpServer.IsRemote() {
+		return nil, i18n.Errorf("doctor.remote_skipped", mcpServer.ResolvedType())
+	}
+	if err := mcpServer.Validate(); err != nil {
+		return nil, err
+	}
+
+	capabilities := &server.Capabilities{
+		Tools:     []server.Tool{},
+		Prompts:   []server.Prompt{},
+		Resources: []server.Resource{},
+	}
+	stderr, err := withSession(ctx, mcpServer, func(ctx context.Context, c *mcpclient.Client) error {
+		info, err := c.Initialize(ctx, "mcpjson", clientVersion())
+		if err != nil {
+			return err
+		}
+		capabilities.ServerName = info.ServerInfo.Name
+		capabilities.ServerVersion = info.ServerInfo.Version
+
+		lists := []struct {
+			capability, method, field string
+			v                         interface{}
+		}{
+			{"tools", "tools/list", "tools", &capabilities.Tools},
+			{"prompts", "prompts/list", "prompts", &capabilities.Prompts},
+			{"resources", "resources/list", "resources", &capabilities.Resources},
+		}
+		for _, l := range lists {
+			if !info.Has(l.capability) {
+				continue
+			}
+			items, err := c.List(ctx, l.method, l.field)
+			if err != nil {
+				return err
+			}
+			if err := decodeItems(items, l.v); err != nil {
+				return i18n.Errorf("doctor.invalid_result", l.method, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if stderr != "" {
+			return nil, i18n.Errorf("doctor.failed_with_stderr", err, stderr)
+		}
+		return nil, err
+	}
+
+	capabilities.InspectedAt = time.Now()
+	return capabilities, nil
+}
+
+func decodeItems(items []json.RawMessage, v interface{}) error {
+	data, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// withSession launches the server, runs fn against it within the server's
+// timeout and stops it again. On failure the tail of stderr is returned.
+func withSession(ctx context.Context, mcpServer server.MCPServer, fn func(context.Context, *mcpclient.Client) error) (string, error) {
+	timeout := mcpclient.Timeout(mcpServer)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	stderr := &tailBuffer{limit: maxStderr}
+	c, err := mcpclient.Start(mcpServer, stderr)
+	if err != nil {
+		return "", err
+	}
+	err = fn(ctx, c)
+	c.Close()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = mcpclient.TimeoutError(timeout)
+	}
+	if err != nil {
+		return stderr.String(), err
+	}
+	return "", nil
+}
+
+// Failed returns the number of results that failed
+func Failed(results []Result) int {
+	failed := 0
+	for _, result := range results {
+		if result.Status == StatusFailed {
+			failed++
+		}
+	}
+	return failed
+}
+
+func clientVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "dev"
+}
+
+// tailBuffer keeps the last limit bytes written to it
+type tailBuffer struct {
+	mu    sync.Mutex
+	limit int
+	buf   []byte
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf = append(b.buf, p...)
+	if len(b.buf) > b.limit {
+		b.buf = b.buf[len(b.buf)-b.limit:]
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return string(bytes.TrimSpace(b.buf))
+}
@@ -0,0 +1,135 @@
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/naoto24kawa/mcpjson/internal/mcpclient"
+	"github.com/naoto24kawa/mcpjson/internal/output"
+	"github.com/naoto24kawa/mcpjson/internal/server"
+	"github.com/naoto24kawa/mcpjson/internal/testutil"
+)
+
+func TestMain(m *testing.M) {
+	testutil.RunFakeServerIfRequested()
+	os.Exit(m.Run())
+}
+
+func TestCheck(t *testing.T) {
+	envFile := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(envFile, []byte("FAKE_NAME=from-file\nFAKE_VERSION=1.2.0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	healthy := testutil.FakeServer("ok")
+	healthy.Env["FAKE_NAME"] = "fake"
+	healthy.EnvFile = &envFile
+
+	timeout := 1
+	hanging := testutil.FakeServer("hang")
+	hanging.Timeout = &timeout
+
+	tests := []struct {
+		name       string
+		server     server.MCPServer
+		wantStatus string
+		wantError  string
+		wantStderr string
+	}{
+		{name: "正常に応答", server: healthy, wantStatus: StatusOK},
+		{name: "起動直後に終了", server: testutil.FakeServer("crash"), wantStatus: StatusFailed, wantError: "exit status 3", wantStderr: "API_KEY is not set"},
+		{name: "エラー応答", server: testutil.FakeServer("error"), wantStatus: StatusFailed, wantError: "database unavailable"},
+		{name: "標準出力にログ", server: testutil.FakeServer("noise"), wantStatus: StatusFailed, wantError: "starting server..."},
+		{name: "タイムアウト", server: hanging, wantStatus: StatusFailed, wantError: "1s"},
+		{name: "存在しないコマンド", server: server.MCPServer{Command: "mcpjson-no-such-command"}, wantStatus: StatusFailed, wantError: "mcpjson-no-such-command"},
+		{name: "リモートサーバー", server: server.MCPServer{URL: "https://example.com/mcp"}, wantStatus: StatusSkipped},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Check(context.Background(), "srv", tt.server)
+			if result.Status != tt.wantStatus {
+				t.Fatalf("Status = %s, want %s (error: %s)", result.Status, tt.wantStatus, result.Error)
+			}
+			if !strings.Contains(result.Error, tt.wantError) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.wantError)
+			}
+			if !strings.Contains(result.Stderr, tt.wantStderr) {
+				t.Errorf("Stderr = %q, want it to contain %q", result.Stderr, tt.wantStderr)
+			}
+		})
+	}
+
+	result := Check(context.Background(), "srv", healthy)
+	if result.ServerName != "fake" || result.ServerVersion != "1.2.0" {
+		t.Errorf("server info = %s %s, want the env to override the env file", result.ServerName, result.ServerVersion)
+	}
+	if result.Tools != 4 || result.ProtocolVersion != mcpclient.ProtocolVersion {
+		t.Errorf("Tools = %d, ProtocolVersion = %s, want 4 tools over two pages", result.Tools, result.ProtocolVersion)
+	}
+}
+
+func TestCheckAll_Write(t *testing.T) {
+	results := CheckAll(context.Background(), map[string]server.MCPServer{
+		"ok":     testutil.FakeServer("ok"),
+		"broken": testutil.FakeServer("crash"),
+	})
+	if len(results) != 2 || results[0].Name != "broken" || results[1].Name != "ok" {
+		t.Fatalf("results = %+v, want them in name order", results)
+	}
+	if Failed(results) != 1 {
+		t.Errorf("Failed() = %d, want 1", Failed(results))
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, output.FormatTable, results); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "    fatal: API_KEY is not set") {
+		t.Errorf("table output does not show the stderr of the failed server:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := Write(&buf, output.FormatJSON, results); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []Result
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || decoded[1].Tools != 4 {
+		t.Errorf("json output = %s (%v)", buf.String(), err)
+	}
+}
+
+func TestInspect(t *testing.T) {
+	capabilities, err := Inspect(context.Background(), testutil.FakeServer("ok"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(capabilities.Tools) != 4 || capabilities.Tools[0].Name != "search" || capabilities.Tools[3].Name != "fetch" {
+		t.Errorf("Tools = %+v, want search, delete, fail and fetch over two pages", capabilities.Tools)
+	}
+	if len(capabilities.Prompts) != 1 || !capabilities.Prompts[0].Arguments[0].Required {
+		t.Errorf("Prompts = %+v", capabilities.Prompts)
+	}
+	if len(capabilities.Resources) != 1 || capabilities.Resources[0].MimeType != "text/markdown" {
+		t.Errorf("Resources = %+v", capabilities.Resources)
+	}
+	if capabilities.InspectedAt.IsZero() {
+		t.Error("InspectedAt is not set")
+	}
+
+	var buf bytes.Buffer
+	server.WriteCapabilities(&buf, "fake", capabilities)
+	for _, want := range []string{"search(limit, query*): Searches ok\n", "review(code*)", "file:///readme.md (readme)"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output does not contain %q:\n%s", want, buf.String())
+		}
+	}
+
+	if _, err := Inspect(context.Background(), testutil.FakeServer("crash")); err == nil || !strings.Contains(err.Error(), "API_KEY is not set") {
+		t.Errorf("Inspect() error = %v, want it to include the stderr of the server", err)
+	}
+}
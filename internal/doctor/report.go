@@ -0,0 +1,73 @@
+package doctor
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/output"
+)
+
+// Write renders the results as a table followed by the errors, or as JSON
+// or YAML
+func Write(w io.Writer, format output.Format, results []Result) error {
+	if format.IsStructured() {
+		return output.Write(w, format, results)
+	}
+
+	table := output.NewTable(
+		i18n.T("doctor.column_server"),
+		i18n.T("doctor.column_status"),
+		i18n.T("doctor.column_latency"),
+		i18n.T("doctor.column_info"),
+		i18n.T("doctor.column_tools"),
+	)
+	for _, result := range results {
+		latency, tools, info := "-", "-", "-"
+		if result.Status != StatusSkipped {
+			latency = fmt.Sprintf("%dms", result.LatencyMS)
+		}
+		if result.Status == StatusOK {
+			tools = strconv.Itoa(result.Tools)
+			info = strings.TrimSpace(result.ServerName + " " + result.ServerVersion)
+		}
+		table.AddRow(result.Name, statusLabel(result.Status), latency, info, tools)
+	}
+	if err := table.Write(w); err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		if result.Error == "" {
+			continue
+		}
+		fmt.Fprintln(w, i18n.T("doctor.error_detail", result.Name, result.Error))
+		if result.Stderr != "" {
+			fmt.Fprintln(w, i18n.T("doctor.stderr_header"))
+			for _, line := range strings.Split(result.Stderr, "\n") {
+				fmt.Fprintln(w, "    "+line)
+			}
+		}
+	}
+
+	ok := 0
+	for _, result := range results {
+		if result.Status == StatusOK {
+			ok++
+		}
+	}
+	_, err := fmt.Fprintln(w, i18n.T("doctor.summary", ok, len(results)))
+	return err
+}
+
+func statusLabel(status string) string {
+	switch status {
+	case StatusOK:
+		return i18n.T("doctor.status_ok")
+	case StatusSkipped:
+		return i18n.T("doctor.status_skipped")
+	}
+	return i18n.T("doctor.status_failed")
+}
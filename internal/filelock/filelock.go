@@ -0,0 +1,261 @@
+// Package filelock provides advisory lock files that serialize mcpjson
+// processes working on the same store or MCP config file.
+//
+// Locks are plain files created with O_EXCL, so they work on every
+// platform and filesystem. A lock left behind by a crashed process is
+// broken once its owner is gone, or, when the owner cannot be checked,
+// once it is older than StaleAfter.
+//
+// Locks are reentrant within a process: they exclude other processes, not
+// other goroutines of the same process. Code that runs operations
+// concurrently has to serialize them itself.
+package filelock
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+)
+
+// StoreLockName is the name of the lock file placed in the store directory
+const StoreLockName = ".lock"
+
+var (
+	// Timeout is how long Acquire waits for a lock held by another process
+	Timeout = 10 * time.Second
+	// StaleAfter is the age after which a lock file whose owner cannot be
+	// checked is considered abandoned
+	StaleAfter = 10 * time.Minute
+	// pollInterval is the wait between acquisition attempts
+	pollInterval = 50 * time.Millisecond
+)
+
+var (
+	// mu guards held and kept. It is never held while waiting for a lock.
+	mu   sync.Mutex
+	held = make(map[string]*Lock)
+	// kept counts the KeepStore calls in effect for each lock path
+	kept = make(map[string]int)
+)
+
+// Lock is an acquired lock file
+type Lock struct {
+	path  string
+	count int
+}
+
+// Acquire takes the lock file at path, waiting up to Timeout when another
+// process holds it
+func Acquire(path string) (*Lock, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, i18n.Errorf("filelock.path_failed", err)
+	}
+	if l := reenter(absPath); l != nil {
+		return l, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(absPath), 0755); err != nil {
+		return nil, i18n.Errorf("filelock.mkdir_failed", err)
+	}
+
+	deadline := time.Now().Add(Timeout)
+	for {
+		l, err := tryAcquire(absPath)
+		if l != nil || err != nil {
+			return l, err
+		}
+
+		if isStale(absPath) {
+			breakStale(absPath)
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			return nil, i18n.Errorf("filelock.busy", absPath)
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// reenter returns the lock at path when this process already holds it
+func reenter(path string) *Lock {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if l, ok := held[path]; ok {
+		l.count++
+		return l
+	}
+	return nil
+}
+
+// tryAcquire creates the lock file unless it exists. It returns nil
+// without an error when another process holds the lock.
+func tryAcquire(path string) (*Lock, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	// Another goroutine may have taken the lock while this one waited
+	if l, ok := held[path]; ok {
+		l.count++
+		return l, nil
+	}
+	created, err := tryCreate(path)
+	if err != nil || !created {
+		return nil, err
+	}
+	l := &Lock{path: path, count: 1}
+	held[path] = l
+	return l, nil
+}
+
+// breakStale moves a stale lock out of the way. The lock is renamed to a
+// unique name and checked again, so that when another process broke it
+// and took the lock in the meantime, the live lock is put back rather
+// than removed.
+func breakStale(path string) {
+	aside := fmt.Sprintf("%s.stale-%d-%d", path, os.Getpid(), time.Now().UnixNano())
+	if err := os.Rename(path, aside); err != nil {
+		// 別のプロセスが先に解除した
+		return
+	}
+	if !isStale(aside) {
+		_ = os.Link(aside, path)
+	}
+	_ = os.Remove(aside)
+}
+
+// Release releases the lock. The lock file is removed when the outermost
+// holder in this process releases it, unless KeepStore keeps it.
+func (l *Lock) Release() error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if l.count == 0 {
+		return nil
+	}
+	l.count--
+	if l.count > 0 || kept[l.path] > 0 {
+		return nil
+	}
+	return l.remove()
+}
+
+// remove deletes the lock file of a lock no longer held. mu must be held.
+func (l *Lock) remove() error {
+	delete(held, l.path)
+	if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+		return i18n.Errorf("filelock.remove_failed", err)
+	}
+	return nil
+}
+
+// LockStore locks the store that contains dir (a profiles, servers or
+// groups directory) and returns a function that releases the lock
+func LockStore(dir string) (func(), error) {
+	return lockPath(storeLockPath(dir))
+}
+
+// KeepStore keeps the lock of the store that contains dir, once this
+// process takes it, until the returned function is called. Work that has
+// to follow a change under the same lock, such as recording it, runs
+// before that call. The lock is only taken when the change needs it, so
+// prompts before the change do not hold up other processes.
+func KeepStore(dir string) func() {
+	path, err := filepath.Abs(storeLockPath(dir))
+	if err != nil {
+		return func() {}
+	}
+
+	mu.Lock()
+	kept[path]++
+	mu.Unlock()
+
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if kept[path]--; kept[path] > 0 {
+			return
+		}
+		delete(kept, path)
+		if l, ok := held[path]; ok && l.count == 0 {
+			_ = l.remove()
+		}
+	}
+}
+
+func storeLockPath(dir string) string {
+	return filepath.Join(filepath.Dir(filepath.Clean(dir)), StoreLockName)
+}
+
+// LockFile locks path (typically an MCP config file) through a sibling
+// "<path>.lock" file and returns a function that releases the lock
+func LockFile(path string) (func(), error) {
+	return lockPath(path + ".lock")
+}
+
+func lockPath(path string) (func(), error) {
+	l, err := Acquire(path)
+	if err != nil {
+		return nil, err
+	}
+	return func() { l.Release() }, nil
+}
+
+func tryCreate(path string) (bool, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		if os.IsExist(err) {
+			return false, nil
+		}
+		return false, i18n.Errorf("filelock.create_failed", err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(strconv.Itoa(os.Getpid())); err != nil {
+		return false, i18n.Errorf("filelock.write_failed", err)
+	}
+	return true, nil
+}
+
+// isStale reports whether the lock file at path was left behind
+func isStale(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	old := time.Since(info.ModTime()) > StaleAfter
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		// 書き込み途中のロックファイルは、古くなるまで保持されているものとみなす
+		return old
+	}
+	if pid == os.Getpid() {
+		// 同じ pid だった以前のプロセスのロックは生存を確認できない
+		return old && !holds(path)
+	}
+	// 生存しているプロセスのロックは古くても解除しない
+	return !processAlive(pid)
+}
+
+// holds reports whether this process holds the lock at path
+func holds(path string) bool {
+	mu.Lock()
+	defer mu.Unlock()
+
+	_, ok := held[path]
+	return ok
+}
@@ -0,0 +1,211 @@
+package filelock
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestAcquire_Reentrant(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.lock")
+
+	first, err := Acquire(path)
+	if err != nil {
+		t.Fatalf("Acquire() failed: %v", err)
+	}
+	second, err := Acquire(path)
+	if err != nil {
+		t.Fatalf("reentrant Acquire() failed: %v", err)
+	}
+
+	if err := second.Release(); err != nil {
+		t.Fatalf("Release() failed: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("lock file should remain while the outer holder keeps it: %v", err)
+	}
+
+	if err := first.Release(); err != nil {
+		t.Fatalf("Release() failed: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("lock file should be removed after the last release, err = %v", err)
+	}
+}
+
+func TestAcquire_HeldByOtherProcess(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.lock")
+
+	// 親プロセスは生存しているため、ロックは有効とみなされる
+	if err := os.WriteFile(path, []byte(strconv.Itoa(os.Getppid())), 0644); err != nil {
+		t.Fatalf("failed to create lock file: %v", err)
+	}
+
+	defer func(timeout time.Duration) { Timeout = timeout }(Timeout)
+	Timeout = 100 * time.Millisecond
+
+	if _, err := Acquire(path); err == nil {
+		t.Fatal("Acquire() expected error while another process holds the lock")
+	}
+}
+
+func TestAcquire_BreaksStaleLock(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		age     time.Duration
+	}{
+		{"終了したプロセスのロック", "999999999", 0},
+		{"pid を読めない古いロック", "", time.Hour},
+		{"同じ pid だった以前のプロセスの古いロック", strconv.Itoa(os.Getpid()), time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "store.lock")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("failed to create lock file: %v", err)
+			}
+			if tt.age > 0 {
+				old := time.Now().Add(-tt.age)
+				if err := os.Chtimes(path, old, old); err != nil {
+					t.Fatalf("failed to age lock file: %v", err)
+				}
+			}
+
+			l, err := Acquire(path)
+			if err != nil {
+				t.Fatalf("Acquire() should break a stale lock: %v", err)
+			}
+			l.Release()
+		})
+	}
+}
+
+func TestAcquire_KeepsOldLockOfLiveProcess(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.lock")
+
+	// 長く実行中のプロセスのロックは StaleAfter を過ぎても有効
+	if err := os.WriteFile(path, []byte(strconv.Itoa(os.Getppid())), 0644); err != nil {
+		t.Fatalf("failed to create lock file: %v", err)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatalf("failed to age lock file: %v", err)
+	}
+
+	defer func(timeout time.Duration) { Timeout = timeout }(Timeout)
+	Timeout = 100 * time.Millisecond
+
+	if _, err := Acquire(path); err == nil {
+		t.Fatal("Acquire() broke the lock of a live process")
+	}
+}
+
+func TestAcquire_WaitDoesNotBlockOtherLocks(t *testing.T) {
+	dir := t.TempDir()
+	busy := filepath.Join(dir, "busy.lock")
+	if err := os.WriteFile(busy, []byte(strconv.Itoa(os.Getppid())), 0644); err != nil {
+		t.Fatalf("failed to create lock file: %v", err)
+	}
+
+	defer func(timeout time.Duration) { Timeout = timeout }(Timeout)
+	Timeout = time.Second
+
+	waiting := make(chan struct{})
+	go func() {
+		defer close(waiting)
+		_, _ = Acquire(busy)
+	}()
+	time.Sleep(2 * pollInterval)
+
+	start := time.Now()
+	l, err := Acquire(filepath.Join(dir, "free.lock"))
+	if err != nil {
+		t.Fatalf("Acquire() failed: %v", err)
+	}
+	l.Release()
+	if elapsed := time.Since(start); elapsed > Timeout/2 {
+		t.Errorf("Acquire() of a free lock took %v while another lock was awaited", elapsed)
+	}
+	<-waiting
+}
+
+func TestBreakStale_KeepsLiveLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.lock")
+
+	// 古いと判断した後に別のプロセスが取得したロックは残す
+	if err := os.WriteFile(path, []byte(strconv.Itoa(os.Getppid())), 0644); err != nil {
+		t.Fatalf("failed to create lock file: %v", err)
+	}
+	breakStale(path)
+
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != strconv.Itoa(os.Getppid()) {
+		t.Errorf("live lock = %q, %v, want it put back", data, err)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("got %d files, want only the lock", len(entries))
+	}
+}
+
+func TestLockStore_UsesParentDirectory(t *testing.T) {
+	baseDir := t.TempDir()
+	profilesDir := filepath.Join(baseDir, "profiles")
+
+	unlock, err := LockStore(profilesDir)
+	if err != nil {
+		t.Fatalf("LockStore() failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(baseDir, StoreLockName)); err != nil {
+		t.Errorf("store lock should be created in the store directory: %v", err)
+	}
+
+	// 同じストアの別ディレクトリからのロックは再入になる
+	unlockServers, err := LockStore(filepath.Join(baseDir, "servers"))
+	if err != nil {
+		t.Fatalf("LockStore() for sibling directory failed: %v", err)
+	}
+	unlockServers()
+	unlock()
+
+	if _, err := os.Stat(filepath.Join(baseDir, StoreLockName)); !os.IsNotExist(err) {
+		t.Errorf("store lock should be removed after unlock, err = %v", err)
+	}
+}
+
+func TestKeepStore(t *testing.T) {
+	baseDir := t.TempDir()
+	profilesDir := filepath.Join(baseDir, "profiles")
+	lockPath := filepath.Join(baseDir, StoreLockName)
+
+	release := KeepStore(profilesDir)
+	if _, err := os.Stat(lockPath); !os.IsNotExist(err) {
+		t.Fatalf("KeepStore() should not take the lock itself, err = %v", err)
+	}
+
+	unlock, err := LockStore(profilesDir)
+	if err != nil {
+		t.Fatalf("LockStore() failed: %v", err)
+	}
+	unlock()
+	if _, err := os.Stat(lockPath); err != nil {
+		t.Errorf("lock should be kept after the release: %v", err)
+	}
+
+	// 保持中のロックは再入できる
+	unlock, err = LockStore(profilesDir)
+	if err != nil {
+		t.Fatalf("LockStore() of a kept lock failed: %v", err)
+	}
+	unlock()
+
+	release()
+	if _, err := os.Stat(lockPath); !os.IsNotExist(err) {
+		t.Errorf("lock should be released by the function KeepStore returned, err = %v", err)
+	}
+}
@@ -0,0 +1,16 @@
+//go:build windows
+
+package filelock
+
+import "os"
+
+// processAlive reports whether a process with the given pid exists.
+// On Windows FindProcess opens a handle and fails for unknown pids.
+func processAlive(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	process.Release()
+	return true
+}
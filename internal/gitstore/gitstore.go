@@ -0,0 +1,438 @@
+// Package gitstore keeps the store directory in a git repository, commits
+// every change to it and synchronizes it with a shared remote.
+//
+// Synchronization never leaves conflict markers in profiles or templates:
+// files changed on both sides are detected before merging and reported
+// as profile or template conflicts.
+package gitstore
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/utils"
+)
+
+const (
+	// RemoteName is the git remote used by Sync
+	RemoteName = "origin"
+
+	// emptyTree is the hash of git's empty tree, used as the merge base of
+	// unrelated histories
+	emptyTree = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+	commitPrefix = "mcpjson: "
+)
+
+// gitignoreHeader introduces the lines mcpjson adds to .gitignore
+const gitignoreHeader = "# mcpjson が管理するファイルのうち、共有しないもの"
+
+// gitignoreLines keep per-machine files out of the shared repository
+var gitignoreLines = []string{
+	".lock",
+	"*.lock",
+	".*.tmp-*",
+	config.HistoryDir + "/",
+	config.StateDir + "/",
+	config.SecretsDir + "/",
+	config.SettingsFileName,
+}
+
+// ErrNotRepository is returned when the store is not a git repository
+var ErrNotRepository = i18n.Error("gitstore.not_repository")
+
+// Prefer selects which side wins for conflicting files during Sync
+type Prefer string
+
+const (
+	PreferNone   Prefer = ""
+	PreferLocal  Prefer = "local"
+	PreferRemote Prefer = "remote"
+)
+
+// Conflict is a file changed differently on both sides
+type Conflict struct {
+	Path string
+	// Kind is "profile", "template" or "file"
+	Kind string
+	Name string
+}
+
+func (c Conflict) String() string {
+	switch c.Kind {
+	case "profile":
+		return i18n.T("profile.context", c.Name)
+	case "template":
+		return i18n.T("profile.template_context", c.Name)
+	default:
+		return c.Path
+	}
+}
+
+// ConflictError is returned by Sync when both sides changed the same files
+type ConflictError struct {
+	Conflicts []Conflict
+}
+
+func (e *ConflictError) Error() string {
+	lines := make([]string, len(e.Conflicts))
+	for i, c := range e.Conflicts {
+		lines[i] = "  " + c.String()
+	}
+	return i18n.T("gitstore.conflict",
+		strings.Join(lines, "\n"))
+}
+
+// SyncResult describes what Sync did
+type SyncResult struct {
+	Pulled   bool
+	Pushed   bool
+	Resolved []Conflict
+}
+
+// Repo is a store directory managed with git
+type Repo struct {
+	dir string
+	env []string
+}
+
+// Open returns the repository in dir. It does not check that dir is a
+// repository; see IsRepository.
+func Open(dir string) *Repo {
+	return &Repo{dir: dir}
+}
+
+// IsRepository reports whether the store has been initialized with Init
+func (r *Repo) IsRepository() bool {
+	info, err := os.Stat(filepath.Join(r.dir, ".git"))
+	return err == nil && info.IsDir()
+}
+
+// Init makes the store a git repository and commits its current content.
+// Calling it on an existing repository only adds the missing lines to
+// .gitignore.
+func Init(dir string) (*Repo, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, i18n.Errorf("gitstore.git_not_found", err)
+	}
+	if err := os.MkdirAll(dir, config.DefaultDirPerm); err != nil {
+		return nil, i18n.Errorf("common.mkdir_failed", err)
+	}
+
+	r := Open(dir)
+	if !r.IsRepository() {
+		if _, err := r.git("init", "--quiet"); err != nil {
+			return nil, err
+		}
+	}
+	if err := updateGitignore(dir); err != nil {
+		return nil, i18n.Errorf("gitstore.gitignore_failed", err)
+	}
+	if _, err := r.CommitAll(i18n.T("gitstore.init_commit")); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// updateGitignore appends the lines of gitignoreLines that the store's
+// .gitignore lacks, keeping the rules already in it
+func updateGitignore(dir string) error {
+	path := filepath.Join(dir, ".gitignore")
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	present := map[string]bool{}
+	for _, line := range strings.Split(string(existing), "\n") {
+		present[strings.TrimSpace(line)] = true
+	}
+	var missing []string
+	for _, line := range gitignoreLines {
+		if !present[line] {
+			missing = append(missing, line)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	buf.Write(existing)
+	if len(existing) > 0 {
+		if !bytes.HasSuffix(existing, []byte("\n")) {
+			buf.WriteByte('\n')
+		}
+		buf.WriteByte('\n')
+	}
+	if !present[gitignoreHeader] {
+		missing = append([]string{gitignoreHeader}, missing...)
+	}
+	buf.WriteString(strings.Join(missing, "\n") + "\n")
+	return utils.WriteFileAtomic(path, buf.Bytes(), 0644)
+}
+
+// SetRemote sets the URL of the remote used by Sync. A local path to a
+// bare repository works as well.
+func (r *Repo) SetRemote(url string) error {
+	if !r.IsRepository() {
+		return ErrNotRepository
+	}
+	if _, err := r.git("remote", "get-url", RemoteName); err == nil {
+		_, err := r.git("remote", "set-url", RemoteName, url)
+		return err
+	}
+	_, err := r.git("remote", "add", RemoteName, url)
+	return err
+}
+
+// Remote returns the URL of the remote, or an empty string when none is set
+func (r *Repo) Remote() string {
+	url, err := r.git("remote", "get-url", RemoteName)
+	if err != nil {
+		return ""
+	}
+	return url
+}
+
+// CommitAll commits every change in the store with a message describing
+// the operation. It reports whether a commit was made.
+func (r *Repo) CommitAll(operation string) (bool, error) {
+	if _, err := r.git("add", "--all"); err != nil {
+		return false, err
+	}
+	status, err := r.git("status", "--porcelain")
+	if err != nil {
+		return false, err
+	}
+	if status == "" {
+		return false, nil
+	}
+	if _, err := r.git("commit", "--quiet", "-m", commitPrefix+operation); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// AutoCommit commits the changes made by operation when the store in dir
+// is a git repository, and does nothing otherwise
+func AutoCommit(dir, operation string) error {
+	r := Open(dir)
+	if !r.IsRepository() {
+		return nil
+	}
+	_, err := r.CommitAll(operation)
+	return err
+}
+
+// Sync commits local changes, merges the remote branch and pushes the
+// result. When both sides changed the same file, it returns a
+// *ConflictError and leaves the store untouched unless prefer selects the
+// side to keep.
+func (r *Repo) Sync(prefer Prefer) (*SyncResult, error) {
+	if !r.IsRepository() {
+		return nil, ErrNotRepository
+	}
+	if r.Remote() == "" {
+		return nil, i18n.Errorf("gitstore.no_remote")
+	}
+	if _, err := r.CommitAll(i18n.T("gitstore.presync_commit")); err != nil {
+		return nil, err
+	}
+
+	branch, err := r.git("rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SyncResult{}
+	if _, err := r.git("fetch", "--quiet", RemoteName, branch); err != nil {
+		// リモートにまだブランチがない場合は push のみ行う
+		if _, lsErr := r.git("ls-remote", "--exit-code", "--heads", RemoteName, branch); lsErr == nil {
+			return nil, err
+		}
+	} else {
+		merged, resolved, err := r.merge(prefer)
+		if err != nil {
+			return nil, err
+		}
+		result.Pulled = merged
+		result.Resolved = resolved
+	}
+
+	if _, err := r.git("push", "--quiet", "--set-upstream", RemoteName, branch); err != nil {
+		return nil, err
+	}
+	result.Pushed = true
+	return result, nil
+}
+
+// merge merges FETCH_HEAD into HEAD. It reports whether anything was merged.
+func (r *Repo) merge(prefer Prefer) (bool, []Conflict, error) {
+	if _, err := r.git("merge-base", "--is-ancestor", "FETCH_HEAD", "HEAD"); err == nil {
+		return false, nil, nil
+	}
+	if _, err := r.git("merge-base", "--is-ancestor", "HEAD", "FETCH_HEAD"); err == nil {
+		_, err := r.git("merge", "--quiet", "--ff-only", "FETCH_HEAD")
+		return err == nil, nil, err
+	}
+
+	base, err := r.git("merge-base", "HEAD", "FETCH_HEAD")
+	if err != nil {
+		base = emptyTree
+	}
+
+	conflicts, err := r.conflicts(base)
+	if err != nil {
+		return false, nil, err
+	}
+	if len(conflicts) > 0 && prefer == PreferNone {
+		return false, nil, &ConflictError{Conflicts: conflicts}
+	}
+
+	args := []string{"merge", "--quiet", "--no-commit", "--no-ff", "--allow-unrelated-histories"}
+	if prefer == PreferLocal {
+		args = append(args, "--strategy-option=ours")
+	} else if prefer == PreferRemote {
+		args = append(args, "--strategy-option=theirs")
+	}
+	if _, err := r.git(append(args, "FETCH_HEAD")...); err != nil && len(conflicts) == 0 {
+		r.git("merge", "--abort")
+		return false, nil, err
+	}
+
+	// 競合したファイルは行単位で混ぜず、選んだ側の内容をそのまま使う
+	side := "HEAD"
+	if prefer == PreferRemote {
+		side = "FETCH_HEAD"
+	}
+	for _, c := range conflicts {
+		if _, err := r.git("cat-file", "-e", side+":"+c.Path); err == nil {
+			_, err = r.git("checkout", side, "--", c.Path)
+			if err != nil {
+				r.git("merge", "--abort")
+				return false, nil, err
+			}
+		} else if _, err := r.git("rm", "--quiet", "--force", "--ignore-unmatch", "--", c.Path); err != nil {
+			r.git("merge", "--abort")
+			return false, nil, err
+		}
+	}
+
+	if _, err := r.git("commit", "--quiet", "--no-edit", "-m", commitPrefix+i18n.T("gitstore.merge_commit")); err != nil {
+		r.git("merge", "--abort")
+		return false, nil, err
+	}
+	return true, conflicts, nil
+}
+
+// conflicts returns the files changed on both sides since base whose
+// content differs
+func (r *Repo) conflicts(base string) ([]Conflict, error) {
+	ours, err := r.changedFiles(base, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	theirs, err := r.changedFiles(base, "FETCH_HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for path := range ours {
+		if _, ok := theirs[path]; !ok {
+			continue
+		}
+		if ours[path] != theirs[path] {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	conflicts := make([]Conflict, len(paths))
+	for i, path := range paths {
+		conflicts[i] = describe(path)
+	}
+	return conflicts, nil
+}
+
+// changedFiles returns the files changed between from and to, mapped to
+// their blob hash in to (empty when deleted)
+func (r *Repo) changedFiles(from, to string) (map[string]string, error) {
+	out, err := r.git("diff", "--raw", "--no-renames", "-z", from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]string)
+	fields := strings.Split(out, "\x00")
+	for i := 0; i+1 < len(fields); i += 2 {
+		// ":100644 100644 <old> <new> M" に続いてパス
+		meta := strings.Fields(fields[i])
+		if len(meta) < 5 {
+			continue
+		}
+		hash := meta[3]
+		if strings.Trim(hash, "0") == "" {
+			hash = ""
+		}
+		files[fields[i+1]] = hash
+	}
+	return files, nil
+}
+
+// describe maps a path in the store to the profile or template it holds
+func describe(path string) Conflict {
+	dir, file := filepath.Split(filepath.ToSlash(path))
+	name := strings.TrimSuffix(file, config.FileExtension)
+	switch {
+	case dir == config.ProfilesDir+"/" && name != file:
+		return Conflict{Path: path, Kind: "profile", Name: name}
+	case dir == config.ServersDir+"/" && name != file:
+		return Conflict{Path: path, Kind: "template", Name: name}
+	default:
+		return Conflict{Path: path, Kind: "file"}
+	}
+}
+
+func (r *Repo) git(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.dir
+	if r.env == nil {
+		r.env = append(os.Environ(), identityEnv(r.dir)...)
+	}
+	cmd.Env = r.env
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", i18n.Errorf("gitstore.git_failed", args[0], msg)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// identityEnv supplies a committer identity when git has none configured,
+// so that automatic commits do not fail on a fresh machine
+func identityEnv(dir string) []string {
+	cmd := exec.Command("git", "config", "user.email")
+	cmd.Dir = dir
+	if out, err := cmd.Output(); err == nil && len(bytes.TrimSpace(out)) > 0 {
+		return nil
+	}
+
+	return []string{
+		"GIT_AUTHOR_NAME=mcpjson", "GIT_AUTHOR_EMAIL=mcpjson@localhost",
+		"GIT_COMMITTER_NAME=mcpjson", "GIT_COMMITTER_EMAIL=mcpjson@localhost",
+	}
+}
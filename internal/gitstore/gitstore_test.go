@@ -0,0 +1,269 @@
+package gitstore
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func requireGit(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("gitが見つかりません")
+	}
+}
+
+func writeStoreFile(t *testing.T, dir, path, content string) {
+	t.Helper()
+	full := filepath.Join(dir, path)
+	if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readStoreFile(t *testing.T, dir, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, path))
+	if err != nil {
+		t.Fatalf("ReadFile(%s) error = %v", path, err)
+	}
+	return string(data)
+}
+
+// setupStores returns two stores sharing a bare remote, with alice's
+// initial content already pushed and pulled by bob
+func setupStores(t *testing.T) (alice, bob *Repo, aliceDir, bobDir string) {
+	t.Helper()
+	requireGit(t)
+
+	root := t.TempDir()
+	remote := filepath.Join(root, "remote.git")
+	if out, err := exec.Command("git", "init", "--quiet", "--bare", remote).CombinedOutput(); err != nil {
+		t.Fatalf("git init --bare: %v: %s", err, out)
+	}
+
+	aliceDir = filepath.Join(root, "alice")
+	bobDir = filepath.Join(root, "bob")
+
+	writeStoreFile(t, aliceDir, "servers/github.jsonc", `{"name": "github"}`)
+	alice, err := Init(aliceDir)
+	if err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	if err := alice.SetRemote(remote); err != nil {
+		t.Fatalf("SetRemote() error = %v", err)
+	}
+	if _, err := alice.Sync(PreferNone); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	bob, err = Init(bobDir)
+	if err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	if err := bob.SetRemote(remote); err != nil {
+		t.Fatalf("SetRemote() error = %v", err)
+	}
+	// ブランチ名が異なる環境でも同じブランチに揃える
+	branch, err := alice.git("rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bob.git("branch", "-M", branch); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bob.Sync(PreferNone); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	return alice, bob, aliceDir, bobDir
+}
+
+func TestInit_IgnoresLocalFiles(t *testing.T) {
+	requireGit(t)
+	dir := t.TempDir()
+	writeStoreFile(t, dir, "profiles/work.jsonc", "{}")
+	writeStoreFile(t, dir, "secrets/vault.key", "key")
+	writeStoreFile(t, dir, ".history/000001/entry.json", "{}")
+
+	repo, err := Init(dir)
+	if err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+
+	files, err := repo.git("ls-files")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(files, "profiles/work.jsonc") {
+		t.Errorf("profiles/work.jsonc がコミットされていません: %s", files)
+	}
+	for _, ignored := range []string{"secrets/", ".history/"} {
+		if strings.Contains(files, ignored) {
+			t.Errorf("%s がコミットされています: %s", ignored, files)
+		}
+	}
+}
+
+func TestInit_KeepsExistingGitignore(t *testing.T) {
+	requireGit(t)
+	dir := t.TempDir()
+	writeStoreFile(t, dir, ".gitignore", "# 個人のメモ\nnotes/\n*.lock")
+
+	if _, err := Init(dir); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, ".gitignore"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+	if !strings.HasPrefix(content, "# 個人のメモ\nnotes/\n*.lock\n") {
+		t.Errorf(".gitignore lost the existing rules:\n%s", content)
+	}
+	if strings.Count(content, "*.lock") != 1 {
+		t.Errorf(".gitignore repeats a rule it already had:\n%s", content)
+	}
+	for _, line := range gitignoreLines {
+		if !strings.Contains(content, line+"\n") {
+			t.Errorf(".gitignore lacks %q:\n%s", line, content)
+		}
+	}
+
+	// 2回目は何も追加しない
+	if _, err := Init(dir); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	again, err := os.ReadFile(filepath.Join(dir, ".gitignore"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(again) != content {
+		t.Errorf("second Init() changed .gitignore:\n%s", again)
+	}
+}
+
+func TestAutoCommit(t *testing.T) {
+	requireGit(t)
+	dir := t.TempDir()
+
+	// gitで管理されていないストアでは何もしない
+	if err := AutoCommit(dir, "create work"); err != nil {
+		t.Fatalf("AutoCommit() error = %v", err)
+	}
+
+	repo, err := Init(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeStoreFile(t, dir, "profiles/work.jsonc", "{}")
+	if err := AutoCommit(dir, "create work"); err != nil {
+		t.Fatalf("AutoCommit() error = %v", err)
+	}
+
+	subject, err := repo.git("log", "-1", "--format=%s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject != "mcpjson: create work" {
+		t.Errorf("commit subject = %q, want %q", subject, "mcpjson: create work")
+	}
+}
+
+func TestSync_MergesChangesToDifferentFiles(t *testing.T) {
+	alice, bob, aliceDir, bobDir := setupStores(t)
+
+	if got := readStoreFile(t, bobDir, "servers/github.jsonc"); got != `{"name": "github"}` {
+		t.Fatalf("bob has %q, want alice's template", got)
+	}
+
+	writeStoreFile(t, aliceDir, "profiles/work.jsonc", "alice")
+	writeStoreFile(t, bobDir, "profiles/home.jsonc", "bob")
+	if _, err := alice.Sync(PreferNone); err != nil {
+		t.Fatalf("alice Sync() error = %v", err)
+	}
+	result, err := bob.Sync(PreferNone)
+	if err != nil {
+		t.Fatalf("bob Sync() error = %v", err)
+	}
+	if !result.Pulled || !result.Pushed {
+		t.Errorf("Sync() = %+v, want pulled and pushed", result)
+	}
+	if got := readStoreFile(t, bobDir, "profiles/work.jsonc"); got != "alice" {
+		t.Errorf("profiles/work.jsonc = %q, want alice", got)
+	}
+}
+
+func TestSync_ReportsConflicts(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefer Prefer
+		want   string
+	}{
+		{name: "指定なしはエラー", prefer: PreferNone, want: "bob"},
+		{name: "ローカルを優先", prefer: PreferLocal, want: "bob"},
+		{name: "リモートを優先", prefer: PreferRemote, want: "alice"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alice, bob, aliceDir, bobDir := setupStores(t)
+
+			writeStoreFile(t, aliceDir, "servers/github.jsonc", "alice")
+			writeStoreFile(t, bobDir, "servers/github.jsonc", "bob")
+			if _, err := alice.Sync(PreferNone); err != nil {
+				t.Fatalf("alice Sync() error = %v", err)
+			}
+
+			result, err := bob.Sync(tt.prefer)
+			if tt.prefer == PreferNone {
+				var conflictErr *ConflictError
+				if !errors.As(err, &conflictErr) {
+					t.Fatalf("Sync() error = %v, want *ConflictError", err)
+				}
+				if len(conflictErr.Conflicts) != 1 || conflictErr.Conflicts[0].String() != "サーバーテンプレート 'github'" {
+					t.Errorf("Conflicts = %v", conflictErr.Conflicts)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("Sync() error = %v", err)
+				}
+				if len(result.Resolved) != 1 {
+					t.Errorf("Resolved = %v, want 1 conflict", result.Resolved)
+				}
+			}
+
+			got := readStoreFile(t, bobDir, "servers/github.jsonc")
+			if got != tt.want {
+				t.Errorf("servers/github.jsonc = %q, want %q", got, tt.want)
+			}
+			if strings.Contains(got, "<<<<<<<") {
+				t.Error("競合マーカーが書き込まれています")
+			}
+		})
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "profiles/work.jsonc", want: "プロファイル 'work'"},
+		{path: "servers/github.jsonc", want: "サーバーテンプレート 'github'"},
+		{path: "groups/dev.jsonc", want: "groups/dev.jsonc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := describe(tt.path).String(); got != tt.want {
+				t.Errorf("describe(%s) = %s, want %s", tt.path, got, tt.want)
+			}
+		})
+	}
+}
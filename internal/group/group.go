@@ -2,13 +2,18 @@ package group
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/filelock"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
 	"github.com/naoto24kawa/mcpjson/internal/interaction"
+	"github.com/naoto24kawa/mcpjson/internal/output"
 	"github.com/naoto24kawa/mcpjson/internal/server"
 	"github.com/naoto24kawa/mcpjson/internal/utils"
 )
@@ -25,6 +30,7 @@ type Group struct {
 // Manager handles server group operations
 type Manager struct {
 	groupsDir string
+	readDirs  []string
 }
 
 // NewManager creates a new Group Manager instance
@@ -34,12 +40,31 @@ func NewManager(groupsDir string) *Manager {
 	}
 }
 
+// UseConfig makes group reads fall through the stores of cfg. Writes
+// still go to the directory the manager was created with.
+func (gm *Manager) UseConfig(cfg *config.Config) {
+	gm.readDirs = cfg.GroupDirs()
+}
+
 // Create creates a new group
 func (gm *Manager) Create(name, description string, force bool) error {
+	overwrite := force
 	if !force && gm.exists(name) {
-		if !interaction.ConfirmOverwrite("グループ", name) {
-			return fmt.Errorf("上書きをキャンセルしました")
+		if !interaction.ConfirmOverwrite(i18n.T("kind.group"), name) {
+			return i18n.Errorf("common.overwrite_cancelled")
 		}
+		overwrite = true
+	}
+
+	// 確認の入力を待つ間はストアをロックしない
+	unlock, err := gm.lockStore()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if !overwrite && gm.exists(name) {
+		return i18n.Errorf("group.already_exists", name)
 	}
 
 	group := &Group{
@@ -58,91 +83,110 @@ func (gm *Manager) Create(name, description string, force bool) error {
 		return err
 	}
 
-	fmt.Printf("グループ '%s' を作成しました\n", name)
+	fmt.Println(i18n.T("group.created", name))
 	return nil
 }
 
+// Summary is one row of 'group list' in the json and yaml output formats
+type Summary struct {
+	Name        string    `json:"name"`
+	Description *string   `json:"description"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+	ServerCount int       `json:"serverCount"`
+}
+
 // List displays all groups
 func (gm *Manager) List(detail bool) error {
-	if _, err := os.Stat(gm.groupsDir); os.IsNotExist(err) {
-		fmt.Println("グループが存在しません")
-		return nil
-	}
+	return gm.ListWithFormat(os.Stdout, detail, output.FormatTable)
+}
 
-	files, err := os.ReadDir(gm.groupsDir)
+// ListWithFormat writes all groups to w. The json and yaml formats write a
+// list of Summary, or of Group with detail.
+func (gm *Manager) ListWithFormat(w io.Writer, detail bool, format output.Format) error {
+	groups, err := gm.loadAll()
 	if err != nil {
-		return fmt.Errorf("グループディレクトリの読み込みに失敗しました: %w", err)
+		return err
 	}
 
-	groupFiles := []string{}
-	for _, file := range files {
-		if strings.HasSuffix(file.Name(), config.FileExtension) {
-			groupFiles = append(groupFiles, file.Name())
+	if format.IsStructured() {
+		if detail {
+			return output.Write(w, format, groups)
+		}
+		summaries := make([]Summary, 0, len(groups))
+		for _, group := range groups {
+			summaries = append(summaries, Summary{
+				Name:        group.Name,
+				Description: group.Description,
+				CreatedAt:   group.CreatedAt,
+				UpdatedAt:   group.UpdatedAt,
+				ServerCount: len(group.Servers),
+			})
 		}
+		return output.Write(w, format, summaries)
 	}
 
-	if len(groupFiles) == 0 {
-		fmt.Println("グループが存在しません")
+	if len(groups) == 0 {
+		fmt.Fprintln(w, i18n.T("group.none"))
 		return nil
 	}
 
 	if detail {
-		return gm.printDetailedList(groupFiles)
+		gm.printDetailedList(w, groups)
+		return nil
 	}
 
-	return gm.printSimpleList(groupFiles)
+	table := output.NewTable(i18n.T("group.column_name"), i18n.T("common.created_at"), i18n.T("common.server_count"))
+	for _, group := range groups {
+		table.AddRow(group.Name, group.CreatedAt.Format(server.TimestampFormat), strconv.Itoa(len(group.Servers)))
+	}
+	return table.Write(w)
 }
 
-func (gm *Manager) printSimpleList(groupFiles []string) error {
-	fmt.Printf("%-20s %-20s %s\n", "グループ名", "作成日時", "サーバー数")
-	fmt.Printf("%-20s %-20s %s\n", strings.Repeat("-", 20), strings.Repeat("-", 20), strings.Repeat("-", 8))
+// loadAll loads every group. Groups that cannot be read are reported on
+// stderr and skipped.
+func (gm *Manager) loadAll() ([]*Group, error) {
+	if _, err := os.ReadDir(gm.groupsDir); err != nil && !os.IsNotExist(err) {
+		return nil, i18n.Errorf("group.read_dir_failed", err)
+	}
 
-	for _, file := range groupFiles {
+	names := config.ListNames(gm.groupsDir)
+	if len(gm.readDirs) > 0 {
+		names = config.ListNames(gm.readDirs...)
+	}
+
+	groups := []*Group{}
+	for _, name := range names {
 		group := &Group{}
-		if err := utils.LoadJSON(filepath.Join(gm.groupsDir, file), group); err != nil {
-			fmt.Printf("%-20s %-20s %s\n", "エラー", "-", "-")
+		if err := utils.LoadJSON(gm.lookupPath(name), group); err != nil {
+			fmt.Fprintln(os.Stderr, i18n.T("group.load_failed_skip", name, err))
 			continue
 		}
-
-		serverCount := len(group.Servers)
-		fmt.Printf("%-20s %-20s %d\n",
-			group.Name,
-			group.CreatedAt.Format(server.TimestampFormat),
-			serverCount,
-		)
+		groups = append(groups, group)
 	}
-
-	return nil
+	return groups, nil
 }
 
-func (gm *Manager) printDetailedList(groupFiles []string) error {
-	for i, file := range groupFiles {
+func (gm *Manager) printDetailedList(w io.Writer, groups []*Group) {
+	for i, group := range groups {
 		if i > 0 {
-			fmt.Println()
+			fmt.Fprintln(w)
 		}
 
-		group := &Group{}
-		if err := utils.LoadJSON(filepath.Join(gm.groupsDir, file), group); err != nil {
-			fmt.Printf("グループ: %s (読み込みエラー)\n", strings.TrimSuffix(file, config.FileExtension))
-			continue
-		}
-
-		fmt.Printf("グループ名: %s\n", group.Name)
+		fmt.Fprintln(w, i18n.T("group.detail_name", group.Name))
 		if group.Description != nil {
-			fmt.Printf("説明: %s\n", *group.Description)
+			fmt.Fprintln(w, i18n.T("group.detail_description", *group.Description))
 		}
-		fmt.Printf("作成日時: %s\n", group.CreatedAt.Format(server.TimestampFormat))
-		fmt.Printf("更新日時: %s\n", group.UpdatedAt.Format(server.TimestampFormat))
-		fmt.Printf("サーバー数: %d\n", len(group.Servers))
+		fmt.Fprintln(w, i18n.T("group.detail_created_at", group.CreatedAt.Format(server.TimestampFormat)))
+		fmt.Fprintln(w, i18n.T("group.detail_updated_at", group.UpdatedAt.Format(server.TimestampFormat)))
+		fmt.Fprintln(w, i18n.T("group.detail_server_count", len(group.Servers)))
 		if len(group.Servers) > 0 {
-			fmt.Println("サーバー:")
+			fmt.Fprintln(w, i18n.T("group.detail_servers"))
 			for _, serverName := range group.Servers {
-				fmt.Printf("  - %s\n", serverName)
+				fmt.Fprintf(w, "  - %s\n", serverName)
 			}
 		}
 	}
-
-	return nil
 }
 
 // Delete deletes a group
@@ -150,26 +194,43 @@ func (gm *Manager) Delete(name string, force bool) error {
 	groupPath := gm.getGroupPath(name)
 
 	if _, err := os.Stat(groupPath); os.IsNotExist(err) {
-		return fmt.Errorf("グループ '%s' が見つかりません", name)
+		return i18n.Errorf("group.not_found", name)
 	}
 
 	if !force {
-		if !interaction.Confirm(fmt.Sprintf("グループ '%s' を削除しますか？", name)) {
-			fmt.Println("削除をキャンセルしました")
+		if !interaction.Confirm(i18n.T("group.confirm_delete", name)) {
+			fmt.Println(i18n.T("common.delete_cancelled"))
 			return nil
 		}
 	}
 
+	// 確認の入力を待つ間はストアをロックせず、ロック後に改めて確認する
+	unlock, err := gm.lockStore()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if _, err := os.Stat(groupPath); os.IsNotExist(err) {
+		return i18n.Errorf("group.not_found", name)
+	}
+
 	if err := os.Remove(groupPath); err != nil {
-		return fmt.Errorf("グループの削除に失敗しました: %w", err)
+		return i18n.Errorf("group.delete_failed", err)
 	}
 
-	fmt.Printf("グループ '%s' を削除しました\n", name)
+	fmt.Println(i18n.T("group.deleted", name))
 	return nil
 }
 
 // Rename renames a group
 func (gm *Manager) Rename(oldName, newName string, force bool) error {
+	unlock, err := gm.lockStore()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	if err := gm.validateRename(oldName, newName, force); err != nil {
 		return err
 	}
@@ -182,15 +243,19 @@ func (gm *Manager) Rename(oldName, newName string, force bool) error {
 	group.Name = newName
 	group.UpdatedAt = time.Now()
 
-	if err := gm.save(group); err != nil {
+	tx := utils.NewTransaction()
+	defer tx.Rollback()
+
+	if err := tx.SaveJSONFrom(gm.getGroupPath(oldName), gm.getGroupPath(newName), group); err != nil {
 		return err
 	}
 
-	if err := os.Remove(gm.getGroupPath(oldName)); err != nil {
-		return fmt.Errorf("古いグループファイルの削除に失敗しました: %w", err)
+	if err := tx.Remove(gm.getGroupPath(oldName)); err != nil {
+		return i18n.Errorf("group.remove_old_failed", err)
 	}
+	tx.Commit()
 
-	fmt.Printf("グループ '%s' を '%s' に変更しました\n", oldName, newName)
+	fmt.Println(i18n.T("group.renamed", oldName, newName))
 	return nil
 }
 
@@ -199,11 +264,11 @@ func (gm *Manager) validateRename(oldName, newName string, force bool) error {
 	newPath := gm.getGroupPath(newName)
 
 	if _, err := os.Stat(oldPath); os.IsNotExist(err) {
-		return fmt.Errorf("グループ '%s' が見つかりません", oldName)
+		return i18n.Errorf("group.not_found", oldName)
 	}
 
 	if _, err := os.Stat(newPath); err == nil && !force {
-		return fmt.Errorf("グループ '%s' は既に存在します\n別の名前を指定するか、--force オプションで上書きしてください", newName)
+		return i18n.Errorf("group.already_exists", newName)
 	}
 
 	return nil
@@ -211,11 +276,17 @@ func (gm *Manager) validateRename(oldName, newName string, force bool) error {
 
 // AddServer adds a server to a group
 func (gm *Manager) AddServer(groupName, serverName string, serverManager *server.Manager) error {
+	unlock, err := gm.lockStore()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	// サーバーが存在するかチェック
 	if exists, err := serverManager.Exists(serverName); err != nil {
-		return fmt.Errorf("サーバー存在確認に失敗しました: %w", err)
+		return i18n.Errorf("group.server_check_failed", err)
 	} else if !exists {
-		return fmt.Errorf("サーバーテンプレート '%s' が見つかりません", serverName)
+		return i18n.Errorf("common.template_not_found", serverName)
 	}
 
 	group, err := gm.Load(groupName)
@@ -226,7 +297,7 @@ func (gm *Manager) AddServer(groupName, serverName string, serverManager *server
 	// 既にグループに含まれているかチェック
 	for _, server := range group.Servers {
 		if server == serverName {
-			return fmt.Errorf("サーバー '%s' は既にグループ '%s' に含まれています", serverName, groupName)
+			return i18n.Errorf("group.server_already_member", serverName, groupName)
 		}
 	}
 
@@ -237,12 +308,18 @@ func (gm *Manager) AddServer(groupName, serverName string, serverManager *server
 		return err
 	}
 
-	fmt.Printf("サーバー '%s' をグループ '%s' に追加しました\n", serverName, groupName)
+	fmt.Println(i18n.T("group.server_added", serverName, groupName))
 	return nil
 }
 
 // RemoveServer removes a server from a group
 func (gm *Manager) RemoveServer(groupName, serverName string) error {
+	unlock, err := gm.lockStore()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	group, err := gm.Load(groupName)
 	if err != nil {
 		return err
@@ -258,7 +335,7 @@ func (gm *Manager) RemoveServer(groupName, serverName string) error {
 	}
 
 	if serverIndex == -1 {
-		return fmt.Errorf("サーバー '%s' はグループ '%s' に含まれていません", serverName, groupName)
+		return i18n.Errorf("group.server_not_member", serverName, groupName)
 	}
 
 	// サーバーを削除
@@ -269,7 +346,7 @@ func (gm *Manager) RemoveServer(groupName, serverName string) error {
 		return err
 	}
 
-	fmt.Printf("サーバー '%s' をグループ '%s' から削除しました\n", serverName, groupName)
+	fmt.Println(i18n.T("group.server_removed", serverName, groupName))
 	return nil
 }
 
@@ -280,16 +357,16 @@ func (gm *Manager) Show(name string) error {
 		return err
 	}
 
-	fmt.Printf("グループ名: %s\n", group.Name)
+	fmt.Println(i18n.T("group.detail_name", group.Name))
 	if group.Description != nil {
-		fmt.Printf("説明: %s\n", *group.Description)
+		fmt.Println(i18n.T("group.detail_description", *group.Description))
 	}
-	fmt.Printf("作成日時: %s\n", group.CreatedAt.Format(server.TimestampFormat))
-	fmt.Printf("更新日時: %s\n", group.UpdatedAt.Format(server.TimestampFormat))
-	fmt.Printf("サーバー数: %d\n", len(group.Servers))
+	fmt.Println(i18n.T("group.detail_created_at", group.CreatedAt.Format(server.TimestampFormat)))
+	fmt.Println(i18n.T("group.detail_updated_at", group.UpdatedAt.Format(server.TimestampFormat)))
+	fmt.Println(i18n.T("group.detail_server_count", len(group.Servers)))
 
 	if len(group.Servers) > 0 {
-		fmt.Println("サーバー:")
+		fmt.Println(i18n.T("group.detail_servers"))
 		for _, serverName := range group.Servers {
 			fmt.Printf("  - %s\n", serverName)
 		}
@@ -301,11 +378,11 @@ func (gm *Manager) Show(name string) error {
 // Load loads a group by name
 func (gm *Manager) Load(name string) (*Group, error) {
 	group := &Group{}
-	if err := utils.LoadJSON(gm.getGroupPath(name), group); err != nil {
+	if err := utils.LoadJSON(gm.lookupPath(name), group); err != nil {
 		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("グループ '%s' が見つかりません", name)
+			return nil, i18n.Errorf("group.not_found", name)
 		}
-		return nil, fmt.Errorf("グループの読み込みに失敗しました: %w", err)
+		return nil, i18n.Errorf("group.load_failed", err)
 	}
 
 	return group, nil
@@ -319,7 +396,7 @@ func (gm *Manager) Apply(groupName, mcpConfigPath string, serverManager *server.
 	}
 
 	if len(group.Servers) == 0 {
-		fmt.Printf("グループ '%s' にはサーバーが含まれていません\n", groupName)
+		fmt.Println(i18n.T("group.empty", groupName))
 		return nil
 	}
 
@@ -327,14 +404,14 @@ func (gm *Manager) Apply(groupName, mcpConfigPath string, serverManager *server.
 	for _, serverName := range group.Servers {
 		err := serverManager.AddToMCPConfig(mcpConfigPath, serverName, "", nil)
 		if err != nil {
-			fmt.Printf("警告: サーバー '%s' の追加に失敗しました: %v\n", serverName, err)
+			fmt.Println(i18n.T("group.add_server_failed", serverName, err))
 		} else {
 			successCount++
 		}
 	}
 
-	fmt.Printf("グループ '%s' から %d/%d のサーバーをMCP設定ファイルに追加しました: %s\n",
-		groupName, successCount, len(group.Servers), mcpConfigPath)
+	fmt.Println(i18n.T("group.applied",
+		groupName, successCount, len(group.Servers), mcpConfigPath))
 	return nil
 }
 
@@ -346,7 +423,7 @@ func (gm *Manager) RemoveFromMCP(groupName, mcpConfigPath string, serverManager
 	}
 
 	if len(group.Servers) == 0 {
-		fmt.Printf("グループ '%s' にはサーバーが含まれていません\n", groupName)
+		fmt.Println(i18n.T("group.empty", groupName))
 		return nil
 	}
 
@@ -354,21 +431,32 @@ func (gm *Manager) RemoveFromMCP(groupName, mcpConfigPath string, serverManager
 	for _, serverName := range group.Servers {
 		err := serverManager.RemoveFromMCPConfig(mcpConfigPath, serverName)
 		if err != nil {
-			fmt.Printf("警告: サーバー '%s' の削除に失敗しました: %v\n", serverName, err)
+			fmt.Println(i18n.T("group.remove_server_failed", serverName, err))
 		} else {
 			successCount++
 		}
 	}
 
-	fmt.Printf("グループ '%s' から %d/%d のサーバーをMCP設定ファイルから削除しました: %s\n",
-		groupName, successCount, len(group.Servers), mcpConfigPath)
+	fmt.Println(i18n.T("group.unapplied",
+		groupName, successCount, len(group.Servers), mcpConfigPath))
 	return nil
 }
 
+// lockStore serializes store mutations across mcpjson processes
+func (gm *Manager) lockStore() (func(), error) {
+	return filelock.LockStore(gm.groupsDir)
+}
+
 func (gm *Manager) getGroupPath(name string) string {
 	return filepath.Join(gm.groupsDir, name+config.FileExtension)
 }
 
+// lookupPath returns the path a group is read from: the first read
+// directory that has it, otherwise its path in the manager's directory
+func (gm *Manager) lookupPath(name string) string {
+	return config.LookupPath(gm.readDirs, gm.groupsDir, name)
+}
+
 func (gm *Manager) exists(name string) bool {
 	_, err := os.Stat(gm.getGroupPath(name))
 	return err == nil
@@ -376,21 +464,22 @@ func (gm *Manager) exists(name string) bool {
 
 func (gm *Manager) save(group *Group) error {
 	if err := os.MkdirAll(gm.groupsDir, 0755); err != nil {
-		return fmt.Errorf("グループディレクトリの作成に失敗しました: %w", err)
+		return i18n.Errorf("group.mkdir_failed", err)
 	}
 	return utils.SaveJSON(gm.getGroupPath(group.Name), group)
 }
 
-// Reset deletes all groups
+// Reset deletes all groups. It asks before locking the store; groups
+// created while the user answers are kept.
 func (gm *Manager) Reset(force bool) error {
 	if _, err := os.Stat(gm.groupsDir); os.IsNotExist(err) {
-		fmt.Println("グループディレクトリが存在しません")
+		fmt.Println(i18n.T("group.no_dir"))
 		return nil
 	}
 
 	files, err := os.ReadDir(gm.groupsDir)
 	if err != nil {
-		return fmt.Errorf("グループディレクトリの読み込みに失敗しました: %w", err)
+		return i18n.Errorf("group.read_dir_failed", err)
 	}
 
 	groupFiles := []string{}
@@ -401,34 +490,40 @@ func (gm *Manager) Reset(force bool) error {
 	}
 
 	if len(groupFiles) == 0 {
-		fmt.Println("削除するグループが存在しません")
+		fmt.Println(i18n.T("group.reset_none"))
 		return nil
 	}
 
 	if !force {
-		fmt.Printf("以下の%d個のグループを削除します:\n", len(groupFiles))
+		fmt.Println(i18n.T("group.reset_list", len(groupFiles)))
 		for _, file := range groupFiles {
 			name := strings.TrimSuffix(file, config.FileExtension)
 			fmt.Printf("  - %s\n", name)
 		}
 		fmt.Println()
 
-		if !interaction.Confirm("すべてのグループを削除しますか？") {
-			fmt.Println("リセットをキャンセルしました")
+		if !interaction.Confirm(i18n.T("group.confirm_reset")) {
+			fmt.Println(i18n.T("common.reset_cancelled"))
 			return nil
 		}
 	}
 
+	unlock, err := gm.lockStore()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	deletedCount := 0
 	for _, file := range groupFiles {
 		groupPath := filepath.Join(gm.groupsDir, file)
-		if err := os.Remove(groupPath); err != nil {
-			fmt.Printf("警告: %s の削除に失敗しました: %v\n", file, err)
-		} else {
+		if err := os.Remove(groupPath); err == nil {
 			deletedCount++
+		} else if !os.IsNotExist(err) {
+			fmt.Println(i18n.T("common.remove_failed_warning", file, err))
 		}
 	}
 
-	fmt.Printf("グループを%d個削除しました\n", deletedCount)
+	fmt.Println(i18n.T("group.reset_done", deletedCount))
 	return nil
 }
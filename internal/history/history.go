@@ -0,0 +1,581 @@
+// Package history keeps a journal of the files changed by destructive
+// operations, so that they can be listed and undone later.
+//
+// An operation is wrapped in Begin and Commit: Begin copies the files it
+// may touch into memory, and Commit stores the previous content of the
+// files that actually changed as a new journal entry.
+package history
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/filelock"
+	"github.com/naoto24kawa/mcpjson/internal/gitstore"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/utils"
+)
+
+const (
+	entryFileName = "entry.json"
+	filesDirName  = "files"
+
+	// Backups may contain resolved secrets, so they are only readable by
+	// the owner
+	dirPerm  = 0700
+	filePerm = 0600
+)
+
+var (
+	// ErrNotFound is returned when no entry has the requested ID
+	ErrNotFound = i18n.Error("history.not_found")
+	// ErrNothingToUndo is returned when every entry has been undone
+	ErrNothingToUndo = i18n.Error("history.nothing_to_undo")
+)
+
+// FileSnapshot is the content a file had before an operation
+type FileSnapshot struct {
+	Path string `json:"path"`
+	// Existed is false when the operation created the file
+	Existed bool        `json:"existed"`
+	Mode    os.FileMode `json:"mode,omitempty"`
+	// Blob is the name of the backup in the entry's files directory
+	Blob string `json:"blob,omitempty"`
+	// After is the hash of the file right after the operation, or empty
+	// when the operation removed it
+	After string `json:"after,omitempty"`
+}
+
+// Entry is one recorded operation
+type Entry struct {
+	ID        int            `json:"id"`
+	Operation string         `json:"operation"`
+	CreatedAt time.Time      `json:"createdAt"`
+	Files     []FileSnapshot `json:"files"`
+	// UndoOf is set on entries recorded by Undo
+	UndoOf   int        `json:"undoOf,omitempty"`
+	UndoneAt *time.Time `json:"undoneAt,omitempty"`
+}
+
+// Journal stores entries in a directory, one subdirectory per entry
+type Journal struct {
+	dir        string
+	maxEntries int
+	maxAge     time.Duration
+}
+
+// NewJournal returns a journal kept in dir that never prunes entries
+func NewJournal(dir string) *Journal {
+	return &Journal{dir: dir}
+}
+
+// Open returns the journal of the store with the retention configured in
+// the settings file
+func Open(cfg *config.Config) (*Journal, error) {
+	settings, err := cfg.LoadSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	journal := NewJournal(cfg.HistoryDir())
+	journal.SetRetention(settings.History.MaxEntries, time.Duration(settings.History.MaxAgeDays)*24*time.Hour)
+	return journal, nil
+}
+
+// SetRetention sets how many entries are kept and for how long.
+// Zero or negative values disable the corresponding limit.
+func (j *Journal) SetRetention(maxEntries int, maxAge time.Duration) {
+	j.maxEntries = maxEntries
+	j.maxAge = maxAge
+}
+
+// Run records fn as operation in the store's journal. paths are the files
+// and directories fn may change. When the store is a git repository, the
+// changes are also committed. The store lock fn takes for its change is
+// kept until they are recorded, so that a change another process makes in
+// between is not recorded as part of operation. A failure to record them
+// is only reported as a warning, since fn has already been carried out.
+func Run(cfg *config.Config, operation string, paths []string, fn func() error) error {
+	journal, err := Open(cfg)
+	if err != nil {
+		return err
+	}
+
+	release := filelock.KeepStore(cfg.ProfilesDir)
+	defer release()
+
+	snapshot, err := journal.Begin(operation, paths...)
+	if err != nil {
+		return err
+	}
+
+	fnErr := fn()
+	unlock, err := filelock.LockStore(cfg.ProfilesDir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T("history.record_failed", err))
+		return fnErr
+	}
+	defer unlock()
+
+	if _, err := snapshot.Commit(); err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T("history.record_failed", err))
+	}
+	if err := gitstore.AutoCommit(cfg.BaseDir, operation); err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T("history.commit_failed", err))
+	}
+	return fnErr
+}
+
+// Snapshot holds the content of files before an operation
+type Snapshot struct {
+	journal   *Journal
+	operation string
+	undoOf    int
+	dirs      []string
+	files     map[string]*fileState
+}
+
+type fileState struct {
+	existed bool
+	mode    os.FileMode
+	data    []byte
+}
+
+// Begin reads the current content of paths. A directory stands for every
+// file below it, including files the operation creates in it.
+func (j *Journal) Begin(operation string, paths ...string) (*Snapshot, error) {
+	s := &Snapshot{journal: j, operation: operation, files: make(map[string]*fileState)}
+
+	for _, path := range paths {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return nil, i18n.Errorf("history.backup_failed", err)
+		}
+
+		if info, err := os.Stat(abs); err == nil && info.IsDir() {
+			s.dirs = append(s.dirs, abs)
+			files, err := listFiles(abs)
+			if err != nil {
+				return nil, i18n.Errorf("history.backup_failed", err)
+			}
+			for _, file := range files {
+				if err := s.read(file); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		} else if err != nil && os.IsNotExist(err) {
+			// 存在しないパスは、これから作られるファイルとしてもディレクトリとしても扱う
+			s.dirs = append(s.dirs, abs)
+		}
+
+		if err := s.read(abs); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+func (s *Snapshot) read(path string) error {
+	if _, ok := s.files[path]; ok {
+		return nil
+	}
+
+	state := &fileState{}
+	info, err := os.Stat(path)
+	switch {
+	case err == nil && info.IsDir():
+		return nil
+	case err == nil:
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return i18n.Errorf("history.backup_failed", err)
+		}
+		state.existed = true
+		state.mode = info.Mode().Perm()
+		state.data = data
+	case !os.IsNotExist(err):
+		return i18n.Errorf("history.backup_failed", err)
+	}
+
+	s.files[path] = state
+	return nil
+}
+
+// Commit stores the files that changed since Begin as a new entry and
+// prunes old entries. It returns nil when nothing changed.
+func (s *Snapshot) Commit() (*Entry, error) {
+	// 操作中に作成されたファイルを追加する
+	for _, dir := range s.dirs {
+		// 存在しなかったパスがディレクトリとして作られた場合は、中のファイルで置き換える
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			delete(s.files, dir)
+		}
+		files, err := listFiles(dir)
+		if err != nil {
+			return nil, i18n.Errorf("history.check_changed_failed", err)
+		}
+		for _, file := range files {
+			if _, ok := s.files[file]; !ok {
+				s.files[file] = &fileState{}
+			}
+		}
+	}
+
+	paths := make([]string, 0, len(s.files))
+	for path := range s.files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	entry := &Entry{Operation: s.operation, CreatedAt: time.Now(), UndoOf: s.undoOf}
+	var blobs [][]byte
+	for _, path := range paths {
+		before := s.files[path]
+		after, err := fileHash(path)
+		if err != nil {
+			return nil, i18n.Errorf("history.check_changed_failed", err)
+		}
+		if after == before.hash() {
+			continue
+		}
+
+		snapshot := FileSnapshot{Path: path, Existed: before.existed, After: after}
+		if before.existed {
+			snapshot.Mode = before.mode
+			snapshot.Blob = strconv.Itoa(len(blobs))
+			blobs = append(blobs, before.data)
+		}
+		entry.Files = append(entry.Files, snapshot)
+	}
+
+	if len(entry.Files) == 0 {
+		return nil, nil
+	}
+	if err := s.journal.save(entry, blobs); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (f *fileState) hash() string {
+	if !f.existed {
+		return ""
+	}
+	sum := sha256.Sum256(f.data)
+	return hex.EncodeToString(sum[:])
+}
+
+// List returns every entry, newest first
+func (j *Journal) List() ([]*Entry, error) {
+	ids, err := j.ids()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*Entry, 0, len(ids))
+	for i := len(ids) - 1; i >= 0; i-- {
+		entry, err := j.Get(ids[i])
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Get returns the entry with the given ID
+func (j *Journal) Get(id int) (*Entry, error) {
+	entry := &Entry{}
+	data, err := os.ReadFile(filepath.Join(j.entryDir(id), entryFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: #%d", ErrNotFound, id)
+		}
+		return nil, i18n.Errorf("history.load_failed", err)
+	}
+	if err := json.Unmarshal(data, entry); err != nil {
+		return nil, i18n.Errorf("history.parse_failed", id, err)
+	}
+	return entry, nil
+}
+
+// Undo restores the files of the entry with the given ID, or of the latest
+// entry that has not been undone when id is 0. The restore is recorded as
+// a new entry, so undoing that entry redoes the operation. Unless force is
+// set, Undo fails when a file was changed again after the operation.
+func (j *Journal) Undo(id int, force bool) (*Entry, error) {
+	unlock, err := filelock.LockStore(j.dir)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	entry, err := j.undoTarget(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !force {
+		var changed []string
+		for _, file := range entry.Files {
+			current, err := fileHash(file.Path)
+			if err != nil {
+				return nil, i18n.Errorf("history.stat_failed", err)
+			}
+			if current != file.After {
+				changed = append(changed, file.Path)
+			}
+		}
+		if len(changed) > 0 {
+			return nil, i18n.Errorf("history.modified_since",
+				entry.ID, strings.Join(changed, "\n  "))
+		}
+	}
+
+	paths := make([]string, 0, len(entry.Files))
+	for _, file := range entry.Files {
+		paths = append(paths, file.Path)
+	}
+	snapshot, err := j.Begin(fmt.Sprintf("undo #%d (%s)", entry.ID, entry.Operation), paths...)
+	if err != nil {
+		return nil, err
+	}
+	snapshot.undoOf = entry.ID
+
+	if err := j.restore(entry); err != nil {
+		return nil, err
+	}
+
+	if _, err := snapshot.Commit(); err != nil {
+		return nil, err
+	}
+
+	// 取り消しの取り消しでは、元の操作を未取り消しに戻す
+	now := time.Now()
+	entry.UndoneAt = &now
+	if err := j.writeEntry(entry); err != nil {
+		return nil, err
+	}
+	if entry.UndoOf != 0 {
+		if original, err := j.Get(entry.UndoOf); err == nil {
+			original.UndoneAt = nil
+			if err := j.writeEntry(original); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return entry, nil
+}
+
+func (j *Journal) undoTarget(id int) (*Entry, error) {
+	if id != 0 {
+		entry, err := j.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		if entry.UndoneAt != nil {
+			return nil, i18n.Errorf("history.already_undone", id)
+		}
+		return entry, nil
+	}
+
+	entries, err := j.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.UndoneAt == nil && entry.UndoOf == 0 {
+			return entry, nil
+		}
+	}
+	return nil, ErrNothingToUndo
+}
+
+func (j *Journal) restore(entry *Entry) error {
+	for _, file := range entry.Files {
+		if !file.Existed {
+			if err := os.Remove(file.Path); err != nil && !os.IsNotExist(err) {
+				return i18n.Errorf("history.remove_failed", err)
+			}
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(j.entryDir(entry.ID), filesDirName, file.Blob))
+		if err != nil {
+			return i18n.Errorf("history.backup_read_failed", err)
+		}
+		if err := os.MkdirAll(filepath.Dir(file.Path), config.DefaultDirPerm); err != nil {
+			return i18n.Errorf("common.mkdir_failed", err)
+		}
+		if err := utils.WriteFileAtomic(file.Path, data, file.Mode); err != nil {
+			return i18n.Errorf("history.restore_failed", err)
+		}
+		if err := os.Chmod(file.Path, file.Mode); err != nil {
+			return i18n.Errorf("history.restore_failed", err)
+		}
+	}
+	return nil
+}
+
+// Prune removes entries beyond the retention limits and returns how many
+// were removed
+func (j *Journal) Prune() (int, error) {
+	unlock, err := filelock.LockStore(j.dir)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	return j.prune()
+}
+
+func (j *Journal) prune() (int, error) {
+	ids, err := j.ids()
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for i, id := range ids {
+		newer := len(ids) - 1 - i
+		expired := j.maxEntries > 0 && newer >= j.maxEntries
+		if !expired && j.maxAge > 0 {
+			entry, err := j.Get(id)
+			expired = err == nil && time.Since(entry.CreatedAt) > j.maxAge
+		}
+		if !expired {
+			continue
+		}
+
+		if err := os.RemoveAll(j.entryDir(id)); err != nil {
+			return removed, i18n.Errorf("history.prune_failed", err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+func (j *Journal) save(entry *Entry, blobs [][]byte) error {
+	unlock, err := filelock.LockStore(j.dir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	ids, err := j.ids()
+	if err != nil {
+		return err
+	}
+	entry.ID = 1
+	if len(ids) > 0 {
+		entry.ID = ids[len(ids)-1] + 1
+	}
+
+	filesDir := filepath.Join(j.entryDir(entry.ID), filesDirName)
+	if err := os.MkdirAll(filesDir, dirPerm); err != nil {
+		return i18n.Errorf("history.mkdir_failed", err)
+	}
+	for i, data := range blobs {
+		if err := os.WriteFile(filepath.Join(filesDir, strconv.Itoa(i)), data, filePerm); err != nil {
+			os.RemoveAll(j.entryDir(entry.ID))
+			return i18n.Errorf("history.backup_save_failed", err)
+		}
+	}
+	if err := j.writeEntry(entry); err != nil {
+		os.RemoveAll(j.entryDir(entry.ID))
+		return err
+	}
+
+	_, err = j.prune()
+	return err
+}
+
+func (j *Journal) writeEntry(entry *Entry) error {
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return i18n.Errorf("history.save_failed", err)
+	}
+	if err := utils.WriteFileAtomic(filepath.Join(j.entryDir(entry.ID), entryFileName), append(data, '\n'), filePerm); err != nil {
+		return i18n.Errorf("history.save_failed", err)
+	}
+	return nil
+}
+
+// ids returns the IDs of all entries in ascending order
+func (j *Journal) ids() ([]int, error) {
+	dirEntries, err := os.ReadDir(j.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, i18n.Errorf("history.load_failed", err)
+	}
+
+	var ids []int
+	for _, e := range dirEntries {
+		if !e.IsDir() {
+			continue
+		}
+		if id, err := strconv.Atoi(e.Name()); err == nil && id > 0 {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	return ids, nil
+}
+
+func (j *Journal) entryDir(id int) string {
+	return filepath.Join(j.dir, fmt.Sprintf("%06d", id))
+}
+
+// listFiles returns the regular files below dir, skipping hidden files
+// such as lock files and temporary files of atomic writes
+func listFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if strings.HasPrefix(d.Name(), ".") && path != dir {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Type().IsRegular() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files, err
+}
+
+// fileHash returns the hash of the file at path, or an empty string when
+// it does not exist
+func fileHash(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,260 @@
+package history
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile(%s) error = %v", path, err)
+	}
+	return string(data)
+}
+
+func record(t *testing.T, journal *Journal, operation string, paths []string, fn func()) *Entry {
+	t.Helper()
+	snapshot, err := journal.Begin(operation, paths...)
+	if err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	fn()
+	entry, err := snapshot.Commit()
+	if err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	return entry
+}
+
+func TestJournal_CommitRecordsChangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	journal := NewJournal(filepath.Join(dir, ".history"))
+	profilesDir := filepath.Join(dir, "profiles")
+	writeFile(t, filepath.Join(profilesDir, "a.jsonc"), "a")
+	writeFile(t, filepath.Join(profilesDir, "b.jsonc"), "b")
+	writeFile(t, filepath.Join(profilesDir, "c.jsonc"), "c")
+
+	entry := record(t, journal, "rename a d", []string{profilesDir}, func() {
+		os.Rename(filepath.Join(profilesDir, "a.jsonc"), filepath.Join(profilesDir, "d.jsonc"))
+		writeFile(t, filepath.Join(profilesDir, "b.jsonc"), "b2")
+	})
+	if entry == nil {
+		t.Fatal("Commit() = nil, want entry")
+	}
+
+	got := map[string]FileSnapshot{}
+	for _, file := range entry.Files {
+		got[filepath.Base(file.Path)] = file
+	}
+	if len(got) != 3 {
+		t.Fatalf("Files = %v, want a, b and d", entry.Files)
+	}
+	if !got["a.jsonc"].Existed || got["a.jsonc"].After != "" {
+		t.Errorf("a.jsonc = %+v, want removed", got["a.jsonc"])
+	}
+	if got["d.jsonc"].Existed {
+		t.Errorf("d.jsonc = %+v, want created", got["d.jsonc"])
+	}
+	if _, ok := got["c.jsonc"]; ok {
+		t.Error("変更されていない c.jsonc が記録されています")
+	}
+}
+
+func TestJournal_CommitWithoutChanges(t *testing.T) {
+	dir := t.TempDir()
+	journal := NewJournal(filepath.Join(dir, ".history"))
+	target := filepath.Join(dir, ".mcp.json")
+	writeFile(t, target, "{}")
+
+	if entry := record(t, journal, "noop", []string{target}, func() {}); entry != nil {
+		t.Errorf("Commit() = %+v, want nil", entry)
+	}
+	entries, err := journal.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("List() = %d entries, want 0", len(entries))
+	}
+}
+
+func TestJournal_Undo(t *testing.T) {
+	dir := t.TempDir()
+	journal := NewJournal(filepath.Join(dir, ".history"))
+	profilesDir := filepath.Join(dir, "profiles")
+	target := filepath.Join(dir, "project", ".mcp.json")
+	writeFile(t, filepath.Join(profilesDir, "work.jsonc"), "work")
+
+	record(t, journal, "delete work", []string{profilesDir}, func() {
+		os.Remove(filepath.Join(profilesDir, "work.jsonc"))
+	})
+	record(t, journal, "apply work", []string{target}, func() {
+		writeFile(t, target, "applied")
+	})
+
+	// 直前の操作（apply）から順に取り消す
+	entry, err := journal.Undo(0, false)
+	if err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	if entry.Operation != "apply work" {
+		t.Errorf("Undo() = %s, want apply work", entry.Operation)
+	}
+	if _, err := os.Stat(target); !os.IsNotExist(err) {
+		t.Error("apply で作成されたファイルが削除されていません")
+	}
+
+	entry, err = journal.Undo(0, false)
+	if err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	if entry.Operation != "delete work" {
+		t.Errorf("Undo() = %s, want delete work", entry.Operation)
+	}
+	if got := readFile(t, filepath.Join(profilesDir, "work.jsonc")); got != "work" {
+		t.Errorf("restored content = %q, want work", got)
+	}
+
+	if _, err := journal.Undo(0, false); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("Undo() error = %v, want ErrNothingToUndo", err)
+	}
+
+	// 取り消しを取り消すと元に戻る
+	entries, err := journal.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entries[0].UndoOf != 1 {
+		t.Fatalf("latest entry = %+v, want undo of #1", entries[0])
+	}
+	if _, err := journal.Undo(entries[0].ID, false); err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(profilesDir, "work.jsonc")); !os.IsNotExist(err) {
+		t.Error("やり直し後もファイルが残っています")
+	}
+	original, err := journal.Get(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if original.UndoneAt != nil {
+		t.Error("やり直した操作が取り消し済みのままです")
+	}
+}
+
+func TestJournal_CommitCreatedDirectory(t *testing.T) {
+	dir := t.TempDir()
+	journal := NewJournal(filepath.Join(dir, ".history"))
+	stateDir := filepath.Join(dir, "state")
+
+	// 操作前に存在しなかったパスがディレクトリとして作られる
+	entry := record(t, journal, "apply work", []string{stateDir}, func() {
+		writeFile(t, filepath.Join(stateDir, "work.json"), "state")
+	})
+	if entry == nil || len(entry.Files) != 1 || entry.Files[0].Path != filepath.Join(stateDir, "work.json") {
+		t.Fatalf("Commit() = %+v, want the file created in the directory", entry)
+	}
+
+	if _, err := journal.Undo(0, false); err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(stateDir, "work.json")); !os.IsNotExist(err) {
+		t.Error("作成されたディレクトリ内のファイルが削除されていません")
+	}
+}
+
+func TestJournal_UndoRefusesLaterChanges(t *testing.T) {
+	dir := t.TempDir()
+	journal := NewJournal(filepath.Join(dir, ".history"))
+	target := filepath.Join(dir, ".mcp.json")
+	writeFile(t, target, "before")
+
+	entry := record(t, journal, "apply work", []string{target}, func() {
+		writeFile(t, target, "applied")
+	})
+	writeFile(t, target, "edited by hand")
+
+	_, err := journal.Undo(entry.ID, false)
+	if err == nil || !strings.Contains(err.Error(), target) {
+		t.Fatalf("Undo() error = %v, want error naming %s", err, target)
+	}
+	if got := readFile(t, target); got != "edited by hand" {
+		t.Errorf("content = %q, want unchanged", got)
+	}
+
+	if _, err := journal.Undo(entry.ID, true); err != nil {
+		t.Fatalf("Undo(force) error = %v", err)
+	}
+	if got := readFile(t, target); got != "before" {
+		t.Errorf("content = %q, want before", got)
+	}
+}
+
+func TestJournal_Prune(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxEntries int
+		maxAge     time.Duration
+		age        time.Duration
+		wantIDs    []int
+	}{
+		{name: "件数の上限", maxEntries: 2, wantIDs: []int{4, 3}},
+		{name: "保持期間", maxAge: time.Hour, age: 2 * time.Hour, wantIDs: nil},
+		{name: "制限なし", wantIDs: []int{4, 3, 2, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			journal := NewJournal(filepath.Join(dir, ".history"))
+			target := filepath.Join(dir, ".mcp.json")
+			for i := 0; i < 4; i++ {
+				entry := record(t, journal, "apply", []string{target}, func() {
+					writeFile(t, target, strings.Repeat("x", i+1))
+				})
+				entry.CreatedAt = entry.CreatedAt.Add(-tt.age)
+				if err := journal.writeEntry(entry); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			journal.SetRetention(tt.maxEntries, tt.maxAge)
+			if _, err := journal.Prune(); err != nil {
+				t.Fatalf("Prune() error = %v", err)
+			}
+
+			entries, err := journal.List()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ids []int
+			for _, entry := range entries {
+				ids = append(ids, entry.ID)
+			}
+			if len(ids) != len(tt.wantIDs) {
+				t.Fatalf("IDs = %v, want %v", ids, tt.wantIDs)
+			}
+			for i := range ids {
+				if ids[i] != tt.wantIDs[i] {
+					t.Errorf("IDs = %v, want %v", ids, tt.wantIDs)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,692 @@
+package i18n
+
+// en is the English message catalog
+var en = map[string]string{
+	"bundle.collision":             "%s '%s' already exists (use --on-conflict %s|%s|%s to choose what to do)",
+	"bundle.collision_prompt":      "%s '%s' already exists. What do you want to do?",
+	"bundle.store_changed":         "%s '%s' was added to the store while the import was being confirmed; run the import again",
+	"bundle.convert_failed":        "Failed to convert %s '%s': %w",
+	"bundle.entry_failed":          "Archive entry %s: %w",
+	"bundle.entry_too_large":       "A file in the archive is too large: %s",
+	"bundle.exported":              "Exported to '%s' (profiles: %d, server templates: %d, groups: %d)",
+	"bundle.imported":              "Imported %[2]d items from '%[1]s'",
+	"bundle.invalid_entry":         "The archive has an invalid entry: %s",
+	"bundle.item_identical":        "  = %s '%s' (identical, skipped)",
+	"bundle.item_identical_copy":   "  = %s '%s' (identical to '%s', skipped)",
+	"bundle.item_overwritten":      "  ~ %s '%s' (overwritten)",
+	"bundle.item_renamed":          "  + %s '%s' → '%s' (renamed)",
+	"bundle.item_skipped":          "  - %s '%s' (skipped)",
+	"bundle.manifest_parse_failed": "Failed to parse the manifest: %w",
+	"bundle.manifest_write_failed": "Failed to create the manifest: %w",
+	"bundle.missing_manifest":      "The archive has no %s",
+	"bundle.need_profile_or_all":   "Specify profile names or --all",
+	"bundle.no_import_file":        "No file to import was given",
+	"bundle.no_profiles":           "No profiles to export were given",
+	"bundle.read_dir_failed":       "Failed to read the directory: %w",
+	"bundle.read_failed":           "Failed to read the archive: %w",
+	"bundle.save_failed":           "Failed to save %s '%s': %w",
+	"bundle.secrets_replaced":      "The following values were replaced with secret references",
+	"bundle.set_secrets":           "Set the following secrets with 'mcpjson secret set <name>'",
+	"bundle.unknown_entry":         "The archive has an unknown entry: %s",
+	"bundle.unsupported_version":   "This version of mcpjson cannot read the archive (format: %d)",
+	"bundle.write_failed":          "Failed to write the archive: %w",
+
+	"catalog.column_description": "Description",
+	"catalog.column_env":         "Required env",
+	"catalog.column_id":          "ID",
+	"catalog.define_secret":      "Define the secret '%s' in your secret backend for %s",
+	"catalog.entry_no_id":        "Server %[2]d of the catalog %[1]s has no id",
+	"catalog.env_empty":          "No value was entered for %s",
+	"catalog.env_missing":        "Give the required environment variables %s with --env",
+	"catalog.http_status":        "The server returned %s",
+	"catalog.install_no_id":      "Specify a catalog id",
+	"catalog.installed":          "Installed '%s' as the server template '%s'",
+	"catalog.no_match":           "No server matches '%s'",
+	"catalog.no_sources":         "No catalog is configured. Add paths or URLs to catalogs in the settings file, or use --catalog",
+	"catalog.not_found":          "There is no server '%s' in the catalogs (try server search)",
+	"catalog.parse_failed":       "Cannot parse the catalog %s: %w",
+	"catalog.read_failed":        "Cannot read the catalog %s: %w",
+	"catalog.skipped":            "Warning: %v",
+
+	"client.remote_unsupported": "%s does not support %s servers: %s",
+	"client.unknown":            "Unsupported client '%s' (available: %s)",
+
+	"cmd.config_init_failed": "Failed to initialise the configuration: %w",
+	"cmd.file_create_failed": "Failed to create the file: %w",
+	"cmd.file_read_failed":   "Failed to read the file: %w",
+	"cmd.file_save_failed":   "Failed to save the file: %w",
+	"cmd.invalid_flag":       "%v (see '%s --help')",
+	"cmd.unexpected_args":    "Unexpected arguments: %s (see '%s --help')",
+	"cmd.unknown_command":    "Unknown command: '%s' (see '%s --help')",
+
+	"common.copy_same_name":           "The source and destination names are the same",
+	"common.created_at":               "Created",
+	"common.delete_cancelled":         "Deletion cancelled",
+	"common.detail_created_at":        "  Created: %s",
+	"common.detail_description":       "  Description: %s",
+	"common.detail_updated_at":        "  Updated: %s",
+	"common.error_prefix":             "Error:",
+	"common.json_failed":              "Failed to generate JSON: %w",
+	"common.load_failed_skip":         "Error: failed to load %s: %v",
+	"common.mcp_read_existing_failed": "Failed to read the existing MCP config file: %w",
+	"common.mcp_read_failed":          "Failed to read the MCP config file: %w",
+	"common.mcp_save_failed":          "Failed to save the MCP config file: %w",
+	"common.mcp_update_failed":        "Failed to update the MCP config: %w",
+	"common.mkdir_failed":             "Failed to create the directory: %w",
+	"common.overwrite_cancelled":      "Overwrite cancelled",
+	"common.remove_failed_warning":    "Warning: failed to delete %s: %v",
+	"common.reset_cancelled":          "Reset cancelled",
+	"common.server_count":             "Servers",
+	"common.server_invalid":           "Invalid configuration for server '%s': %w",
+	"common.template_not_found":       "Server template '%s' not found",
+
+	"completion.no_shell":          "Specify a shell (bash|zsh|fish)",
+	"completion.unsupported_shell": "Unsupported shell: %s (bash|zsh|fish)",
+
+	"config.home_dir_failed":           "Failed to get the home directory: %w",
+	"config.mkdir_failed":              "Failed to create directory %s: %w",
+	"config.project_empty_target":      "targets in %s contains an empty path",
+	"config.project_parse_failed":      "Failed to parse the project file %s: %w",
+	"config.project_read_failed":       "Failed to read the project file %s: %w",
+	"config.project_templates_not_dir": "Template directory %s not found (templates in %s)",
+	"config.settings_parse_failed":     "Failed to parse the settings file %s: %w",
+	"config.settings_read_failed":      "Failed to read the settings file: %w",
+	"config.store_duplicate":           "Store name '%s' is duplicated or reserved (%s)",
+	"config.store_incomplete":          "Each entry of stores in %s needs a name and a path",
+	"config.unknown_store":             "Store '%s' not found (available: %s)",
+
+	"copy.no_destination": "No destination profile name given",
+
+	"detail.load_failed":     "Failed to load the profile: %v",
+	"detail.no_profile":      "No profile name given",
+	"detail.resolved_format": "--resolved requires the %s or %s format",
+
+	"diff.empty_source":   "No comparison target given: '%s'",
+	"diff.hunk_added":     "@@ %s (added) @@",
+	"diff.hunk_removed":   "@@ %s (removed) @@",
+	"diff.no_changes":     "No differences (%s and %s)",
+	"diff.summary":        "\nAdded: %d, removed: %d, changed: %d",
+	"diff.unknown_format": "Unknown output format: '%s' (available: %s, %s)",
+	"diff.unknown_kind":   "Unknown comparison target kind: '%s' (available: %s, %s, %s, %s)",
+	"diff.usage":          "Specify two targets to compare\nUsage: mcpjson diff <from> <to> [--format text|json]",
+
+	"doctor.column_info":        "Server info",
+	"doctor.column_latency":     "Latency",
+	"doctor.column_server":      "Server",
+	"doctor.column_status":      "Status",
+	"doctor.column_tools":       "Tools",
+	"doctor.error_detail":       "\n%s: %s",
+	"doctor.failed":             "%d servers failed to start or respond",
+	"doctor.failed_with_stderr": "%w\nStandard error:\n%s",
+	"doctor.invalid_result":     "Cannot parse the response to %s: %w",
+	"doctor.remote_skipped":     "%s servers cannot be checked",
+	"doctor.status_failed":      "Failed",
+	"doctor.status_ok":          "OK",
+	"doctor.status_skipped":     "Skipped",
+	"doctor.stderr_header":      "  Standard error:",
+	"doctor.summary":            "\n%d of %d servers responded",
+
+	"filelock.busy":          "Could not acquire the lock: %s\nAnother mcpjson process may be running. If none is running, delete this file",
+	"filelock.create_failed": "Failed to create the lock file: %w",
+	"filelock.mkdir_failed":  "Failed to create the lock file directory: %w",
+	"filelock.path_failed":   "Failed to resolve the lock file path: %w",
+	"filelock.remove_failed": "Failed to remove the lock file: %w",
+	"filelock.write_failed":  "Failed to write the lock file: %w",
+
+	"gitstore.conflict":         "Changed both locally and on the remote:\n%s\nUse --prefer local or --prefer remote to choose which changes to keep",
+	"gitstore.git_failed":       "git %s failed: %s",
+	"gitstore.git_not_found":    "git not found: %w",
+	"gitstore.gitignore_failed": "Failed to create .gitignore: %w",
+	"gitstore.init_commit":      "Initialise",
+	"gitstore.merge_commit":     "Merge remote changes",
+	"gitstore.no_remote":        "No remote is configured (set one with 'mcpjson sync remote <URL>')",
+	"gitstore.not_repository":   "The store is not a git repository (initialise it with 'mcpjson sync init')",
+	"gitstore.presync_commit":   "Changes before sync",
+
+	"group.add_server_failed":     "Warning: failed to add server '%s': %v",
+	"group.already_exists":        "Group '%s' already exists\nChoose another name or use --force to overwrite it",
+	"group.applied":               "Added %[2]d/%[3]d servers of group '%[1]s' to the MCP config file: %[4]s",
+	"group.column_name":           "Group",
+	"group.confirm_delete":        "Delete group '%s'?",
+	"group.confirm_reset":         "Delete all groups?",
+	"group.created":               "Created group '%s'",
+	"group.delete_failed":         "Failed to delete the group: %w",
+	"group.deleted":               "Deleted group '%s'",
+	"group.detail_created_at":     "Created: %s",
+	"group.detail_description":    "Description: %s",
+	"group.detail_name":           "Group: %s",
+	"group.detail_server_count":   "Servers: %d",
+	"group.detail_servers":        "Servers:",
+	"group.detail_updated_at":     "Updated: %s",
+	"group.empty":                 "Group '%s' has no servers",
+	"group.load_failed":           "Failed to load the group: %w",
+	"group.load_failed_skip":      "Error: failed to load group %s: %v",
+	"group.mkdir_failed":          "Failed to create the group directory: %w",
+	"group.no_dir":                "The group directory does not exist",
+	"group.none":                  "No groups",
+	"group.not_found":             "Group '%s' not found",
+	"group.read_dir_failed":       "Failed to read the group directory: %w",
+	"group.remove_old_failed":     "Failed to delete the old group file: %w",
+	"group.remove_server_failed":  "Warning: failed to remove server '%s': %v",
+	"group.renamed":               "Renamed group '%s' to '%s'",
+	"group.reset_done":            "Deleted %d groups",
+	"group.reset_list":            "The following %d groups will be deleted:",
+	"group.reset_none":            "There are no groups to delete",
+	"group.server_added":          "Added server '%s' to group '%s'",
+	"group.server_already_member": "Server '%s' is already in group '%s'",
+	"group.server_check_failed":   "Failed to check whether the server exists: %w",
+	"group.server_not_member":     "Server '%s' is not in group '%s'",
+	"group.server_removed":        "Removed server '%s' from group '%s'",
+	"group.unapplied":             "Removed %[2]d/%[3]d servers of group '%[1]s' from the MCP config file: %[4]s",
+
+	"help.apply.client":            "Write in the format of the given client (without --to, to the client's default path)",
+	"help.apply.conflict":          "What to do on conflicts (profile-wins|file-wins|fail)",
+	"help.apply.dry_run":           "Show the changes without writing them",
+	"help.apply.format":            "Output format of --dry-run (text|json)",
+	"help.apply.long":              "Writes the servers of a profile to an MCP config file.\nWithout a profile name the default profile '%s' is applied.",
+	"help.apply.mode":              "How to treat existing servers (replace|merge|update-only)",
+	"help.apply.short":             "Apply a profile to an MCP config file",
+	"help.apply.show_secrets":      "Show env and header values, sensitive flags and URL parameters in the --dry-run output instead of hiding them",
+	"help.apply.to":                "MCP config file to write (default: the targets of the project file, or ./.mcp.json)",
+	"help.apply.via_proxy":         "Write a single 'mcpjson proxy' entry instead of one entry per server",
+	"help.completion.long":         "Writes a completion script for bash, zsh or fish to standard output.\nProfile names, server template names and the servers in MCP config files are completed as well.",
+	"help.completion.short":        "Generate a shell completion script",
+	"help.copy.long":               "Copies a profile under another name.\nWithout a source the default profile '%s' is copied.",
+	"help.copy.short":              "Copy a profile",
+	"help.create.long":             "Creates an empty profile, or one based on the profile given with --template.\nWithout a profile name the default profile '%s' is created.",
+	"help.create.short":            "Create a profile",
+	"help.create.template":         "Profile to start from",
+	"help.delete.long":             "Deletes a profile.\nWithout a profile name the default profile '%s' is deleted.",
+	"help.delete.short":            "Delete a profile",
+	"help.detail.long":             "Shows the contents of a profile. With --resolved the extends chain is flattened and the profile each server came from is shown.",
+	"help.detail.resolved":         "Flatten the extends chain (json|yaml only)",
+	"help.detail.short":            "Show the details of a profile",
+	"help.detail.tools":            "List the tools the servers of the profile expose (uses the results of server inspect)",
+	"help.diff.format":             "Output format (text|json)",
+	"help.diff.json":               "Same as --format json",
+	"help.diff.long":               "Shows the differences between the server settings of two sources, given as:\n  profile:<name>   servers of a profile (variables not expanded)\n  build:<name>     what apply would write (variables and secrets expanded)\n  template:<name>  a server template\n  file:<path>      an MCP config file\nWithout a prefix, paths and existing files are read as MCP config files and anything else as a profile.",
+	"help.diff.short":              "Compare profiles, MCP config files and templates",
+	"help.diff.show_secrets":       "Show env and header values, sensitive flags and URL parameters instead of hiding them",
+	"help.doctor.long":             "Launches the stdio servers of a profile with the configuration apply would write, sends the MCP initialize and tools/list requests and checks the responses.\nShows the latency, server info and tool count, and the standard error output of servers that failed. HTTP/SSE servers are skipped.\nUses '%s' when no profile name is given.",
+	"help.doctor.short":            "Launch the servers of a profile and check that they work",
+	"help.export.all":              "Export every profile, template and group",
+	"help.export.long":             "Writes profiles, with the server templates and groups they refer to, to an archive.\nWith --all every profile, template and group is written.\nValues such as tokens and passwords are replaced with ${secret:...} references.",
+	"help.export.output":           "File to write (default: <profile>.tar.gz, or mcpjson-bundle.tar.gz with --all)",
+	"help.export.short":            "Export profiles and server templates to an archive",
+	"help.flag.detail":             "Show details",
+	"help.flag.force":              "Do not ask for confirmation",
+	"help.flag.help":               "Show help",
+	"help.flag.lang":               "Language of messages (ja|en)",
+	"help.flag.output":             "Output format (table|json|yaml)",
+	"help.flag.store":              "Store to write to (personal, project, or a name from stores in settings.jsonc)",
+	"help.flag.version":            "Show the version",
+	"help.group.long":              "Manages server groups.\n\nNote: groups are still under development",
+	"help.group.short":             "Manage server groups",
+	"help.group_list.short":        "List groups",
+	"help.heading.commands":        "Commands:",
+	"help.heading.examples":        "Examples:",
+	"help.heading.flags":           "Flags:",
+	"help.heading.global_flags":    "Global flags:",
+	"help.heading.usage":           "Usage:",
+	"help.help.short":              "Show help for a command",
+	"help.history.limit":           "Number of entries to show (0 shows all)",
+	"help.history.long":            "Shows the recorded operations, newest first.\nBefore deletes, renames, resets, applies and similar operations the files they change are saved to ~/.mcpconfig/.history.\nUndoing is recorded as well, so undoing the undo's ID redoes the operation.",
+	"help.history.short":           "Show the operation history",
+	"help.history_prune.short":     "Remove entries past the retention",
+	"help.history_show.short":      "Show the files an operation changed",
+	"help.import.long":             "Reads an archive written by export.\nWhen a name is already taken you can rename, skip or overwrite the item.\nReferences to renamed templates and profiles are rewritten automatically.",
+	"help.import.on_conflict":      "What to do when a name is taken (rename|skip|overwrite)",
+	"help.import.short":            "Import an archive",
+	"help.list.short":              "List profiles",
+	"help.mcp_serve.allow":         "Tools to offer (comma-separated, \"*\" for all)",
+	"help.mcp_serve.long":          "Runs mcpjson as a stdio MCP server that offers tools for agents to list profiles, review their changes and apply them.\nChoose the tools to offer with --allow or mcpServe.allow in the settings file. Without either only the tools that do not change any file (list_profiles, list_templates, list_groups, diff_profile) are offered; \"*\" offers every tool.\napply_profile only runs when called with the confirm argument set to true.",
+	"help.mcp_serve.short":         "Run mcpjson as an MCP server",
+	"help.merge.long":              "Combines the servers of the source profiles into the destination profile.",
+	"help.merge.short":             "Merge profiles",
+	"help.more":                    "Run '%s <command> --help' for more about a command",
+	"help.path.long":               "Prints the absolute path of a profile file. Without a profile name the path of the default profile is printed.",
+	"help.path.short":              "Print the path of a profile file",
+	"help.proxy.long":              "Starts every enabled stdio server of the profile and offers them together as one stdio MCP server.\nTool names are prefixed with \"<server>__\". Set tools.allow / tools.deny on a server of the profile to choose which of its tools are offered.\nServers that fail to start and remote servers are reported on stderr and left out.\nWithout a profile name the default profile '%s' is used. apply --via-proxy writes a single entry that runs this command.",
+	"help.proxy.project":           "Directory to look for the project file in (default: the current directory)",
+	"help.proxy.short":             "Serve the servers of a profile as one MCP server",
+	"help.rename.long":             "Renames a profile.\nWithout the current name the default profile '%s' is renamed.",
+	"help.rename.short":            "Rename a profile",
+	"help.reset.short":             "Reset settings during development",
+	"help.reset_all.short":         "Reset everything (profiles and server templates)",
+	"help.reset_profiles.short":    "Delete every profile",
+	"help.reset_servers.short":     "Delete every server template",
+	"help.root.long":               "mcpjson - MCP configuration file manager\n\nManages MCP config files such as .mcp.json with server templates and profiles.\nProfile names in brackets such as [profile] are optional and default to the profile '%s'.",
+	"help.root.short":              "MCP configuration file manager",
+	"help.save.from":               "MCP config file to read",
+	"help.save.from_client":        "Read the config file of the given client (without --from, from the client's default path)",
+	"help.save.long":               "Saves the servers of an MCP config file as server templates and a profile.\nWithout --from the MCP config file is detected automatically.\nWithout a profile name the default profile '%s' is used.",
+	"help.save.short":              "Save an MCP config file as a profile",
+	"help.secret.long":             "Manages values such as tokens and passwords as secrets.\nTemplates and profiles refer to them as ${secret:name}; they are expanded by apply.\nThe secret provider is configured with \"secrets\" in ~/.mcpconfig/settings.jsonc:\n  {\"secrets\": {\"provider\": \"vault\"}}                                     encrypted file (default)\n  {\"secrets\": {\"provider\": \"exec\", \"command\": [\"pass\", \"show\", \"{name}\"]}}  external command (read-only)",
+	"help.secret.short":            "Manage secrets",
+	"help.secret_get.short":        "Print the value of a secret",
+	"help.secret_list.short":       "List secret names",
+	"help.secret_rm.short":         "Delete a secret",
+	"help.secret_set.long":         "Stores a secret. Without a value it is read from stdin, so it does not end up in the shell history.",
+	"help.secret_set.short":        "Store a secret",
+	"help.server.args":             "Command arguments (comma separated)",
+	"help.server.env":              "Environment variables KEY=VALUE (comma separated)",
+	"help.server.env_file":         "File to read environment variables from",
+	"help.server.short":            "Manage MCP server templates",
+	"help.server_add.allow_tools":  "Tools to offer through the proxy (comma-separated, patterns such as * allowed)",
+	"help.server_add.args":         "Override the arguments (comma separated)",
+	"help.server_add.args_append":  "Arguments to append to the template's (comma separated)",
+	"help.server_add.args_prepend": "Arguments to prepend to the template's (comma separated)",
+	"help.server_add.as":           "Name of the added server (default: the template name)",
+	"help.server_add.command":      "Override the command",
+	"help.server_add.deny_tools":   "Tools to hide from the proxy (comma-separated, patterns such as * allowed)",
+	"help.server_add.disabled":     "Disable the server",
+	"help.server_add.enabled":      "Enable the server",
+	"help.server_add.env_file":     "Environment file the server reads",
+	"help.server_add.long":         "Adds a server template to a profile or an MCP config file.\nWhen --to names an existing profile the server is added to the profile; when it is a path (containing a separator or ending in .json) it is added to the MCP config file there (default: ./.mcp.json).\nOverride flags such as --command and --args, and --update, only apply when adding to a profile.",
+	"help.server_add.short":        "Add a server to a profile or an MCP config file",
+	"help.server_add.timeout":      "Timeout in seconds",
+	"help.server_add.to":           "Profile name or MCP config file to add to",
+	"help.server_add.transport":    "Transport type",
+	"help.server_add.update":       "Update a server that is already in the profile",
+	"help.server_copy.long":        "Copies a server template under another name. The original template is kept.",
+	"help.server_copy.short":       "Copy a server template",
+	"help.server_delete.long":      "Deletes a server template. When profiles use it, asks whether to remove the references from them as well.",
+	"help.server_delete.short":     "Delete a server template",
+	"help.server_detail.short":     "Show the details of a server template",
+	"help.server_inspect.long":     "Launches a server template, connects over MCP and lists its tools (with input schemas), prompts and resources.\nThe result is recorded in the template with the time of inspection and used by detail --tools.",
+	"help.server_inspect.short":    "Inspect the tools, prompts and resources of a server",
+	"help.server_install.as":       "Template name (defaults to the catalog id)",
+	"help.server_install.long":     "Creates a server template from a server of a catalog. Name the template with --as; it defaults to the catalog id.\nRequired environment variables not given with --env are asked for. Secret values are stored in the secret vault as '<template>/<variable>' and the template refers to them with ${secret:...}.",
+	"help.server_install.short":    "Save a server of a catalog as a template",
+	"help.server_list.short":       "List server templates",
+	"help.server_path.long":        "Prints the absolute path of a server template file.",
+	"help.server_path.short":       "Print the path of a server template file",
+	"help.server_remove.from":      "MCP config file to change",
+	"help.server_remove.short":     "Remove a server from an MCP config file",
+	"help.server_rename.short":     "Rename a server template",
+	"help.server_save.command":     "Command to start",
+	"help.server_save.from":        "MCP config file to read",
+	"help.server_save.header":      "Request header \"Name: value\" (repeatable)",
+	"help.server_save.long":        "Saves a server template in one of these ways:\n  --server and --from  from a server of an MCP config file (--from defaults to ./.mcp.json)\n  --command            from a command with its arguments and environment\n  --url                as a remote server (http|sse)",
+	"help.server_save.server":      "Server name in the MCP config file",
+	"help.server_save.short":       "Save a server template",
+	"help.server_save.type":        "Server type (stdio|http|sse)",
+	"help.server_save.url":         "URL of a remote server",
+	"help.server_search.catalog":   "Catalog to use instead of the settings file (path or URL, repeatable)",
+	"help.server_search.long":      "Lists the servers of the catalogs whose id, name, description or tags contain every term. Without a term every server is listed.\nCatalogs are read from catalogs in the settings file (file paths or http(s) URLs), or from --catalog when given.",
+	"help.server_search.short":     "Search the catalogs for servers",
+	"help.server_test.short":       "Launch a server template and check that it works",
+	"help.sync.long":               "Pulls changes from the remote and pushes local ones.\nOnce initialised, every change to profiles and server templates is committed automatically.\nWhen the same profile or template changed on both sides, sync stops with an error without changing anything.\nWith --prefer, conflicting files are replaced with one side's content.\nSecrets, the operation history and settings.jsonc are not shared.",
+	"help.sync.prefer":             "Side to use on conflicts (local|remote)",
+	"help.sync.short":              "Share and sync the store with git",
+	"help.sync_init.remote":        "URL of the repository to sync with",
+	"help.sync_init.short":         "Make the store a git repository",
+	"help.sync_remote.short":       "Show or set the repository to sync with",
+	"help.sync_status.short":       "Show the sync status",
+	"help.undo.force":              "Also overwrite files changed since the operation",
+	"help.undo.long":               "Undoes an operation recorded in the history. Without an ID the latest operation is undone.",
+	"help.undo.short":              "Undo an operation",
+	"help.version.short":           "Show the version",
+
+	"history.already_undone":       "#%d has already been undone",
+	"history.backup_failed":        "Failed to create the backup: %w",
+	"history.backup_read_failed":   "Failed to read the backup: %w",
+	"history.backup_save_failed":   "Failed to save the backup: %w",
+	"history.check_changed_failed": "Failed to check the changed files: %w",
+	"history.commit_failed":        "Warning: failed to commit the change: %v",
+	"history.empty":                "No operation history",
+	"history.file_created":         "  + %s (created)",
+	"history.file_deleted":         "  - %s (deleted)",
+	"history.file_modified":        "  ~ %s (modified)",
+	"history.invalid_id":           "Invalid history ID: '%s'",
+	"history.invalid_limit":        "--limit must be a number of 0 or more: '%s'",
+	"history.list_line":            "#%-4d %s  %s (%d files)%s",
+	"history.load_failed":          "Failed to read the history: %w",
+	"history.mkdir_failed":         "Failed to create the history directory: %w",
+	"history.modified_since":       "The following files were changed after #%d (use --force to overwrite them):\n  %s",
+	"history.no_id":                "No history ID given",
+	"history.not_found":            "History entry not found",
+	"history.nothing_to_undo":      "There is no operation to undo",
+	"history.parse_failed":         "Failed to parse history entry #%d: %w",
+	"history.prune_failed":         "Failed to delete old history: %w",
+	"history.pruned":               "Deleted %d old history entries",
+	"history.read_failed":          "Failed to read the file: %w",
+	"history.record_failed":        "Warning: failed to save the operation history: %v",
+	"history.remove_failed":        "Failed to delete the file: %w",
+	"history.restore_failed":       "Failed to restore the file: %w",
+	"history.save_failed":          "Failed to save the history: %w",
+	"history.show_files":           "Files:",
+	"history.show_time":            "Time: %s",
+	"history.show_undone":          "Undone: %s",
+	"history.stat_failed":          "Failed to check the file: %w",
+	"history.undo_done":            "Undid #%d %s (%d files restored)",
+	"history.undone_mark":          "  [undone]",
+
+	"i18n.unknown_locale": "Unknown language: '%s' (available: %s)",
+
+	"interaction.already_exists":    "Warning: %s '%s' already exists",
+	"interaction.choose":            "%s [%s] (default: %s): ",
+	"interaction.confirm_overwrite": "Overwrite? (y/N): ",
+
+	"interpolate.failure_context":    "%s (%s)",
+	"interpolate.no_secret_provider": "No secret provider is configured",
+	"interpolate.unresolved":         "The following variables could not be resolved:",
+
+	"jsonedit.delete_root":          "Cannot delete the root",
+	"jsonedit.empty_value":          "Cannot apply an empty value",
+	"jsonedit.expect_colon":         "Expected ':'",
+	"jsonedit.expect_comma_brace":   "Expected ',' or '}'",
+	"jsonedit.expect_comma_bracket": "Expected ',' or ']'",
+	"jsonedit.expect_key":           "Expected an object key",
+	"jsonedit.invalid_number":       "Invalid number: %s",
+	"jsonedit.invalid_path":         "Invalid path element: %v",
+	"jsonedit.invalid_result":       "The edited document is invalid: %w",
+	"jsonedit.invalid_string":       "Invalid string: %v",
+	"jsonedit.missing_array":        "Cannot set an element of a missing array: %v",
+	"jsonedit.missing_value":        "Missing value",
+	"jsonedit.not_object":           "Cannot set key '%s' on a value that is not an object",
+	"jsonedit.out_of_range":         "Cannot set an element outside the array: %v",
+	"jsonedit.parse_failed":         "Failed to parse JSONC (offset %d): %s",
+	"jsonedit.trailing_data":        "Unexpected data after the value",
+	"jsonedit.unclosed_array":       "Unclosed array",
+	"jsonedit.unclosed_comment":     "Unclosed comment",
+	"jsonedit.unclosed_object":      "Unclosed object",
+	"jsonedit.unclosed_string":      "Unterminated string",
+	"jsonedit.unexpected_char":      "Unexpected character '%c'",
+
+	"kind.group":    "group",
+	"kind.profile":  "profile",
+	"kind.template": "server template",
+
+	"mcpclient.closed":           "The server closed its standard output",
+	"mcpclient.error_response":   "%s returned an error: %s (code %d)",
+	"mcpclient.exited":           "The server exited: %w",
+	"mcpclient.invalid_message":  "Got output that is not JSON-RPC: %s",
+	"mcpclient.invalid_result":   "Cannot parse the response to %s: %w",
+	"mcpclient.method_not_found": "Method '%s' is not supported",
+	"mcpclient.start_failed":     "Cannot launch '%s': %w",
+	"mcpclient.stdin_closed":     "The server stopped reading its standard input",
+	"mcpclient.timeout":          "No response within %s",
+
+	"mcpjson.conflict":             "The following servers were added or edited in the MCP config file and cannot be overwritten: %s\nSpecify --conflict=%s or --conflict=%s",
+	"mcpjson.expand_failed":        "Cannot expand the variables of profile '%s': %w",
+	"mcpjson.extends_cycle":        "Profile inheritance is circular: %s -> %s",
+	"mcpjson.invalid_tool_pattern": "Invalid tool pattern '%s': %w",
+	"mcpjson.no_template":          "Server '%[2]s' of profile '%[1]s' has no template",
+	"mcpjson.parent_load_failed":   "Cannot load parent '%[2]s' of profile '%[1]s': %[3]w",
+	"mcpjson.parent_unreadable":    "Cannot load the parents of profile '%s'",
+	"mcpjson.server_context":       "server '%s'",
+	"mcpjson.template_load_failed": "Failed to load server template '%s': %w",
+	"mcpjson.unknown_conflict":     "Unknown conflict policy: '%s' (available: %s, %s, %s)",
+	"mcpjson.unknown_mode":         "Unknown apply mode: '%s' (available: %s, %s, %s)",
+
+	"mcpserve.arg.client":                "Client whose file format is used",
+	"mcpserve.arg.conflict":              "What to do when a server added or edited by hand collides (defaults to fail)",
+	"mcpserve.arg.mode":                  "How to combine with the servers already in the file (defaults to replace)",
+	"mcpserve.arg.name":                  "Server name in the file (defaults to the template name)",
+	"mcpserve.arg.path":                  "Path of the MCP config file (defaults to the client's or the project's file)",
+	"mcpserve.arg.profile":               "Profile name (defaults to '%s')",
+	"mcpserve.arg.server_path":           "Path of the MCP config file (defaults to ./.mcp.json)",
+	"mcpserve.arg.template":              "Server template name",
+	"mcpserve.confirm_description":       "Set to true once the changes have been reviewed",
+	"mcpserve.confirm_required":          "%s changes MCP config files. Review the changes, then call it again with %s set to true",
+	"mcpserve.invalid_arguments":         "Invalid arguments for tool '%s': %w",
+	"mcpserve.invalid_params":            "Invalid parameters: %v",
+	"mcpserve.method_not_found":          "Method '%s' is not supported",
+	"mcpserve.parse_error":               "Cannot parse the request: %v",
+	"mcpserve.started":                   "MCP server started (tools: %s)",
+	"mcpserve.tool.add_server_to_config": "Adds the server of a template to the MCP config file at path. Fails when a server of the same name is already there",
+	"mcpserve.tool.apply_profile":        "Writes the servers of a profile to MCP config files and returns the changes made. Review them first with diff_profile. They take effect once the client reloads the file",
+	"mcpserve.tool.diff_profile":         "Shows how applying a profile would change the servers in MCP config files without changing them. Env and header values are hidden",
+	"mcpserve.tool.list_groups":          "Lists the server groups",
+	"mcpserve.tool.list_profiles":        "Lists the mcpjson profiles with their description, parents and number of servers",
+	"mcpserve.tool.list_templates":       "Lists the server templates with their description, type and command or URL",
+	"mcpserve.unknown_allow":             "Unknown tool '%s' in the allow-list (available: %s)",
+	"mcpserve.unknown_tool":              "There is no tool '%s'",
+
+	"merge.no_sources": "Specify the destination and at least one source profile",
+
+	"output.not_structured": "Cannot write in the '%s' output format",
+	"output.unknown_format": "Unknown output format: '%s' (available: %s, %s, %s)",
+	"output.yaml_failed":    "Failed to generate YAML: %w",
+
+	"path.get_failed": "Failed to get the profile path: %w",
+
+	"profile.applied":                 "Applied profile '%s'",
+	"profile.apply_summary":           "Added: %d, updated: %d, removed: %d, kept: %d",
+	"profile.column_description":      "Description",
+	"profile.column_name":             "Profile",
+	"profile.column_server":           "Server",
+	"profile.column_tool":             "Tool",
+	"profile.confirm_delete":          "Delete profile '%s'?",
+	"profile.confirm_reset":           "Delete all profiles?",
+	"profile.conflicted":              "Conflicting servers (%s): %s",
+	"profile.context":                 "profile '%s'",
+	"profile.copied":                  "Copied profile '%s' to '%s'",
+	"profile.created":                 "Created profile '%s' (%d servers)",
+	"profile.created_from_template":   "Created from template %s",
+	"profile.delete_failed":           "Failed to delete the profile: %w",
+	"profile.deleted":                 "Deleted profile '%s'",
+	"profile.detail_excluded":         "    - %s [excluded from the parent]",
+	"profile.detail_extends":          "  Extends: %s",
+	"profile.detail_header":           "\nProfile: %s",
+	"profile.detail_override":         "    - %s (overrides the parent)",
+	"profile.detail_server":           "    - %s (template: %s)%s",
+	"profile.detail_server_count":     "  Servers: %d",
+	"profile.detail_servers":          "  Servers:",
+	"profile.disabled":                " [disabled]",
+	"profile.dropped_fields":          "⚠️ Server '%s': %s cannot be expressed in the %s config file and was not written",
+	"profile.duplicate_server":        "Warning: server '%s' was already added and is skipped (profile: %s)",
+	"profile.exists":                  "Profile '%s' already exists",
+	"profile.exists_force":            "Profile '%s' already exists. Use --force to overwrite it",
+	"profile.exists_rename_or_force":  "Profile '%s' already exists. Choose another name or use --force to overwrite it",
+	"profile.extends_update_failed":   "Failed to update the parents of profile '%s': %w",
+	"profile.extends_updated":         "Updated the parents of %d profiles",
+	"profile.load_failed":             "Failed to load the profile: %w",
+	"profile.load_named_failed":       "Failed to load profile '%s': %w",
+	"profile.mcp_read_failed":         "Failed to read the MCP config: %w",
+	"profile.merged_description":      "Merged from %d profiles",
+	"profile.merged_from":             "Merged from: %v",
+	"profile.no_dir":                  "The profile directory does not exist",
+	"profile.none":                    "No profiles",
+	"profile.not_found":               "Profile '%s' not found",
+	"profile.provenance_failed":       "Warning: failed to save the apply record: %v",
+	"profile.read_dir_failed":         "Failed to read the profile directory: %w",
+	"profile.refs_removed":            "Removed %[3]d references to server template '%[2]s' from profile '%[1]s'",
+	"profile.refs_removed_total":      "Removed the references to server template '%[2]s' from %[1]d profiles in total",
+	"profile.remove_old_failed":       "Failed to delete the old profile: %w",
+	"profile.remove_refs_failed":      "Failed to remove the references from profile '%s': %w (%v)",
+	"profile.remove_refs_rolled_back": "Failed to remove the references from profile '%s', so all changes were rolled back: %w",
+	"profile.renamed":                 "Renamed profile '%s' to '%s'",
+	"profile.reset_done":              "Deleted %d profiles",
+	"profile.reset_list":              "The following %d profiles will be deleted:",
+	"profile.reset_none":              "There are no profiles to delete",
+	"profile.saved":                   "Saved profile '%s' (%d servers)",
+	"profile.saved_description":       "Saved from %s",
+	"profile.saved_servers":           "Saved %d server configurations to '%s'",
+	"profile.server_added":            "Added server '%s' to profile '%s'",
+	"profile.server_exists":           "Server name '%s' already exists in profile '%s'. Choose another name (use the --as option)",
+	"profile.server_failed":           "Failed to process the servers: %w",
+	"profile.server_not_found":        "Server '%s' not found in profile '%s'",
+	"profile.server_removed":          "Removed server '%s' from profile '%s'",
+	"profile.server_updated":          "Updated server '%[2]s' of profile '%[1]s'",
+	"profile.skipped_missing":         "Servers not added because they are not in the MCP config file: %s",
+	"profile.template_context":        "server template '%s'",
+	"profile.template_exists":         "Server template '%s' already exists; using the existing one",
+	"profile.tool_collision":          "\n⚠️ Tool name '%s' is exposed by several servers: %s",
+	"profile.tool_collision_mark":     "(collision)",
+	"profile.tools_uninspected":       "\nServers not inspected: %s (inspect them with mcpjson server inspect <template>)",
+
+	"provenance.mkdir_failed": "Failed to create the state directory: %w",
+	"provenance.parse_failed": "Failed to parse the apply state %s: %w",
+	"provenance.read_failed":  "Failed to read the apply state: %w",
+	"provenance.save_failed":  "Failed to save the apply state: %w",
+
+	"proxy.call_failed":        "'%s' failed: %w",
+	"proxy.error_response":     "'%s' returned an error: %s (code %d)",
+	"proxy.invalid_tool":       "Cannot parse a tool of tools/list: %w",
+	"proxy.prefix_collision":   "Left out '%s': its tool prefix collides with '%s' (%s)",
+	"proxy.project_dir_failed": "Cannot change to the project directory '%s': %v",
+	"proxy.remote_skipped":     "Left out '%s': it is a %s remote server",
+	"proxy.start_failed":       "Left out '%s': %v",
+	"proxy.started":            "Proxy for profile '%s' started (%d tools)",
+
+	"reset.all_done":         "Reset everything",
+	"reset.all_list":         "All of the following will be deleted:",
+	"reset.all_profiles":     "  - all profiles",
+	"reset.all_templates":    "  - all server templates",
+	"reset.confirm_all":      "Really reset everything?",
+	"reset.profiles_failed":  "Failed to reset the profiles: %v",
+	"reset.templates_failed": "Failed to reset the server templates: %v",
+
+	"save.detected":    "Detected the MCP config file: %s",
+	"save.no_mcp_file": "MCP config file not found\nUsage: mcpconfig save [profile] --from <path>",
+
+	"secret.cipher_failed":         "Failed to initialise the cipher: %w",
+	"secret.cmd_no_name":           "No secret name given",
+	"secret.command_failed":        "The secret command failed: %w",
+	"secret.command_failed_output": "The secret command failed: %w: %s",
+	"secret.confirm_delete":        "Delete secret '%s'?",
+	"secret.corrupted":             "The data of secret '%s' is corrupted",
+	"secret.decrypt_failed":        "Cannot decrypt secret '%s' (the key file may be different)",
+	"secret.deleted":               "Deleted secret '%s'",
+	"secret.empty_value":           "The secret value is empty",
+	"secret.encrypt_failed":        "Failed to encrypt the secret: %w",
+	"secret.get_failed":            "Cannot get secret '%s': %w",
+	"secret.invalid_name":          "The secret name contains invalid characters (allowed: letters, digits, hyphens, underscores, dots and slashes)",
+	"secret.key_invalid":           "The secret key file is invalid: %s",
+	"secret.key_not_found":         "The secret key file was not found: %s",
+	"secret.key_read_failed":       "Failed to read the secret key file: %w",
+	"secret.key_save_failed":       "Failed to save the secret key file: %w",
+	"secret.keygen_failed":         "Failed to generate the secret key: %w",
+	"secret.load_failed":           "Failed to read the secrets: %w",
+	"secret.mkdir_failed":          "Failed to create the secret directory: %w",
+	"secret.name_too_long":         "The secret name must be at most %d characters",
+	"secret.named_not_found":       "Secret '%s' not found",
+	"secret.no_command":            "The exec secret provider has no command (secrets.command in settings.jsonc)",
+	"secret.no_name":               "No secret name given",
+	"secret.none":                  "No secrets registered",
+	"secret.not_found":             "Secret not found",
+	"secret.parse_failed":          "Failed to parse the secret file %s: %w",
+	"secret.prompt":                "Enter the value of secret '%s': ",
+	"secret.read_only":             "The configured secret provider does not support writing",
+	"secret.read_value_failed":     "Failed to read the secret value: %w",
+	"secret.save_failed":           "Failed to save the secret: %w",
+	"secret.saved":                 "Saved secret '%s'",
+	"secret.unknown_provider":      "Unknown secret provider: '%s' (available: %s, %s)",
+	"secret.unsupported_version":   "Unsupported secret file version: %d",
+
+	"server.added_to_mcp":               "Added server '%s' to the MCP config file: %s",
+	"server.already_exists":             "Server template '%s' already exists\nChoose another name or use --force to overwrite it",
+	"server.already_in_mcp":             "Server '%s' already exists in the MCP config file",
+	"server.capabilities_other_store":   "Server template '%s' is read from %s, outside the selected store, so its inspection result cannot be saved\nSelect that store with --store to inspect it",
+	"server.capabilities_save_failed":   "Cannot save the inspection result in server template '%s': %w",
+	"server.cmd_no_template":            "No template name given",
+	"server.column_command":             "Command",
+	"server.column_name":                "Template",
+	"server.confirm_delete":             "Delete server template '%s'?",
+	"server.confirm_delete_refs":        "Also remove the references from the profiles?",
+	"server.confirm_reset":              "Delete all server templates?",
+	"server.copied":                     "Copied server template '%s' to '%s'",
+	"server.copy_no_dest":               "No destination server template name given",
+	"server.copy_no_source":             "No source server template name given",
+	"server.copy_usage":                 "Specify the source and destination server names",
+	"server.delete_failed":              "Failed to delete the server template: %w",
+	"server.deleted":                    "Deleted server template '%s'",
+	"server.detail_args":                "  Args: %v",
+	"server.detail_command":             "  Command: %s",
+	"server.detail_env":                 "  Environment:",
+	"server.detail_header":              "\nTemplate: %s",
+	"server.detail_headers":             "  Headers:",
+	"server.detail_load_failed":         "Failed to load the server template: %v",
+	"server.detail_no_name":             "Specify a server name",
+	"server.detail_type":                "  Type: %s",
+	"server.expand_failed":              "Cannot expand the variables of template '%s': %w",
+	"server.force_remove_refs":          "Forced deletion: removing the references from the profiles as well",
+	"server.inspect_failed":             "Cannot inspect server template '%s': %w",
+	"server.inspect_header":             "Template: %s\nServer: %s\nInspected at: %s",
+	"server.inspect_no_name":            "Specify a template name",
+	"server.inspect_prompts":            "\nPrompts (%d):",
+	"server.inspect_resources":          "\nResources (%d):",
+	"server.inspect_tools":              "\nTools (%d):",
+	"server.invalid_timeout":            "--timeout must be a positive integer: '%s'",
+	"server.mcp_not_object":             "Failed to parse the MCP config file: not a JSON object",
+	"server.mcp_parse_failed":           "Failed to parse the MCP config file: %w",
+	"server.mcp_server_invalid":         "Invalid configuration for MCP server '%s': %w",
+	"server.mcp_server_not_found":       "MCP server '%s' not found in the MCP config file",
+	"server.no_command":                 "No command given",
+	"server.no_dir":                     "The server template directory does not exist",
+	"server.no_templates":               "No server templates",
+	"server.no_url":                     "No URL given",
+	"server.not_in_mcp":                 "Server '%s' not found in the MCP config file\nFile: %s\nAvailable servers: %v",
+	"server.overrides_profile_only":     "Override options such as --command/--args/--timeout and --update can only be used when adding to a profile",
+	"server.path_failed":                "Failed to get the server template path: %v",
+	"server.path_no_name":               "Specify a server template name",
+	"server.read_dir_failed":            "Failed to read the server directory: %w",
+	"server.read_template_dir_failed":   "Failed to read the server template directory: %w",
+	"server.remote_and_command":         "--url/--header and --command/--args cannot be used together",
+	"server.remote_needs_url":           "A remote server (%s) needs a url",
+	"server.remove_failed":              "Failed to remove server '%s': %w",
+	"server.remove_no_name":             "No server name given",
+	"server.remove_old_failed":          "Failed to delete the old server template: %w",
+	"server.remove_refs_failed":         "Warning: failed to remove the references from the profiles: %v",
+	"server.removed_from_mcp":           "Removed server '%s' from the MCP config file: %s",
+	"server.rename_no_names":            "Specify the current and new server template names",
+	"server.renamed":                    "Renamed server template '%s' to '%s'",
+	"server.reset_done":                 "Deleted %d server templates",
+	"server.reset_list":                 "The following %d server templates will be deleted:",
+	"server.reset_none":                 "There are no server templates to delete",
+	"server.save_needs_source":          "Saving from a config file needs --server and --from\nCreating one by hand needs --command (--url for remote servers)",
+	"server.saved_args":                 "Args: %v",
+	"server.saved_command":              "Command: %s",
+	"server.saved_type":                 "Type: %s",
+	"server.section_not_object":         "Failed to parse %s: not a JSON object",
+	"server.server_parse_failed":        "Failed to parse server '%s': %w",
+	"server.stdio_needs_command":        "A stdio server needs a command",
+	"server.target_ambiguous":           "--to '%[1]s' is neither a profile nor a file path. Create the profile first to add to it, or write a path such as ./%[1]s to write a file",
+	"server.template_created":           "Created server template '%s'",
+	"server.template_invalid":           "Invalid configuration in server template '%s': %w",
+	"server.template_load_failed":       "Failed to load the server template: %w",
+	"server.template_load_failed_short": "Failed to load template '%s': %w",
+	"server.template_saved":             "Saved server template '%s'",
+	"server.template_updated":           "Updated server template '%s'",
+	"server.test_no_name":               "Specify a template name",
+	"server.unknown_type":               "Unknown server type: '%s'",
+	"server.unknown_type_available":     "Unknown server type: '%s' (available: %s, %s, %s)",
+	"server.unresolved_secret":          "Aborted writing because server '%s' still has unresolved secret references: %s",
+	"server.usage_check_failed":         "Failed to check which profiles use the template: %w",
+	"server.used_by_profiles":           "Warning: server template '%s' is used by the following profiles:",
+
+	"sync.auto_commit":      "Changes will be committed automatically from now on",
+	"sync.initialised":      "Initialised '%s' as a git repository",
+	"sync.invalid_prefer":   "--prefer must be %s or %s: '%s'",
+	"sync.no_remote":        "No remote is configured",
+	"sync.not_managed":      "The store is not managed with git (initialise it with 'mcpjson sync init')",
+	"sync.pulled":           "Merged the remote changes",
+	"sync.pushed":           "Pushed the local changes",
+	"sync.remote_set":       "Set the remote to '%s'",
+	"sync.resolved":         "Resolved conflicts (using the %s version): %s",
+	"sync.status_no_remote": "Remote: not configured",
+	"sync.status_remote":    "Remote: %s",
+	"sync.status_store":     "Store: %s (managed with git)",
+	"sync.up_to_date":       "No changes to merge",
+
+	"utils.backup_failed":          "Failed to create backup: %w",
+	"utils.default_profile":        "No profile name given, using default '%s'",
+	"utils.default_source_profile": "No source profile name given, using default '%s'",
+	"utils.env_file_invalid":       "Invalid environment file format: '%s' line %d",
+	"utils.env_file_not_found":     "Environment file not found: '%s'",
+	"utils.env_file_read_failed":   "Failed to read environment file: '%s'",
+	"utils.env_invalid":            "Invalid environment variable format: '%s'",
+	"utils.env_name_invalid":       "Invalid environment variable name: '%s'",
+	"utils.flag_missing_value":     "No value given for the %s option",
+	"utils.header_invalid":         "Invalid header format: '%s' (example: \"Authorization: Bearer xxx\")",
+	"utils.header_name_invalid":    "Invalid header name: '%s'",
+	"utils.name_empty":             "%s name is not specified",
+	"utils.name_invalid_chars":     "%s name contains invalid characters (allowed: letters, digits, hyphens and underscores)",
+	"utils.name_reserved":          "%s name cannot be the reserved word '%s'",
+	"utils.name_too_long":          "%s name must be at most %d characters",
+	"utils.rename_missing_name":    "No new profile name given\nUsage: mcpconfig rename [current profile name] <new profile name>",
+	"utils.rollback_failed":        "Failed to roll back changes: %w",
+	"utils.transaction_closed":     "The transaction has already finished",
+}
@@ -0,0 +1,692 @@
+package i18n
+
+// ja is the Japanese message catalog
+var ja = map[string]string{
+	"bundle.collision":             "%s '%s' は既に存在します（--on-conflict %s|%s|%s で動作を指定してください）",
+	"bundle.collision_prompt":      "%s '%s' は既に存在します。どうしますか？",
+	"bundle.store_changed":         "確認中にストアに %s '%s' が追加されました。もう一度インポートしてください",
+	"bundle.convert_failed":        "%s '%s' の変換に失敗しました: %w",
+	"bundle.entry_failed":          "アーカイブのエントリ %s: %w",
+	"bundle.entry_too_large":       "アーカイブのファイルが大きすぎます: %s",
+	"bundle.exported":              "'%s' にエクスポートしました（プロファイル: %d, サーバーテンプレート: %d, グループ: %d）",
+	"bundle.imported":              "'%s' から%d件をインポートしました",
+	"bundle.invalid_entry":         "アーカイブに不正なエントリがあります: %s",
+	"bundle.item_identical":        "  = %s '%s'（同じ内容のため省略）",
+	"bundle.item_identical_copy":   "  = %s '%s'（同じ内容の '%s' があるため省略）",
+	"bundle.item_overwritten":      "  ~ %s '%s'（上書き）",
+	"bundle.item_renamed":          "  + %s '%s' → '%s'（名前を変更）",
+	"bundle.item_skipped":          "  - %s '%s'（スキップ）",
+	"bundle.manifest_parse_failed": "マニフェストの解析に失敗しました: %w",
+	"bundle.manifest_write_failed": "マニフェストの作成に失敗しました: %w",
+	"bundle.missing_manifest":      "アーカイブに %s がありません",
+	"bundle.need_profile_or_all":   "プロファイル名または --all を指定してください",
+	"bundle.no_import_file":        "インポートするファイルが指定されていません",
+	"bundle.no_profiles":           "エクスポートするプロファイルが指定されていません",
+	"bundle.read_dir_failed":       "ディレクトリの読み込みに失敗しました: %w",
+	"bundle.read_failed":           "アーカイブの読み込みに失敗しました: %w",
+	"bundle.save_failed":           "%s '%s' の保存に失敗しました: %w",
+	"bundle.secrets_replaced":      "次の値はシークレット参照に置き換えました",
+	"bundle.set_secrets":           "次のシークレットを 'mcpjson secret set <名前>' で設定してください",
+	"bundle.unknown_entry":         "アーカイブに不明なエントリがあります: %s",
+	"bundle.unsupported_version":   "このバージョンのmcpjsonでは読み込めないアーカイブです（形式: %d）",
+	"bundle.write_failed":          "アーカイブの書き込みに失敗しました: %w",
+
+	"catalog.column_description": "説明",
+	"catalog.column_env":         "必須の環境変数",
+	"catalog.column_id":          "ID",
+	"catalog.define_secret":      "シークレット '%s' を %s の値としてシークレットのバックエンドに登録してください",
+	"catalog.entry_no_id":        "カタログ %s の %d 番目のサーバーに id がありません",
+	"catalog.env_empty":          "%s の値が入力されていません",
+	"catalog.env_missing":        "必須の環境変数 %s を --env で指定してください",
+	"catalog.http_status":        "サーバーが %s を返しました",
+	"catalog.install_no_id":      "カタログの ID を指定してください",
+	"catalog.installed":          "'%s' をサーバーテンプレート '%s' として保存しました",
+	"catalog.no_match":           "'%s' に一致するサーバーはありません",
+	"catalog.no_sources":         "カタログが設定されていません。設定ファイルの catalogs にパスまたは URL を追加するか、--catalog を指定してください",
+	"catalog.not_found":          "カタログにサーバー '%s' がありません（server search で検索できます）",
+	"catalog.parse_failed":       "カタログ %s を解析できません: %w",
+	"catalog.read_failed":        "カタログ %s を読み込めません: %w",
+	"catalog.skipped":            "警告: %v",
+
+	"client.remote_unsupported": "%s は %s サーバーに対応していません: %s",
+	"client.unknown":            "クライアント '%s' には対応していません (利用可能: %s)",
+
+	"cmd.config_init_failed": "設定の初期化に失敗しました: %w",
+	"cmd.file_create_failed": "ファイルの作成に失敗しました: %w",
+	"cmd.file_read_failed":   "ファイルの読み込みに失敗しました: %w",
+	"cmd.file_save_failed":   "ファイルの保存に失敗しました: %w",
+	"cmd.invalid_flag":       "オプションが正しくありません: %v（'%s --help' で使用方法を確認してください）",
+	"cmd.unexpected_args":    "余分な引数があります: %s（'%s --help' で使用方法を確認してください）",
+	"cmd.unknown_command":    "不明なコマンドです: '%s'（'%s --help' で使用方法を確認してください）",
+
+	"common.copy_same_name":           "コピー元とコピー先が同じ名前です",
+	"common.created_at":               "作成日時",
+	"common.delete_cancelled":         "削除をキャンセルしました",
+	"common.detail_created_at":        "  作成日時: %s",
+	"common.detail_description":       "  説明: %s",
+	"common.detail_updated_at":        "  更新日時: %s",
+	"common.error_prefix":             "エラー:",
+	"common.json_failed":              "JSONの生成に失敗しました: %w",
+	"common.load_failed_skip":         "エラー: %s の読み込みに失敗しました: %v",
+	"common.mcp_read_existing_failed": "既存のMCP設定ファイルの読み込みに失敗しました: %w",
+	"common.mcp_read_failed":          "MCP設定ファイルの読み込みに失敗しました: %w",
+	"common.mcp_save_failed":          "MCP設定ファイルの保存に失敗しました: %w",
+	"common.mcp_update_failed":        "MCP設定の更新に失敗しました: %w",
+	"common.mkdir_failed":             "ディレクトリの作成に失敗しました: %w",
+	"common.overwrite_cancelled":      "上書きをキャンセルしました",
+	"common.remove_failed_warning":    "警告: %s の削除に失敗しました: %v",
+	"common.reset_cancelled":          "リセットをキャンセルしました",
+	"common.server_count":             "サーバー数",
+	"common.server_invalid":           "サーバー '%s' の設定が不正です: %w",
+	"common.template_not_found":       "サーバーテンプレート '%s' が見つかりません",
+
+	"completion.no_shell":          "シェルを指定してください (bash|zsh|fish)",
+	"completion.unsupported_shell": "対応していないシェルです: %s (bash|zsh|fish)",
+
+	"config.home_dir_failed":           "ホームディレクトリの取得に失敗しました: %w",
+	"config.mkdir_failed":              "ディレクトリの作成に失敗しました %s: %w",
+	"config.project_empty_target":      "%s の targets に空のパスがあります",
+	"config.project_parse_failed":      "プロジェクトファイル %s の解析に失敗しました: %w",
+	"config.project_read_failed":       "プロジェクトファイル %s の読み込みに失敗しました: %w",
+	"config.project_templates_not_dir": "テンプレートディレクトリ %s が見つかりません (%s の templates)",
+	"config.settings_parse_failed":     "設定ファイルの解析に失敗しました %s: %w",
+	"config.settings_read_failed":      "設定ファイルの読み込みに失敗しました: %w",
+	"config.store_duplicate":           "ストア名 '%s' が重複しているか予約されています (%s)",
+	"config.store_incomplete":          "%s の stores には name と path が必要です",
+	"config.unknown_store":             "ストア '%s' が見つかりません (利用可能: %s)",
+
+	"copy.no_destination": "コピー先のプロファイル名が指定されていません",
+
+	"detail.load_failed":     "プロファイルの読み込みに失敗しました: %v",
+	"detail.no_profile":      "プロファイル名が指定されていません",
+	"detail.resolved_format": "--resolved では %s または %s 形式を指定してください",
+
+	"diff.empty_source":   "比較対象が指定されていません: '%s'",
+	"diff.hunk_added":     "@@ %s（追加） @@",
+	"diff.hunk_removed":   "@@ %s（削除） @@",
+	"diff.no_changes":     "差分はありません（%s と %s）",
+	"diff.summary":        "\n追加: %d, 削除: %d, 変更: %d",
+	"diff.unknown_format": "不明な出力形式です: '%s'（使用可能: %s, %s）",
+	"diff.unknown_kind":   "不明な比較対象の種類です: '%s'（使用可能: %s, %s, %s, %s）",
+	"diff.usage":          "比較対象を2つ指定してください\n使用方法: mcpjson diff <比較元> <比較先> [--format text|json]",
+
+	"doctor.column_info":        "サーバー情報",
+	"doctor.column_latency":     "応答時間",
+	"doctor.column_server":      "サーバー",
+	"doctor.column_status":      "状態",
+	"doctor.column_tools":       "ツール数",
+	"doctor.error_detail":       "\n%s: %s",
+	"doctor.failed":             "%d 件のサーバーが起動または応答に失敗しました",
+	"doctor.failed_with_stderr": "%w\n標準エラー出力:\n%s",
+	"doctor.invalid_result":     "%s の応答を解析できません: %w",
+	"doctor.remote_skipped":     "%s サーバーは確認できません",
+	"doctor.status_failed":      "失敗",
+	"doctor.status_ok":          "OK",
+	"doctor.status_skipped":     "スキップ",
+	"doctor.stderr_header":      "  標準エラー出力:",
+	"doctor.summary":            "\n%d/%d 件のサーバーが正常に応答しました",
+
+	"filelock.busy":          "ロックを取得できませんでした: %s\n他のmcpjsonプロセスが実行中の可能性があります。実行中のプロセスがない場合はこのファイルを削除してください",
+	"filelock.create_failed": "ロックファイルの作成に失敗しました: %w",
+	"filelock.mkdir_failed":  "ロックファイルのディレクトリ作成に失敗しました: %w",
+	"filelock.path_failed":   "ロックファイルのパス解決に失敗しました: %w",
+	"filelock.remove_failed": "ロックファイルの削除に失敗しました: %w",
+	"filelock.write_failed":  "ロックファイルの書き込みに失敗しました: %w",
+
+	"gitstore.conflict":         "ローカルとリモートの両方で変更されています:\n%s\n--prefer local または --prefer remote で、どちらの変更を残すか指定してください",
+	"gitstore.git_failed":       "git %s に失敗しました: %s",
+	"gitstore.git_not_found":    "gitが見つかりません: %w",
+	"gitstore.gitignore_failed": ".gitignore の作成に失敗しました: %w",
+	"gitstore.init_commit":      "初期化",
+	"gitstore.merge_commit":     "リモートの変更を取り込み",
+	"gitstore.no_remote":        "リモートが設定されていません（'mcpjson sync remote <URL>' で設定してください）",
+	"gitstore.not_repository":   "ストアはgitリポジトリではありません（'mcpjson sync init' で初期化してください）",
+	"gitstore.presync_commit":   "同期前の変更",
+
+	"group.add_server_failed":     "警告: サーバー '%s' の追加に失敗しました: %v",
+	"group.already_exists":        "グループ '%s' は既に存在します\n別の名前を指定するか、--force オプションで上書きしてください",
+	"group.applied":               "グループ '%s' から %d/%d のサーバーをMCP設定ファイルに追加しました: %s",
+	"group.column_name":           "グループ名",
+	"group.confirm_delete":        "グループ '%s' を削除しますか？",
+	"group.confirm_reset":         "すべてのグループを削除しますか？",
+	"group.created":               "グループ '%s' を作成しました",
+	"group.delete_failed":         "グループの削除に失敗しました: %w",
+	"group.deleted":               "グループ '%s' を削除しました",
+	"group.detail_created_at":     "作成日時: %s",
+	"group.detail_description":    "説明: %s",
+	"group.detail_name":           "グループ名: %s",
+	"group.detail_server_count":   "サーバー数: %d",
+	"group.detail_servers":        "サーバー:",
+	"group.detail_updated_at":     "更新日時: %s",
+	"group.empty":                 "グループ '%s' にはサーバーが含まれていません",
+	"group.load_failed":           "グループの読み込みに失敗しました: %w",
+	"group.load_failed_skip":      "エラー: グループ %s の読み込みに失敗しました: %v",
+	"group.mkdir_failed":          "グループディレクトリの作成に失敗しました: %w",
+	"group.no_dir":                "グループディレクトリが存在しません",
+	"group.none":                  "グループが存在しません",
+	"group.not_found":             "グループ '%s' が見つかりません",
+	"group.read_dir_failed":       "グループディレクトリの読み込みに失敗しました: %w",
+	"group.remove_old_failed":     "古いグループファイルの削除に失敗しました: %w",
+	"group.remove_server_failed":  "警告: サーバー '%s' の削除に失敗しました: %v",
+	"group.renamed":               "グループ '%s' を '%s' に変更しました",
+	"group.reset_done":            "グループを%d個削除しました",
+	"group.reset_list":            "以下の%d個のグループを削除します:",
+	"group.reset_none":            "削除するグループが存在しません",
+	"group.server_added":          "サーバー '%s' をグループ '%s' に追加しました",
+	"group.server_already_member": "サーバー '%s' は既にグループ '%s' に含まれています",
+	"group.server_check_failed":   "サーバー存在確認に失敗しました: %w",
+	"group.server_not_member":     "サーバー '%s' はグループ '%s' に含まれていません",
+	"group.server_removed":        "サーバー '%s' をグループ '%s' から削除しました",
+	"group.unapplied":             "グループ '%s' から %d/%d のサーバーをMCP設定ファイルから削除しました: %s",
+
+	"help.apply.client":            "指定したクライアントの形式で書き込む (--to を省略するとクライアントの既定のパス)",
+	"help.apply.conflict":          "競合時の動作 (profile-wins|file-wins|fail)",
+	"help.apply.dry_run":           "書き込まずに変更内容を表示",
+	"help.apply.format":            "--dry-run の出力形式 (text|json)",
+	"help.apply.long":              "プロファイルのサーバーをMCP設定ファイルに書き込みます。\nプロファイル名を省略した場合はデフォルトプロファイル '%s' を適用します。",
+	"help.apply.mode":              "既存サーバーの扱い (replace|merge|update-only)",
+	"help.apply.short":             "プロファイルをMCP設定ファイルに適用",
+	"help.apply.show_secrets":      "--dry-run で環境変数・ヘッダーの値、機密性の高いフラグやURLパラメーターを伏せずに表示",
+	"help.apply.to":                "適用先のMCP設定ファイル (デフォルト: プロジェクトファイルの targets、なければ ./.mcp.json)",
+	"help.apply.via_proxy":         "サーバーごとのエントリの代わりに 'mcpjson proxy' のエントリを1つだけ書き込む",
+	"help.completion.long":         "bash・zsh・fish 用の補完スクリプトを標準出力に書き出します。\nプロファイル名・サーバーテンプレート名・MCP設定ファイル内のサーバー名も補完されます。",
+	"help.completion.short":        "シェル補完スクリプトを出力",
+	"help.copy.long":               "プロファイルを別名でコピーします。\nコピー元を省略した場合はデフォルトプロファイル '%s' をコピーします。",
+	"help.copy.short":              "プロファイルをコピー",
+	"help.create.long":             "空のプロファイル、または --template で指定したプロファイルを元にプロファイルを作成します。\nプロファイル名を省略した場合はデフォルトプロファイル '%s' を作成します。",
+	"help.create.short":            "新規プロファイルを作成",
+	"help.create.template":         "元にするプロファイル",
+	"help.delete.long":             "プロファイルを削除します。\nプロファイル名を省略した場合はデフォルトプロファイル '%s' を削除します。",
+	"help.delete.short":            "プロファイルを削除",
+	"help.detail.long":             "プロファイルの内容を表示します。--resolved を指定すると継承を展開し、各サーバーの継承元も表示します。",
+	"help.detail.resolved":         "継承を展開して表示 (json|yaml のみ)",
+	"help.detail.short":            "プロファイルの詳細を表示",
+	"help.detail.tools":            "プロファイルのサーバーが公開するツールを一覧表示（server inspect の結果を使用）",
+	"help.diff.format":             "出力形式 (text|json)",
+	"help.diff.json":               "--format json と同じ",
+	"help.diff.long":               "2つの比較対象のサーバー設定の差分を表示します。比較対象は次の形式で指定します:\n  profile:<名前>   プロファイルのサーバー定義（変数は未展開）\n  build:<名前>     apply で書き込まれる内容（変数・シークレットを展開）\n  template:<名前>  サーバーテンプレート\n  file:<パス>      MCP設定ファイル\n接頭辞を省略した場合、パスらしい指定や既存のファイルはMCP設定ファイル、それ以外はプロファイルとして扱います。",
+	"help.diff.short":              "プロファイル・MCP設定ファイル・テンプレートを比較",
+	"help.diff.show_secrets":       "環境変数・ヘッダーの値、機密性の高いフラグやURLパラメーターを伏せずに表示",
+	"help.doctor.long":             "プロファイルの stdio サーバーを apply と同じ設定で起動し、MCP の initialize と tools/list を送って応答を確認します。\n応答時間・サーバー情報・ツール数を表示し、失敗したサーバーは標準エラー出力も表示します。HTTP/SSE サーバーはスキップします。\nプロファイル名を省略した場合は '%s' を使用します。",
+	"help.doctor.short":            "プロファイルのサーバーを起動して動作を確認",
+	"help.export.all":              "すべてのプロファイル・テンプレート・グループを書き出し",
+	"help.export.long":             "プロファイルと、そのプロファイルが参照するサーバーテンプレート・グループをアーカイブに書き出します。\n--all を指定するとすべてのプロファイル・テンプレート・グループを書き出します。\nトークンやパスワードなどの値は ${secret:...} 参照に置き換えて書き出されます。",
+	"help.export.output":           "書き出すファイル (デフォルト: <プロファイル名>.tar.gz、--all の場合は mcpjson-bundle.tar.gz)",
+	"help.export.short":            "プロファイルとサーバーテンプレートをアーカイブに書き出し",
+	"help.flag.detail":             "詳細を表示",
+	"help.flag.force":              "確認なしで実行",
+	"help.flag.help":               "ヘルプを表示",
+	"help.flag.lang":               "メッセージの言語 (ja|en)",
+	"help.flag.output":             "出力形式 (table|json|yaml)",
+	"help.flag.store":              "書き込み先のストア (personal, project, または settings.jsonc の stores の名前)",
+	"help.flag.version":            "バージョンを表示",
+	"help.group.long":              "サーバーグループを管理します。\n\n注意: グループ機能は現在開発中です",
+	"help.group.short":             "サーバーグループを管理",
+	"help.group_list.short":        "グループ一覧を表示",
+	"help.heading.commands":        "コマンド:",
+	"help.heading.examples":        "例:",
+	"help.heading.flags":           "オプション:",
+	"help.heading.global_flags":    "グローバルオプション:",
+	"help.heading.usage":           "使用方法:",
+	"help.help.short":              "コマンドのヘルプを表示",
+	"help.history.limit":           "表示する件数（0はすべて）",
+	"help.history.long":            "操作履歴を新しい順に表示します。\n削除・名前変更・リセット・適用などの前に、変更されるファイルが ~/.mcpconfig/.history に保存されます。\n取り消し自体も履歴に記録されるため、取り消しの ID を指定して undo するとやり直せます。",
+	"help.history.short":           "操作履歴を表示",
+	"help.history_prune.short":     "保持期間を過ぎた履歴を削除",
+	"help.history_show.short":      "操作で変更されたファイルを表示",
+	"help.import.long":             "export で書き出したアーカイブを読み込みます。\n既存の名前と衝突した場合は、名前の変更・スキップ・上書きを選択できます。\n名前を変更したテンプレートやプロファイルへの参照は自動的に書き換えられます。",
+	"help.import.on_conflict":      "名前が衝突したときの動作 (rename|skip|overwrite)",
+	"help.import.short":            "アーカイブを読み込み",
+	"help.list.short":              "プロファイル一覧を表示",
+	"help.mcp_serve.allow":         "公開するツール（カンマ区切り、\"*\" ですべて）",
+	"help.mcp_serve.long":          "mcpjson を stdio の MCP サーバーとして起動し、エージェントがプロファイルの一覧・差分の確認・適用を行えるツールを公開します。\n公開するツールは --allow または設定ファイルの mcpServe.allow で指定します。指定しない場合はファイルを変更しないツール（list_profiles、list_templates、list_groups、diff_profile）だけを公開し、\"*\" ですべてのツールを公開します。\napply_profile は confirm 引数に true を指定した場合だけ実行されます。",
+	"help.mcp_serve.short":         "mcpjson を MCP サーバーとして起動",
+	"help.merge.long":              "ソースプロファイルのサーバーを合成先プロファイルにまとめます。",
+	"help.merge.short":             "複数のプロファイルを合成",
+	"help.more":                    "各コマンドの詳細は '%s <コマンド> --help' で確認してください",
+	"help.path.long":               "指定されたプロファイルファイルの絶対パスを表示します。プロファイル名を省略した場合はデフォルトプロファイルのパスを表示します。",
+	"help.path.short":              "プロファイルファイルのパスを表示",
+	"help.proxy.long":              "プロファイルの有効な stdio サーバーをすべて起動し、1つの stdio MCP サーバーとしてまとめて公開します。\nツール名には「<サーバー名>__」が付きます。プロファイルでサーバーごとに tools.allow / tools.deny を指定すると公開するツールを絞り込めます。\n起動できないサーバーとリモートサーバーは標準エラーに表示して除外します。\nプロファイル名を省略した場合はデフォルトプロファイル '%s' を使用します。apply --via-proxy で、このコマンドを起動するエントリを1つだけ書き込めます。",
+	"help.proxy.project":           "プロジェクトファイルを探すディレクトリ（既定: カレントディレクトリ）",
+	"help.proxy.short":             "プロファイルのサーバーを1つのMCPサーバーにまとめて起動",
+	"help.rename.long":             "プロファイル名を変更します。\n現在の名前を省略した場合はデフォルトプロファイル '%s' の名前を変更します。",
+	"help.rename.short":            "プロファイル名を変更",
+	"help.reset.short":             "開発用設定のリセット",
+	"help.reset_all.short":         "すべての設定をリセット (プロファイル + サーバーテンプレート)",
+	"help.reset_profiles.short":    "すべてのプロファイルを削除",
+	"help.reset_servers.short":     "すべてのサーバーテンプレートを削除",
+	"help.root.long":               "mcpjson - MCP設定ファイル管理ツール\n\nサーバーテンプレートとプロファイルで .mcp.json などのMCP設定ファイルを管理します。\n[profile] のように[]で囲まれたプロファイル名は省略可能で、省略時はデフォルトプロファイル '%s' が使用されます。",
+	"help.root.short":              "MCP設定ファイル管理ツール",
+	"help.save.from":               "読み込むMCP設定ファイル",
+	"help.save.from_client":        "指定したクライアントの設定ファイルから読み込む (--from を省略するとクライアントの既定のパス)",
+	"help.save.long":               "MCP設定ファイルのサーバーをサーバーテンプレートとプロファイルとして保存します。\n--from を省略した場合はMCP設定ファイルを自動検出します。\nプロファイル名を省略した場合はデフォルトプロファイル '%s' に保存します。",
+	"help.save.short":              "現在のMCP設定ファイルをプロファイルとして保存",
+	"help.secret.long":             "トークンやパスワードなどの値をシークレットとして管理します。\nテンプレートやプロファイルでは ${secret:名前} で参照でき、apply 時に展開されます。\nシークレットプロバイダーは ~/.mcpconfig/settings.jsonc の \"secrets\" で設定します:\n  {\"secrets\": {\"provider\": \"vault\"}}                                     暗号化ファイル（デフォルト）\n  {\"secrets\": {\"provider\": \"exec\", \"command\": [\"pass\", \"show\", \"{name}\"]}}  外部コマンド（読み取り専用）",
+	"help.secret.short":            "シークレットを管理",
+	"help.secret_get.short":        "シークレットの値を表示",
+	"help.secret_list.short":       "シークレット名の一覧を表示",
+	"help.secret_rm.short":         "シークレットを削除",
+	"help.secret_set.long":         "シークレットを保存します。値を省略した場合は標準入力から読み込むため、シェルの履歴に残りません。",
+	"help.secret_set.short":        "シークレットを保存",
+	"help.server.args":             "コマンドの引数（カンマ区切り）",
+	"help.server.env":              "環境変数 KEY=VALUE（カンマ区切り）",
+	"help.server.env_file":         "環境変数を読み込むファイル",
+	"help.server.short":            "MCPサーバーテンプレートを管理",
+	"help.server_add.allow_tools":  "proxy で公開するツール（カンマ区切り、* などのパターン可）",
+	"help.server_add.args":         "引数を上書き（カンマ区切り）",
+	"help.server_add.args_append":  "テンプレートの引数の後に追加（カンマ区切り）",
+	"help.server_add.args_prepend": "テンプレートの引数の前に追加（カンマ区切り）",
+	"help.server_add.as":           "追加するサーバーの名前（省略時はテンプレート名）",
+	"help.server_add.command":      "コマンドを上書き",
+	"help.server_add.deny_tools":   "proxy で公開しないツール（カンマ区切り、* などのパターン可）",
+	"help.server_add.disabled":     "サーバーを無効にする",
+	"help.server_add.enabled":      "サーバーを有効にする",
+	"help.server_add.env_file":     "サーバーが読み込む環境変数ファイル",
+	"help.server_add.long":         "サーバーテンプレートをプロファイル、またはMCP設定ファイルに追加します。\n--to に既存のプロファイル名を指定した場合はプロファイル、パス（区切り文字を含むか .json で終わるもの）を指定した場合はMCP設定ファイルに追加します（省略時は ./.mcp.json）。\n--command や --args などの上書きオプションと --update はプロファイルへの追加時のみ使用できます。",
+	"help.server_add.short":        "プロファイルまたはMCP設定ファイルにサーバーを追加",
+	"help.server_add.timeout":      "タイムアウト（秒）",
+	"help.server_add.to":           "追加先のプロファイル名またはMCP設定ファイル",
+	"help.server_add.transport":    "トランスポートの種類",
+	"help.server_add.update":       "既に追加されているサーバーの設定を更新",
+	"help.server_copy.long":        "既存のサーバーテンプレートを別名でコピーします。元のサーバーテンプレートはそのまま残ります。",
+	"help.server_copy.short":       "サーバーテンプレートをコピー",
+	"help.server_delete.long":      "サーバーテンプレートを削除します。プロファイルで使用されている場合は、プロファイルからの参照も削除するか確認します。",
+	"help.server_delete.short":     "サーバーテンプレートを削除",
+	"help.server_detail.short":     "サーバーテンプレートの詳細を表示",
+	"help.server_inspect.long":     "サーバーテンプレートを起動して MCP で接続し、ツール（入力スキーマ付き）・プロンプト・リソースの一覧を取得します。\n結果は調査日時とともにテンプレートに記録され、detail --tools で参照されます。",
+	"help.server_inspect.short":    "サーバーのツール・プロンプト・リソースを調査",
+	"help.server_install.as":       "テンプレート名（省略時はカタログの ID）",
+	"help.server_install.long":     "カタログのサーバーからサーバーテンプレートを作成します。テンプレート名は --as で指定でき、省略するとカタログの ID になります。\n必須の環境変数のうち --env で指定されなかったものは入力を求めます。シークレットの値はシークレットの保管庫に '<テンプレート名>/<変数名>' として保存し、テンプレートには ${secret:...} で参照を書き込みます。",
+	"help.server_install.short":    "カタログのサーバーをテンプレートとして保存",
+	"help.server_list.short":       "サーバーテンプレート一覧を表示",
+	"help.server_path.long":        "指定されたサーバーテンプレートファイルの絶対パスを表示します。",
+	"help.server_path.short":       "サーバーテンプレートファイルのパスを表示",
+	"help.server_remove.from":      "対象のMCP設定ファイル",
+	"help.server_remove.short":     "MCP設定ファイルからサーバーを削除",
+	"help.server_rename.short":     "サーバーテンプレート名を変更",
+	"help.server_save.command":     "起動するコマンド",
+	"help.server_save.from":        "読み込むMCP設定ファイル",
+	"help.server_save.header":      "リクエストヘッダー \"名前: 値\"（複数指定可）",
+	"help.server_save.long":        "サーバーテンプレートを次のいずれかの方法で保存します:\n  --server と --from   MCP設定ファイルのサーバーから保存（--from の省略時は ./.mcp.json）\n  --command            コマンドと引数・環境変数を指定して作成\n  --url                リモートサーバー（http|sse）を作成",
+	"help.server_save.server":      "MCP設定ファイル内のサーバー名",
+	"help.server_save.short":       "サーバーテンプレートを保存",
+	"help.server_save.type":        "サーバーの種類 (stdio|http|sse)",
+	"help.server_save.url":         "リモートサーバーのURL",
+	"help.server_search.catalog":   "設定ファイルの代わりに使うカタログ（パスまたは URL、複数指定可）",
+	"help.server_search.long":      "サーバーカタログから、ID・名前・説明・タグにすべての語を含むサーバーを表示します。語を省略するとすべてのサーバーを表示します。\nカタログは設定ファイルの catalogs（ファイルのパスまたは http(s) の URL）から読み込みます。--catalog を指定した場合はそちらを使います。",
+	"help.server_search.short":     "カタログからサーバーを検索",
+	"help.server_test.short":       "サーバーテンプレートを起動して動作を確認",
+	"help.sync.long":               "リモートの変更を取り込み、ローカルの変更を送信します。\n初期化後は、プロファイルやサーバーテンプレートを変更するたびに自動的にコミットされます。\n同じプロファイル・テンプレートが両方で変更されている場合は何も変更せずにエラー終了します。\n--prefer を指定すると、競合したファイルはどちらか一方の内容で置き換えられます。\nシークレット・操作履歴・settings.jsonc は共有されません。",
+	"help.sync.prefer":             "競合時に使用する内容 (local|remote)",
+	"help.sync.short":              "ストアをgitで共有・同期",
+	"help.sync_init.remote":        "同期先のリポジトリURL",
+	"help.sync_init.short":         "ストアをgitリポジトリとして初期化",
+	"help.sync_remote.short":       "同期先を表示・設定",
+	"help.sync_status.short":       "同期の状態を表示",
+	"help.undo.force":              "操作の後に変更されたファイルも上書きする",
+	"help.undo.long":               "履歴に記録された操作を取り消します。IDを省略した場合は直前の操作を取り消します。",
+	"help.undo.short":              "操作を取り消す",
+	"help.version.short":           "バージョンを表示",
+
+	"history.already_undone":       "#%d は既に取り消されています",
+	"history.backup_failed":        "バックアップの作成に失敗しました: %w",
+	"history.backup_read_failed":   "バックアップの読み込みに失敗しました: %w",
+	"history.backup_save_failed":   "バックアップの保存に失敗しました: %w",
+	"history.check_changed_failed": "変更されたファイルの確認に失敗しました: %w",
+	"history.commit_failed":        "警告: 変更のコミットに失敗しました: %v",
+	"history.empty":                "操作履歴はありません",
+	"history.file_created":         "  + %s（作成）",
+	"history.file_deleted":         "  - %s（削除）",
+	"history.file_modified":        "  ~ %s（変更）",
+	"history.invalid_id":           "不正な履歴IDです: '%s'",
+	"history.invalid_limit":        "--limit には0以上の数値を指定してください: '%s'",
+	"history.list_line":            "#%-4d %s  %s（%dファイル）%s",
+	"history.load_failed":          "履歴の読み込みに失敗しました: %w",
+	"history.mkdir_failed":         "履歴ディレクトリの作成に失敗しました: %w",
+	"history.modified_since":       "次のファイルは #%d の後に変更されています（--force で上書きできます）:\n  %s",
+	"history.no_id":                "履歴IDが指定されていません",
+	"history.not_found":            "履歴が見つかりません",
+	"history.nothing_to_undo":      "取り消せる操作がありません",
+	"history.parse_failed":         "履歴 #%d の解析に失敗しました: %w",
+	"history.prune_failed":         "古い履歴の削除に失敗しました: %w",
+	"history.pruned":               "%d件の古い履歴を削除しました",
+	"history.read_failed":          "ファイルの読み込みに失敗しました: %w",
+	"history.record_failed":        "警告: 操作履歴の保存に失敗しました: %v",
+	"history.remove_failed":        "ファイルの削除に失敗しました: %w",
+	"history.restore_failed":       "ファイルの復元に失敗しました: %w",
+	"history.save_failed":          "履歴の保存に失敗しました: %w",
+	"history.show_files":           "ファイル:",
+	"history.show_time":            "日時: %s",
+	"history.show_undone":          "取り消し: %s",
+	"history.stat_failed":          "ファイルの確認に失敗しました: %w",
+	"history.undo_done":            "#%d %s を取り消しました（%dファイルを復元）",
+	"history.undone_mark":          "  [取り消し済み]",
+
+	"i18n.unknown_locale": "不明な言語です: '%s'（使用可能: %s）",
+
+	"interaction.already_exists":    "警告: %s '%s' は既に存在します",
+	"interaction.choose":            "%s [%s] (デフォルト: %s): ",
+	"interaction.confirm_overwrite": "上書きしますか？ (y/N): ",
+
+	"interpolate.failure_context":    "%s（%s）",
+	"interpolate.no_secret_provider": "シークレットプロバイダーが設定されていません",
+	"interpolate.unresolved":         "次の変数を解決できませんでした:",
+
+	"jsonedit.delete_root":          "ルートは削除できません",
+	"jsonedit.empty_value":          "空の値は適用できません",
+	"jsonedit.expect_colon":         "':' が必要です",
+	"jsonedit.expect_comma_brace":   "',' または '}' が必要です",
+	"jsonedit.expect_comma_bracket": "',' または ']' が必要です",
+	"jsonedit.expect_key":           "オブジェクトのキーが必要です",
+	"jsonedit.invalid_number":       "不正な数値です: %s",
+	"jsonedit.invalid_path":         "不正なパス要素です: %v",
+	"jsonedit.invalid_result":       "編集後のドキュメントが不正です: %w",
+	"jsonedit.invalid_string":       "不正な文字列です: %v",
+	"jsonedit.missing_array":        "存在しない配列の要素は設定できません: %v",
+	"jsonedit.missing_value":        "値がありません",
+	"jsonedit.not_object":           "オブジェクトではない値にキー '%s' は設定できません",
+	"jsonedit.out_of_range":         "配列の範囲外の要素は設定できません: %v",
+	"jsonedit.parse_failed":         "JSONCの解析に失敗しました (オフセット %d): %s",
+	"jsonedit.trailing_data":        "値の後に余分なデータがあります",
+	"jsonedit.unclosed_array":       "閉じられていない配列があります",
+	"jsonedit.unclosed_comment":     "閉じられていないコメントがあります",
+	"jsonedit.unclosed_object":      "閉じられていないオブジェクトがあります",
+	"jsonedit.unclosed_string":      "文字列が閉じられていません",
+	"jsonedit.unexpected_char":      "予期しない文字 '%c' があります",
+
+	"kind.group":    "グループ",
+	"kind.profile":  "プロファイル",
+	"kind.template": "サーバーテンプレート",
+
+	"mcpclient.closed":           "サーバーが標準出力を閉じました",
+	"mcpclient.error_response":   "%s がエラーを返しました: %s (コード %d)",
+	"mcpclient.exited":           "サーバーが終了しました: %w",
+	"mcpclient.invalid_message":  "JSON-RPCではない出力がありました: %s",
+	"mcpclient.invalid_result":   "%s の応答を解析できません: %w",
+	"mcpclient.method_not_found": "メソッド '%s' はサポートされていません",
+	"mcpclient.start_failed":     "'%s' を起動できません: %w",
+	"mcpclient.stdin_closed":     "サーバーが標準入力を読まなくなりました",
+	"mcpclient.timeout":          "%s 以内に応答がありませんでした",
+
+	"mcpjson.conflict":             "次のサーバーはMCP設定ファイル側で追加・編集されているため上書きできません: %s\n--conflict=%s または --conflict=%s を指定してください",
+	"mcpjson.expand_failed":        "プロファイル '%s' の変数を展開できません: %w",
+	"mcpjson.extends_cycle":        "プロファイルの継承が循環しています: %s -> %s",
+	"mcpjson.invalid_tool_pattern": "ツールのパターン '%s' が不正です: %w",
+	"mcpjson.no_template":          "プロファイル '%s' のサーバー '%s' にテンプレートが指定されていません",
+	"mcpjson.parent_load_failed":   "プロファイル '%s' の継承元 '%s' を読み込めません: %w",
+	"mcpjson.parent_unreadable":    "プロファイル '%s' の継承元を読み込めません",
+	"mcpjson.server_context":       "サーバー '%s'",
+	"mcpjson.template_load_failed": "サーバーテンプレート '%s' の読み込みに失敗しました: %w",
+	"mcpjson.unknown_conflict":     "不明な競合時の動作です: '%s'（使用可能: %s, %s, %s）",
+	"mcpjson.unknown_mode":         "不明な適用モードです: '%s'（使用可能: %s, %s, %s）",
+
+	"mcpserve.arg.client":                "ファイル形式のクライアント",
+	"mcpserve.arg.conflict":              "手動で追加・編集されたサーバーと衝突した場合の動作（省略時は fail）",
+	"mcpserve.arg.mode":                  "既存のサーバーとの組み合わせ方（省略時は replace）",
+	"mcpserve.arg.name":                  "設定ファイルでのサーバー名（省略時はテンプレート名）",
+	"mcpserve.arg.path":                  "MCP設定ファイルのパス（省略時はクライアントまたはプロジェクトの設定ファイル）",
+	"mcpserve.arg.profile":               "プロファイル名（省略時は '%s'）",
+	"mcpserve.arg.server_path":           "MCP設定ファイルのパス（省略時は ./.mcp.json）",
+	"mcpserve.arg.template":              "サーバーテンプレート名",
+	"mcpserve.confirm_description":       "変更内容を確認済みの場合に true を指定",
+	"mcpserve.confirm_required":          "%s はMCP設定ファイルを変更します。変更内容を確認し、%s に true を指定して再度呼び出してください",
+	"mcpserve.invalid_arguments":         "ツール '%s' の引数が不正です: %w",
+	"mcpserve.invalid_params":            "パラメータが不正です: %v",
+	"mcpserve.method_not_found":          "メソッド '%s' はサポートされていません",
+	"mcpserve.parse_error":               "リクエストを解析できません: %v",
+	"mcpserve.started":                   "MCP サーバーを起動しました（ツール: %s）",
+	"mcpserve.tool.add_server_to_config": "サーバーテンプレートのサーバーを path のMCP設定ファイルに追加します。同じ名前のサーバーがある場合は失敗します",
+	"mcpserve.tool.apply_profile":        "プロファイルのサーバーをMCP設定ファイルに書き込み、適用した変更を返します。変更内容は diff_profile で事前に確認できます。クライアントがファイルを読み直すまで反映されません",
+	"mcpserve.tool.diff_profile":         "プロファイルを適用した場合にMCP設定ファイルのサーバーがどう変わるかを表示します。ファイルは変更しません。環境変数とヘッダーの値は伏せられます",
+	"mcpserve.tool.list_groups":          "サーバーグループを一覧表示します",
+	"mcpserve.tool.list_profiles":        "mcpjson のプロファイルを一覧表示します（名前・説明・継承元・サーバー数）",
+	"mcpserve.tool.list_templates":       "サーバーテンプレートを一覧表示します（名前・説明・種類・コマンドまたはURL）",
+	"mcpserve.unknown_allow":             "不明なツール '%s' が許可リストにあります（使用可能: %s）",
+	"mcpserve.unknown_tool":              "ツール '%s' はありません",
+
+	"merge.no_sources": "合成先と少なくとも1つのソースプロファイルを指定してください",
+
+	"output.not_structured": "出力形式 '%s' では書き出せません",
+	"output.unknown_format": "不明な出力形式です: '%s'（使用可能: %s, %s, %s）",
+	"output.yaml_failed":    "YAMLの生成に失敗しました: %w",
+
+	"path.get_failed": "プロファイルパスの取得に失敗しました: %w",
+
+	"profile.applied":                 "プロファイル '%s' を適用しました",
+	"profile.apply_summary":           "追加: %d, 更新: %d, 削除: %d, 保持: %d",
+	"profile.column_description":      "説明",
+	"profile.column_name":             "プロファイル名",
+	"profile.column_server":           "サーバー",
+	"profile.column_tool":             "ツール",
+	"profile.confirm_delete":          "プロファイル '%s' を削除しますか？",
+	"profile.confirm_reset":           "すべてのプロファイルを削除しますか？",
+	"profile.conflicted":              "競合したサーバー（%s）: %s",
+	"profile.context":                 "プロファイル '%s'",
+	"profile.copied":                  "プロファイル '%s' を '%s' にコピーしました",
+	"profile.created":                 "プロファイル '%s' を作成しました（%d個のサーバー）",
+	"profile.created_from_template":   "テンプレート %s から作成",
+	"profile.delete_failed":           "プロファイルの削除に失敗しました: %w",
+	"profile.deleted":                 "プロファイル '%s' を削除しました",
+	"profile.detail_excluded":         "    - %s [継承元から除外]",
+	"profile.detail_extends":          "  継承元: %s",
+	"profile.detail_header":           "\nプロファイル: %s",
+	"profile.detail_override":         "    - %s (継承元の設定を上書き)",
+	"profile.detail_server":           "    - %s (テンプレート: %s)%s",
+	"profile.detail_server_count":     "  サーバー数: %d",
+	"profile.detail_servers":          "  サーバー:",
+	"profile.disabled":                " [無効]",
+	"profile.dropped_fields":          "⚠️ サーバー '%s' の %s は %s の設定ファイルで表せないため書き込みませんでした",
+	"profile.duplicate_server":        "警告: サーバー '%s' は既に追加されているため、スキップします（プロファイル: %s）",
+	"profile.exists":                  "プロファイル '%s' は既に存在します",
+	"profile.exists_force":            "プロファイル '%s' は既に存在します。--force オプションで上書きしてください",
+	"profile.exists_rename_or_force":  "プロファイル '%s' は既に存在します。別の名前を指定するか、--force オプションで上書きしてください",
+	"profile.extends_update_failed":   "プロファイル '%s' の継承元の更新に失敗しました: %w",
+	"profile.extends_updated":         "%d個のプロファイルの継承元を更新しました",
+	"profile.load_failed":             "プロファイルの読み込みに失敗しました: %w",
+	"profile.load_named_failed":       "プロファイル '%s' の読み込みに失敗しました: %w",
+	"profile.mcp_read_failed":         "MCP設定の読み込みに失敗: %w",
+	"profile.merged_description":      "%d個のプロファイルを合成",
+	"profile.merged_from":             "合成元: %v",
+	"profile.no_dir":                  "プロファイルディレクトリが存在しません",
+	"profile.none":                    "プロファイルが存在しません",
+	"profile.not_found":               "プロファイル '%s' が見つかりません",
+	"profile.provenance_failed":       "警告: 適用履歴の保存に失敗しました: %v",
+	"profile.read_dir_failed":         "プロファイルディレクトリの読み込みに失敗しました: %w",
+	"profile.refs_removed":            "プロファイル '%s' からサーバーテンプレート '%s' の参照を%d個削除しました",
+	"profile.refs_removed_total":      "合計%d個のプロファイルからサーバーテンプレート '%s' の参照を削除しました",
+	"profile.remove_old_failed":       "古いプロファイルの削除に失敗しました: %w",
+	"profile.remove_refs_failed":      "プロファイル '%s' からの参照削除に失敗しました: %w（%v）",
+	"profile.remove_refs_rolled_back": "プロファイル '%s' からの参照削除に失敗したため、すべての変更を取り消しました: %w",
+	"profile.renamed":                 "プロファイル '%s' を '%s' に変更しました",
+	"profile.reset_done":              "プロファイルを%d個削除しました",
+	"profile.reset_list":              "以下の%d個のプロファイルを削除します:",
+	"profile.reset_none":              "削除するプロファイルが存在しません",
+	"profile.saved":                   "プロファイル '%s' を保存しました (%d個のサーバー)",
+	"profile.saved_description":       "%s から保存",
+	"profile.saved_servers":           "%d個のサーバー設定を '%s' に保存",
+	"profile.server_added":            "サーバー '%s' をプロファイル '%s' に追加しました",
+	"profile.server_exists":           "サーバー名 '%s' は既にプロファイル '%s' に存在します。別の名前を指定してください（--as オプションを使用）",
+	"profile.server_failed":           "サーバー処理に失敗: %w",
+	"profile.server_not_found":        "サーバー '%s' がプロファイル '%s' に見つかりません",
+	"profile.server_removed":          "サーバー '%s' をプロファイル '%s' から削除しました",
+	"profile.server_updated":          "プロファイル '%s' のサーバー '%s' を更新しました",
+	"profile.skipped_missing":         "MCP設定ファイルに存在しないため追加しなかったサーバー: %s",
+	"profile.template_context":        "サーバーテンプレート '%s'",
+	"profile.template_exists":         "サーバーテンプレート '%s' は既に存在するため、既存のものを使用します",
+	"profile.tool_collision":          "\n⚠️ ツール名 '%s' が複数のサーバーで公開されています: %s",
+	"profile.tool_collision_mark":     "(重複)",
+	"profile.tools_uninspected":       "\n未調査のサーバー: %s（mcpjson server inspect <テンプレート> で調査できます）",
+
+	"provenance.mkdir_failed": "状態ディレクトリの作成に失敗しました: %w",
+	"provenance.parse_failed": "適用履歴の解析に失敗しました %s: %w",
+	"provenance.read_failed":  "適用履歴の読み込みに失敗しました: %w",
+	"provenance.save_failed":  "適用履歴の保存に失敗しました: %w",
+
+	"proxy.call_failed":        "'%s' でエラーが発生しました: %w",
+	"proxy.error_response":     "'%s' がエラーを返しました: %s (コード %d)",
+	"proxy.invalid_tool":       "tools/list のツールを解析できません: %w",
+	"proxy.prefix_collision":   "'%s' を除外しました: '%s' とツール名の接頭辞 '%s' が重複します",
+	"proxy.project_dir_failed": "プロジェクトディレクトリ '%s' に移動できません: %v",
+	"proxy.remote_skipped":     "'%s' は %s のリモートサーバーのため除外しました",
+	"proxy.start_failed":       "'%s' を除外しました: %v",
+	"proxy.started":            "プロファイル '%s' のプロキシを起動しました（ツール: %d 個）",
+
+	"reset.all_done":         "すべての設定をリセットしました",
+	"reset.all_list":         "以下の設定がすべて削除されます:",
+	"reset.all_profiles":     "  - すべてのプロファイル",
+	"reset.all_templates":    "  - すべてのサーバーテンプレート",
+	"reset.confirm_all":      "本当にすべての設定をリセットしますか？",
+	"reset.profiles_failed":  "プロファイルのリセットに失敗しました: %v",
+	"reset.templates_failed": "サーバーテンプレートのリセットに失敗しました: %v",
+
+	"save.detected":    "MCP設定ファイルを自動検出しました: %s",
+	"save.no_mcp_file": "MCP設定ファイルが見つかりません\n使用方法: mcpconfig save [プロファイル名] --from <パス>",
+
+	"secret.cipher_failed":         "暗号の初期化に失敗しました: %w",
+	"secret.cmd_no_name":           "シークレット名が指定されていません",
+	"secret.command_failed":        "シークレット取得コマンドが失敗しました: %w",
+	"secret.command_failed_output": "シークレット取得コマンドが失敗しました: %w: %s",
+	"secret.confirm_delete":        "シークレット '%s' を削除しますか？",
+	"secret.corrupted":             "シークレット '%s' のデータが破損しています",
+	"secret.decrypt_failed":        "シークレット '%s' を復号できません（鍵ファイルが異なる可能性があります）",
+	"secret.deleted":               "シークレット '%s' を削除しました",
+	"secret.empty_value":           "シークレットの値が空です",
+	"secret.encrypt_failed":        "シークレットの暗号化に失敗しました: %w",
+	"secret.get_failed":            "シークレット '%s' を取得できません: %w",
+	"secret.invalid_name":          "シークレット名に使用できない文字が含まれています（使用可能: 英数字、ハイフン、アンダースコア、ドット、スラッシュ）",
+	"secret.key_invalid":           "シークレットの鍵ファイルが不正です: %s",
+	"secret.key_not_found":         "シークレットの鍵ファイルが見つかりません: %s",
+	"secret.key_read_failed":       "シークレットの鍵ファイルの読み込みに失敗しました: %w",
+	"secret.key_save_failed":       "シークレットの鍵ファイルの保存に失敗しました: %w",
+	"secret.keygen_failed":         "シークレットの鍵の生成に失敗しました: %w",
+	"secret.load_failed":           "シークレットの読み込みに失敗しました: %w",
+	"secret.mkdir_failed":          "シークレットディレクトリの作成に失敗しました: %w",
+	"secret.name_too_long":         "シークレット名は%d文字以内で指定してください",
+	"secret.named_not_found":       "シークレット '%s' が見つかりません",
+	"secret.no_command":            "exec シークレットプロバイダーのコマンドが設定されていません（settings.jsonc の secrets.command）",
+	"secret.no_name":               "シークレット名が指定されていません",
+	"secret.none":                  "シークレットは登録されていません",
+	"secret.not_found":             "シークレットが見つかりません",
+	"secret.parse_failed":          "シークレットファイルの解析に失敗しました %s: %w",
+	"secret.prompt":                "シークレット '%s' の値を入力してください: ",
+	"secret.read_only":             "設定されているシークレットプロバイダーは書き込みに対応していません",
+	"secret.read_value_failed":     "シークレットの値の読み込みに失敗しました: %w",
+	"secret.save_failed":           "シークレットの保存に失敗しました: %w",
+	"secret.saved":                 "シークレット '%s' を保存しました",
+	"secret.unknown_provider":      "不明なシークレットプロバイダーです: '%s'（使用可能: %s, %s）",
+	"secret.unsupported_version":   "未対応のシークレットファイルのバージョンです: %d",
+
+	"server.added_to_mcp":               "サーバー '%s' をMCP設定ファイルに追加しました: %s",
+	"server.already_exists":             "サーバーテンプレート '%s' は既に存在します\n別の名前を指定するか、--force オプションで上書きしてください",
+	"server.already_in_mcp":             "サーバー '%s' は既にMCP設定ファイルに存在します",
+	"server.capabilities_other_store":   "サーバーテンプレート '%s' は選択中のストア外の %s から読み込まれているため、調査結果を保存できません\n--store でそのストアを選択してから調査してください",
+	"server.capabilities_save_failed":   "サーバーテンプレート '%s' に調査結果を保存できません: %w",
+	"server.cmd_no_template":            "テンプレート名が指定されていません",
+	"server.column_command":             "コマンド",
+	"server.column_name":                "テンプレート名",
+	"server.confirm_delete":             "サーバーテンプレート '%s' を削除しますか？",
+	"server.confirm_delete_refs":        "プロファイルからの参照も削除しますか？",
+	"server.confirm_reset":              "すべてのサーバーテンプレートを削除しますか？",
+	"server.copied":                     "サーバーテンプレート '%s' を '%s' にコピーしました",
+	"server.copy_no_dest":               "コピー先のサーバーテンプレート名が指定されていません",
+	"server.copy_no_source":             "コピー元のサーバーテンプレート名が指定されていません",
+	"server.copy_usage":                 "コピー元とコピー先のサーバー名を指定してください",
+	"server.delete_failed":              "サーバーテンプレートの削除に失敗しました: %w",
+	"server.deleted":                    "サーバーテンプレート '%s' を削除しました",
+	"server.detail_args":                "  引数: %v",
+	"server.detail_command":             "  コマンド: %s",
+	"server.detail_env":                 "  環境変数:",
+	"server.detail_header":              "\nテンプレート: %s",
+	"server.detail_headers":             "  ヘッダー:",
+	"server.detail_load_failed":         "サーバーテンプレートの読み込みに失敗しました: %v",
+	"server.detail_no_name":             "サーバー名を指定してください",
+	"server.detail_type":                "  タイプ: %s",
+	"server.expand_failed":              "テンプレート '%s' の変数を展開できません: %w",
+	"server.force_remove_refs":          "強制削除: プロファイルからの参照も削除します",
+	"server.inspect_failed":             "サーバーテンプレート '%s' を調査できません: %w",
+	"server.inspect_header":             "テンプレート: %s\nサーバー: %s\n調査日時: %s",
+	"server.inspect_no_name":            "テンプレート名を指定してください",
+	"server.inspect_prompts":            "\nプロンプト (%d):",
+	"server.inspect_resources":          "\nリソース (%d):",
+	"server.inspect_tools":              "\nツール (%d):",
+	"server.invalid_timeout":            "--timeout には正の整数を指定してください: '%s'",
+	"server.mcp_not_object":             "MCP設定ファイルの解析に失敗しました: JSONオブジェクトではありません",
+	"server.mcp_parse_failed":           "MCP設定ファイルの解析に失敗しました: %w",
+	"server.mcp_server_invalid":         "MCPサーバー '%s' の設定が不正です: %w",
+	"server.mcp_server_not_found":       "MCPサーバー '%s' がMCP設定ファイルに見つかりません",
+	"server.no_command":                 "コマンドが指定されていません",
+	"server.no_dir":                     "サーバーテンプレートディレクトリが存在しません",
+	"server.no_templates":               "サーバーテンプレートが存在しません",
+	"server.no_url":                     "URLが指定されていません",
+	"server.not_in_mcp":                 "サーバー '%s' がMCP設定ファイルに見つかりません\nファイル: %s\n利用可能なサーバー: %v",
+	"server.overrides_profile_only":     "--command/--args/--timeout などの上書きオプションと --update はプロファイルへの追加時のみ使用できます",
+	"server.path_failed":                "サーバーテンプレートパスの取得に失敗しました: %v",
+	"server.path_no_name":               "サーバーテンプレート名を指定してください",
+	"server.read_dir_failed":            "サーバーディレクトリの読み込みに失敗しました: %w",
+	"server.read_template_dir_failed":   "サーバーテンプレートディレクトリの読み込みに失敗しました: %w",
+	"server.remote_and_command":         "--url/--header と --command/--args は同時に指定できません",
+	"server.remote_needs_url":           "リモートサーバー（%s）には url が必要です",
+	"server.remove_failed":              "サーバー '%s' の削除に失敗しました: %w",
+	"server.remove_no_name":             "サーバー名が指定されていません",
+	"server.remove_old_failed":          "古いサーバーテンプレートの削除に失敗しました: %w",
+	"server.remove_refs_failed":         "警告: プロファイルからの参照削除に失敗しました: %v",
+	"server.removed_from_mcp":           "サーバー '%s' をMCP設定ファイルから削除しました: %s",
+	"server.rename_no_names":            "変更前と変更後のサーバーテンプレート名を指定してください",
+	"server.renamed":                    "サーバーテンプレート '%s' を '%s' に変更しました",
+	"server.reset_done":                 "サーバーテンプレートを%d個削除しました",
+	"server.reset_list":                 "以下の%d個のサーバーテンプレートを削除します:",
+	"server.reset_none":                 "削除するサーバーテンプレートが存在しません",
+	"server.save_needs_source":          "設定ファイルからの保存には --server と --from が必要です\n手動作成には --command（リモートサーバーの場合は --url）が必要です",
+	"server.saved_args":                 "引数: %v",
+	"server.saved_command":              "コマンド: %s",
+	"server.saved_type":                 "タイプ: %s",
+	"server.section_not_object":         "%s の解析に失敗しました: JSONオブジェクトではありません",
+	"server.server_parse_failed":        "サーバー '%s' の解析に失敗しました: %w",
+	"server.stdio_needs_command":        "stdioサーバーには command が必要です",
+	"server.target_ambiguous":           "--to '%[1]s' はプロファイルでもファイルのパスでもありません。プロファイルに追加する場合は先にプロファイルを作成し、ファイルに書き込む場合は ./%[1]s のようにパスで指定してください",
+	"server.template_created":           "サーバーテンプレート '%s' を作成しました",
+	"server.template_invalid":           "サーバーテンプレート '%s' の設定が不正です: %w",
+	"server.template_load_failed":       "サーバーテンプレートの読み込みに失敗しました: %w",
+	"server.template_load_failed_short": "テンプレート '%s' の読み込みに失敗しました: %w",
+	"server.template_saved":             "サーバーテンプレート '%s' を保存しました",
+	"server.template_updated":           "サーバーテンプレート '%s' を更新しました",
+	"server.test_no_name":               "テンプレート名を指定してください",
+	"server.unknown_type":               "不明なサーバータイプです: '%s'",
+	"server.unknown_type_available":     "不明なサーバータイプです: '%s'（使用可能: %s, %s, %s）",
+	"server.unresolved_secret":          "サーバー '%s' に未解決のシークレット参照が残っているため書き込みを中止しました: %s",
+	"server.usage_check_failed":         "プロファイルでの使用状況確認に失敗しました: %w",
+	"server.used_by_profiles":           "警告: サーバーテンプレート '%s' は以下のプロファイルで使用されています:",
+
+	"sync.auto_commit":      "以降の変更は自動的にコミットされます",
+	"sync.initialised":      "'%s' をgitリポジトリとして初期化しました",
+	"sync.invalid_prefer":   "--prefer には %s または %s を指定してください: '%s'",
+	"sync.no_remote":        "同期先は設定されていません",
+	"sync.not_managed":      "ストアはgitで管理されていません（'mcpjson sync init' で初期化できます）",
+	"sync.pulled":           "リモートの変更を取り込みました",
+	"sync.pushed":           "ローカルの変更を送信しました",
+	"sync.remote_set":       "同期先を '%s' に設定しました",
+	"sync.resolved":         "競合を解決しました（%s の内容を使用）: %s",
+	"sync.status_no_remote": "同期先: 未設定",
+	"sync.status_remote":    "同期先: %s",
+	"sync.status_store":     "ストア: %s（gitで管理）",
+	"sync.up_to_date":       "取り込む変更はありません",
+
+	"utils.backup_failed":          "バックアップの作成に失敗しました: %w",
+	"utils.default_profile":        "プロファイル名が指定されていないため、デフォルト '%s' を使用します",
+	"utils.default_source_profile": "元のプロファイル名が指定されていないため、デフォルト '%s' を使用します",
+	"utils.env_file_invalid":       "環境変数ファイルの形式が不正です: '%s' 行%d",
+	"utils.env_file_not_found":     "環境変数ファイルが見つかりません: '%s'",
+	"utils.env_file_read_failed":   "環境変数ファイルの読み込みに失敗しました: '%s'",
+	"utils.env_invalid":            "環境変数の形式が不正です: '%s'",
+	"utils.env_name_invalid":       "環境変数名が不正です: '%s'",
+	"utils.flag_missing_value":     "%s オプションに値が指定されていません",
+	"utils.header_invalid":         "ヘッダーの形式が不正です: '%s'（例: \"Authorization: Bearer xxx\"）",
+	"utils.header_name_invalid":    "ヘッダー名が不正です: '%s'",
+	"utils.name_empty":             "%s名が指定されていません",
+	"utils.name_invalid_chars":     "%s名に使用できない文字が含まれています（使用可能: 英数字、ハイフン、アンダースコア）",
+	"utils.name_reserved":          "%s名に予約語 '%s' は使用できません",
+	"utils.name_too_long":          "%s名は%d文字以内で指定してください",
+	"utils.rename_missing_name":    "新しいプロファイル名が指定されていません\n使用方法: mcpconfig rename [現在のプロファイル名] <新しいプロファイル名>",
+	"utils.rollback_failed":        "変更の取り消しに失敗しました: %w",
+	"utils.transaction_closed":     "終了したトランザクションは使用できません",
+}
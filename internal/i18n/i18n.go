@@ -0,0 +1,139 @@
+// Package i18n looks up user-facing messages by ID in the catalog of the
+// selected locale.
+//
+// Messages are fmt format strings. Every ID has to be present in every
+// catalog; i18n_test.go checks this and that the verbs of each
+// translation match the Japanese original.
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Locale is a language that has a message catalog
+type Locale string
+
+const (
+	Japanese Locale = "ja"
+	English  Locale = "en"
+
+	// DefaultLocale is used when no locale is configured
+	DefaultLocale = Japanese
+
+	// EnvLang selects the locale, taking precedence over LANG
+	EnvLang = "MCPJSON_LANG"
+)
+
+var catalogs = map[Locale]map[string]string{
+	Japanese: ja,
+	English:  en,
+}
+
+var current = DefaultLocale
+
+// SetLocale selects the catalog used by T and Errorf
+func SetLocale(locale Locale) {
+	if _, ok := catalogs[locale]; ok {
+		current = locale
+	}
+}
+
+// CurrentLocale returns the selected locale
+func CurrentLocale() Locale {
+	return current
+}
+
+// Locales returns the locales that have a catalog, sorted
+func Locales() []Locale {
+	locales := make([]Locale, 0, len(catalogs))
+	for locale := range catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Slice(locales, func(i, j int) bool { return locales[i] < locales[j] })
+	return locales
+}
+
+// ParseLocale accepts a locale name such as "en", "ja_JP.UTF-8" or "en-US"
+func ParseLocale(s string) (Locale, error) {
+	name := strings.ToLower(s)
+	if i := strings.IndexAny(name, "_-.@"); i >= 0 {
+		name = name[:i]
+	}
+	if _, ok := catalogs[Locale(name)]; ok {
+		return Locale(name), nil
+	}
+	return "", Errorf("i18n.unknown_locale", s, strings.Join(localeNames(), ", "))
+}
+
+// Detect selects the locale from the --lang flag, MCPJSON_LANG or the
+// standard LC_ALL, LC_MESSAGES and LANG variables, in that order. An
+// invalid flag or MCPJSON_LANG is an error. A system locale without a
+// catalog falls back to English, and an unset or "C" locale to Japanese.
+func Detect(flag string) (Locale, error) {
+	if flag != "" {
+		return ParseLocale(flag)
+	}
+	if value := os.Getenv(EnvLang); value != "" {
+		return ParseLocale(value)
+	}
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+		if value == "C" || value == "POSIX" || strings.HasPrefix(value, "C.") {
+			return DefaultLocale, nil
+		}
+		if locale, err := ParseLocale(value); err == nil {
+			return locale, nil
+		}
+		return English, nil
+	}
+	return DefaultLocale, nil
+}
+
+// T returns the message with the given ID in the selected locale,
+// formatted with args. Unknown IDs fall back to the Japanese catalog and
+// then to the ID itself.
+func T(id string, args ...interface{}) string {
+	format := lookup(id)
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Errorf is fmt.Errorf with the message with the given ID. It supports %w.
+func Errorf(id string, args ...interface{}) error {
+	return fmt.Errorf(lookup(id), args...)
+}
+
+func lookup(id string) string {
+	if message, ok := catalogs[current][id]; ok {
+		return message
+	}
+	if message, ok := catalogs[DefaultLocale][id]; ok {
+		return message
+	}
+	return id
+}
+
+func localeNames() []string {
+	names := make([]string, 0, len(catalogs))
+	for _, locale := range Locales() {
+		names = append(names, string(locale))
+	}
+	return names
+}
+
+// Error is an error with the message of the given ID. The message is looked
+// up when the error is printed, so package-level sentinel errors follow the
+// locale selected at startup and can still be compared with errors.Is.
+type Error string
+
+func (e Error) Error() string {
+	return lookup(string(e))
+}
@@ -0,0 +1,193 @@
+package i18n
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+)
+
+var verbPattern = regexp.MustCompile(`%(\[\d+\])?[-+# 0]*\d*(\.\d+)?[a-zA-Z%]`)
+
+// messageRefPattern matches the message IDs passed to T, Errorf, Error and
+// the JSONC parser's errorf
+var messageRefPattern = regexp.MustCompile(`(?:i18n\.(?:T|Errorf|Error)|p\.errorf|[^.\w]Errorf)\("([a-z0-9_.]+)"`)
+
+func TestCatalogs_SameKeys(t *testing.T) {
+	for locale, catalog := range catalogs {
+		for id := range catalogs[DefaultLocale] {
+			if _, ok := catalog[id]; !ok {
+				t.Errorf("%s カタログに %s がありません", locale, id)
+			}
+		}
+		for id := range catalog {
+			if _, ok := catalogs[DefaultLocale][id]; !ok {
+				t.Errorf("%s カタログの %s は %s カタログにありません", locale, id, DefaultLocale)
+			}
+		}
+	}
+}
+
+func TestCatalogs_SameVerbs(t *testing.T) {
+	for locale, catalog := range catalogs {
+		for id, message := range catalog {
+			want := verbs(catalogs[DefaultLocale][id])
+			if got := verbs(message); !equalStrings(got, want) {
+				t.Errorf("%s の %s の書式指定子 %v が %v と一致しません", locale, id, got, want)
+			}
+		}
+	}
+}
+
+// TestCatalogs_CoverSource checks that every message ID used in the source
+// tree has an entry, and that every entry is used
+func TestCatalogs_CoverSource(t *testing.T) {
+	root := filepath.Join("..", "..")
+	used := map[string]bool{}
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if strings.HasPrefix(info.Name(), ".") && path != root {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, match := range messageRefPattern.FindAllStringSubmatch(string(data), -1) {
+			used[match[1]] = true
+			for locale, catalog := range catalogs {
+				if _, ok := catalog[match[1]]; !ok {
+					t.Errorf("%s: %s カタログに %s がありません", path, locale, match[1])
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for id := range catalogs[DefaultLocale] {
+		if !used[id] {
+			t.Errorf("%s はどこからも使用されていません", id)
+		}
+	}
+}
+
+func TestParseLocale(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Locale
+		wantErr bool
+	}{
+		{input: "ja", want: Japanese},
+		{input: "en", want: English},
+		{input: "ja_JP.UTF-8", want: Japanese},
+		{input: "en-US", want: English},
+		{input: "EN", want: English},
+		{input: "fr", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseLocale(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLocale() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseLocale() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name    string
+		flag    string
+		env     map[string]string
+		want    Locale
+		wantErr bool
+	}{
+		{name: "未設定", want: Japanese},
+		{name: "フラグを優先", flag: "en", env: map[string]string{EnvLang: "ja"}, want: English},
+		{name: "MCPJSON_LANGはLANGより優先", env: map[string]string{EnvLang: "ja", "LANG": "en_US.UTF-8"}, want: Japanese},
+		{name: "LANG", env: map[string]string{"LANG": "en_US.UTF-8"}, want: English},
+		{name: "LC_ALLはLANGより優先", env: map[string]string{"LC_ALL": "ja_JP.UTF-8", "LANG": "en_US.UTF-8"}, want: Japanese},
+		{name: "Cロケール", env: map[string]string{"LANG": "C.UTF-8"}, want: Japanese},
+		{name: "カタログのないシステムロケール", env: map[string]string{"LANG": "de_DE.UTF-8"}, want: English},
+		{name: "不明なフラグ", flag: "de", wantErr: true},
+		{name: "不明なMCPJSON_LANG", env: map[string]string{EnvLang: "de"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{EnvLang, "LC_ALL", "LC_MESSAGES", "LANG"} {
+				t.Setenv(name, tt.env[name])
+			}
+			got, err := Detect(tt.flag)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Detect() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Detect() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestT(t *testing.T) {
+	defer SetLocale(CurrentLocale())
+
+	SetLocale(English)
+	if got, want := T("profile.not_found", "work"), "Profile 'work' not found"; got != want {
+		t.Errorf("T() = %q, want %q", got, want)
+	}
+	if got, want := Error("secret.not_found").Error(), "Secret not found"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+
+	SetLocale(Japanese)
+	if got, want := T("profile.not_found", "work"), "プロファイル 'work' が見つかりません"; got != want {
+		t.Errorf("T() = %q, want %q", got, want)
+	}
+	if got := T("no.such.message"); got != "no.such.message" {
+		t.Errorf("T() = %q, want the ID", got)
+	}
+}
+
+func verbs(message string) []string {
+	found := verbPattern.FindAllString(message, -1)
+	result := make([]string, 0, len(found))
+	for _, verb := range found {
+		if verb != "%%" {
+			result = append(result, explicitIndex.ReplaceAllString(verb, "%"))
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+var explicitIndex = regexp.MustCompile(`^%\[\d+\]`)
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
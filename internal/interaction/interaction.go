@@ -3,46 +3,80 @@ package interaction
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
 )
 
+// Input is read for the answers to prompts instead of stdin when it is
+// set. Tests use it to answer prompts without a terminal.
+var Input io.Reader
+
 func IsInteractive() bool {
 	stat, _ := os.Stdin.Stat()
 	return (stat.Mode() & os.ModeCharDevice) != 0
 }
 
 func ConfirmOverwrite(resourceType, name string) bool {
-	if !IsInteractive() {
+	if !canAsk() {
 		return false
 	}
 
-	fmt.Printf("警告: %s '%s' は既に存在します\n", resourceType, name)
-	fmt.Print("上書きしますか？ (y/N): ")
-
-	reader := bufio.NewReader(os.Stdin)
-	input, err := reader.ReadString('\n')
-	if err != nil {
-		return false
-	}
+	fmt.Println(i18n.T("interaction.already_exists", resourceType, name))
+	fmt.Print(i18n.T("interaction.confirm_overwrite"))
 
-	input = strings.TrimSpace(strings.ToLower(input))
-	return input == "y" || input == "yes"
+	input, ok := readAnswer()
+	return ok && (input == "y" || input == "yes")
 }
 
 func Confirm(message string) bool {
-	if !IsInteractive() {
+	if !canAsk() {
 		return false
 	}
 
 	fmt.Printf("%s (y/N): ", message)
 
-	reader := bufio.NewReader(os.Stdin)
-	input, err := reader.ReadString('\n')
-	if err != nil {
-		return false
+	input, ok := readAnswer()
+	return ok && (input == "y" || input == "yes")
+}
+
+// Choose asks the user to pick one of choices. The first letter of a
+// choice is accepted as well. It returns defaultChoice when stdin is not
+// interactive or the answer matches no choice.
+func Choose(message string, choices []string, defaultChoice string) string {
+	if !canAsk() {
+		return defaultChoice
 	}
 
-	input = strings.TrimSpace(strings.ToLower(input))
-	return input == "y" || input == "yes"
+	fmt.Print(i18n.T("interaction.choose", message, strings.Join(choices, "/"), defaultChoice))
+
+	input, ok := readAnswer()
+	if !ok {
+		return defaultChoice
+	}
+	for _, choice := range choices {
+		if input == choice || (len(input) == 1 && strings.HasPrefix(choice, input)) {
+			return choice
+		}
+	}
+	return defaultChoice
+}
+
+func canAsk() bool {
+	return Input != nil || IsInteractive()
+}
+
+// readAnswer reads one line of input, trimmed and in lower case
+func readAnswer() (string, bool) {
+	in := Input
+	if in == nil {
+		in = os.Stdin
+	}
+	input, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimSpace(strings.ToLower(input)), true
 }
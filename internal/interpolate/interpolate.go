@@ -0,0 +1,205 @@
+// Package interpolate resolves placeholders such as ${env:NAME},
+// ${file:path}, ${profile.name} and ${secret:name} in server settings at
+// apply time.
+//
+// Only registered scopes are touched. Other ${...} forms, such as the
+// ${VAR} syntax some MCP clients expand themselves, are left as-is.
+// A placeholder can be written literally by doubling the dollar sign:
+// $${env:NAME} becomes ${env:NAME}.
+package interpolate
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+)
+
+// SecretScope is the scope of ${secret:name} placeholders
+const SecretScope = "secret"
+
+// ErrNotFound is returned by a LookupFunc when the name is not defined
+var ErrNotFound = errors.New("not found")
+
+// ErrNoSecretProvider is reported for ${secret:name} placeholders until a
+// provider is registered for the secret scope
+var ErrNoSecretProvider = i18n.Error("interpolate.no_secret_provider")
+
+// LookupFunc resolves a name within a scope
+type LookupFunc func(name string) (string, error)
+
+type scope struct {
+	separator string
+	lookup    LookupFunc
+}
+
+var placeholderPattern = regexp.MustCompile(`\$?\$\{([A-Za-z][A-Za-z0-9_-]*)([:.])([^}]*)\}`)
+
+// Resolver expands placeholders and collects the ones it cannot resolve
+type Resolver struct {
+	scopes   map[string]scope
+	context  string
+	failures []string
+	seen     map[string]bool
+}
+
+// New returns a resolver for the env, file and profile scopes.
+// vars holds the profile variables referenced as ${profile.name}.
+// The secret scope fails with ErrNoSecretProvider until a provider is
+// registered, so secret references are never silently kept.
+func New(vars map[string]string) *Resolver {
+	r := &Resolver{
+		scopes: make(map[string]scope),
+		seen:   make(map[string]bool),
+	}
+	r.Register("env", ":", lookupEnv)
+	r.Register("file", ":", lookupFile)
+	r.Register("profile", ".", func(name string) (string, error) {
+		if value, ok := vars[name]; ok {
+			return value, nil
+		}
+		return "", ErrNotFound
+	})
+	r.Register(SecretScope, ":", func(string) (string, error) {
+		return "", ErrNoSecretProvider
+	})
+	return r
+}
+
+// Register adds or replaces a scope written as ${name<separator>key}
+func (r *Resolver) Register(name, separator string, lookup LookupFunc) {
+	r.scopes[name] = scope{separator: separator, lookup: lookup}
+}
+
+// SetContext sets a label (for example the server name) that is attached
+// to failures recorded from now on
+func (r *Resolver) SetContext(context string) {
+	r.context = context
+}
+
+// Expand replaces every resolvable placeholder in s.
+// Unresolvable placeholders are kept and recorded for Err.
+func (r *Resolver) Expand(s string) string {
+	if !strings.Contains(s, "${") {
+		return s
+	}
+
+	return placeholderPattern.ReplaceAllStringFunc(s, func(match string) string {
+		if strings.HasPrefix(match, "$$") {
+			return match[1:]
+		}
+
+		parts := placeholderPattern.FindStringSubmatch(match)
+		sc, ok := r.scopes[parts[1]]
+		if !ok || sc.separator != parts[2] {
+			return match
+		}
+
+		value, err := sc.lookup(parts[3])
+		if err != nil {
+			r.fail(match, err)
+			return match
+		}
+		return value
+	})
+}
+
+// HasPlaceholders reports whether s contains a placeholder of a registered scope
+func (r *Resolver) HasPlaceholders(s string) bool {
+	for _, parts := range placeholderPattern.FindAllStringSubmatch(s, -1) {
+		if strings.HasPrefix(parts[0], "$$") {
+			continue
+		}
+		if sc, ok := r.scopes[parts[1]]; ok && sc.separator == parts[2] {
+			return true
+		}
+	}
+	return false
+}
+
+// References returns the placeholders of the given scope found in s,
+// including ones that were written literally with $$
+func References(s, scopeName string) []string {
+	var refs []string
+	for _, parts := range placeholderPattern.FindAllStringSubmatch(s, -1) {
+		if parts[1] != scopeName {
+			continue
+		}
+		ref := parts[0]
+		if strings.HasPrefix(ref, "$$") {
+			ref = ref[1:]
+		}
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+// Err returns a *MissingError listing every placeholder that could not be
+// resolved, or nil
+func (r *Resolver) Err() error {
+	if len(r.failures) == 0 {
+		return nil
+	}
+	return &MissingError{Failures: append([]string(nil), r.failures...)}
+}
+
+func (r *Resolver) fail(placeholder string, err error) {
+	entry := placeholder
+	if !errors.Is(err, ErrNotFound) {
+		entry = fmt.Sprintf("%s: %v", placeholder, err)
+	}
+	if r.context != "" {
+		entry = i18n.T("interpolate.failure_context", entry, r.context)
+	}
+
+	if r.seen[entry] {
+		return
+	}
+	r.seen[entry] = true
+	r.failures = append(r.failures, entry)
+}
+
+// MissingError lists placeholders that could not be resolved
+type MissingError struct {
+	Failures []string
+}
+
+func (e *MissingError) Error() string {
+	var b strings.Builder
+	b.WriteString(i18n.T("interpolate.unresolved"))
+	for _, failure := range e.Failures {
+		b.WriteString("\n  - ")
+		b.WriteString(failure)
+	}
+	return b.String()
+}
+
+func lookupEnv(name string) (string, error) {
+	if value, ok := os.LookupEnv(name); ok {
+		return value, nil
+	}
+	return "", ErrNotFound
+}
+
+func lookupFile(path string) (string, error) {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(home, path[1:])
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
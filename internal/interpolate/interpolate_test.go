@@ -0,0 +1,110 @@
+package interpolate
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolver_Expand(t *testing.T) {
+	tempDir := t.TempDir()
+	tokenFile := filepath.Join(tempDir, "token")
+	if err := os.WriteFile(tokenFile, []byte("file-secret\n"), 0600); err != nil {
+		t.Fatalf("failed to write token file: %v", err)
+	}
+	t.Setenv("MCPJSON_TEST_TOKEN", "env-secret")
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"プレースホルダーなし", "plain", "plain"},
+		{"環境変数", "Bearer ${env:MCPJSON_TEST_TOKEN}", "Bearer env-secret"},
+		{"ファイル", "${file:" + tokenFile + "}", "file-secret"},
+		{"プロファイル変数", "--root=${profile.root}", "--root=/work"},
+		{"複数", "${profile.root}:${env:MCPJSON_TEST_TOKEN}", "/work:env-secret"},
+		{"エスケープ", "$${env:MCPJSON_TEST_TOKEN}", "${env:MCPJSON_TEST_TOKEN}"},
+		{"未登録のスコープは残す", "${workspaceFolder}/${HOME}", "${workspaceFolder}/${HOME}"},
+		{"区切り文字が違う場合は残す", "${profile:root}", "${profile:root}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(map[string]string{"root": "/work"})
+			if got := r.Expand(tt.input); got != tt.want {
+				t.Errorf("Expand(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+			if err := r.Err(); err != nil {
+				t.Errorf("Err() = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestResolver_MissingVariables(t *testing.T) {
+	os.Unsetenv("MCPJSON_TEST_MISSING")
+
+	r := New(nil)
+	r.SetContext("サーバー 'github'")
+	r.Expand("${env:MCPJSON_TEST_MISSING}")
+	r.Expand("${env:MCPJSON_TEST_MISSING}")
+	r.SetContext("サーバー 'fs'")
+	got := r.Expand("${profile.root}/${file:/nonexistent/mcpjson-token}")
+
+	if got != "${profile.root}/${file:/nonexistent/mcpjson-token}" {
+		t.Errorf("unresolved placeholders should be kept, got %q", got)
+	}
+
+	var missing *MissingError
+	if err := r.Err(); !errors.As(err, &missing) {
+		t.Fatalf("Err() = %v, want *MissingError", err)
+	}
+	if len(missing.Failures) != 3 {
+		t.Fatalf("Failures = %v, want 3 entries", missing.Failures)
+	}
+	for _, want := range []string{"${env:MCPJSON_TEST_MISSING}（サーバー 'github'）", "${profile.root}（サーバー 'fs'）", "${file:/nonexistent/mcpjson-token}"} {
+		if !strings.Contains(missing.Error(), want) {
+			t.Errorf("error should mention %q:\n%s", want, missing.Error())
+		}
+	}
+}
+
+func TestResolver_Register(t *testing.T) {
+	r := New(nil)
+	r.Register("secret", ":", func(name string) (string, error) {
+		if name == "token" {
+			return "s3cr3t", nil
+		}
+		return "", ErrNotFound
+	})
+
+	if got := r.Expand("${secret:token}"); got != "s3cr3t" {
+		t.Errorf("Expand() = %q, want s3cr3t", got)
+	}
+	if !r.HasPlaceholders("x ${secret:other}") || r.HasPlaceholders("$${secret:other} ${HOME}") {
+		t.Error("HasPlaceholders() returned unexpected result")
+	}
+}
+
+func TestResolver_SecretWithoutProvider(t *testing.T) {
+	r := New(nil)
+
+	if got := r.Expand("${secret:token}"); got != "${secret:token}" {
+		t.Errorf("Expand() = %q, want the placeholder kept", got)
+	}
+	err := r.Err()
+	if err == nil || !strings.Contains(err.Error(), ErrNoSecretProvider.Error()) {
+		t.Errorf("Err() = %v, want %v", err, ErrNoSecretProvider)
+	}
+}
+
+func TestReferences(t *testing.T) {
+	got := References("${secret:a} $${secret:b} ${env:C} ${secret.d}", SecretScope)
+	want := []string{"${secret:a}", "${secret:b}", "${secret.d}"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("References() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,620 @@
+// Package jsonedit provides read/modify/write access to JSONC documents.
+//
+// Edits are applied as text splices on the original source, so comments,
+// key order, trailing commas and indentation outside the edited values
+// survive a round trip.
+package jsonedit
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/tidwall/jsonc"
+)
+
+const defaultIndent = "  "
+
+// Document is an editable JSONC document
+type Document struct {
+	src  []byte
+	root *node
+}
+
+// Parse parses JSONC source into a document.
+// Empty input (or input with only comments) yields an empty document.
+func Parse(src []byte) (*Document, error) {
+	root, err := parse(src)
+	if err != nil {
+		return nil, err
+	}
+	return &Document{src: append([]byte(nil), src...), root: root}, nil
+}
+
+// New returns an empty document
+func New() *Document {
+	return &Document{}
+}
+
+// Bytes returns the current source of the document
+func (d *Document) Bytes() []byte {
+	return append([]byte(nil), d.src...)
+}
+
+// IsEmpty reports whether the document has no value
+func (d *Document) IsEmpty() bool {
+	return d.root == nil
+}
+
+// Get returns the value at path as plain JSON with comments removed
+func (d *Document) Get(path ...interface{}) (json.RawMessage, bool) {
+	n := d.find(path)
+	if n == nil {
+		return nil, false
+	}
+	return d.plainJSON(n), true
+}
+
+// Keys returns the member keys of the object at path in source order
+func (d *Document) Keys(path ...interface{}) []string {
+	n := d.find(path)
+	if n == nil || n.kind != kindObject {
+		return nil
+	}
+
+	keys := make([]string, 0, len(n.members))
+	for _, m := range n.members {
+		keys = append(keys, m.key)
+	}
+	return keys
+}
+
+// Set replaces the value at path, creating missing parent objects.
+// Path elements are object keys (string) or array indices (int).
+func (d *Document) Set(value interface{}, path ...interface{}) error {
+	raw, err := marshal(value)
+	if err != nil {
+		return err
+	}
+	return d.setRaw(path, raw)
+}
+
+// Patch updates the value at path to match value with the smallest edits
+// it can: unchanged members keep their text and comments, changed
+// members are rewritten in place and new members are appended.
+func (d *Document) Patch(value interface{}, path ...interface{}) error {
+	raw, err := marshal(value)
+	if err != nil {
+		return err
+	}
+
+	target, err := Parse(raw)
+	if err != nil {
+		return err
+	}
+	if target.root == nil {
+		return i18n.Errorf("jsonedit.empty_value")
+	}
+
+	return d.patchNode(path, target, target.root)
+}
+
+// Delete removes the member or element at path and reports whether it existed
+func (d *Document) Delete(path ...interface{}) (bool, error) {
+	if len(path) == 0 {
+		return false, i18n.Errorf("jsonedit.delete_root")
+	}
+
+	parent := d.find(path[:len(path)-1])
+	if parent == nil {
+		return false, nil
+	}
+
+	switch last := path[len(path)-1].(type) {
+	case string:
+		if parent.kind != kindObject {
+			return false, nil
+		}
+		for i, m := range parent.members {
+			if m.key == last {
+				if err := d.deleteMember(parent, i); err != nil {
+					return false, err
+				}
+				return true, d.collapseEmptyObject(path[:len(path)-1])
+			}
+		}
+	case int:
+		if parent.kind != kindArray || last < 0 || last >= len(parent.elements) {
+			return false, nil
+		}
+		return true, d.deleteElement(parent, last)
+	}
+	return false, nil
+}
+
+func (d *Document) find(path []interface{}) *node {
+	n := d.root
+	for _, elem := range path {
+		if n == nil {
+			return nil
+		}
+
+		switch key := elem.(type) {
+		case string:
+			if n.kind != kindObject {
+				return nil
+			}
+			var next *node
+			for _, m := range n.members {
+				if m.key == key {
+					next = m.value
+				}
+			}
+			n = next
+		case int:
+			if n.kind != kindArray || key < 0 || key >= len(n.elements) {
+				return nil
+			}
+			n = n.elements[key].value
+		default:
+			return nil
+		}
+	}
+	return n
+}
+
+func (d *Document) setRaw(path []interface{}, raw []byte) error {
+	if d.root == nil {
+		if len(path) == 0 {
+			return d.replace(len(d.src), len(d.src), format(raw, "", defaultIndent)+"\n")
+		}
+		if err := d.replace(len(d.src), len(d.src), "{}\n"); err != nil {
+			return err
+		}
+	}
+
+	if len(path) == 0 {
+		return d.replace(d.root.start, d.root.end, format(raw, d.indentAt(d.root.start), d.indentUnit()))
+	}
+
+	parentPath := path[:len(path)-1]
+	parent := d.find(parentPath)
+	last := path[len(path)-1]
+
+	if parent == nil {
+		key, ok := last.(string)
+		if !ok {
+			return i18n.Errorf("jsonedit.missing_array", path)
+		}
+		wrapped, err := marshal(map[string]json.RawMessage{key: raw})
+		if err != nil {
+			return err
+		}
+		return d.setRaw(parentPath, wrapped)
+	}
+
+	switch key := last.(type) {
+	case string:
+		if parent.kind != kindObject {
+			return i18n.Errorf("jsonedit.not_object", key)
+		}
+		for _, m := range parent.members {
+			if m.key == key {
+				return d.replace(m.value.start, m.value.end, d.renderReplacement(parent, m.value, raw, d.indentAt(m.start)))
+			}
+		}
+		return d.insertMember(parent, key, raw)
+	case int:
+		if parent.kind != kindArray || key < 0 || key >= len(parent.elements) {
+			return i18n.Errorf("jsonedit.out_of_range", path)
+		}
+		e := parent.elements[key]
+		return d.replace(e.value.start, e.value.end, d.renderReplacement(parent, e.value, raw, d.indentAt(e.value.start)))
+	default:
+		return i18n.Errorf("jsonedit.invalid_path", last)
+	}
+}
+
+func (d *Document) patchNode(path []interface{}, target *Document, want *node) error {
+	have := d.find(path)
+	if have == nil {
+		return d.setRaw(path, target.src[want.start:want.end])
+	}
+
+	if bytes.Equal(d.plainJSON(have), target.plainJSON(want)) {
+		return nil
+	}
+
+	switch {
+	case have.kind == kindObject && want.kind == kindObject:
+		wantKeys := make(map[string]bool, len(want.members))
+		for _, m := range want.members {
+			wantKeys[m.key] = true
+		}
+		for _, key := range d.Keys(path...) {
+			if !wantKeys[key] {
+				if _, err := d.Delete(append(append([]interface{}(nil), path...), key)...); err != nil {
+					return err
+				}
+			}
+		}
+		for _, m := range want.members {
+			if err := d.patchNode(append(append([]interface{}(nil), path...), m.key), target, m.value); err != nil {
+				return err
+			}
+		}
+		return nil
+	case have.kind == kindArray && want.kind == kindArray && len(have.elements) > 0:
+		return d.patchArray(path, have, target, want)
+	default:
+		return d.setRaw(path, target.src[want.start:want.end])
+	}
+}
+
+// patchArray matches the elements that are equal in both arrays and
+// edits the runs between them: elements are patched in place pairwise,
+// and the surplus is deleted or inserted. Unchanged elements keep their
+// text and comments.
+func (d *Document) patchArray(path []interface{}, have *node, target *Document, want *node) error {
+	haveJSON := make([]string, len(have.elements))
+	for i, e := range have.elements {
+		haveJSON[i] = string(d.plainJSON(e.value))
+	}
+	wantJSON := make([]string, len(want.elements))
+	for i, e := range want.elements {
+		wantJSON[i] = string(target.plainJSON(e.value))
+	}
+
+	// Runs are edited from the end so the indices of earlier elements stay valid
+	anchors := append(commonElements(haveJSON, wantJSON), [2]int{len(haveJSON), len(wantJSON)})
+	for i := len(anchors) - 1; i >= 0; i-- {
+		haveStart, wantStart := 0, 0
+		if i > 0 {
+			haveStart, wantStart = anchors[i-1][0]+1, anchors[i-1][1]+1
+		}
+		haveEnd, wantEnd := anchors[i][0], anchors[i][1]
+
+		paired := min(haveEnd-haveStart, wantEnd-wantStart)
+		for j := haveEnd - 1; j >= haveStart+paired; j-- {
+			if err := d.deleteElement(d.find(path), j); err != nil {
+				return err
+			}
+		}
+		for j := wantStart + paired; j < wantEnd; j++ {
+			e := want.elements[j].value
+			if err := d.insertElement(d.find(path), haveStart+j-wantStart, target.src[e.start:e.end]); err != nil {
+				return err
+			}
+		}
+		for j := 0; j < paired; j++ {
+			elemPath := append(append([]interface{}(nil), path...), haveStart+j)
+			if err := d.patchNode(elemPath, target, want.elements[wantStart+j].value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// commonElements returns the index pairs of a longest common subsequence
+// of a and b
+func commonElements(a, b []string) [][2]int {
+	// lengths[i][j] is the length of the LCS of a[i:] and b[j:]
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+
+	var pairs [][2]int
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			pairs = append(pairs, [2]int{i, j})
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return pairs
+}
+
+// insertElement inserts raw into a non-empty array before the element at
+// index, or after the last element when index is the array's length
+func (d *Document) insertElement(parent *node, index int, raw []byte) error {
+	if index < len(parent.elements) {
+		next := parent.elements[index].value
+		if !d.startsLine(next.start) {
+			return d.replace(next.start, next.start, compact(raw)+", ")
+		}
+		indent := d.indentAt(next.start)
+		pos := d.leadingCommentStart(d.lineStart(next.start))
+		return d.replace(pos, pos, indent+format(raw, indent, d.indentUnit())+",\n")
+	}
+
+	last := parent.elements[len(parent.elements)-1]
+	if !d.startsLine(last.value.start) {
+		return d.appendEntry(last.value.end, last.commaPos, false, "", compact(raw))
+	}
+	indent := d.indentAt(last.value.start)
+	return d.appendEntry(last.value.end, last.commaPos, true, indent, format(raw, indent, d.indentUnit()))
+}
+
+func (d *Document) insertMember(parent *node, key string, raw []byte) error {
+	encodedKey, err := marshal(key)
+	if err != nil {
+		return err
+	}
+
+	if len(parent.members) == 0 {
+		if bytes.IndexByte(bytes.TrimSpace(d.src), '\n') < 0 && d.root != parent {
+			return d.replace(parent.start, parent.end, "{ "+string(encodedKey)+": "+compact(raw)+" }")
+		}
+		base := d.indentAt(parent.start)
+		inner := base + d.indentUnit()
+		text := string(encodedKey) + ": " + format(raw, inner, d.indentUnit())
+		return d.replace(parent.start, parent.end, "{\n"+inner+text+"\n"+base+"}")
+	}
+
+	last := parent.members[len(parent.members)-1]
+	if !d.isMultiline(parent) {
+		return d.appendEntry(last.value.end, last.commaPos, false, "", string(encodedKey)+": "+compact(raw))
+	}
+
+	indent := d.indentAt(parent.members[0].start)
+	return d.appendEntry(last.value.end, last.commaPos, true, indent, string(encodedKey)+": "+format(raw, indent, d.indentUnit()))
+}
+
+// appendEntry adds text after the last entry of a container, whose value
+// ends at end and is followed by the comma at commaPos, or -1. With
+// newLine the text goes on a new line after indent; otherwise it stays on
+// the same line.
+func (d *Document) appendEntry(end, commaPos int, newLine bool, indent, text string) error {
+	if !newLine {
+		if commaPos >= 0 {
+			return d.replace(commaPos+1, commaPos+1, " "+text+",")
+		}
+		return d.replace(end, end, ", "+text)
+	}
+
+	if commaPos >= 0 {
+		pos := d.lineEnd(commaPos + 1)
+		return d.replace(pos, pos, "\n"+indent+text+",")
+	}
+
+	// The comma goes in first; the line break then moves one byte further
+	pos := d.lineEnd(end) + 1
+	src := make([]byte, 0, len(d.src)+len(text)+len(indent)+2)
+	src = append(src, d.src[:end]...)
+	src = append(src, ',')
+	src = append(src, d.src[end:pos-1]...)
+	src = append(src, "\n"+indent+text...)
+	src = append(src, d.src[pos-1:]...)
+	return d.reset(src)
+}
+
+func (d *Document) deleteMember(parent *node, index int) error {
+	m := parent.members[index]
+	var prevComma int = -1
+	if index > 0 {
+		prevComma = parent.members[index-1].commaPos
+	}
+	return d.deleteEntry(m.start, m.value.end, m.commaPos, prevComma)
+}
+
+func (d *Document) deleteElement(parent *node, index int) error {
+	e := parent.elements[index]
+	var prevComma int = -1
+	if index > 0 {
+		prevComma = parent.elements[index-1].commaPos
+	}
+	return d.deleteEntry(e.value.start, e.value.end, e.commaPos, prevComma)
+}
+
+// deleteEntry removes the text of an object member or array element
+// spanning [start, end), together with its separating comma
+func (d *Document) deleteEntry(start, end, commaPos, prevComma int) error {
+	if commaPos >= 0 {
+		end = commaPos + 1
+	}
+
+	if d.startsLine(start) {
+		start = d.leadingCommentStart(d.lineStart(start))
+		end = d.lineEnd(end)
+		if end < len(d.src) && d.src[end] == '\n' {
+			end++
+		}
+		if commaPos < 0 && prevComma >= 0 {
+			if err := d.replace(start, end, ""); err != nil {
+				return err
+			}
+			return d.replace(prevComma, prevComma+1, "")
+		}
+		return d.replace(start, end, "")
+	}
+
+	if commaPos >= 0 {
+		for end < len(d.src) && (d.src[end] == ' ' || d.src[end] == '\t') {
+			end++
+		}
+	} else if prevComma >= 0 {
+		start = prevComma
+	}
+	return d.replace(start, end, "")
+}
+
+// collapseEmptyObject rewrites an object left with only whitespace as {}
+func (d *Document) collapseEmptyObject(path []interface{}) error {
+	n := d.find(path)
+	if n == nil || n.kind != kindObject || len(n.members) > 0 {
+		return nil
+	}
+	if len(bytes.TrimSpace(d.src[n.start+1:n.end-1])) > 0 {
+		return nil
+	}
+	return d.replace(n.start, n.end, "{}")
+}
+
+func (d *Document) replace(start, end int, text string) error {
+	src := make([]byte, 0, len(d.src)-(end-start)+len(text))
+	src = append(src, d.src[:start]...)
+	src = append(src, text...)
+	src = append(src, d.src[end:]...)
+	return d.reset(src)
+}
+
+// reset swaps in edited source after checking that it still parses
+func (d *Document) reset(src []byte) error {
+	root, err := parse(src)
+	if err != nil {
+		return i18n.Errorf("jsonedit.invalid_result", err)
+	}
+	d.src = src
+	d.root = root
+	return nil
+}
+
+func (d *Document) plainJSON(n *node) []byte {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, jsonc.ToJSON(d.src[n.start:n.end])); err != nil {
+		return d.src[n.start:n.end]
+	}
+	return buf.Bytes()
+}
+
+// render formats raw for placement inside container, keeping single-line
+// containers on a single line
+func (d *Document) render(container *node, raw []byte, prefix string) string {
+	if !d.isMultiline(container) {
+		return compact(raw)
+	}
+	return format(raw, prefix, d.indentUnit())
+}
+
+// renderReplacement formats raw to replace old. An object or array that
+// was written on a single line stays on a single line.
+func (d *Document) renderReplacement(container, old *node, raw []byte, prefix string) string {
+	if (old.kind == kindObject || old.kind == kindArray) && !d.isMultiline(old) {
+		return compact(raw)
+	}
+	return d.render(container, raw, prefix)
+}
+
+func (d *Document) isMultiline(n *node) bool {
+	return bytes.IndexByte(d.src[n.start:n.end], '\n') >= 0
+}
+
+func (d *Document) lineStart(pos int) int {
+	for pos > 0 && d.src[pos-1] != '\n' {
+		pos--
+	}
+	return pos
+}
+
+// lineEnd skips spaces and a trailing comment on the same line and returns
+// the position of the line break. When other content follows on the same
+// line, pos is returned unchanged.
+func (d *Document) lineEnd(pos int) int {
+	i := pos
+	for i < len(d.src) {
+		switch c := d.src[i]; {
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case c == '/' && i+1 < len(d.src) && d.src[i+1] == '/':
+			for i < len(d.src) && d.src[i] != '\n' {
+				i++
+			}
+			return i
+		case c == '/' && i+1 < len(d.src) && d.src[i+1] == '*':
+			end := indexFrom(d.src, i+2, "*/")
+			if end < 0 || bytes.IndexByte(d.src[i:end], '\n') >= 0 {
+				return pos
+			}
+			i = end + 2
+		case c == '\n':
+			return i
+		default:
+			return pos
+		}
+	}
+	return i
+}
+
+func (d *Document) startsLine(pos int) bool {
+	return len(bytes.TrimSpace(d.src[d.lineStart(pos):pos])) == 0
+}
+
+// leadingCommentStart extends a line start upwards over comment-only
+// lines that directly precede it
+func (d *Document) leadingCommentStart(start int) int {
+	for start > 0 {
+		prev := d.lineStart(start - 1)
+		line := strings.TrimSpace(string(d.src[prev : start-1]))
+		if !strings.HasPrefix(line, "//") && !(strings.HasPrefix(line, "/*") && strings.HasSuffix(line, "*/")) {
+			break
+		}
+		start = prev
+	}
+	return start
+}
+
+func (d *Document) indentAt(pos int) string {
+	start := d.lineStart(pos)
+	end := start
+	for end < len(d.src) && (d.src[end] == ' ' || d.src[end] == '\t') {
+		end++
+	}
+	return string(d.src[start:end])
+}
+
+// indentUnit guesses the indentation step used by the document
+func (d *Document) indentUnit() string {
+	if d.root != nil && d.root.kind == kindObject && len(d.root.members) > 0 {
+		if unit := d.indentAt(d.root.members[0].start); unit != "" && d.startsLine(d.root.members[0].start) {
+			return unit
+		}
+	}
+	return defaultIndent
+}
+
+func marshal(value interface{}) ([]byte, error) {
+	if raw, ok := value.(json.RawMessage); ok {
+		return raw, nil
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(value); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+func compact(raw []byte) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, jsonc.ToJSON(raw)); err != nil {
+		return string(raw)
+	}
+	return buf.String()
+}
+
+func format(raw []byte, prefix, indent string) string {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(compact(raw)), prefix, indent); err != nil {
+		return string(raw)
+	}
+	return buf.String()
+}
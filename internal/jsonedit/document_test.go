@@ -0,0 +1,468 @@
+package jsonedit
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const profileSource = `{
+  // チーム共通のプロファイル
+  "name": "team",
+  "description": "shared", // 説明
+  "servers": [
+    {"name": "git", "template": "git"}, // Git
+  ],
+  "tags": ["a", "b"],
+}
+`
+
+func mustParse(t *testing.T, src string) *Document {
+	t.Helper()
+
+	doc, err := Parse([]byte(src))
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	return doc
+}
+
+func decode(t *testing.T, doc *Document) map[string]interface{} {
+	t.Helper()
+
+	raw, ok := doc.Get()
+	if !ok {
+		t.Fatal("Get() returned no value")
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		t.Fatalf("document is not valid JSON after edit: %v\n%s", err, doc.Bytes())
+	}
+	return result
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{"unterminated object", `{"a": 1`},
+		{"missing colon", `{"a" 1}`},
+		{"missing comma", `{"a": 1 "b": 2}`},
+		{"unterminated comment", `{"a": 1 /* }`},
+		{"bad literal", `{"a": nope}`},
+		{"trailing data", `{} {}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse([]byte(tt.src)); err == nil {
+				t.Errorf("Parse(%q) expected error", tt.src)
+			}
+		})
+	}
+}
+
+func TestParse_EmptyAndCommentOnly(t *testing.T) {
+	for _, src := range []string{"", "  \n", "// nothing here\n"} {
+		doc := mustParse(t, src)
+		if !doc.IsEmpty() {
+			t.Errorf("Parse(%q) should yield an empty document", src)
+		}
+	}
+}
+
+func TestDocument_GetAndKeys(t *testing.T) {
+	doc := mustParse(t, profileSource)
+
+	keys := doc.Keys()
+	want := []string{"name", "description", "servers", "tags"}
+	if strings.Join(keys, ",") != strings.Join(want, ",") {
+		t.Errorf("Keys() = %v, want %v", keys, want)
+	}
+
+	raw, ok := doc.Get("servers", 0, "template")
+	if !ok || string(raw) != `"git"` {
+		t.Errorf("Get(servers, 0, template) = %s, %v", raw, ok)
+	}
+
+	if _, ok := doc.Get("missing"); ok {
+		t.Error("Get(missing) should report false")
+	}
+}
+
+func TestDocument_SetKeepsComments(t *testing.T) {
+	doc := mustParse(t, profileSource)
+
+	if err := doc.Set("updated", "description"); err != nil {
+		t.Fatalf("Set() failed: %v", err)
+	}
+
+	out := string(doc.Bytes())
+	for _, comment := range []string{"// チーム共通のプロファイル", "// 説明", "// Git"} {
+		if !strings.Contains(out, comment) {
+			t.Errorf("comment %q lost:\n%s", comment, out)
+		}
+	}
+	if decode(t, doc)["description"] != "updated" {
+		t.Errorf("description not updated:\n%s", out)
+	}
+}
+
+func TestDocument_SetInsertsNewMember(t *testing.T) {
+	doc := mustParse(t, `{
+  "name": "team", // 名前
+  "servers": []
+}
+`)
+
+	if err := doc.Set(map[string]string{"env": "prod"}, "vars"); err != nil {
+		t.Fatalf("Set() failed: %v", err)
+	}
+
+	out := string(doc.Bytes())
+	want := `{
+  "name": "team", // 名前
+  "servers": [],
+  "vars": {
+    "env": "prod"
+  }
+}
+`
+	if out != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestDocument_SetAfterTrailingComma(t *testing.T) {
+	doc := mustParse(t, profileSource)
+
+	if err := doc.Set(true, "enabled"); err != nil {
+		t.Fatalf("Set() failed: %v", err)
+	}
+
+	out := string(doc.Bytes())
+	if !strings.Contains(out, `"tags": ["a", "b"],`+"\n"+`  "enabled": true,`) {
+		t.Errorf("member should be appended keeping the trailing comma style:\n%s", out)
+	}
+	decode(t, doc)
+}
+
+func TestDocument_SetAfterTrailingLineComment(t *testing.T) {
+	doc := mustParse(t, `{
+  "a": 1 // one
+}`)
+
+	if err := doc.Set(2, "b"); err != nil {
+		t.Fatalf("Set() failed: %v", err)
+	}
+
+	want := `{
+  "a": 1, // one
+  "b": 2
+}`
+	if out := string(doc.Bytes()); out != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestDocument_SetCreatesParents(t *testing.T) {
+	doc := New()
+
+	if err := doc.Set(map[string]string{"command": "uvx"}, "mcpServers", "git"); err != nil {
+		t.Fatalf("Set() failed: %v", err)
+	}
+
+	result := decode(t, doc)
+	servers := result["mcpServers"].(map[string]interface{})
+	if servers["git"].(map[string]interface{})["command"] != "uvx" {
+		t.Errorf("nested value not created:\n%s", doc.Bytes())
+	}
+}
+
+func TestDocument_SetSingleLineObject(t *testing.T) {
+	doc := mustParse(t, `{"a": 1}`)
+
+	if err := doc.Set("x", "b"); err != nil {
+		t.Fatalf("Set() failed: %v", err)
+	}
+
+	if out := string(doc.Bytes()); out != `{"a": 1, "b": "x"}` {
+		t.Errorf("unexpected output: %s", out)
+	}
+}
+
+func TestDocument_DeleteMember(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		path []interface{}
+		want string
+	}{
+		{
+			name: "middle member with leading comment",
+			src: `{
+  "a": 1,
+  // about b
+  "b": 2, // trailing
+  "c": 3
+}`,
+			path: []interface{}{"b"},
+			want: `{
+  "a": 1,
+  "c": 3
+}`,
+		},
+		{
+			name: "last member removes previous comma",
+			src: `{
+  "a": 1, // keep
+  "b": 2
+}`,
+			path: []interface{}{"b"},
+			want: `{
+  "a": 1 // keep
+}`,
+		},
+		{
+			name: "only member collapses object",
+			src: `{
+  "servers": {
+    "git": {"command": "uvx"}
+  }
+}`,
+			path: []interface{}{"servers", "git"},
+			want: `{
+  "servers": {}
+}`,
+		},
+		{
+			name: "inline member",
+			src:  `{"a": 1, "b": 2, "c": 3}`,
+			path: []interface{}{"b"},
+			want: `{"a": 1, "c": 3}`,
+		},
+		{
+			name: "inline last member",
+			src:  `{"a": 1, "b": 2}`,
+			path: []interface{}{"b"},
+			want: `{"a": 1}`,
+		},
+		{
+			name: "array element",
+			src:  `{"a": [1, 2, 3]}`,
+			path: []interface{}{"a", 2},
+			want: `{"a": [1, 2]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := mustParse(t, tt.src)
+
+			deleted, err := doc.Delete(tt.path...)
+			if err != nil {
+				t.Fatalf("Delete() failed: %v", err)
+			}
+			if !deleted {
+				t.Fatal("Delete() should report the member existed")
+			}
+			if out := string(doc.Bytes()); out != tt.want {
+				t.Errorf("unexpected output:\n%s\nwant:\n%s", out, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocument_DeleteMissing(t *testing.T) {
+	doc := mustParse(t, `{"a": 1}`)
+
+	deleted, err := doc.Delete("missing")
+	if err != nil || deleted {
+		t.Errorf("Delete(missing) = %v, %v; want false, nil", deleted, err)
+	}
+}
+
+func TestDocument_PatchMinimalEdits(t *testing.T) {
+	doc := mustParse(t, profileSource)
+
+	value := map[string]interface{}{
+		"name":        "team",
+		"description": "shared",
+		"servers": []map[string]string{
+			{"name": "git", "template": "git-v2"},
+		},
+		"updatedAt": "2025-01-01T00:00:00Z",
+	}
+
+	if err := doc.Patch(value); err != nil {
+		t.Fatalf("Patch() failed: %v", err)
+	}
+
+	out := string(doc.Bytes())
+	for _, comment := range []string{"// チーム共通のプロファイル", "// 説明", "// Git"} {
+		if !strings.Contains(out, comment) {
+			t.Errorf("comment %q lost:\n%s", comment, out)
+		}
+	}
+
+	result := decode(t, doc)
+	if _, exists := result["tags"]; exists {
+		t.Errorf("removed member still present:\n%s", out)
+	}
+	if result["updatedAt"] != "2025-01-01T00:00:00Z" {
+		t.Errorf("new member missing:\n%s", out)
+	}
+	servers := result["servers"].([]interface{})
+	if servers[0].(map[string]interface{})["template"] != "git-v2" {
+		t.Errorf("nested value not patched:\n%s", out)
+	}
+	if strings.Index(out, `"name"`) > strings.Index(out, `"description"`) {
+		t.Errorf("key order changed:\n%s", out)
+	}
+}
+
+func TestDocument_PatchUnchangedIsNoop(t *testing.T) {
+	doc := mustParse(t, profileSource)
+
+	var value map[string]interface{}
+	raw, _ := doc.Get()
+	if err := json.Unmarshal(raw, &value); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if err := doc.Patch(value); err != nil {
+		t.Fatalf("Patch() failed: %v", err)
+	}
+	if string(doc.Bytes()) != profileSource {
+		t.Errorf("unchanged patch should not modify the source:\n%s", doc.Bytes())
+	}
+}
+
+func TestDocument_PatchReplacesTypeChange(t *testing.T) {
+	doc := mustParse(t, `{"a": {"b": 1}}`)
+
+	if err := doc.Patch(map[string]interface{}{"a": []int{1, 2}}); err != nil {
+		t.Fatalf("Patch() failed: %v", err)
+	}
+
+	if out := string(doc.Bytes()); out != `{"a": [1,2]}` {
+		t.Errorf("unexpected output: %s", out)
+	}
+}
+
+func TestDocument_PatchEmptyDocument(t *testing.T) {
+	doc := New()
+
+	if err := doc.Patch(map[string]string{"name": "x"}); err != nil {
+		t.Fatalf("Patch() failed: %v", err)
+	}
+	if out := string(doc.Bytes()); out != "{\n  \"name\": \"x\"\n}\n" {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
+
+func TestDocument_DoesNotEscapeHTML(t *testing.T) {
+	doc := mustParse(t, `{"url": ""}`)
+
+	if err := doc.Set("https://example.com/?a=1&b=<2>", "url"); err != nil {
+		t.Fatalf("Set() failed: %v", err)
+	}
+	if !strings.Contains(string(doc.Bytes()), "a=1&b=<2>") {
+		t.Errorf("value should be written verbatim: %s", doc.Bytes())
+	}
+}
+
+func TestDocument_PatchArrayKeepsComments(t *testing.T) {
+	const src = `{
+  "args": [
+    "-y", // npx
+    // パッケージ
+    "@scope/server",
+    "--verbose" // 詳細
+  ],
+  "tags": ["a", /* b */ "b"]
+}
+`
+
+	tests := []struct {
+		name string
+		args []string
+		tags []string
+		want string
+	}{
+		{
+			name: "末尾に追加",
+			args: []string{"-y", "@scope/server", "--verbose", "--port", "80"},
+			tags: []string{"a", "b", "c"},
+			want: `{
+  "args": [
+    "-y", // npx
+    // パッケージ
+    "@scope/server",
+    "--verbose", // 詳細
+    "--port",
+    "80"
+  ],
+  "tags": ["a", /* b */ "b", "c"]
+}
+`,
+		},
+		{
+			name: "先頭に追加",
+			args: []string{"--quiet", "-y", "@scope/server", "--verbose"},
+			tags: []string{"z", "a", "b"},
+			want: `{
+  "args": [
+    "--quiet",
+    "-y", // npx
+    // パッケージ
+    "@scope/server",
+    "--verbose" // 詳細
+  ],
+  "tags": ["z", "a", /* b */ "b"]
+}
+`,
+		},
+		{
+			name: "途中を削除",
+			args: []string{"-y", "--verbose"},
+			tags: []string{"b"},
+			want: `{
+  "args": [
+    "-y", // npx
+    "--verbose" // 詳細
+  ],
+  "tags": [/* b */ "b"]
+}
+`,
+		},
+		{
+			name: "削除と変更",
+			args: []string{"@scope/server", "--debug"},
+			tags: []string{"a", "b"},
+			want: `{
+  "args": [
+    // パッケージ
+    "@scope/server",
+    "--debug" // 詳細
+  ],
+  "tags": ["a", /* b */ "b"]
+}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := mustParse(t, src)
+			value := map[string]interface{}{"args": tt.args, "tags": tt.tags}
+			if err := doc.Patch(value); err != nil {
+				t.Fatalf("Patch() failed: %v", err)
+			}
+			if got := string(doc.Bytes()); got != tt.want {
+				t.Errorf("Patch() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package jsonedit
+
+import (
+	"encoding/json"
+
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+)
+
+type kind int
+
+const (
+	kindObject kind = iota
+	kindArray
+	kindString
+	kindNumber
+	kindBool
+	kindNull
+)
+
+// node is a parsed JSONC value together with its byte span in the source
+type node struct {
+	kind     kind
+	start    int
+	end      int
+	members  []*member
+	elements []*element
+}
+
+// member is an object member. commaPos is the offset of the comma that
+// follows the value, or -1 when the member is not followed by one.
+type member struct {
+	key      string
+	start    int
+	value    *node
+	commaPos int
+}
+
+type element struct {
+	value    *node
+	commaPos int
+}
+
+type parser struct {
+	src []byte
+	pos int
+}
+
+// parse parses JSONC source. It returns a nil node for input that
+// contains only whitespace and comments.
+func parse(src []byte) (*node, error) {
+	p := &parser{src: src}
+	if err := p.skipTrivia(); err != nil {
+		return nil, err
+	}
+	if p.pos >= len(p.src) {
+		return nil, nil
+	}
+
+	root, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.skipTrivia(); err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.src) {
+		return nil, p.errorf("jsonedit.trailing_data")
+	}
+	return root, nil
+}
+
+func (p *parser) errorf(id string, args ...interface{}) error {
+	return i18n.Errorf("jsonedit.parse_failed", p.pos, i18n.T(id, args...))
+}
+
+func (p *parser) skipTrivia() error {
+	for p.pos < len(p.src) {
+		switch c := p.src[p.pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			p.pos++
+		case c == '/' && p.peek(1) == '/':
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+		case c == '/' && p.peek(1) == '*':
+			end := indexFrom(p.src, p.pos+2, "*/")
+			if end < 0 {
+				return p.errorf("jsonedit.unclosed_comment")
+			}
+			p.pos = end + 2
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+func (p *parser) peek(offset int) byte {
+	if p.pos+offset < len(p.src) {
+		return p.src[p.pos+offset]
+	}
+	return 0
+}
+
+func (p *parser) parseValue() (*node, error) {
+	if p.pos >= len(p.src) {
+		return nil, p.errorf("jsonedit.missing_value")
+	}
+
+	switch c := p.src[p.pos]; {
+	case c == '{':
+		return p.parseObject()
+	case c == '[':
+		return p.parseArray()
+	case c == '"':
+		start := p.pos
+		if _, err := p.parseString(); err != nil {
+			return nil, err
+		}
+		return &node{kind: kindString, start: start, end: p.pos}, nil
+	case c == '-' || (c >= '0' && c <= '9'):
+		return p.parseNumber()
+	default:
+		return p.parseLiteral()
+	}
+}
+
+func (p *parser) parseObject() (*node, error) {
+	n := &node{kind: kindObject, start: p.pos}
+	p.pos++
+
+	for {
+		if err := p.skipTrivia(); err != nil {
+			return nil, err
+		}
+		if p.pos >= len(p.src) {
+			return nil, p.errorf("jsonedit.unclosed_object")
+		}
+		if p.src[p.pos] == '}' {
+			p.pos++
+			n.end = p.pos
+			return n, nil
+		}
+		if len(n.members) > 0 && n.members[len(n.members)-1].commaPos < 0 {
+			return nil, p.errorf("jsonedit.expect_comma_brace")
+		}
+		if p.src[p.pos] != '"' {
+			return nil, p.errorf("jsonedit.expect_key")
+		}
+
+		m := &member{start: p.pos, commaPos: -1}
+		key, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		m.key = key
+
+		if err := p.skipTrivia(); err != nil {
+			return nil, err
+		}
+		if p.pos >= len(p.src) || p.src[p.pos] != ':' {
+			return nil, p.errorf("jsonedit.expect_colon")
+		}
+		p.pos++
+		if err := p.skipTrivia(); err != nil {
+			return nil, err
+		}
+
+		if m.value, err = p.parseValue(); err != nil {
+			return nil, err
+		}
+
+		if err := p.skipTrivia(); err != nil {
+			return nil, err
+		}
+		if p.pos < len(p.src) && p.src[p.pos] == ',' {
+			m.commaPos = p.pos
+			p.pos++
+		}
+		n.members = append(n.members, m)
+	}
+}
+
+func (p *parser) parseArray() (*node, error) {
+	n := &node{kind: kindArray, start: p.pos}
+	p.pos++
+
+	for {
+		if err := p.skipTrivia(); err != nil {
+			return nil, err
+		}
+		if p.pos >= len(p.src) {
+			return nil, p.errorf("jsonedit.unclosed_array")
+		}
+		if p.src[p.pos] == ']' {
+			p.pos++
+			n.end = p.pos
+			return n, nil
+		}
+		if len(n.elements) > 0 && n.elements[len(n.elements)-1].commaPos < 0 {
+			return nil, p.errorf("jsonedit.expect_comma_bracket")
+		}
+
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		e := &element{value: value, commaPos: -1}
+
+		if err := p.skipTrivia(); err != nil {
+			return nil, err
+		}
+		if p.pos < len(p.src) && p.src[p.pos] == ',' {
+			e.commaPos = p.pos
+			p.pos++
+		}
+		n.elements = append(n.elements, e)
+	}
+}
+
+func (p *parser) parseString() (string, error) {
+	start := p.pos
+	p.pos++
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case '\\':
+			p.pos += 2
+		case '"':
+			p.pos++
+			var s string
+			if err := json.Unmarshal(p.src[start:p.pos], &s); err != nil {
+				return "", p.errorf("jsonedit.invalid_string", err)
+			}
+			return s, nil
+		case '\n':
+			return "", p.errorf("jsonedit.unclosed_string")
+		default:
+			p.pos++
+		}
+	}
+	return "", p.errorf("jsonedit.unclosed_string")
+}
+
+func (p *parser) parseNumber() (*node, error) {
+	start := p.pos
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if (c >= '0' && c <= '9') || c == '-' || c == '+' || c == '.' || c == 'e' || c == 'E' {
+			p.pos++
+			continue
+		}
+		break
+	}
+
+	if !json.Valid(p.src[start:p.pos]) {
+		return nil, p.errorf("jsonedit.invalid_number", p.src[start:p.pos])
+	}
+	return &node{kind: kindNumber, start: start, end: p.pos}, nil
+}
+
+func (p *parser) parseLiteral() (*node, error) {
+	literals := []struct {
+		text string
+		kind kind
+	}{
+		{"true", kindBool},
+		{"false", kindBool},
+		{"null", kindNull},
+	}
+
+	for _, lit := range literals {
+		if hasPrefixAt(p.src, p.pos, lit.text) {
+			start := p.pos
+			p.pos += len(lit.text)
+			return &node{kind: lit.kind, start: start, end: p.pos}, nil
+		}
+	}
+	return nil, p.errorf("jsonedit.unexpected_char", p.src[p.pos])
+}
+
+func hasPrefixAt(src []byte, pos int, prefix string) bool {
+	return pos+len(prefix) <= len(src) && string(src[pos:pos+len(prefix)]) == prefix
+}
+
+func indexFrom(src []byte, from int, sep string) int {
+	for i := from; i+len(sep) <= len(src); i++ {
+		if string(src[i:i+len(sep)]) == sep {
+			return i
+		}
+	}
+	return -1
+}
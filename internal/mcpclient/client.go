@@ -0,0 +1,385 @@
+// Package mcpclient talks to stdio MCP servers: it launches a server,
+// performs the handshake and sends requests to it over newline-delimited
+// JSON-RPC. doctor uses it to check and inspect servers, and proxy to
+// relay tool calls to them.
+package mcpclient
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os/exec"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/server"
+)
+
+const (
+	// ProtocolVersion is the MCP revision requested in initialize
+	ProtocolVersion = "2025-03-26"
+	// DefaultTimeout bounds the start of a server without a timeout
+	DefaultTimeout = 30 * time.Second
+
+	// maxPages stops paging through a list method
+	maxPages = 100
+	// closeTimeout is how long a server may take to exit once stdin is closed
+	closeTimeout = 2 * time.Second
+	// waitDelay is how long to wait for output after the server is killed
+	waitDelay = time.Second
+)
+
+// JSON-RPC error codes
+const codeMethodNotFound = -32601
+
+// Client is a connection to one stdio server. Requests may be sent
+// concurrently; a reader goroutine hands each response to the request
+// waiting for it.
+type Client struct {
+	cmd    *exec.Cmd
+	stdout io.Closer
+
+	// writeMu serializes writes to stdin. It is separate from mu so that
+	// a server slow to read does not hold up the dispatch of responses.
+	writeMu sync.Mutex
+	stdin   io.WriteCloser
+
+	mu      sync.Mutex
+	nextID  int
+	pending map[string]*request
+	// err is set once the connection can no longer be used
+	err error
+
+	// done is closed once the server's stdout is closed and the server
+	// has been waited for
+	done chan struct{}
+}
+
+type message struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// request is a request waiting for its response
+type request struct {
+	method string
+	ch     chan response
+}
+
+type response struct {
+	result json.RawMessage
+	err    error
+}
+
+// ResponseError is an error response of the server
+type ResponseError struct {
+	Method  string
+	Code    int
+	Message string
+}
+
+func (e *ResponseError) Error() string {
+	return i18n.T("mcpclient.error_response", e.Method, e.Message, e.Code)
+}
+
+// InitializeResult is the server's answer to the handshake
+type InitializeResult struct {
+	ProtocolVersion string                     `json:"protocolVersion"`
+	Capabilities    map[string]json.RawMessage `json:"capabilities"`
+	ServerInfo      struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	} `json:"serverInfo"`
+}
+
+// Has reports whether the server declared the capability
+func (r *InitializeResult) Has(capability string) bool {
+	_, ok := r.Capabilities[capability]
+	return ok
+}
+
+// Timeout returns how long the server may take to answer: its timeout, or
+// DefaultTimeout when it has none
+func Timeout(mcpServer server.MCPServer) time.Duration {
+	if mcpServer.Timeout != nil && *mcpServer.Timeout > 0 {
+		return time.Duration(*mcpServer.Timeout) * time.Second
+	}
+	return DefaultTimeout
+}
+
+// TimeoutError reports that a server did not answer within timeout
+func TimeoutError(timeout time.Duration) error {
+	return i18n.Errorf("mcpclient.timeout", timeout)
+}
+
+// Start launches the server. Its stderr is copied to stderr.
+func Start(mcpServer server.MCPServer, stderr io.Writer) (*Client, error) {
+	env, err := mcpServer.Environ()
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command(mcpServer.Command, mcpServer.Args...)
+	cmd.Env = env
+	cmd.Stderr = stderr
+	cmd.WaitDelay = waitDelay
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, i18n.Errorf("mcpclient.start_failed", mcpServer.Command, err)
+	}
+
+	c := &Client{
+		cmd:     cmd,
+		stdout:  stdout,
+		stdin:   stdin,
+		pending: map[string]*request{},
+		done:    make(chan struct{}),
+	}
+	go c.read(stdout)
+	return c, nil
+}
+
+// read dispatches the messages of the server until its stdout is closed,
+// then waits for the server and fails the requests still waiting
+func (c *Client) read(stdout io.Reader) {
+	defer close(c.done)
+
+	reader := bufio.NewReader(stdout)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			c.dispatch(line)
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	exitErr := i18n.Errorf("mcpclient.closed")
+	if err := c.cmd.Wait(); err != nil {
+		exitErr = i18n.Errorf("mcpclient.exited", err)
+	}
+	c.fail(exitErr)
+}
+
+func (c *Client) dispatch(line []byte) {
+	var msg message
+	if json.Unmarshal(line, &msg) != nil {
+		// stdout is reserved for MCP messages, so a server writing
+		// anything else cannot be relied on
+		c.fail(i18n.Errorf("mcpclient.invalid_message", bytes.TrimSpace(line)))
+		return
+	}
+
+	if msg.Method != "" {
+		// Requests from the server, such as sampling, are not supported;
+		// ping is answered so that the server keeps going
+		if len(msg.ID) > 0 {
+			reply := map[string]interface{}{"jsonrpc": "2.0", "id": msg.ID}
+			if msg.Method == "ping" {
+				reply["result"] = map[string]interface{}{}
+			} else {
+				reply["error"] = map[string]interface{}{"code": codeMethodNotFound, "message": i18n.T("mcpclient.method_not_found", msg.Method)}
+			}
+			_ = c.write(reply)
+		}
+		return
+	}
+
+	c.mu.Lock()
+	req, ok := c.pending[string(msg.ID)]
+	delete(c.pending, string(msg.ID))
+	c.mu.Unlock()
+	if !ok {
+		return
+	}
+	if msg.Error != nil {
+		req.ch <- response{err: &ResponseError{Method: req.method, Code: msg.Error.Code, Message: msg.Error.Message}}
+		return
+	}
+	req.ch <- response{result: msg.Result}
+}
+
+// fail makes the pending and later requests fail with err, unless the
+// connection has already failed
+func (c *Client) fail(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err == nil {
+		c.err = err
+	}
+	for id, req := range c.pending {
+		req.ch <- response{err: c.err}
+		delete(c.pending, id)
+	}
+}
+
+// Call sends a request and waits for its result. When ctx is done first,
+// the server is told that the request was cancelled.
+func (c *Client) Call(ctx context.Context, method string, params interface{}) (json.RawMessage, error) {
+	ch := make(chan response, 1)
+
+	c.mu.Lock()
+	if c.err != nil {
+		err := c.err
+		c.mu.Unlock()
+		return nil, err
+	}
+	c.nextID++
+	id := c.nextID
+	c.pending[strconv.Itoa(id)] = &request{method: method, ch: ch}
+	c.mu.Unlock()
+
+	if err := c.send(map[string]interface{}{"jsonrpc": "2.0", "id": id, "method": method, "params": params}); err != nil {
+		c.forget(id)
+		return nil, err
+	}
+
+	select {
+	case resp := <-ch:
+		return resp.result, resp.err
+	case <-ctx.Done():
+		c.forget(id)
+		_ = c.write(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"method":  "notifications/cancelled",
+			"params":  map[string]interface{}{"requestId": id},
+		})
+		return nil, ctx.Err()
+	}
+}
+
+// Notify sends a notification
+func (c *Client) Notify(method string, params interface{}) error {
+	notification := map[string]interface{}{"jsonrpc": "2.0", "method": method}
+	if params != nil {
+		notification["params"] = params
+	}
+	return c.send(notification)
+}
+
+func (c *Client) forget(id int) {
+	c.mu.Lock()
+	delete(c.pending, strconv.Itoa(id))
+	c.mu.Unlock()
+}
+
+// send writes a message. When the server no longer reads it, the error
+// the connection failed with, such as the exit status, is returned.
+func (c *Client) send(message interface{}) error {
+	if err := c.write(message); err != nil {
+		select {
+		case <-c.done:
+		case <-time.After(closeTimeout):
+			c.fail(i18n.Errorf("mcpclient.stdin_closed"))
+		}
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return c.err
+	}
+	return nil
+}
+
+// write writes a message to the server's stdin
+func (c *Client) write(message interface{}) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_, err = c.stdin.Write(append(data, '\n'))
+	return err
+}
+
+// Initialize performs the MCP handshake, introducing the client as
+// clientName at version
+func (c *Client) Initialize(ctx context.Context, clientName, version string) (*InitializeResult, error) {
+	raw, err := c.Call(ctx, "initialize", map[string]interface{}{
+		"protocolVersion": ProtocolVersion,
+		"capabilities":    map[string]interface{}{},
+		"clientInfo":      map[string]string{"name": clientName, "version": version},
+	})
+	if err != nil {
+		return nil, err
+	}
+	info := &InitializeResult{}
+	if err := json.Unmarshal(raw, info); err != nil {
+		return nil, i18n.Errorf("mcpclient.invalid_result", "initialize", err)
+	}
+	if err := c.Notify("notifications/initialized", nil); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// List pages through a list method, such as tools/list, and returns the
+// items in field
+func (c *Client) List(ctx context.Context, method, field string) ([]json.RawMessage, error) {
+	items := []json.RawMessage{}
+	params := map[string]interface{}{}
+	for page := 0; page < maxPages; page++ {
+		raw, err := c.Call(ctx, method, params)
+		if err != nil {
+			return nil, err
+		}
+		var result map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &result); err != nil {
+			return nil, i18n.Errorf("mcpclient.invalid_result", method, err)
+		}
+
+		var pageItems []json.RawMessage
+		if raw, ok := result[field]; ok {
+			if err := json.Unmarshal(raw, &pageItems); err != nil {
+				return nil, i18n.Errorf("mcpclient.invalid_result", method, err)
+			}
+		}
+		items = append(items, pageItems...)
+
+		var cursor string
+		if raw, ok := result["nextCursor"]; ok {
+			_ = json.Unmarshal(raw, &cursor)
+		}
+		if cursor == "" {
+			break
+		}
+		params = map[string]interface{}{"cursor": cursor}
+	}
+	return items, nil
+}
+
+// Close stops the server, giving it a moment to exit once stdin is closed
+func (c *Client) Close() {
+	_ = c.stdin.Close()
+	select {
+	case <-c.done:
+		return
+	case <-time.After(closeTimeout):
+	}
+
+	_ = c.cmd.Process.Kill()
+	select {
+	case <-c.done:
+	case <-time.After(waitDelay):
+		// a child the server started may still hold stdout open
+		_ = c.stdout.Close()
+		<-c.done
+	}
+}
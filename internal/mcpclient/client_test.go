@@ -0,0 +1,97 @@
+package mcpclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/naoto24kawa/mcpjson/internal/testutil"
+)
+
+func TestMain(m *testing.M) {
+	testutil.RunFakeServerIfRequested()
+	os.Exit(m.Run())
+}
+
+func TestClient(t *testing.T) {
+	c, err := Start(testutil.FakeServer("ok"), io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	ctx := context.Background()
+	info, err := c.Initialize(ctx, "test", "v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.ProtocolVersion != ProtocolVersion || !info.Has("tools") {
+		t.Errorf("Initialize() = %+v", info)
+	}
+
+	tools, err := c.List(ctx, "tools/list", "tools")
+	if err != nil || len(tools) != 4 {
+		t.Fatalf("List() = %d tools, %v, want 4 over two pages", len(tools), err)
+	}
+
+	// 並行して送った要求にそれぞれの応答が返ること
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			args := fmt.Sprintf(`{"n":%d}`, i)
+			raw, err := c.Call(ctx, "tools/call", map[string]interface{}{"name": "search", "arguments": json.RawMessage(args)})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			var result struct {
+				Content []struct {
+					Text string `json:"text"`
+				} `json:"content"`
+			}
+			if err := json.Unmarshal(raw, &result); err != nil || len(result.Content) != 1 || result.Content[0].Text != "ok search "+args {
+				t.Errorf("Call() = %s, want the text of call %d", raw, i)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	_, err = c.Call(ctx, "tools/call", map[string]interface{}{"name": "fail"})
+	var responseErr *ResponseError
+	if !errors.As(err, &responseErr) || responseErr.Method != "tools/call" || responseErr.Message != "database unavailable" {
+		t.Errorf("Call() error = %v, want a ResponseError of tools/call", err)
+	}
+}
+
+func TestClient_Broken(t *testing.T) {
+	tests := []struct {
+		mode    string
+		wantErr string
+	}{
+		{mode: "crash", wantErr: "exit status 3"},
+		{mode: "noise", wantErr: "starting server..."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			c, err := Start(testutil.FakeServer(tt.mode), io.Discard)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer c.Close()
+
+			_, err = c.Initialize(context.Background(), "test", "v1")
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Initialize() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
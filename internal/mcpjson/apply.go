@@ -0,0 +1,239 @@
+package mcpjson
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/filelock"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/provenance"
+	"github.com/naoto24kawa/mcpjson/internal/server"
+)
+
+// ApplyMode selects how built servers are combined with the target file
+type ApplyMode string
+
+const (
+	// ModeReplace replaces every server in the target file
+	ModeReplace ApplyMode = "replace"
+	// ModeMerge keeps servers mcpjson does not manage and replaces the
+	// ones it wrote before
+	ModeMerge ApplyMode = "merge"
+	// ModeUpdateOnly only updates servers already present in the target file
+	ModeUpdateOnly ApplyMode = "update-only"
+)
+
+// ConflictPolicy decides what happens when a built server collides with a
+// server that was added or edited by hand in the target file
+type ConflictPolicy string
+
+const (
+	PolicyProfileWins ConflictPolicy = "profile-wins"
+	PolicyFileWins    ConflictPolicy = "file-wins"
+	PolicyFail        ConflictPolicy = "fail"
+)
+
+// ParseApplyMode validates an apply mode given on the command line
+func ParseApplyMode(s string) (ApplyMode, error) {
+	switch mode := ApplyMode(s); mode {
+	case ModeReplace, ModeMerge, ModeUpdateOnly:
+		return mode, nil
+	}
+	return "", i18n.Errorf("mcpjson.unknown_mode", s, ModeReplace, ModeMerge, ModeUpdateOnly)
+}
+
+// ParseConflictPolicy validates a conflict policy given on the command line
+func ParseConflictPolicy(s string) (ConflictPolicy, error) {
+	switch policy := ConflictPolicy(s); policy {
+	case PolicyProfileWins, PolicyFileWins, PolicyFail:
+		return policy, nil
+	}
+	return "", i18n.Errorf("mcpjson.unknown_conflict", s, PolicyProfileWins, PolicyFileWins, PolicyFail)
+}
+
+// ApplyOptions controls PlanApply
+type ApplyOptions struct {
+	Mode     ApplyMode
+	Conflict ConflictPolicy
+	// Managed holds the fingerprints of the servers written by the
+	// previous apply (see provenance.Record)
+	Managed map[string]string
+	// Client is the format of the target file. When nil the file is a
+	// plain .mcp.json.
+	Client server.ClientAdapter
+}
+
+// ApplyResult is the outcome of combining built servers with a target file
+type ApplyResult struct {
+	// Servers is the new content of mcpServers
+	Servers map[string]server.MCPServer
+	// Managed holds the fingerprints of the servers mcpjson now manages
+	Managed map[string]string
+
+	Added     []string
+	Updated   []string
+	Removed   []string
+	Kept      []string
+	Skipped   []string
+	Conflicts []string
+	// Dropped lists, per server, the fields the client's format cannot
+	// hold and that were left out of the file
+	Dropped map[string][]string
+}
+
+// ConflictError lists servers that would overwrite hand-made changes
+type ConflictError struct {
+	Servers []string
+}
+
+func (e *ConflictError) Error() string {
+	return i18n.T("mcpjson.conflict",
+		strings.Join(e.Servers, ", "), PolicyProfileWins, PolicyFileWins)
+}
+
+// PlanApply combines the servers in the target file (current) with the
+// built servers according to opts. A server in the file conflicts with a
+// built one of the same name when they differ and mcpjson did not write
+// the file's version; with PolicyFail such conflicts return a *ConflictError.
+func PlanApply(current, built map[string]server.MCPServer, opts ApplyOptions) (*ApplyResult, error) {
+	if opts.Mode == "" {
+		opts.Mode = ModeReplace
+	}
+	if opts.Conflict == "" {
+		opts.Conflict = PolicyFail
+	}
+	result := &ApplyResult{
+		Servers: make(map[string]server.MCPServer),
+		Managed: make(map[string]string),
+	}
+	if opts.Client != nil {
+		// 書き込み後に読み戻した形で比較する
+		converted := make(map[string]server.MCPServer, len(built))
+		for name, mcpServer := range built {
+			var err error
+			if converted[name], err = server.ConvertServer(opts.Client, name, mcpServer); err != nil {
+				return nil, err
+			}
+			// クライアントの形式で表せないフィールドは報告する
+			if fields := server.DroppedFields(mcpServer, converted[name]); len(fields) > 0 {
+				if result.Dropped == nil {
+					result.Dropped = make(map[string][]string)
+				}
+				result.Dropped[name] = fields
+			}
+		}
+		built = converted
+	}
+
+	// 既存のサーバーのうち残すものを決める
+	for _, name := range sortedNames(current) {
+		existing := current[name]
+		if _, inBuilt := built[name]; inBuilt {
+			continue
+		}
+
+		switch {
+		case opts.Mode == ModeReplace:
+			result.Removed = append(result.Removed, name)
+		case opts.Mode == ModeMerge && isManagedCopy(name, existing, opts.Managed):
+			// mcpjson が書き込み、その後編集されていないサーバーは削除する
+			result.Removed = append(result.Removed, name)
+		default:
+			result.Servers[name] = existing
+			result.Kept = append(result.Kept, name)
+		}
+	}
+
+	var conflicts []string
+	for _, name := range sortedNames(built) {
+		mcpServer := built[name]
+		existing, exists := current[name]
+
+		switch {
+		case !exists && opts.Mode == ModeUpdateOnly:
+			result.Skipped = append(result.Skipped, name)
+			continue
+		case !exists:
+			result.Added = append(result.Added, name)
+		case provenance.Fingerprint(existing) == provenance.Fingerprint(mcpServer):
+			// 変更なし
+		case opts.Mode == ModeReplace || isManagedCopy(name, existing, opts.Managed):
+			result.Updated = append(result.Updated, name)
+		default:
+			result.Conflicts = append(result.Conflicts, name)
+			switch opts.Conflict {
+			case PolicyFileWins:
+				result.Servers[name] = existing
+				result.Kept = append(result.Kept, name)
+				continue
+			case PolicyFail:
+				conflicts = append(conflicts, name)
+				continue
+			}
+			result.Updated = append(result.Updated, name)
+		}
+
+		result.Servers[name] = mcpServer
+		result.Managed[name] = provenance.Fingerprint(mcpServer)
+	}
+
+	if len(conflicts) > 0 {
+		return nil, &ConflictError{Servers: conflicts}
+	}
+	return result, nil
+}
+
+// ApplyToFile plans an apply against the current content of targetPath
+// and writes the result. Unknown keys in the file are preserved.
+func (m *MCPConfigManager) ApplyToFile(mcpConfig *server.MCPConfig, targetPath string, opts ApplyOptions) (*ApplyResult, error) {
+	if err := os.MkdirAll(filepath.Dir(targetPath), config.DefaultDirPerm); err != nil {
+		return nil, i18n.Errorf("common.mkdir_failed", err)
+	}
+
+	unlock, err := filelock.LockFile(targetPath)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	doc, err := m.loadOrCreateDocument(targetPath, opts.Client)
+	if err != nil {
+		return nil, err
+	}
+	current, err := doc.Config()
+	if err != nil {
+		return nil, i18n.Errorf("common.mcp_read_existing_failed", err)
+	}
+
+	result, err := PlanApply(current.McpServers, mcpConfig.McpServers, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := doc.ReplaceServers(result.Servers); err != nil {
+		return nil, i18n.Errorf("common.mcp_update_failed", err)
+	}
+	if err := doc.Save(targetPath); err != nil {
+		return nil, i18n.Errorf("common.mcp_save_failed", err)
+	}
+	return result, nil
+}
+
+// isManagedCopy reports whether the file still holds exactly what mcpjson
+// wrote for the server
+func isManagedCopy(name string, existing server.MCPServer, managed map[string]string) bool {
+	fingerprint, ok := managed[name]
+	return ok && fingerprint == provenance.Fingerprint(existing)
+}
+
+func sortedNames(servers map[string]server.MCPServer) []string {
+	names := make([]string, 0, len(servers))
+	for name := range servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,211 @@
+package mcpjson
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/naoto24kawa/mcpjson/internal/provenance"
+	"github.com/naoto24kawa/mcpjson/internal/server"
+)
+
+func TestPlanApply(t *testing.T) {
+	profileServer := server.MCPServer{Command: "npx", Args: []string{"profile"}}
+	oldServer := server.MCPServer{Command: "npx", Args: []string{"old"}}
+	handServer := server.MCPServer{Command: "node", Args: []string{"hand.js"}}
+
+	tests := []struct {
+		name        string
+		current     map[string]server.MCPServer
+		built       map[string]server.MCPServer
+		opts        ApplyOptions
+		wantServers map[string]server.MCPServer
+		wantRemoved []string
+		wantKept    []string
+		wantSkipped []string
+		wantErr     bool
+	}{
+		{
+			name:        "replace は既存のサーバーをすべて置き換える",
+			current:     map[string]server.MCPServer{"hand": handServer, "a": oldServer},
+			built:       map[string]server.MCPServer{"a": profileServer},
+			opts:        ApplyOptions{Mode: ModeReplace},
+			wantServers: map[string]server.MCPServer{"a": profileServer},
+			wantRemoved: []string{"hand"},
+		},
+		{
+			name:        "merge は管理外のサーバーを残す",
+			current:     map[string]server.MCPServer{"hand": handServer},
+			built:       map[string]server.MCPServer{"a": profileServer},
+			opts:        ApplyOptions{Mode: ModeMerge},
+			wantServers: map[string]server.MCPServer{"hand": handServer, "a": profileServer},
+			wantKept:    []string{"hand"},
+		},
+		{
+			name:    "merge は以前書き込んだサーバーを削除する",
+			current: map[string]server.MCPServer{"hand": handServer, "old": oldServer},
+			built:   map[string]server.MCPServer{"a": profileServer},
+			opts: ApplyOptions{
+				Mode:    ModeMerge,
+				Managed: map[string]string{"old": provenance.Fingerprint(oldServer)},
+			},
+			wantServers: map[string]server.MCPServer{"hand": handServer, "a": profileServer},
+			wantRemoved: []string{"old"},
+			wantKept:    []string{"hand"},
+		},
+		{
+			name:    "merge は書き込み後に編集されたサーバーを残す",
+			current: map[string]server.MCPServer{"old": handServer},
+			built:   map[string]server.MCPServer{"a": profileServer},
+			opts: ApplyOptions{
+				Mode:    ModeMerge,
+				Managed: map[string]string{"old": provenance.Fingerprint(oldServer)},
+			},
+			wantServers: map[string]server.MCPServer{"old": handServer, "a": profileServer},
+			wantKept:    []string{"old"},
+		},
+		{
+			name:    "merge は以前書き込んだサーバーを更新する",
+			current: map[string]server.MCPServer{"a": oldServer},
+			built:   map[string]server.MCPServer{"a": profileServer},
+			opts: ApplyOptions{
+				Mode:    ModeMerge,
+				Managed: map[string]string{"a": provenance.Fingerprint(oldServer)},
+			},
+			wantServers: map[string]server.MCPServer{"a": profileServer},
+		},
+		{
+			name:        "update-only は存在しないサーバーを追加しない",
+			current:     map[string]server.MCPServer{"a": oldServer},
+			built:       map[string]server.MCPServer{"a": profileServer, "b": profileServer},
+			opts:        ApplyOptions{Mode: ModeUpdateOnly, Conflict: PolicyProfileWins},
+			wantServers: map[string]server.MCPServer{"a": profileServer},
+			wantSkipped: []string{"b"},
+		},
+		{
+			name:    "競合時に fail はエラー",
+			current: map[string]server.MCPServer{"a": handServer},
+			built:   map[string]server.MCPServer{"a": profileServer},
+			opts:    ApplyOptions{Mode: ModeMerge, Conflict: PolicyFail},
+			wantErr: true,
+		},
+		{
+			name:        "競合時に profile-wins はプロファイルで上書き",
+			current:     map[string]server.MCPServer{"a": handServer},
+			built:       map[string]server.MCPServer{"a": profileServer},
+			opts:        ApplyOptions{Mode: ModeMerge, Conflict: PolicyProfileWins},
+			wantServers: map[string]server.MCPServer{"a": profileServer},
+		},
+		{
+			name:        "競合時に file-wins はファイルの内容を残す",
+			current:     map[string]server.MCPServer{"a": handServer},
+			built:       map[string]server.MCPServer{"a": profileServer},
+			opts:        ApplyOptions{Mode: ModeMerge, Conflict: PolicyFileWins},
+			wantServers: map[string]server.MCPServer{"a": handServer},
+			wantKept:    []string{"a"},
+		},
+		{
+			name:        "同じ内容なら競合しない",
+			current:     map[string]server.MCPServer{"a": profileServer},
+			built:       map[string]server.MCPServer{"a": profileServer},
+			opts:        ApplyOptions{Mode: ModeMerge, Conflict: PolicyFail},
+			wantServers: map[string]server.MCPServer{"a": profileServer},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := PlanApply(tt.current, tt.built, tt.opts)
+			if tt.wantErr {
+				var conflictErr *ConflictError
+				if !errors.As(err, &conflictErr) {
+					t.Fatalf("PlanApply() error = %v, want *ConflictError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("PlanApply() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(result.Servers, tt.wantServers) {
+				t.Errorf("Servers = %v, want %v", result.Servers, tt.wantServers)
+			}
+			if !reflect.DeepEqual(result.Removed, tt.wantRemoved) {
+				t.Errorf("Removed = %v, want %v", result.Removed, tt.wantRemoved)
+			}
+			if !reflect.DeepEqual(result.Kept, tt.wantKept) {
+				t.Errorf("Kept = %v, want %v", result.Kept, tt.wantKept)
+			}
+			if !reflect.DeepEqual(result.Skipped, tt.wantSkipped) {
+				t.Errorf("Skipped = %v, want %v", result.Skipped, tt.wantSkipped)
+			}
+			for name := range result.Managed {
+				if _, ok := tt.built[name]; !ok {
+					t.Errorf("Managed に管理外のサーバー '%s' が含まれています", name)
+				}
+			}
+		})
+	}
+}
+
+func TestPlanApply_ReportsDroppedFields(t *testing.T) {
+	timeout := 30
+	built := map[string]server.MCPServer{
+		"slow": {Command: "npx", Timeout: &timeout},
+		"fast": {Command: "npx"},
+	}
+	client, err := server.LookupClient(server.ClientClaudeDesktop)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := PlanApply(nil, built, ApplyOptions{Client: client})
+	if err != nil {
+		t.Fatalf("PlanApply() error = %v", err)
+	}
+	want := map[string][]string{"slow": {"timeout"}}
+	if !reflect.DeepEqual(result.Dropped, want) {
+		t.Errorf("Dropped = %v, want %v", result.Dropped, want)
+	}
+	if result.Servers["slow"].Timeout != nil {
+		t.Errorf("Servers[slow].Timeout = %v, want nil", *result.Servers["slow"].Timeout)
+	}
+}
+
+func TestMCPConfigManager_ApplyToFile_PreservesUnknownKeys(t *testing.T) {
+	targetPath := filepath.Join(t.TempDir(), ".mcp.json")
+	content := `{
+  // 手動で追加した設定
+  "other": true,
+  "mcpServers": {
+    "hand": {"command": "node", "args": ["hand.js"]}
+  }
+}`
+	if err := os.WriteFile(targetPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	built := &server.MCPConfig{McpServers: map[string]server.MCPServer{
+		"a": {Command: "npx", Args: []string{"a"}},
+	}}
+	result, err := NewMCPConfigManager().ApplyToFile(built, targetPath, ApplyOptions{Mode: ModeMerge})
+	if err != nil {
+		t.Fatalf("ApplyToFile() error = %v", err)
+	}
+	if !reflect.DeepEqual(result.Added, []string{"a"}) {
+		t.Errorf("Added = %v, want [a]", result.Added)
+	}
+
+	data, err := os.ReadFile(targetPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"other": true`, `"hand"`, `"a"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("保存結果に %s が含まれていません:\n%s", want, data)
+		}
+	}
+}
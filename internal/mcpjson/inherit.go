@@ -0,0 +1,149 @@
+package mcpjson
+
+import (
+	"strings"
+
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+)
+
+// ProfileLoader loads a profile by name. It is used to resolve the
+// profiles listed in extends.
+type ProfileLoader func(name string) (*ProfileData, error)
+
+// ResolvedServer is a server reference of a resolved profile together
+// with the profiles it came from
+type ResolvedServer struct {
+	ServerRef
+	// Source is the profile that defined the server's template
+	Source string `json:"source"`
+	// OverriddenBy lists the descendant profiles that changed its overrides
+	OverriddenBy []string `json:"overriddenBy,omitempty"`
+}
+
+// ResolvedProfile is a profile with its extends chain flattened
+type ResolvedProfile struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Extends     []string          `json:"extends,omitempty"`
+	Vars        map[string]string `json:"vars,omitempty"`
+	Servers     []ResolvedServer  `json:"servers"`
+}
+
+// ServerRefs returns the resolved server references without their sources
+func (r *ResolvedProfile) ServerRefs() []ServerRef {
+	refs := make([]ServerRef, len(r.Servers))
+	for i, s := range r.Servers {
+		refs[i] = s.ServerRef
+	}
+	return refs
+}
+
+// SetProfileLoader sets the loader used to resolve extends
+func (m *MCPConfigManager) SetProfileLoader(loader ProfileLoader) {
+	m.profileLoader = loader
+}
+
+// ResolveProfile flattens the extends chain of profile.
+// Parents are applied in the order they are listed, then the profile's own
+// servers: an entry with the name of an inherited server replaces it, or
+// only adds its overrides when no template is given, and an entry with
+// "remove": true drops it. Vars are inherited the same way.
+func (m *MCPConfigManager) ResolveProfile(profile *ProfileData) (*ResolvedProfile, error) {
+	return m.resolveProfile(profile, nil)
+}
+
+func (m *MCPConfigManager) resolveProfile(profile *ProfileData, chain []string) (*ResolvedProfile, error) {
+	for _, name := range chain {
+		if name == profile.Name {
+			return nil, i18n.Errorf("mcpjson.extends_cycle", strings.Join(chain, " -> "), profile.Name)
+		}
+	}
+	chain = append(chain, profile.Name)
+
+	resolved := &ResolvedProfile{
+		Name:        profile.Name,
+		Description: profile.Description,
+		Extends:     profile.Extends,
+	}
+
+	for _, parentName := range profile.Extends {
+		if m.profileLoader == nil {
+			return nil, i18n.Errorf("mcpjson.parent_unreadable", profile.Name)
+		}
+		parent, err := m.profileLoader(parentName)
+		if err != nil {
+			return nil, i18n.Errorf("mcpjson.parent_load_failed", profile.Name, parentName, err)
+		}
+		parentResolved, err := m.resolveProfile(parent, chain)
+		if err != nil {
+			return nil, err
+		}
+
+		resolved.Vars = mergeVars(resolved.Vars, parentResolved.Vars)
+		for _, s := range parentResolved.Servers {
+			resolved.setServer(s)
+		}
+	}
+
+	resolved.Vars = mergeVars(resolved.Vars, profile.Vars)
+	for _, ref := range profile.Servers {
+		if err := resolved.apply(profile.Name, ref); err != nil {
+			return nil, err
+		}
+	}
+
+	return resolved, nil
+}
+
+// apply applies one server entry of the profile called source
+func (r *ResolvedProfile) apply(source string, ref ServerRef) error {
+	index := r.indexOf(ref.Name)
+
+	switch {
+	case ref.Remove:
+		if index >= 0 {
+			r.Servers = append(r.Servers[:index], r.Servers[index+1:]...)
+		}
+	case ref.Template == "" && index >= 0:
+		existing := &r.Servers[index]
+		existing.Overrides = existing.Overrides.Merge(ref.Overrides)
+		existing.OverriddenBy = append(existing.OverriddenBy, source)
+	case ref.Template == "":
+		return i18n.Errorf("mcpjson.no_template", source, ref.Name)
+	default:
+		r.setServer(ResolvedServer{ServerRef: ref, Source: source})
+	}
+	return nil
+}
+
+// setServer adds s, replacing a server of the same name in place
+func (r *ResolvedProfile) setServer(s ResolvedServer) {
+	if index := r.indexOf(s.Name); index >= 0 {
+		r.Servers[index] = s
+		return
+	}
+	r.Servers = append(r.Servers, s)
+}
+
+func (r *ResolvedProfile) indexOf(name string) int {
+	for i, s := range r.Servers {
+		if s.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func mergeVars(base, overlay map[string]string) map[string]string {
+	if len(overlay) == 0 {
+		return base
+	}
+	merged := make(map[string]string, len(base)+len(overlay))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range overlay {
+		merged[k] = v
+	}
+	return merged
+}
@@ -0,0 +1,236 @@
+package mcpjson
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/naoto24kawa/mcpjson/internal/server"
+	"github.com/naoto24kawa/mcpjson/internal/utils"
+)
+
+func mapLoader(profiles ...*ProfileData) ProfileLoader {
+	byName := make(map[string]*ProfileData)
+	for _, p := range profiles {
+		byName[p.Name] = p
+	}
+	return func(name string) (*ProfileData, error) {
+		if p, ok := byName[name]; ok {
+			return p, nil
+		}
+		return nil, fmt.Errorf("プロファイル '%s' が見つかりません", name)
+	}
+}
+
+func serverNames(resolved *ResolvedProfile) []string {
+	var names []string
+	for _, s := range resolved.Servers {
+		names = append(names, s.Name+"@"+s.Source)
+	}
+	return names
+}
+
+func TestMCPConfigManager_ResolveProfile(t *testing.T) {
+	base := &ProfileData{
+		Name: "base",
+		Vars: map[string]string{"root": "/base", "env": "dev"},
+		Servers: []ServerRef{
+			{Name: "git", Template: "git"},
+			{Name: "fs", Template: "filesystem", Overrides: ServerOverrides{Env: map[string]string{"LOG": "info"}}},
+			{Name: "slack", Template: "slack"},
+		},
+	}
+	web := &ProfileData{
+		Name:    "web",
+		Extends: []string{"base"},
+		Servers: []ServerRef{{Name: "browser", Template: "playwright"}},
+	}
+
+	tests := []struct {
+		name     string
+		profile  *ProfileData
+		want     []string
+		wantVars map[string]string
+	}{
+		{
+			name:     "継承なし",
+			profile:  base,
+			want:     []string{"git@base", "fs@base", "slack@base"},
+			wantVars: map[string]string{"root": "/base", "env": "dev"},
+		},
+		{
+			name: "サーバーの追加・置換・除外",
+			profile: &ProfileData{
+				Name:    "frontend",
+				Extends: []string{"base"},
+				Vars:    map[string]string{"root": "/frontend"},
+				Servers: []ServerRef{
+					{Name: "git", Template: "git-v2"},
+					{Name: "slack", Remove: true},
+					{Name: "figma", Template: "figma"},
+				},
+			},
+			want:     []string{"git@frontend", "fs@base", "figma@frontend"},
+			wantVars: map[string]string{"root": "/frontend", "env": "dev"},
+		},
+		{
+			name: "多段継承と複数の継承元",
+			profile: &ProfileData{
+				Name:    "full",
+				Extends: []string{"web", "base"},
+				Servers: []ServerRef{{Name: "browser", Remove: true}},
+			},
+			want:     []string{"git@base", "fs@base", "slack@base"},
+			wantVars: map[string]string{"root": "/base", "env": "dev"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := NewMCPConfigManager()
+			manager.SetProfileLoader(mapLoader(base, web))
+
+			resolved, err := manager.ResolveProfile(tt.profile)
+			if err != nil {
+				t.Fatalf("ResolveProfile() failed: %v", err)
+			}
+			if got := serverNames(resolved); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("servers = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(resolved.Vars, tt.wantVars) {
+				t.Errorf("vars = %v, want %v", resolved.Vars, tt.wantVars)
+			}
+		})
+	}
+}
+
+func TestMCPConfigManager_ResolveProfile_MergesOverrides(t *testing.T) {
+	base := &ProfileData{
+		Name: "base",
+		Servers: []ServerRef{
+			{Name: "fs", Template: "filesystem", Overrides: ServerOverrides{Env: map[string]string{"LOG": "info", "MODE": "ro"}}},
+		},
+	}
+	child := &ProfileData{
+		Name:    "child",
+		Extends: []string{"base"},
+		Servers: []ServerRef{
+			{Name: "fs", Overrides: ServerOverrides{Env: map[string]string{"LOG": "debug"}, Enabled: boolPtr(false)}},
+		},
+	}
+
+	manager := NewMCPConfigManager()
+	manager.SetProfileLoader(mapLoader(base))
+
+	resolved, err := manager.ResolveProfile(child)
+	if err != nil {
+		t.Fatalf("ResolveProfile() failed: %v", err)
+	}
+
+	fs := resolved.Servers[0]
+	if fs.Template != "filesystem" || fs.Source != "base" || !reflect.DeepEqual(fs.OverriddenBy, []string{"child"}) {
+		t.Errorf("unexpected resolved server: %+v", fs)
+	}
+	if !reflect.DeepEqual(fs.Overrides.Env, map[string]string{"LOG": "debug", "MODE": "ro"}) || fs.IsEnabled() {
+		t.Errorf("overrides not merged: %+v", fs.Overrides)
+	}
+	if base.Servers[0].Overrides.Env["LOG"] != "info" {
+		t.Error("resolving must not modify the parent profile")
+	}
+}
+
+func TestMCPConfigManager_ResolveProfile_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		profiles []*ProfileData
+		target   string
+		wantErr  string
+	}{
+		{
+			name: "循環",
+			profiles: []*ProfileData{
+				{Name: "a", Extends: []string{"b"}},
+				{Name: "b", Extends: []string{"c"}},
+				{Name: "c", Extends: []string{"a"}},
+			},
+			target:  "a",
+			wantErr: "a -> b -> c -> a",
+		},
+		{
+			name:     "自分自身",
+			profiles: []*ProfileData{{Name: "self", Extends: []string{"self"}}},
+			target:   "self",
+			wantErr:  "循環",
+		},
+		{
+			name:     "継承元が存在しない",
+			profiles: []*ProfileData{{Name: "child", Extends: []string{"missing"}}},
+			target:   "child",
+			wantErr:  "継承元 'missing'",
+		},
+		{
+			name: "テンプレートのない新規サーバー",
+			profiles: []*ProfileData{
+				{Name: "child", Servers: []ServerRef{{Name: "fs"}}},
+			},
+			target:  "child",
+			wantErr: "テンプレートが指定されていません",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loader := mapLoader(tt.profiles...)
+			manager := NewMCPConfigManager()
+			manager.SetProfileLoader(loader)
+
+			target, _ := loader(tt.target)
+			_, err := manager.ResolveProfile(target)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ResolveProfile() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMCPConfigManager_BuildFromProfile_Extends(t *testing.T) {
+	tempDir := t.TempDir()
+	serverManager := server.NewManager(tempDir)
+
+	template := createTestServerTemplate()
+	template.ServerConfig.Args = []string{"${profile.root}"}
+	if err := utils.SaveJSON(filepath.Join(tempDir, "test-template.jsonc"), template); err != nil {
+		t.Fatalf("Failed to save test template: %v", err)
+	}
+
+	base := &ProfileData{
+		Name: "base",
+		Vars: map[string]string{"root": "/base"},
+		Servers: []ServerRef{
+			{Name: "one", Template: "test-template"},
+			{Name: "two", Template: "test-template"},
+		},
+	}
+	child := &ProfileData{
+		Name:    "child",
+		Extends: []string{"base"},
+		Vars:    map[string]string{"root": "/child"},
+		Servers: []ServerRef{{Name: "two", Remove: true}},
+	}
+
+	manager := NewMCPConfigManager()
+	manager.SetProfileLoader(mapLoader(base))
+
+	mcpConfig, err := manager.BuildFromProfile(child, serverManager)
+	if err != nil {
+		t.Fatalf("BuildFromProfile() failed: %v", err)
+	}
+	if len(mcpConfig.McpServers) != 1 {
+		t.Fatalf("servers = %v, want only 'one'", mcpConfig.McpServers)
+	}
+	if got := mcpConfig.McpServers["one"].Args; !reflect.DeepEqual(got, []string{"/child"}) {
+		t.Errorf("args = %v, want child variables applied", got)
+	}
+}
@@ -1,18 +1,21 @@
 package mcpjson
 
 import (
-	"fmt"
 	"os"
 	"path/filepath"
 	"time"
 
 	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/filelock"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
 	"github.com/naoto24kawa/mcpjson/internal/server"
 	"github.com/naoto24kawa/mcpjson/internal/utils"
 )
 
 // MCPConfigManager handles MCP configuration file operations
-type MCPConfigManager struct{}
+type MCPConfigManager struct {
+	profileLoader ProfileLoader
+}
 
 // NewMCPConfigManager creates a new MCPConfigManager instance
 func NewMCPConfigManager() *MCPConfigManager {
@@ -23,49 +26,145 @@ func NewMCPConfigManager() *MCPConfigManager {
 func (m *MCPConfigManager) Load(mcpConfigPath string) (*server.MCPConfig, error) {
 	mcpConfig := &server.MCPConfig{}
 	if err := utils.LoadJSON(mcpConfigPath, mcpConfig); err != nil {
-		return nil, fmt.Errorf("MCP設定ファイルの読み込みに失敗しました: %w", err)
+		return nil, i18n.Errorf("common.mcp_read_failed", err)
 	}
 	return mcpConfig, nil
 }
 
-// Save saves an MCP configuration to file
+// Save saves an MCP configuration to file.
+// When the target already exists only its mcpServers section is replaced;
+// other top-level keys and unknown per-server fields are preserved.
 func (m *MCPConfigManager) Save(mcpConfig *server.MCPConfig, targetPath string) error {
 	targetDir := filepath.Dir(targetPath)
 	if err := os.MkdirAll(targetDir, config.DefaultDirPerm); err != nil {
-		return fmt.Errorf("ディレクトリの作成に失敗しました: %w", err)
+		return i18n.Errorf("common.mkdir_failed", err)
+	}
+
+	unlock, err := filelock.LockFile(targetPath)
+	if err != nil {
+		return err
 	}
+	defer unlock()
 
-	if err := utils.SaveJSON(targetPath, mcpConfig); err != nil {
-		return fmt.Errorf("MCP設定ファイルの保存に失敗しました: %w", err)
+	doc, err := m.loadOrCreateDocument(targetPath, nil)
+	if err != nil {
+		return err
+	}
+
+	servers := make(map[string]server.MCPServer)
+	if mcpConfig != nil {
+		servers = mcpConfig.McpServers
+	}
+	if err := doc.ReplaceServers(servers); err != nil {
+		return i18n.Errorf("common.mcp_update_failed", err)
+	}
+
+	if err := doc.Save(targetPath); err != nil {
+		return i18n.Errorf("common.mcp_save_failed", err)
 	}
 
 	return nil
 }
 
-// BuildFromProfile builds an MCP configuration from profile and server templates
+// loadOrCreateDocument reads targetPath in the client's format. A nil
+// client reads a plain .mcp.json.
+func (m *MCPConfigManager) loadOrCreateDocument(targetPath string, client server.ClientAdapter) (*server.MCPDocument, error) {
+	if client == nil {
+		client = server.DefaultClient
+	}
+	if !utils.FileExists(targetPath) {
+		return server.NewMCPDocumentFor(client), nil
+	}
+
+	doc, err := server.LoadMCPDocumentFor(targetPath, client)
+	if err != nil {
+		return nil, i18n.Errorf("common.mcp_read_existing_failed", err)
+	}
+	return doc, nil
+}
+
+// BuildFromProfile builds an MCP configuration from profile and server templates.
+// Profiles listed in extends are resolved first (see ResolveProfile).
+// Placeholders such as ${env:NAME}, ${file:path} and ${profile.name} are
+// resolved here, and ${secret:name} through the server manager's secret
+// provider; every unresolvable placeholder is reported in one error.
 func (m *MCPConfigManager) BuildFromProfile(profile *ProfileData, serverManager *server.Manager) (*server.MCPConfig, error) {
+	resolved, mcpConfig, err := m.compose(profile, serverManager)
+	if err != nil {
+		return nil, err
+	}
+	serverRefs := resolved.ServerRefs()
+
+	resolver := serverManager.NewResolver(resolved.Vars)
+
+	for _, serverRef := range serverRefs {
+		mcpServer, exists := mcpConfig.McpServers[serverRef.Name]
+		if !exists {
+			continue
+		}
+		resolver.SetContext(i18n.T("mcpjson.server_context", serverRef.Name))
+		mcpConfig.McpServers[serverRef.Name] = mcpServer.MapStrings(resolver.Expand)
+	}
+
+	if err := resolver.Err(); err != nil {
+		return nil, i18n.Errorf("mcpjson.expand_failed", profile.Name, err)
+	}
+
+	for _, serverRef := range serverRefs {
+		mcpServer, exists := mcpConfig.McpServers[serverRef.Name]
+		if !exists {
+			continue
+		}
+		if err := server.CheckNoSecretReferences(serverRef.Name, mcpServer); err != nil {
+			return nil, err
+		}
+		if err := mcpServer.Validate(); err != nil {
+			return nil, i18n.Errorf("common.server_invalid", serverRef.Name, err)
+		}
+	}
+
+	return mcpConfig, nil
+}
+
+// ComposeProfile returns the servers profile would write, with extends
+// resolved and overrides applied but placeholders left unexpanded
+func (m *MCPConfigManager) ComposeProfile(profile *ProfileData, serverManager *server.Manager) (*server.MCPConfig, error) {
+	_, mcpConfig, err := m.compose(profile, serverManager)
+	return mcpConfig, err
+}
+
+func (m *MCPConfigManager) compose(profile *ProfileData, serverManager *server.Manager) (*ResolvedProfile, *server.MCPConfig, error) {
+	resolved, err := m.ResolveProfile(profile)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	mcpConfig := &server.MCPConfig{
 		McpServers: make(map[string]server.MCPServer),
 	}
+	for _, serverRef := range resolved.ServerRefs() {
+		if !serverRef.IsEnabled() {
+			continue
+		}
 
-	for _, serverRef := range profile.Servers {
 		serverTemplate, err := serverManager.Load(serverRef.Template)
 		if err != nil {
-			return nil, fmt.Errorf("サーバーテンプレート '%s' の読み込みに失敗しました: %w", serverRef.Template, err)
+			return nil, nil, i18n.Errorf("mcpjson.template_load_failed", serverRef.Template, err)
 		}
-
-		mcpServer := m.createMCPServer(serverTemplate, &serverRef)
-		mcpConfig.McpServers[serverRef.Name] = mcpServer
+		mcpConfig.McpServers[serverRef.Name] = m.createMCPServer(serverTemplate, &serverRef)
 	}
 
-	return mcpConfig, nil
+	return resolved, mcpConfig, nil
 }
 
 func (m *MCPConfigManager) createMCPServer(template *server.ServerTemplate, serverRef *ServerRef) server.MCPServer {
 	mcpServer := server.MCPServer{
+		Type:          template.ServerConfig.Type,
 		Command:       template.ServerConfig.Command,
-		Args:          template.ServerConfig.Args,
+		Args:          append([]string(nil), template.ServerConfig.Args...),
 		Env:           make(map[string]string),
+		URL:           template.ServerConfig.URL,
+		Headers:       copyStringMap(template.ServerConfig.Headers),
 		Timeout:       template.ServerConfig.Timeout,
 		EnvFile:       template.ServerConfig.EnvFile,
 		TransportType: template.ServerConfig.TransportType,
@@ -77,20 +176,29 @@ func (m *MCPConfigManager) createMCPServer(template *server.ServerTemplate, serv
 	}
 
 	// Apply overrides
-	for k, v := range serverRef.Overrides.Env {
-		mcpServer.Env[k] = v
-	}
+	return serverRef.Overrides.Apply(mcpServer)
+}
 
-	return mcpServer
+func copyStringMap(src map[string]string) map[string]string {
+	if src == nil {
+		return nil
+	}
+	dst := make(map[string]string, len(src))
+	for k, v := range src {
+		dst[k] = v
+	}
+	return dst
 }
 
 // ProfileData represents profile data structure
 type ProfileData struct {
-	Name        string      `json:"name"`
-	Description string      `json:"description"`
-	CreatedAt   time.Time   `json:"createdAt"`
-	UpdatedAt   time.Time   `json:"updatedAt"`
-	Servers     []ServerRef `json:"servers"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	CreatedAt   time.Time         `json:"createdAt"`
+	UpdatedAt   time.Time         `json:"updatedAt"`
+	Extends     []string          `json:"extends,omitempty"`
+	Vars        map[string]string `json:"vars,omitempty"`
+	Servers     []ServerRef       `json:"servers"`
 }
 
 // ServerRef represents a server reference in a profile
@@ -98,9 +206,30 @@ type ServerRef struct {
 	Name      string          `json:"name"`
 	Template  string          `json:"template"`
 	Overrides ServerOverrides `json:"overrides,omitempty"`
+	// Remove drops the server of the same name inherited through extends
+	Remove bool `json:"remove,omitempty"`
 }
 
-// ServerOverrides represents environment variable overrides
+// ServerOverrides represents per-profile changes applied on top of a
+// server template. Unset fields leave the template value untouched.
 type ServerOverrides struct {
-	Env map[string]string `json:"env,omitempty"`
+	Command       string            `json:"command,omitempty"`
+	Args          []string          `json:"args,omitempty"`
+	ArgsPrepend   []string          `json:"argsPrepend,omitempty"`
+	ArgsAppend    []string          `json:"argsAppend,omitempty"`
+	Env           map[string]string `json:"env,omitempty"`
+	Timeout       *int              `json:"timeout,omitempty"`
+	TransportType *string           `json:"transportType,omitempty"`
+	EnvFile       *string           `json:"envFile,omitempty"`
+	Enabled       *bool             `json:"enabled,omitempty"`
+	// Tools limits the tools offered through 'mcpjson proxy'
+	Tools *ToolFilter `json:"tools,omitempty"`
+}
+
+// ToolFilter selects tools by name with path.Match patterns. A tool passes
+// when it matches an Allow pattern, or Allow is empty, and matches no Deny
+// pattern.
+type ToolFilter struct {
+	Allow []string `json:"allow,omitempty"`
+	Deny  []string `json:"deny,omitempty"`
 }
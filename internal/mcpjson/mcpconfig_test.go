@@ -255,6 +255,54 @@ func TestMCPConfigManager_Save(t *testing.T) {
 	}
 }
 
+func TestMCPConfigManager_Save_PreservesExistingKeys(t *testing.T) {
+	// Arrange
+	tempDir := t.TempDir()
+	targetPath := filepath.Join(tempDir, "settings.json")
+	existing := `{
+  "theme": "dark",
+  "mcpServers": {
+    "test-server": {"command": "python", "disabled": true},
+    "stale": {"command": "node"}
+  }
+}`
+	if err := os.WriteFile(targetPath, []byte(existing), 0644); err != nil {
+		t.Fatalf("Failed to write existing file: %v", err)
+	}
+
+	// Act
+	err := NewMCPConfigManager().Save(createTestMCPConfig(), targetPath)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	data, err := os.ReadFile(targetPath)
+	if err != nil {
+		t.Fatalf("Failed to read saved file: %v", err)
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Saved file is not valid JSON: %v", err)
+	}
+
+	if result["theme"] != "dark" {
+		t.Errorf("top-level key lost: %v", result)
+	}
+	servers := result["mcpServers"].(map[string]interface{})
+	if _, exists := servers["stale"]; exists {
+		t.Error("servers not in the new config should be removed")
+	}
+	testServer := servers["test-server"].(map[string]interface{})
+	if testServer["disabled"] != true {
+		t.Errorf("unknown per-server field lost: %v", testServer)
+	}
+	if len(servers) != 2 {
+		t.Errorf("expected 2 servers, got %d", len(servers))
+	}
+}
+
 func TestMCPConfigManager_BuildFromProfile(t *testing.T) {
 	tests := []struct {
 		name           string
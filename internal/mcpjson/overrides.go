@@ -0,0 +1,170 @@
+package mcpjson
+
+import (
+	"path"
+
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/server"
+)
+
+// IsEnabled reports whether the server should be written to MCP config files.
+// Servers are enabled unless their overrides explicitly disable them.
+func (r ServerRef) IsEnabled() bool {
+	return r.Overrides.Enabled == nil || *r.Overrides.Enabled
+}
+
+// IsEmpty reports whether no override is set
+func (o ServerOverrides) IsEmpty() bool {
+	return o.Command == "" && o.Args == nil && o.ArgsPrepend == nil && o.ArgsAppend == nil &&
+		len(o.Env) == 0 && o.Timeout == nil && o.TransportType == nil && o.EnvFile == nil && o.Enabled == nil &&
+		o.Tools == nil
+}
+
+// Apply returns mcpServer with the overrides applied.
+// Args replaces the template arguments; ArgsPrepend and ArgsAppend are then
+// added around the resulting list. Env entries are merged key by key.
+func (o ServerOverrides) Apply(mcpServer server.MCPServer) server.MCPServer {
+	if o.Command != "" {
+		mcpServer.Command = o.Command
+	}
+
+	args := mcpServer.Args
+	if o.Args != nil {
+		args = o.Args
+	}
+	if o.ArgsPrepend != nil || o.ArgsAppend != nil || o.Args != nil {
+		merged := make([]string, 0, len(o.ArgsPrepend)+len(args)+len(o.ArgsAppend))
+		merged = append(merged, o.ArgsPrepend...)
+		merged = append(merged, args...)
+		merged = append(merged, o.ArgsAppend...)
+		mcpServer.Args = merged
+	}
+
+	if len(o.Env) > 0 {
+		env := make(map[string]string, len(mcpServer.Env)+len(o.Env))
+		for k, v := range mcpServer.Env {
+			env[k] = v
+		}
+		for k, v := range o.Env {
+			env[k] = v
+		}
+		mcpServer.Env = env
+	}
+
+	if o.Timeout != nil {
+		mcpServer.Timeout = o.Timeout
+	}
+	if o.TransportType != nil {
+		mcpServer.TransportType = o.TransportType
+	}
+	if o.EnvFile != nil {
+		mcpServer.EnvFile = o.EnvFile
+	}
+
+	return mcpServer
+}
+
+// Merge returns o updated with every field set in other.
+// Env entries are merged key by key; an empty value removes the key.
+func (o ServerOverrides) Merge(other ServerOverrides) ServerOverrides {
+	if other.Command != "" {
+		o.Command = other.Command
+	}
+	if other.Args != nil {
+		o.Args = other.Args
+	}
+	if other.ArgsPrepend != nil {
+		o.ArgsPrepend = other.ArgsPrepend
+	}
+	if other.ArgsAppend != nil {
+		o.ArgsAppend = other.ArgsAppend
+	}
+
+	if len(other.Env) > 0 {
+		env := make(map[string]string, len(o.Env)+len(other.Env))
+		for k, v := range o.Env {
+			env[k] = v
+		}
+		for k, v := range other.Env {
+			if v == "" {
+				delete(env, k)
+				continue
+			}
+			env[k] = v
+		}
+		if len(env) == 0 {
+			env = nil
+		}
+		o.Env = env
+	}
+
+	if other.Timeout != nil {
+		o.Timeout = other.Timeout
+	}
+	if other.TransportType != nil {
+		o.TransportType = other.TransportType
+	}
+	if other.EnvFile != nil {
+		o.EnvFile = other.EnvFile
+	}
+	if other.Enabled != nil {
+		o.Enabled = other.Enabled
+		if *other.Enabled {
+			// 有効は既定値なので保存しない
+			o.Enabled = nil
+		}
+	}
+	if other.Tools != nil {
+		// Allow and Deny are replaced separately; empty lists clear them
+		tools := ToolFilter{}
+		if o.Tools != nil {
+			tools = *o.Tools
+		}
+		if other.Tools.Allow != nil {
+			tools.Allow = other.Tools.Allow
+		}
+		if other.Tools.Deny != nil {
+			tools.Deny = other.Tools.Deny
+		}
+		o.Tools = &tools
+		if len(tools.Allow) == 0 && len(tools.Deny) == 0 {
+			o.Tools = nil
+		}
+	}
+
+	return o
+}
+
+// Validate checks that the patterns are well formed
+func (f *ToolFilter) Validate() error {
+	if f == nil {
+		return nil
+	}
+	for _, pattern := range append(append([]string{}, f.Allow...), f.Deny...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return i18n.Errorf("mcpjson.invalid_tool_pattern", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Allows reports whether the tool passes the filter. A nil filter allows
+// every tool.
+func (f *ToolFilter) Allows(tool string) bool {
+	if f == nil {
+		return true
+	}
+	if len(f.Allow) > 0 && !matchAny(f.Allow, tool) {
+		return false
+	}
+	return !matchAny(f.Deny, tool)
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
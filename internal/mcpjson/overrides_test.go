@@ -0,0 +1,306 @@
+package mcpjson
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/naoto24kawa/mcpjson/internal/secret"
+	"github.com/naoto24kawa/mcpjson/internal/server"
+	"github.com/naoto24kawa/mcpjson/internal/utils"
+)
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func TestServerOverrides_Apply(t *testing.T) {
+	base := server.MCPServer{
+		Command: "npx",
+		Args:    []string{"-y", "@modelcontextprotocol/server-filesystem", "/tmp"},
+		Env:     map[string]string{"A": "1"},
+		Timeout: intPtr(30),
+	}
+
+	tests := []struct {
+		name      string
+		overrides ServerOverrides
+		want      server.MCPServer
+	}{
+		{
+			name:      "上書きなし",
+			overrides: ServerOverrides{},
+			want:      base,
+		},
+		{
+			name: "引数の置き換え",
+			overrides: ServerOverrides{
+				Args: []string{"-y", "@modelcontextprotocol/server-filesystem", "/work"},
+			},
+			want: server.MCPServer{
+				Command: "npx",
+				Args:    []string{"-y", "@modelcontextprotocol/server-filesystem", "/work"},
+				Env:     map[string]string{"A": "1"},
+				Timeout: intPtr(30),
+			},
+		},
+		{
+			name: "引数の前後への追加",
+			overrides: ServerOverrides{
+				ArgsPrepend: []string{"--inspect"},
+				ArgsAppend:  []string{"/home"},
+			},
+			want: server.MCPServer{
+				Command: "npx",
+				Args:    []string{"--inspect", "-y", "@modelcontextprotocol/server-filesystem", "/tmp", "/home"},
+				Env:     map[string]string{"A": "1"},
+				Timeout: intPtr(30),
+			},
+		},
+		{
+			name: "コマンド・タイムアウト・環境変数",
+			overrides: ServerOverrides{
+				Command:       "bunx",
+				Env:           map[string]string{"B": "2"},
+				Timeout:       intPtr(120),
+				TransportType: stringPtr("stdio"),
+				EnvFile:       stringPtr(".env"),
+			},
+			want: server.MCPServer{
+				Command:       "bunx",
+				Args:          []string{"-y", "@modelcontextprotocol/server-filesystem", "/tmp"},
+				Env:           map[string]string{"A": "1", "B": "2"},
+				Timeout:       intPtr(120),
+				TransportType: stringPtr("stdio"),
+				EnvFile:       stringPtr(".env"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.overrides.Apply(base)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apply() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if len(base.Args) != 3 || base.Env["B"] != "" {
+		t.Errorf("Apply() must not modify the template: %+v", base)
+	}
+}
+
+func TestServerOverrides_Merge(t *testing.T) {
+	existing := ServerOverrides{
+		Args:    []string{"/tmp"},
+		Env:     map[string]string{"A": "1", "B": "2"},
+		Enabled: boolPtr(false),
+	}
+
+	merged := existing.Merge(ServerOverrides{
+		ArgsAppend: []string{"--verbose"},
+		Env:        map[string]string{"B": "", "C": "3"},
+		Timeout:    intPtr(60),
+		Enabled:    boolPtr(true),
+	})
+
+	want := ServerOverrides{
+		Args:       []string{"/tmp"},
+		ArgsAppend: []string{"--verbose"},
+		Env:        map[string]string{"A": "1", "C": "3"},
+		Timeout:    intPtr(60),
+	}
+	if !reflect.DeepEqual(merged, want) {
+		t.Errorf("Merge() = %+v, want %+v", merged, want)
+	}
+}
+
+func TestServerOverrides_Merge_Tools(t *testing.T) {
+	existing := ServerOverrides{Tools: &ToolFilter{Allow: []string{"search_*"}, Deny: []string{"search_admin"}}}
+
+	tests := []struct {
+		name  string
+		other *ToolFilter
+		want  *ToolFilter
+	}{
+		{name: "未指定は維持", other: nil, want: existing.Tools},
+		{name: "deny だけ置き換え", other: &ToolFilter{Deny: []string{"delete_*"}}, want: &ToolFilter{Allow: []string{"search_*"}, Deny: []string{"delete_*"}}},
+		{name: "空のリストで解除", other: &ToolFilter{Allow: []string{}, Deny: []string{}}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged := existing.Merge(ServerOverrides{Tools: tt.other})
+			if !reflect.DeepEqual(merged.Tools, tt.want) {
+				t.Errorf("Merge().Tools = %+v, want %+v", merged.Tools, tt.want)
+			}
+		})
+	}
+}
+
+func TestToolFilter_Allows(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter *ToolFilter
+		tool   string
+		want   bool
+	}{
+		{name: "フィルタなし", filter: nil, tool: "delete_repo", want: true},
+		{name: "allow に一致", filter: &ToolFilter{Allow: []string{"search_*", "get_issue"}}, tool: "search_code", want: true},
+		{name: "allow に不一致", filter: &ToolFilter{Allow: []string{"search_*"}}, tool: "delete_repo", want: false},
+		{name: "deny に一致", filter: &ToolFilter{Deny: []string{"delete_*"}}, tool: "delete_repo", want: false},
+		{name: "deny が allow より優先", filter: &ToolFilter{Allow: []string{"*"}, Deny: []string{"delete_*"}}, tool: "delete_repo", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Allows(tt.tool); got != tt.want {
+				t.Errorf("Allows(%q) = %v, want %v", tt.tool, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToolFilter_Validate(t *testing.T) {
+	if err := (&ToolFilter{Allow: []string{"search_*", "get_?"}}).Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	if err := (&ToolFilter{Deny: []string{"[delete"}}).Validate(); err == nil {
+		t.Error("Validate() should reject a malformed pattern")
+	}
+}
+
+func TestMCPConfigManager_BuildFromProfile_Overrides(t *testing.T) {
+	tempDir := t.TempDir()
+	serverManager := server.NewManager(tempDir)
+
+	template := createTestServerTemplate()
+	if err := utils.SaveJSON(filepath.Join(tempDir, "test-template.jsonc"), template); err != nil {
+		t.Fatalf("Failed to save test template: %v", err)
+	}
+
+	profile := &ProfileData{
+		Name:      "overrides",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		Servers: []ServerRef{
+			{
+				Name:     "custom",
+				Template: "test-template",
+				Overrides: ServerOverrides{
+					ArgsAppend: []string{"--root", "/work"},
+					Timeout:    intPtr(90),
+				},
+			},
+			{
+				Name:      "disabled",
+				Template:  "missing-template",
+				Overrides: ServerOverrides{Enabled: boolPtr(false)},
+			},
+		},
+	}
+
+	mcpConfig, err := NewMCPConfigManager().BuildFromProfile(profile, serverManager)
+	if err != nil {
+		t.Fatalf("BuildFromProfile() failed: %v", err)
+	}
+
+	if _, exists := mcpConfig.McpServers["disabled"]; exists {
+		t.Error("disabled server should not be written")
+	}
+	custom := mcpConfig.McpServers["custom"]
+	if !reflect.DeepEqual(custom.Args, []string{"-m", "test", "--root", "/work"}) {
+		t.Errorf("args = %v", custom.Args)
+	}
+	if custom.Timeout == nil || *custom.Timeout != 90 {
+		t.Errorf("timeout = %v, want 90", custom.Timeout)
+	}
+}
+
+func TestMCPConfigManager_BuildFromProfile_Variables(t *testing.T) {
+	tempDir := t.TempDir()
+	serverManager := server.NewManager(tempDir)
+
+	template := createTestServerTemplate()
+	template.ServerConfig.Args = []string{"--root", "${profile.root}"}
+	template.ServerConfig.Env = map[string]string{"TOKEN": "${env:MCPJSON_TEST_BUILD_TOKEN}"}
+	if err := utils.SaveJSON(filepath.Join(tempDir, "test-template.jsonc"), template); err != nil {
+		t.Fatalf("Failed to save test template: %v", err)
+	}
+
+	profile := &ProfileData{
+		Name:    "vars",
+		Vars:    map[string]string{"root": "/work"},
+		Servers: []ServerRef{{Name: "fs", Template: "test-template"}},
+	}
+
+	t.Setenv("MCPJSON_TEST_BUILD_TOKEN", "secret")
+	mcpConfig, err := NewMCPConfigManager().BuildFromProfile(profile, serverManager)
+	if err != nil {
+		t.Fatalf("BuildFromProfile() failed: %v", err)
+	}
+	fs := mcpConfig.McpServers["fs"]
+	if fs.Args[1] != "/work" || fs.Env["TOKEN"] != "secret" {
+		t.Errorf("placeholders not resolved: %+v", fs)
+	}
+
+	profile.Vars = nil
+	t.Setenv("MCPJSON_TEST_BUILD_TOKEN", "")
+	os.Unsetenv("MCPJSON_TEST_BUILD_TOKEN")
+	_, err = NewMCPConfigManager().BuildFromProfile(profile, serverManager)
+	if err == nil {
+		t.Fatal("BuildFromProfile() expected error for missing variables")
+	}
+	for _, want := range []string{"${profile.root}", "${env:MCPJSON_TEST_BUILD_TOKEN}"} {
+		if !contains(err.Error(), want) {
+			t.Errorf("error should list %s: %v", want, err)
+		}
+	}
+}
+
+type mapSecrets map[string]string
+
+func (m mapSecrets) Get(name string) (string, error) {
+	if value, ok := m[name]; ok {
+		return value, nil
+	}
+	return "", secret.ErrNotFound
+}
+
+func TestMCPConfigManager_BuildFromProfile_Secrets(t *testing.T) {
+	tempDir := t.TempDir()
+	serverManager := server.NewManager(tempDir)
+
+	template := createTestServerTemplate()
+	template.ServerConfig.Env = map[string]string{"TOKEN": "${secret:default-token}"}
+	if err := utils.SaveJSON(filepath.Join(tempDir, "test-template.jsonc"), template); err != nil {
+		t.Fatalf("Failed to save test template: %v", err)
+	}
+
+	profile := &ProfileData{
+		Name:    "secrets",
+		Servers: []ServerRef{{Name: "gh", Template: "test-template", Overrides: ServerOverrides{Env: map[string]string{"TOKEN": "${secret:work-token}"}}}},
+	}
+
+	if _, err := NewMCPConfigManager().BuildFromProfile(profile, serverManager); err == nil {
+		t.Fatal("BuildFromProfile() without a secret provider expected error")
+	}
+
+	serverManager.SetSecretProvider(mapSecrets{"work-token": "s3cr3t"})
+	mcpConfig, err := NewMCPConfigManager().BuildFromProfile(profile, serverManager)
+	if err != nil {
+		t.Fatalf("BuildFromProfile() failed: %v", err)
+	}
+	if got := mcpConfig.McpServers["gh"].Env["TOKEN"]; got != "s3cr3t" {
+		t.Errorf("TOKEN = %q, want s3cr3t", got)
+	}
+
+	profile.Servers[0].Overrides.Env["TOKEN"] = "$${secret:work-token}"
+	_, err = NewMCPConfigManager().BuildFromProfile(profile, serverManager)
+	if err == nil || !contains(err.Error(), "未解決のシークレット参照") {
+		t.Errorf("BuildFromProfile() error = %v, want unresolved secret refusal", err)
+	}
+}
@@ -0,0 +1,320 @@
+// Package mcpserve runs mcpjson as a stdio MCP server, so that agents can
+// switch their own toolsets through the tools it offers.
+package mcpserve
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+)
+
+const (
+	// ProtocolVersion is the MCP revision the server speaks
+	ProtocolVersion = "2025-03-26"
+
+	// AllowAll in an allow-list exposes every tool
+	AllowAll = "*"
+	// ConfirmArgument is the argument destructive tools have to be called
+	// with, set to true
+	ConfirmArgument = "confirm"
+
+	// maxMessage bounds the size of a single request
+	maxMessage = 4 << 20
+)
+
+// JSON-RPC error codes
+const (
+	codeParseError     = -32700
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// Tool is a tool offered to clients
+type Tool struct {
+	Name        string
+	Description string
+	// InputSchema is the JSON Schema of the arguments
+	InputSchema map[string]interface{}
+	// ReadOnly tools do not change any file
+	ReadOnly bool
+	// Destructive tools may remove or replace what is in a file. They only
+	// run when called with the confirm argument set to true.
+	Destructive bool
+	// Annotations replaces the hints derived from ReadOnly and Destructive.
+	// A Forward tool without them is listed without annotations.
+	Annotations json.RawMessage
+	// Handler runs the tool and returns the text given back to the client
+	Handler func(ctx context.Context, args json.RawMessage) (string, error)
+	// Forward is used instead of Handler to return a whole tools/call
+	// result, such as one relayed from another server
+	Forward func(ctx context.Context, args json.RawMessage) (json.RawMessage, error)
+}
+
+// Allow returns the tools named in allow, in their original order. An
+// empty allow-list exposes the read-only tools and AllowAll every tool.
+func Allow(tools []Tool, allow []string) ([]Tool, error) {
+	names := make(map[string]bool, len(tools))
+	for _, tool := range tools {
+		names[tool.Name] = true
+	}
+
+	allowed := make(map[string]bool, len(allow))
+	for _, name := range allow {
+		name = strings.TrimSpace(name)
+		if name == AllowAll {
+			return tools, nil
+		}
+		if !names[name] {
+			valid := make([]string, 0, len(tools))
+			for _, tool := range tools {
+				valid = append(valid, tool.Name)
+			}
+			sort.Strings(valid)
+			return nil, i18n.Errorf("mcpserve.unknown_allow", name, strings.Join(valid, ", "))
+		}
+		allowed[name] = true
+	}
+
+	result := make([]Tool, 0, len(tools))
+	for _, tool := range tools {
+		if allowed[tool.Name] || (len(allowed) == 0 && tool.ReadOnly) {
+			result = append(result, tool)
+		}
+	}
+	return result, nil
+}
+
+// Server answers MCP requests with a fixed set of tools
+type Server struct {
+	name    string
+	version string
+	tools   []Tool
+	// mu serializes the responses of tool calls running concurrently
+	mu sync.Mutex
+	// writes serializes the handlers of tools that change files, since
+	// file locks do not exclude goroutines of the same process
+	writes sync.Mutex
+}
+
+// NewServer returns a server that introduces itself as name and version
+func NewServer(name, version string, tools []Tool) *Server {
+	return &Server{name: name, version: version, tools: tools}
+}
+
+type request struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Serve reads newline-delimited JSON-RPC requests from r and writes the
+// responses to w until r is closed or ctx is done. Tool calls run
+// concurrently, except that tools which are not ReadOnly run one at a
+// time; Serve returns once they have all been answered.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxMessage)
+	encoder := json.NewEncoder(w)
+
+	var calls sync.WaitGroup
+	defer calls.Wait()
+
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return nil
+		}
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var req request
+		if err := json.Unmarshal(line, &req); err != nil {
+			message := i18n.T("mcpserve.parse_error", err)
+			if err := s.reply(encoder, json.RawMessage("null"), nil, &rpcError{Code: codeParseError, Message: message}); err != nil {
+				return err
+			}
+			continue
+		}
+		// Notifications such as notifications/initialized need no answer
+		if len(req.ID) == 0 {
+			continue
+		}
+
+		if req.Method == "tools/call" {
+			calls.Add(1)
+			go func() {
+				defer calls.Done()
+				result, rpcErr := s.call(ctx, req.Params)
+				_ = s.reply(encoder, req.ID, result, rpcErr)
+			}()
+			continue
+		}
+		result, rpcErr := s.handle(req)
+		if err := s.reply(encoder, req.ID, result, rpcErr); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// ServeStdio answers MCP requests on stdin and stdout. The managers report
+// progress on stdout, which carries the protocol, so it points to stderr
+// meanwhile.
+func (s *Server) ServeStdio(ctx context.Context) error {
+	out := os.Stdout
+	os.Stdout = os.Stderr
+	defer func() { os.Stdout = out }()
+
+	return s.Serve(ctx, os.Stdin, out)
+}
+
+func (s *Server) reply(encoder *json.Encoder, id json.RawMessage, result interface{}, rpcErr *rpcError) error {
+	response := map[string]interface{}{"jsonrpc": "2.0", "id": id}
+	if rpcErr != nil {
+		response["error"] = rpcErr
+	} else {
+		response["result"] = result
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return encoder.Encode(response)
+}
+
+func (s *Server) handle(req request) (interface{}, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		return map[string]interface{}{
+			"protocolVersion": ProtocolVersion,
+			"capabilities":    map[string]interface{}{"tools": map[string]interface{}{}},
+			"serverInfo":      map[string]string{"name": s.name, "version": s.version},
+		}, nil
+	case "ping":
+		return map[string]interface{}{}, nil
+	case "tools/list":
+		tools := make([]map[string]interface{}, 0, len(s.tools))
+		for _, tool := range s.tools {
+			tools = append(tools, describe(tool))
+		}
+		return map[string]interface{}{"tools": tools}, nil
+	}
+	return nil, &rpcError{Code: codeMethodNotFound, Message: i18n.T("mcpserve.method_not_found", req.Method)}
+}
+
+// call runs a tool. Failures of the tool are reported in the result so
+// that the client can show them to the model.
+func (s *Server) call(ctx context.Context, params json.RawMessage) (interface{}, *rpcError) {
+	var call struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &call); err != nil {
+		return nil, &rpcError{Code: codeInvalidParams, Message: i18n.T("mcpserve.invalid_params", err)}
+	}
+	if len(call.Arguments) == 0 || string(call.Arguments) == "null" {
+		call.Arguments = json.RawMessage("{}")
+	}
+
+	tool, ok := s.lookup(call.Name)
+	if !ok {
+		return nil, &rpcError{Code: codeInvalidParams, Message: i18n.T("mcpserve.unknown_tool", call.Name)}
+	}
+	if tool.Destructive && !confirmed(call.Arguments) {
+		return toolResult(i18n.T("mcpserve.confirm_required", tool.Name, ConfirmArgument), true), nil
+	}
+
+	if tool.Forward != nil {
+		result, err := tool.Forward(ctx, call.Arguments)
+		if err != nil {
+			return toolResult(err.Error(), true), nil
+		}
+		return result, nil
+	}
+	if !tool.ReadOnly {
+		s.writes.Lock()
+		defer s.writes.Unlock()
+	}
+	text, err := tool.Handler(ctx, call.Arguments)
+	if err != nil {
+		return toolResult(err.Error(), true), nil
+	}
+	return toolResult(text, false), nil
+}
+
+func (s *Server) lookup(name string) (Tool, bool) {
+	for _, tool := range s.tools {
+		if tool.Name == name {
+			return tool, true
+		}
+	}
+	return Tool{}, false
+}
+
+// describe returns the tools/list entry of the tool. Destructive tools get
+// the confirm argument added to their schema.
+func describe(tool Tool) map[string]interface{} {
+	schema := map[string]interface{}{"type": "object"}
+	for key, value := range tool.InputSchema {
+		schema[key] = value
+	}
+	if tool.Destructive {
+		properties := map[string]interface{}{}
+		if existing, ok := schema["properties"].(map[string]interface{}); ok {
+			for key, value := range existing {
+				properties[key] = value
+			}
+		}
+		properties[ConfirmArgument] = map[string]interface{}{
+			"type":        "boolean",
+			"description": i18n.T("mcpserve.confirm_description"),
+		}
+		schema["properties"] = properties
+	}
+
+	description := map[string]interface{}{
+		"name":        tool.Name,
+		"description": tool.Description,
+		"inputSchema": schema,
+	}
+	switch {
+	case len(tool.Annotations) > 0:
+		description["annotations"] = tool.Annotations
+	case tool.Forward == nil:
+		// The hints of a relayed tool are unknown unless its server sent
+		// some, so they are only filled in for the tools defined here
+		description["annotations"] = map[string]interface{}{
+			"readOnlyHint":    tool.ReadOnly,
+			"destructiveHint": tool.Destructive,
+		}
+	}
+	return description
+}
+
+func confirmed(args json.RawMessage) bool {
+	var parsed map[string]json.RawMessage
+	if json.Unmarshal(args, &parsed) != nil {
+		return false
+	}
+	return string(parsed[ConfirmArgument]) == "true"
+}
+
+func toolResult(text string, isError bool) map[string]interface{} {
+	return map[string]interface{}{
+		"content": []map[string]string{{"type": "text", "text": text}},
+		"isError": isError,
+	}
+}
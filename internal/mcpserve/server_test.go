@@ -0,0 +1,173 @@
+package mcpserve
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testTools() []Tool {
+	echo := func(ctx context.Context, args json.RawMessage) (string, error) {
+		return string(args), nil
+	}
+	return []Tool{
+		{Name: "list", ReadOnly: true, Handler: echo},
+		{Name: "add", Handler: echo},
+		{Name: "apply", Destructive: true, InputSchema: map[string]interface{}{
+			"properties": map[string]interface{}{"profile": map[string]interface{}{"type": "string"}},
+		}, Handler: echo},
+		{Name: "fail", ReadOnly: true, Handler: func(ctx context.Context, args json.RawMessage) (string, error) {
+			return "", errors.New("profile 'x' not found")
+		}},
+	}
+}
+
+// testResponse holds the parts of a response the tests look at
+type testResponse struct {
+	ID     json.RawMessage `json:"id"`
+	Result struct {
+		ServerInfo struct {
+			Name string `json:"name"`
+		} `json:"serverInfo"`
+		Tools []struct {
+			Name        string `json:"name"`
+			InputSchema struct {
+				Properties map[string]interface{} `json:"properties"`
+			} `json:"inputSchema"`
+		} `json:"tools"`
+		Content []struct {
+			Text string `json:"text"`
+		} `json:"content"`
+		IsError bool `json:"isError"`
+	} `json:"result"`
+	Error *rpcError `json:"error"`
+}
+
+func TestAllow(t *testing.T) {
+	tests := []struct {
+		name    string
+		allow   []string
+		want    string
+		wantErr bool
+	}{
+		{name: "未指定は読み取り専用のみ", allow: nil, want: "list,fail"},
+		{name: "名前で指定", allow: []string{"apply", " list"}, want: "list,apply"},
+		{name: "すべて", allow: []string{AllowAll}, want: "list,add,apply,fail"},
+		{name: "不明なツール", allow: []string{"delete"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tools, err := Allow(testTools(), tt.allow)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Allow() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var names []string
+			for _, tool := range tools {
+				names = append(names, tool.Name)
+			}
+			if got := strings.Join(names, ","); got != tt.want {
+				t.Errorf("Allow() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServer_Serve(t *testing.T) {
+	requests := []string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26"}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"list"}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"apply","arguments":{"profile":"work"}}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"apply","arguments":{"profile":"work","confirm":true}}}`,
+		`{"jsonrpc":"2.0","id":6,"method":"tools/call","params":{"name":"fail"}}`,
+		`{"jsonrpc":"2.0","id":"7","method":"tools/call","params":{"name":"add"}}`,
+		`{"jsonrpc":"2.0","id":8,"method":"resources/list"}`,
+		`not json`,
+	}
+	tools, err := Allow(testTools(), []string{"list", "apply", "fail"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	in := strings.NewReader(strings.Join(requests, "\n") + "\n")
+	if err := NewServer("mcpjson", "v1.0.0", tools).Serve(context.Background(), in, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	// Tool calls are answered as they finish, so responses are looked up by id
+	responses := map[string]testResponse{}
+	decoder := json.NewDecoder(&out)
+	for decoder.More() {
+		var response testResponse
+		if err := decoder.Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		responses[string(response.ID)] = response
+	}
+
+	if len(responses) != 9 {
+		t.Fatalf("got %d responses, want one per request with an id and one for the parse error:\n%s", len(responses), out.String())
+	}
+	if responses["1"].Result.ServerInfo.Name != "mcpjson" {
+		t.Errorf("initialize result = %+v", responses["1"].Result)
+	}
+	if tools := responses["2"].Result.Tools; len(tools) != 3 || tools[1].Name != "apply" || tools[1].InputSchema.Properties[ConfirmArgument] == nil {
+		t.Errorf("tools/list = %+v, want the allowed tools with confirm added to apply", tools)
+	}
+	if r := responses["3"].Result; r.IsError || r.Content[0].Text != "{}" {
+		t.Errorf("call without arguments = %+v", r)
+	}
+	if r := responses["4"].Result; !r.IsError || !strings.Contains(r.Content[0].Text, ConfirmArgument) {
+		t.Errorf("destructive call without confirm = %+v, want it refused", r)
+	}
+	if r := responses["5"].Result; r.IsError || !strings.Contains(r.Content[0].Text, `"profile":"work"`) {
+		t.Errorf("destructive call with confirm = %+v", r)
+	}
+	if r := responses["6"].Result; !r.IsError || r.Content[0].Text != "profile 'x' not found" {
+		t.Errorf("failing call = %+v, want the error in the result", r)
+	}
+	if r := responses[`"7"`]; r.Error == nil || r.Error.Code != codeInvalidParams {
+		t.Errorf("call of a tool that is not allowed = %+v", r)
+	}
+	if r := responses["8"]; r.Error == nil || r.Error.Code != codeMethodNotFound {
+		t.Errorf("unknown method = %+v", r)
+	}
+	if r := responses["null"]; r.Error == nil || r.Error.Code != codeParseError {
+		t.Errorf("invalid message = %+v", r)
+	}
+}
+
+func TestDescribe_Annotations(t *testing.T) {
+	forward := func(ctx context.Context, args json.RawMessage) (json.RawMessage, error) { return nil, nil }
+	tests := []struct {
+		name string
+		tool Tool
+		want string
+	}{
+		{name: "組み込みのツール", tool: Tool{Name: "list", ReadOnly: true}, want: `{"destructiveHint":false,"readOnlyHint":true}`},
+		{name: "中継先の注釈", tool: Tool{Name: "git_push", Annotations: json.RawMessage(`{"destructiveHint":true}`), Forward: forward}, want: `{"destructiveHint":true}`},
+		{name: "注釈のない中継ツール", tool: Tool{Name: "git_log", Forward: forward}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			annotations, ok := describe(tt.tool)["annotations"]
+			if tt.want == "" {
+				if ok {
+					t.Errorf("annotations = %v, want none", annotations)
+				}
+				return
+			}
+			got, err := json.Marshal(annotations)
+			if err != nil || string(got) != tt.want {
+				t.Errorf("annotations = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
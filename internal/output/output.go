@@ -0,0 +1,99 @@
+// Package output renders the results of read commands as human-readable
+// tables or as JSON / YAML for scripts.
+//
+// JSON and YAML are produced from the same values, using their json tags,
+// so both formats share one schema.
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is an output format selected with --output
+type Format string
+
+const (
+	FormatTable Format = "table"
+	FormatJSON  Format = "json"
+	FormatYAML  Format = "yaml"
+)
+
+// ParseFormat validates a format given on the command line
+func ParseFormat(s string) (Format, error) {
+	switch format := Format(strings.ToLower(s)); format {
+	case FormatTable, FormatJSON, FormatYAML:
+		return format, nil
+	case "yml":
+		return FormatYAML, nil
+	}
+	return "", i18n.Errorf("output.unknown_format", s, FormatTable, FormatJSON, FormatYAML)
+}
+
+// IsStructured reports whether the format is meant for programs
+func (f Format) IsStructured() bool {
+	return f == FormatJSON || f == FormatYAML
+}
+
+// Write encodes v as JSON or YAML. Nil slices are written as empty lists.
+func Write(w io.Writer, format Format, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return i18n.Errorf("common.json_failed", err)
+	}
+	if bytes.Equal(data, []byte("null")) {
+		data = []byte("[]")
+	}
+
+	switch format {
+	case FormatJSON:
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	case FormatYAML:
+		out, err := toYAML(data)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(out)
+		return err
+	}
+	return i18n.Errorf("output.not_structured", format)
+}
+
+// toYAML converts a JSON document to block-style YAML, keeping the key
+// order of the JSON document
+func toYAML(data []byte) ([]byte, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, i18n.Errorf("output.yaml_failed", err)
+	}
+	resetStyle(&node)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return nil, i18n.Errorf("output.yaml_failed", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, i18n.Errorf("output.yaml_failed", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// resetStyle drops the flow and quoting styles taken from the JSON syntax.
+// Empty collections keep the flow style so they are written as [] and {}.
+func resetStyle(node *yaml.Node) {
+	if len(node.Content) > 0 || node.Kind == yaml.ScalarNode {
+		node.Style = 0
+	}
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
@@ -0,0 +1,138 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+type testItem struct {
+	Name    string    `json:"name"`
+	Count   int       `json:"count"`
+	Version string    `json:"version"`
+	Tags    []string  `json:"tags"`
+	Created time.Time `json:"createdAt"`
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Format
+		wantErr bool
+	}{
+		{input: "table", want: FormatTable},
+		{input: "json", want: FormatJSON},
+		{input: "YAML", want: FormatYAML},
+		{input: "yml", want: FormatYAML},
+		{input: "xml", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseFormat(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFormat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseFormat() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	items := []testItem{{Name: "github", Count: 2, Version: "1.0", Tags: []string{}, Created: created}}
+
+	tests := []struct {
+		name   string
+		format Format
+		value  interface{}
+		want   string
+	}{
+		{
+			name:   "JSON",
+			format: FormatJSON,
+			value:  items,
+			want: `[
+  {
+    "name": "github",
+    "count": 2,
+    "version": "1.0",
+    "tags": [],
+    "createdAt": "2024-01-02T03:04:05Z"
+  }
+]
+`,
+		},
+		{
+			name:   "YAMLはJSONのキー順を保持し、数値に見える文字列を引用する",
+			format: FormatYAML,
+			value:  items,
+			want: `- name: github
+  count: 2
+  version: "1.0"
+  tags: []
+  createdAt: "2024-01-02T03:04:05Z"
+`,
+		},
+		{
+			name:   "nilのスライスは空のリスト",
+			format: FormatJSON,
+			value:  []testItem(nil),
+			want:   "[]\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Write(&buf, tt.format, tt.value); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("Write() =\n%s\nwant\n%s", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestTable_Write(t *testing.T) {
+	table := NewTable("プロファイル名", "サーバー数")
+	table.AddRow("a-very-long-profile-name", "3")
+	table.AddRow("短い", "10")
+
+	var buf bytes.Buffer
+	if err := table.Write(&buf); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	want := `プロファイル名            サーバー数
+------------------------  ----------
+a-very-long-profile-name  3
+短い                      10
+`
+	if buf.String() != want {
+		t.Errorf("Write() =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int
+	}{
+		{input: "abc", want: 3},
+		{input: "作成日時", want: 8},
+		{input: "ＡＢ", want: 4},
+		{input: "a-日本", want: 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := DisplayWidth(tt.input); got != tt.want {
+				t.Errorf("DisplayWidth(%q) = %d, want %d", tt.input, got, tt.want)
+			}
+		})
+	}
+}
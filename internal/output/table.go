@@ -0,0 +1,108 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+const (
+	// columnGap is the number of spaces between columns
+	columnGap = 2
+	// separatorChar draws the line under the header
+	separatorChar = "-"
+)
+
+// Table is a human-readable table whose columns are sized from its content
+type Table struct {
+	headers []string
+	rows    [][]string
+}
+
+// NewTable creates a table with the given column headers
+func NewTable(headers ...string) *Table {
+	return &Table{headers: headers}
+}
+
+// AddRow appends a row. Missing cells are left empty.
+func (t *Table) AddRow(cells ...string) {
+	t.rows = append(t.rows, cells)
+}
+
+// Len returns the number of rows
+func (t *Table) Len() int {
+	return len(t.rows)
+}
+
+// Write renders the table with a separator line under the header
+func (t *Table) Write(w io.Writer) error {
+	widths := make([]int, len(t.headers))
+	for i, header := range t.headers {
+		widths[i] = DisplayWidth(header)
+	}
+	for _, row := range t.rows {
+		for i := 0; i < len(row) && i < len(widths); i++ {
+			if width := DisplayWidth(row[i]); width > widths[i] {
+				widths[i] = width
+			}
+		}
+	}
+
+	separators := make([]string, len(widths))
+	for i, width := range widths {
+		separators[i] = strings.Repeat(separatorChar, width)
+	}
+
+	lines := append([][]string{t.headers, separators}, t.rows...)
+	for _, cells := range lines {
+		if _, err := fmt.Fprintln(w, formatLine(cells, widths)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatLine(cells []string, widths []int) string {
+	var b strings.Builder
+	for i, width := range widths {
+		cell := ""
+		if i < len(cells) {
+			cell = cells[i]
+		}
+		b.WriteString(cell)
+		if i < len(widths)-1 {
+			b.WriteString(strings.Repeat(" ", width-DisplayWidth(cell)+columnGap))
+		}
+	}
+	return strings.TrimRight(b.String(), " ")
+}
+
+// DisplayWidth returns the number of terminal columns s occupies. East
+// Asian wide characters such as kanji and kana take two columns.
+func DisplayWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		switch {
+		case r < 0x20 || unicode.Is(unicode.Mn, r):
+		case isWide(r):
+			width += 2
+		default:
+			width++
+		}
+	}
+	return width
+}
+
+func isWide(r rune) bool {
+	return r >= 0x1100 && (r <= 0x115f || // ハングル字母
+		(r >= 0x2e80 && r <= 0xa4cf && r != 0x303f) || // CJK部首〜CJK統合漢字・イ文字
+		(r >= 0xac00 && r <= 0xd7a3) || // ハングル音節
+		(r >= 0xf900 && r <= 0xfaff) || // CJK互換漢字
+		(r >= 0xfe30 && r <= 0xfe4f) || // CJK互換形
+		(r >= 0xff00 && r <= 0xff60) || // 全角英数・記号
+		(r >= 0xffe0 && r <= 0xffe6) ||
+		(r >= 0x1f300 && r <= 0x1f64f) || // 絵文字
+		(r >= 0x1f900 && r <= 0x1f9ff) ||
+		(r >= 0x20000 && r <= 0x3fffd))
+}
@@ -2,44 +2,51 @@ package profile
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/filelock"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
 	"github.com/naoto24kawa/mcpjson/internal/interaction"
 	"github.com/naoto24kawa/mcpjson/internal/mcpjson"
+	"github.com/naoto24kawa/mcpjson/internal/output"
+	"github.com/naoto24kawa/mcpjson/internal/provenance"
+	"github.com/naoto24kawa/mcpjson/internal/proxy"
 	"github.com/naoto24kawa/mcpjson/internal/server"
 	"github.com/naoto24kawa/mcpjson/internal/utils"
 )
 
 const (
-	// 一覧表示時のプロファイル名カラム幅
-	ListColumnWidth = 20
-	// 一覧表示時の日時カラム幅
-	DateColumnWidth = 20
 	// 日時フォーマット（RFC3339形式）
 	TimestampFormat = time.RFC3339
-	// テーブル区切り文字
-	TableSeparatorChar = "-"
-	// テーブル区切り線の幅
-	TableSeparatorWidth = 60
 )
 
 type Profile struct {
-	Name        string      `json:"name"`
-	Description string      `json:"description"`
-	CreatedAt   time.Time   `json:"createdAt"`
-	UpdatedAt   time.Time   `json:"updatedAt"`
-	Servers     []ServerRef `json:"servers"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	CreatedAt   time.Time         `json:"createdAt"`
+	UpdatedAt   time.Time         `json:"updatedAt"`
+	Extends     []string          `json:"extends,omitempty"`
+	Vars        map[string]string `json:"vars,omitempty"`
+	Servers     []ServerRef       `json:"servers"`
 }
 
 type ServerRef = mcpjson.ServerRef
 type ServerOverrides = mcpjson.ServerOverrides
+type ToolFilter = mcpjson.ToolFilter
 
 type Manager struct {
 	profilesDir string
+	readDirs    []string
+	// store and projectDir are passed to the proxy entry (see proxy.Entry)
+	store      string
+	projectDir string
 }
 
 func NewManager(profilesDir string) *Manager {
@@ -48,11 +55,28 @@ func NewManager(profilesDir string) *Manager {
 	}
 }
 
+// UseConfig makes profile reads fall through the stores of cfg. Writes
+// still go to the directory the manager was created with. The selected
+// store and the project are also passed on to proxy entries.
+func (m *Manager) UseConfig(cfg *config.Config) {
+	m.readDirs = cfg.ProfileDirs()
+	m.store = cfg.Store
+	if cfg.Project != nil {
+		m.projectDir = cfg.Project.Dir()
+	}
+}
+
 func (m *Manager) Create(name, description string) error {
+	unlock, err := m.lockStore()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	profilePath := m.getProfilePath(name)
 
 	if _, err := os.Stat(profilePath); err == nil {
-		return fmt.Errorf("プロファイル '%s' は既に存在します", name)
+		return i18n.Errorf("profile.exists", name)
 	}
 
 	profile := &Profile{
@@ -67,11 +91,23 @@ func (m *Manager) Create(name, description string) error {
 }
 
 func (m *Manager) Save(name string, mcpConfigPath string, serverManager *server.Manager, force bool) error {
+	return m.SaveFrom(name, mcpConfigPath, nil, serverManager, force)
+}
+
+// SaveFrom creates a profile from the servers in mcpConfigPath, read in the
+// client's format. A nil client reads a plain .mcp.json.
+func (m *Manager) SaveFrom(name string, mcpConfigPath string, client server.ClientAdapter, serverManager *server.Manager, force bool) error {
+	unlock, err := m.lockStore()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	if err := m.validateProfileCreation(name, force); err != nil {
 		return err
 	}
 
-	profile, err := m.buildProfileFromMCP(name, mcpConfigPath, serverManager)
+	profile, err := m.buildProfileFromMCP(name, mcpConfigPath, client, serverManager)
 	if err != nil {
 		return err
 	}
@@ -85,19 +121,19 @@ func (m *Manager) Save(name string, mcpConfigPath string, serverManager *server.
 }
 
 func (m *Manager) printSaveSuccess(name string, serverCount int) {
-	fmt.Printf("プロファイル '%s' を保存しました (%d個のサーバー)\n", name, serverCount)
+	fmt.Println(i18n.T("profile.saved", name, serverCount))
 }
 
-func (m *Manager) buildProfileFromMCP(name, mcpConfigPath string, serverManager *server.Manager) (*Profile, error) {
-	mcpConfig, err := m.loadMCPConfig(mcpConfigPath)
+func (m *Manager) buildProfileFromMCP(name, mcpConfigPath string, client server.ClientAdapter, serverManager *server.Manager) (*Profile, error) {
+	mcpConfig, err := m.loadMCPConfig(mcpConfigPath, client)
 	if err != nil {
-		return nil, fmt.Errorf("MCP設定の読み込みに失敗: %w", err)
+		return nil, i18n.Errorf("profile.mcp_read_failed", err)
 	}
 
 	profile := m.createProfileFromMCP(name, mcpConfigPath)
 
 	if err := m.processServers(profile, mcpConfig, serverManager); err != nil {
-		return nil, fmt.Errorf("サーバー処理に失敗: %w", err)
+		return nil, i18n.Errorf("profile.server_failed", err)
 	}
 
 	return profile, nil
@@ -107,21 +143,29 @@ func (m *Manager) validateProfileCreation(name string, force bool) error {
 	profilePath := filepath.Join(m.profilesDir, name+config.FileExtension)
 
 	if _, err := os.Stat(profilePath); err == nil && !force {
-		return fmt.Errorf("プロファイル '%s' は既に存在します。--force オプションで上書きしてください", name)
+		return i18n.Errorf("profile.exists_force", name)
 	}
 
 	return nil
 }
 
-func (m *Manager) loadMCPConfig(mcpConfigPath string) (*server.MCPConfig, error) {
-	mcpManager := mcpjson.NewMCPConfigManager()
-	return mcpManager.Load(mcpConfigPath)
+func (m *Manager) loadMCPConfig(mcpConfigPath string, client server.ClientAdapter) (*server.MCPConfig, error) {
+	if client == nil {
+		mcpManager := mcpjson.NewMCPConfigManager()
+		return mcpManager.Load(mcpConfigPath)
+	}
+
+	doc, err := server.LoadMCPDocumentFor(mcpConfigPath, client)
+	if err != nil {
+		return nil, err
+	}
+	return doc.Config()
 }
 
 func (m *Manager) createProfileFromMCP(name, mcpConfigPath string) *Profile {
 	return &Profile{
 		Name:        name,
-		Description: fmt.Sprintf("%s から保存", mcpConfigPath),
+		Description: i18n.T("profile.saved_description", mcpConfigPath),
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 		Servers:     []ServerRef{},
@@ -161,18 +205,21 @@ func (m *Manager) ensureServerTemplate(templateName string, mcpServer server.MCP
 		if err := m.createServerTemplate(templateName, mcpServer, serverManager); err != nil {
 			return err
 		}
-		fmt.Printf("サーバーテンプレート '%s' を作成しました\n", templateName)
+		fmt.Println(i18n.T("server.template_created", templateName))
 	} else {
-		fmt.Printf("サーバーテンプレート '%s' は既に存在するため、既存のものを使用します\n", templateName)
+		fmt.Println(i18n.T("profile.template_exists", templateName))
 	}
 	return nil
 }
 
 func (m *Manager) createServerTemplate(templateName string, mcpServer server.MCPServer, serverManager *server.Manager) error {
 	serverConfig := server.MCPServer{
+		Type:          mcpServer.Type,
 		Command:       mcpServer.Command,
 		Args:          mcpServer.Args,
 		Env:           mcpServer.Env,
+		URL:           mcpServer.URL,
+		Headers:       mcpServer.Headers,
 		Timeout:       mcpServer.Timeout,
 		EnvFile:       mcpServer.EnvFile,
 		TransportType: mcpServer.TransportType,
@@ -180,128 +227,312 @@ func (m *Manager) createServerTemplate(templateName string, mcpServer server.MCP
 	return serverManager.SaveFromConfig(templateName, serverConfig)
 }
 
+// ApplyOptions controls how a profile is written to an MCP config file
+type ApplyOptions struct {
+	Mode     mcpjson.ApplyMode
+	Conflict mcpjson.ConflictPolicy
+	// State records which servers mcpjson manages in each target file.
+	// When nil, every server in the file is treated as unmanaged.
+	State *provenance.Store
+	// Client is the format of the target file. When nil the file is a
+	// plain .mcp.json.
+	Client server.ClientAdapter
+	// ViaProxy writes a single entry running 'mcpjson proxy' for the
+	// profile instead of one entry per server
+	ViaProxy bool
+}
+
 func (m *Manager) Apply(name string, targetPath string, serverManager *server.Manager) error {
-	profile, err := m.Load(name)
+	return m.ApplyWithOptions(name, targetPath, serverManager, ApplyOptions{})
+}
+
+// ApplyWithOptions writes the profile to targetPath using the given mode
+// and conflict policy, and records the servers it wrote
+func (m *Manager) ApplyWithOptions(name string, targetPath string, serverManager *server.Manager, opts ApplyOptions) error {
+	mcpConfig, err := m.buildFor(name, serverManager, opts)
 	if err != nil {
 		return err
 	}
 
-	mcpManager := mcpjson.NewMCPConfigManager()
-	mcpConfig, err := mcpManager.BuildFromProfile((*mcpjson.ProfileData)(profile), serverManager)
+	applyOpts, err := m.applyOptions(targetPath, opts)
 	if err != nil {
 		return err
 	}
 
-	if err := mcpManager.Save(mcpConfig, targetPath); err != nil {
+	result, err := mcpjson.NewMCPConfigManager().ApplyToFile(mcpConfig, targetPath, applyOpts)
+	if err != nil {
 		return err
 	}
 
-	fmt.Printf("プロファイル '%s' を適用しました\n", name)
-	fmt.Printf("%d個のサーバー設定を '%s' に保存\n", len(profile.Servers), targetPath)
+	if opts.State != nil {
+		record := &provenance.Record{Profile: name, AppliedAt: time.Now(), Servers: result.Managed}
+		if err := opts.State.Put(targetPath, record); err != nil {
+			fmt.Println(i18n.T("profile.provenance_failed", err))
+		}
+	}
+
+	fmt.Println(i18n.T("profile.applied", name))
+	fmt.Println(i18n.T("profile.saved_servers", len(result.Managed), targetPath))
+	if applyOpts.Mode != mcpjson.ModeReplace {
+		fmt.Println(i18n.T("profile.apply_summary", len(result.Added), len(result.Updated), len(result.Removed), len(result.Kept)))
+	}
+	if len(result.Skipped) > 0 {
+		fmt.Println(i18n.T("profile.skipped_missing", strings.Join(result.Skipped, ", ")))
+	}
+	if len(result.Conflicts) > 0 {
+		fmt.Println(i18n.T("profile.conflicted", applyOpts.Conflict, strings.Join(result.Conflicts, ", ")))
+	}
+	dropped := make([]string, 0, len(result.Dropped))
+	for serverName := range result.Dropped {
+		dropped = append(dropped, serverName)
+	}
+	sort.Strings(dropped)
+	for _, serverName := range dropped {
+		fmt.Println(i18n.T("profile.dropped_fields", serverName, strings.Join(result.Dropped[serverName], ", "), opts.Client.Name()))
+	}
 	return nil
 }
 
-func (m *Manager) List(detail bool) error {
-	files, err := os.ReadDir(m.profilesDir)
+// Plan returns the current servers of targetPath and what applying the
+// profile with opts would turn them into, without writing anything
+func (m *Manager) Plan(name string, targetPath string, serverManager *server.Manager, opts ApplyOptions) (*server.MCPConfig, *mcpjson.ApplyResult, error) {
+	mcpConfig, err := m.buildFor(name, serverManager, opts)
 	if err != nil {
-		return fmt.Errorf("プロファイルディレクトリの読み込みに失敗しました: %w", err)
+		return nil, nil, err
 	}
 
-	if len(files) == 0 {
-		fmt.Println("プロファイルが存在しません")
-		return nil
+	applyOpts, err := m.applyOptions(targetPath, opts)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	if detail {
-		return m.listDetailed(files)
+	current := &server.MCPConfig{McpServers: make(map[string]server.MCPServer)}
+	if utils.FileExists(targetPath) {
+		client := opts.Client
+		if client == nil {
+			client = server.DefaultClient
+		}
+		doc, err := server.LoadMCPDocumentFor(targetPath, client)
+		if err != nil {
+			return nil, nil, i18n.Errorf("common.mcp_read_existing_failed", err)
+		}
+		if current, err = doc.Config(); err != nil {
+			return nil, nil, i18n.Errorf("common.mcp_read_existing_failed", err)
+		}
+	}
+
+	result, err := mcpjson.PlanApply(current.McpServers, mcpConfig.McpServers, applyOpts)
+	if err != nil {
+		return nil, nil, err
 	}
-	return m.listSummary(files)
+	return current, result, nil
 }
 
-func (m *Manager) listDetailed(files []os.DirEntry) error {
-	for _, file := range files {
-		if strings.HasSuffix(file.Name(), config.FileExtension) {
-			name := strings.TrimSuffix(file.Name(), config.FileExtension)
-			profile, err := m.Load(name)
-			if err != nil {
-				fmt.Printf("エラー: %s の読み込みに失敗しました: %v\n", name, err)
-				continue
-			}
+// buildFor returns the servers written by applying the profile with opts.
+// Via the proxy the profile is still built, so that a broken profile is
+// reported now rather than when the client starts the proxy.
+func (m *Manager) buildFor(name string, serverManager *server.Manager, opts ApplyOptions) (*server.MCPConfig, error) {
+	mcpConfig, err := m.Build(name, serverManager)
+	if err != nil || !opts.ViaProxy {
+		return mcpConfig, err
+	}
+	return &server.MCPConfig{McpServers: map[string]server.MCPServer{proxy.ServerName: proxy.Entry(name, m.store, m.projectDir)}}, nil
+}
+
+func (m *Manager) applyOptions(targetPath string, opts ApplyOptions) (mcpjson.ApplyOptions, error) {
+	applyOpts := mcpjson.ApplyOptions{Mode: opts.Mode, Conflict: opts.Conflict, Client: opts.Client}
+	if applyOpts.Mode == "" {
+		applyOpts.Mode = mcpjson.ModeReplace
+	}
+	if applyOpts.Conflict == "" {
+		applyOpts.Conflict = mcpjson.PolicyFail
+	}
 
-			m.printProfileDetails(profile)
+	if opts.State != nil {
+		record, err := opts.State.Get(targetPath)
+		if err != nil {
+			return applyOpts, err
+		}
+		if record != nil {
+			applyOpts.Managed = record.Servers
 		}
 	}
-	return nil
+	return applyOpts, nil
 }
 
-func (m *Manager) listSummary(files []os.DirEntry) error {
-	fmt.Printf("%-*s %-*s %s\n", ListColumnWidth, "プロファイル名", DateColumnWidth, "作成日時", "サーバー数")
-	fmt.Println(strings.Repeat(TableSeparatorChar, TableSeparatorWidth))
+// Summary is one row of 'list' in the json and yaml output formats
+type Summary struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+	Extends     []string  `json:"extends"`
+	ServerCount int       `json:"serverCount"`
+}
 
-	for _, file := range files {
-		if strings.HasSuffix(file.Name(), config.FileExtension) {
-			name := strings.TrimSuffix(file.Name(), config.FileExtension)
-			profile, err := m.Load(name)
-			if err != nil {
-				continue
-			}
+// NewSummary returns the summary of a profile
+func NewSummary(p *Profile) Summary {
+	extends := p.Extends
+	if extends == nil {
+		extends = []string{}
+	}
+	return Summary{
+		Name:        p.Name,
+		Description: p.Description,
+		CreatedAt:   p.CreatedAt,
+		UpdatedAt:   p.UpdatedAt,
+		Extends:     extends,
+		ServerCount: len(p.Servers),
+	}
+}
 
-			fmt.Printf("%-*s %-*s %d\n",
-				ListColumnWidth, profile.Name,
-				DateColumnWidth, profile.CreatedAt.Format(TimestampFormat),
-				len(profile.Servers))
+// List displays all profiles
+func (m *Manager) List(detail bool) error {
+	return m.ListWithFormat(os.Stdout, detail, output.FormatTable)
+}
+
+// ListWithFormat writes all profiles to w. The json and yaml formats write
+// a list of Summary, or of Profile with detail.
+func (m *Manager) ListWithFormat(w io.Writer, detail bool, format output.Format) error {
+	profiles, err := m.loadAll()
+	if err != nil {
+		return err
+	}
+
+	if format.IsStructured() {
+		if detail {
+			return output.Write(w, format, profiles)
+		}
+		summaries := make([]Summary, 0, len(profiles))
+		for _, p := range profiles {
+			summaries = append(summaries, NewSummary(p))
 		}
+		return output.Write(w, format, summaries)
 	}
-	return nil
+
+	if len(profiles) == 0 {
+		fmt.Fprintln(w, i18n.T("profile.none"))
+		return nil
+	}
+
+	if detail {
+		for _, p := range profiles {
+			m.WriteDetails(w, p)
+		}
+		return nil
+	}
+
+	table := output.NewTable(i18n.T("profile.column_name"), i18n.T("common.created_at"), i18n.T("common.server_count"))
+	for _, p := range profiles {
+		table.AddRow(p.Name, p.CreatedAt.Format(TimestampFormat), strconv.Itoa(len(p.Servers)))
+	}
+	return table.Write(w)
 }
 
-func (m *Manager) printProfileDetails(profile *Profile) {
-	fmt.Printf("\nプロファイル: %s\n", profile.Name)
-	fmt.Printf("  説明: %s\n", profile.Description)
-	fmt.Printf("  作成日時: %s\n", profile.CreatedAt.Format(TimestampFormat))
-	fmt.Printf("  更新日時: %s\n", profile.UpdatedAt.Format(TimestampFormat))
-	fmt.Printf("  サーバー数: %d\n", len(profile.Servers))
+// loadAll loads every profile in the store. Profiles that cannot be read
+// are reported on stderr and skipped.
+func (m *Manager) loadAll() ([]*Profile, error) {
+	if _, err := os.ReadDir(m.profilesDir); err != nil {
+		return nil, i18n.Errorf("profile.read_dir_failed", err)
+	}
+
+	names := config.ListNames(m.profilesDir)
+	if len(m.readDirs) > 0 {
+		names = config.ListNames(m.readDirs...)
+	}
+
+	profiles := []*Profile{}
+	for _, name := range names {
+		profile, err := m.Load(name)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, i18n.T("common.load_failed_skip", name, err))
+			continue
+		}
+		profiles = append(profiles, profile)
+	}
+	return profiles, nil
+}
+
+// WriteDetails writes the human-readable details of a profile to w
+func (m *Manager) WriteDetails(w io.Writer, profile *Profile) {
+	fmt.Fprintln(w, i18n.T("profile.detail_header", profile.Name))
+	fmt.Fprintln(w, i18n.T("common.detail_description", profile.Description))
+	fmt.Fprintln(w, i18n.T("common.detail_created_at", profile.CreatedAt.Format(TimestampFormat)))
+	fmt.Fprintln(w, i18n.T("common.detail_updated_at", profile.UpdatedAt.Format(TimestampFormat)))
+	if len(profile.Extends) > 0 {
+		fmt.Fprintln(w, i18n.T("profile.detail_extends", strings.Join(profile.Extends, ", ")))
+	}
+	fmt.Fprintln(w, i18n.T("profile.detail_server_count", len(profile.Servers)))
 
 	if len(profile.Servers) > 0 {
-		fmt.Println("  サーバー:")
+		fmt.Fprintln(w, i18n.T("profile.detail_servers"))
 		for _, server := range profile.Servers {
-			fmt.Printf("    - %s (テンプレート: %s)\n", server.Name, server.Template)
+			switch {
+			case server.Remove:
+				fmt.Fprintln(w, i18n.T("profile.detail_excluded", server.Name))
+			case server.Template == "":
+				fmt.Fprintln(w, i18n.T("profile.detail_override", server.Name))
+			default:
+				status := ""
+				if !server.IsEnabled() {
+					status = i18n.T("profile.disabled")
+				}
+				fmt.Fprintln(w, i18n.T("profile.detail_server", server.Name, server.Template, status))
+			}
 		}
 	}
 }
 
+// Delete deletes a profile. The confirmation is asked before the store is
+// locked, so that other commands are not held up while the user answers.
 func (m *Manager) Delete(name string, force bool) error {
 	profilePath := m.getProfilePath(name)
 
 	if _, err := os.Stat(profilePath); os.IsNotExist(err) {
-		return fmt.Errorf("プロファイル '%s' が見つかりません", name)
+		return i18n.Errorf("profile.not_found", name)
 	}
 
 	if !force {
-		if !interaction.Confirm(fmt.Sprintf("プロファイル '%s' を削除しますか？", name)) {
-			fmt.Println("削除をキャンセルしました")
+		if !interaction.Confirm(i18n.T("profile.confirm_delete", name)) {
+			fmt.Println(i18n.T("common.delete_cancelled"))
 			return nil
 		}
 	}
 
+	unlock, err := m.lockStore()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// 確認中に他のコマンドが削除していないか確認する
+	if _, err := os.Stat(profilePath); os.IsNotExist(err) {
+		return i18n.Errorf("profile.not_found", name)
+	}
 	if err := os.Remove(profilePath); err != nil {
-		return fmt.Errorf("プロファイルの削除に失敗しました: %w", err)
+		return i18n.Errorf("profile.delete_failed", err)
 	}
 
-	fmt.Printf("プロファイル '%s' を削除しました\n", name)
+	fmt.Println(i18n.T("profile.deleted", name))
 	return nil
 }
 
 func (m *Manager) Rename(oldName, newName string, force bool) error {
+	unlock, err := m.lockStore()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	oldPath := m.getProfilePath(oldName)
 	newPath := m.getProfilePath(newName)
 
 	if _, err := os.Stat(oldPath); os.IsNotExist(err) {
-		return fmt.Errorf("プロファイル '%s' が見つかりません", oldName)
+		return i18n.Errorf("profile.not_found", oldName)
 	}
 
 	if _, err := os.Stat(newPath); err == nil && !force {
-		return fmt.Errorf("プロファイル '%s' は既に存在します。別の名前を指定するか、--force オプションで上書きしてください", newName)
+		return i18n.Errorf("profile.exists_rename_or_force", newName)
 	}
 
 	profile, err := m.Load(oldName)
@@ -312,32 +543,95 @@ func (m *Manager) Rename(oldName, newName string, force bool) error {
 	profile.Name = newName
 	profile.UpdatedAt = time.Now()
 
-	if err := m.saveProfile(profile); err != nil {
+	// 新しいファイルの保存と古いファイルの削除はまとめて成功させる
+	tx := utils.NewTransaction()
+	defer tx.Rollback()
+
+	if err := tx.SaveJSONFrom(oldPath, newPath, profile); err != nil {
 		return err
 	}
 
-	if err := os.Remove(oldPath); err != nil {
-		return fmt.Errorf("古いプロファイルの削除に失敗しました: %w", err)
+	if err := tx.Remove(oldPath); err != nil {
+		return i18n.Errorf("profile.remove_old_failed", err)
 	}
 
-	fmt.Printf("プロファイル '%s' を '%s' に変更しました\n", oldName, newName)
+	updated, err := m.renameExtendsReferences(tx, oldName, newName)
+	if err != nil {
+		return err
+	}
+	tx.Commit()
+
+	fmt.Println(i18n.T("profile.renamed", oldName, newName))
+	if updated > 0 {
+		fmt.Println(i18n.T("profile.extends_updated", updated))
+	}
 	return nil
 }
 
+// renameExtendsReferences points the extends entries of other profiles
+// at the renamed profile and returns how many profiles were updated
+func (m *Manager) renameExtendsReferences(tx *utils.Transaction, oldName, newName string) (int, error) {
+	files, err := os.ReadDir(m.profilesDir)
+	if err != nil {
+		return 0, i18n.Errorf("profile.read_dir_failed", err)
+	}
+
+	updated := 0
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), config.FileExtension) {
+			continue
+		}
+		profileName := strings.TrimSuffix(file.Name(), config.FileExtension)
+		if profileName == oldName || profileName == newName {
+			continue
+		}
+
+		profile, err := m.Load(profileName)
+		if err != nil {
+			continue // 読み込みに失敗したプロファイルはスキップ
+		}
+
+		changed := false
+		for i, parent := range profile.Extends {
+			if parent == oldName {
+				profile.Extends[i] = newName
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+
+		profile.UpdatedAt = time.Now()
+		if err := tx.SaveJSON(m.getProfilePath(profileName), profile); err != nil {
+			return 0, i18n.Errorf("profile.extends_update_failed", profileName, err)
+		}
+		updated++
+	}
+	return updated, nil
+}
+
 // Copy creates a duplicate of an existing profile with a new name.
 // The original profile remains unchanged. All server configurations
 // from the source profile are copied to the destination profile.
 func (m *Manager) Copy(sourceName, destName string, force bool) error {
-	// Validate source profile exists
-	sourcePath := m.getProfilePath(sourceName)
+	unlock, err := m.lockStore()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// Validate source profile exists in any store; the copy is written to
+	// the manager's own directory
+	sourcePath := m.lookupPath(sourceName)
 	if _, err := os.Stat(sourcePath); os.IsNotExist(err) {
-		return fmt.Errorf("プロファイル '%s' が見つかりません", sourceName)
+		return i18n.Errorf("profile.not_found", sourceName)
 	}
 
 	// Check destination profile doesn't exist (unless force is true)
 	destPath := m.getProfilePath(destName)
 	if _, err := os.Stat(destPath); err == nil && !force {
-		return fmt.Errorf("プロファイル '%s' は既に存在します。別の名前を指定するか、--force オプションで上書きしてください", destName)
+		return i18n.Errorf("profile.exists_rename_or_force", destName)
 	}
 
 	// Load source profile
@@ -351,12 +645,12 @@ func (m *Manager) Copy(sourceName, destName string, force bool) error {
 	profile.CreatedAt = time.Now()
 	profile.UpdatedAt = time.Now()
 
-	// Save the copied profile
-	if err := m.saveProfile(profile); err != nil {
+	// Save the copied profile, keeping the comments of the source
+	if err := m.saveProfileFrom(sourcePath, profile); err != nil {
 		return err
 	}
 
-	fmt.Printf("プロファイル '%s' を '%s' にコピーしました\n", sourceName, destName)
+	fmt.Println(i18n.T("profile.copied", sourceName, destName))
 	return nil
 }
 
@@ -364,6 +658,12 @@ func (m *Manager) Copy(sourceName, destName string, force bool) error {
 // Server configurations from all source profiles are merged,
 // with duplicate server names being skipped (first-wins policy).
 func (m *Manager) Merge(destName string, sourceNames []string, force bool) error {
+	unlock, err := m.lockStore()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	// Validate destination profile doesn't exist
 	if err := m.validateDestinationProfile(destName, force); err != nil {
 		return err
@@ -391,7 +691,7 @@ func (m *Manager) Merge(destName string, sourceNames []string, force bool) error
 func (m *Manager) validateDestinationProfile(destName string, force bool) error {
 	destPath := m.getProfilePath(destName)
 	if _, err := os.Stat(destPath); err == nil && !force {
-		return fmt.Errorf("プロファイル '%s' は既に存在します。別の名前を指定するか、--force オプションで上書きしてください", destName)
+		return i18n.Errorf("profile.exists_rename_or_force", destName)
 	}
 	return nil
 }
@@ -400,7 +700,7 @@ func (m *Manager) validateDestinationProfile(destName string, force bool) error
 func (m *Manager) createMergedProfile(destName string, sourceNames []string) *Profile {
 	return &Profile{
 		Name:        destName,
-		Description: fmt.Sprintf("%d個のプロファイルを合成", len(sourceNames)),
+		Description: i18n.T("profile.merged_description", len(sourceNames)),
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 		Servers:     []ServerRef{},
@@ -423,7 +723,7 @@ func (m *Manager) collectServersFromProfiles(mergedProfile *Profile, sourceNames
 func (m *Manager) collectServersFromProfile(mergedProfile *Profile, sourceName string, mergedServerNames map[string]bool) error {
 	sourceProfile, err := m.Load(sourceName)
 	if err != nil {
-		return fmt.Errorf("プロファイル '%s' の読み込みに失敗しました: %w", sourceName, err)
+		return i18n.Errorf("profile.load_named_failed", sourceName, err)
 	}
 
 	for _, server := range sourceProfile.Servers {
@@ -439,7 +739,7 @@ func (m *Manager) collectServersFromProfile(mergedProfile *Profile, sourceName s
 // isServerAlreadyMerged checks if server is already included and reports duplicates
 func (m *Manager) isServerAlreadyMerged(serverName, sourceName string, mergedServerNames map[string]bool) bool {
 	if mergedServerNames[serverName] {
-		fmt.Printf("警告: サーバー '%s' は既に追加されているため、スキップします（プロファイル: %s）\n", serverName, sourceName)
+		fmt.Println(i18n.T("profile.duplicate_server", serverName, sourceName))
 		return true
 	}
 	return false
@@ -447,34 +747,109 @@ func (m *Manager) isServerAlreadyMerged(serverName, sourceName string, mergedSer
 
 // reportMergeResults outputs the merge operation results
 func (m *Manager) reportMergeResults(destName string, sourceNames []string, serverCount int) {
-	fmt.Printf("プロファイル '%s' を作成しました（%d個のサーバー）\n", destName, serverCount)
-	fmt.Printf("合成元: %v\n", sourceNames)
+	fmt.Println(i18n.T("profile.created", destName, serverCount))
+	fmt.Println(i18n.T("profile.merged_from", sourceNames))
 }
 
 func (m *Manager) Load(name string) (*Profile, error) {
-	profilePath := m.getProfilePath(name)
+	profilePath := m.lookupPath(name)
 
 	profile := &Profile{}
 	if err := utils.LoadJSON(profilePath, profile); err != nil {
 		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("プロファイル '%s' が見つかりません", name)
+			return nil, i18n.Errorf("profile.not_found", name)
 		}
-		return nil, fmt.Errorf("プロファイルの読み込みに失敗しました: %w", err)
+		return nil, i18n.Errorf("profile.load_failed", err)
 	}
 
 	return profile, nil
 }
 
+// Build returns the MCP configuration that applying the profile writes
+func (m *Manager) Build(name string, serverManager *server.Manager) (*server.MCPConfig, error) {
+	profile, err := m.Load(name)
+	if err != nil {
+		return nil, err
+	}
+	return m.newMCPConfigManager().BuildFromProfile((*mcpjson.ProfileData)(profile), serverManager)
+}
+
+// Compose returns the servers of the profile as defined, with extends and
+// overrides applied but placeholders left unexpanded
+func (m *Manager) Compose(name string, serverManager *server.Manager) (*server.MCPConfig, error) {
+	profile, err := m.Load(name)
+	if err != nil {
+		return nil, err
+	}
+	return m.newMCPConfigManager().ComposeProfile((*mcpjson.ProfileData)(profile), serverManager)
+}
+
+// Resolve loads a profile and flattens its extends chain
+func (m *Manager) Resolve(name string) (*mcpjson.ResolvedProfile, error) {
+	profile, err := m.Load(name)
+	if err != nil {
+		return nil, err
+	}
+	return m.newMCPConfigManager().ResolveProfile((*mcpjson.ProfileData)(profile))
+}
+
+// newMCPConfigManager returns an MCPConfigManager that loads parent
+// profiles from this store
+func (m *Manager) newMCPConfigManager() *mcpjson.MCPConfigManager {
+	mcpManager := mcpjson.NewMCPConfigManager()
+	mcpManager.SetProfileLoader(func(name string) (*mcpjson.ProfileData, error) {
+		profile, err := m.Load(name)
+		if err != nil {
+			return nil, err
+		}
+		return (*mcpjson.ProfileData)(profile), nil
+	})
+	return mcpManager
+}
+
+// lockStore serializes store mutations across mcpjson processes
+func (m *Manager) lockStore() (func(), error) {
+	return filelock.LockStore(m.profilesDir)
+}
+
 func (m *Manager) getProfilePath(name string) string {
 	return filepath.Join(m.profilesDir, name+config.FileExtension)
 }
 
+// lookupPath returns the path a profile is read from: the first read
+// directory that has it, otherwise its path in the manager's directory
+func (m *Manager) lookupPath(name string) string {
+	return config.LookupPath(m.readDirs, m.profilesDir, name)
+}
+
 func (m *Manager) saveProfile(profile *Profile) error {
 	profilePath := m.getProfilePath(profile.Name)
 	return utils.SaveJSON(profilePath, profile)
 }
 
+// saveProfileFrom saves profile using the file at srcPath as the base document
+func (m *Manager) saveProfileFrom(srcPath string, profile *Profile) error {
+	return utils.SaveJSONFrom(srcPath, m.getProfilePath(profile.Name), profile)
+}
+
 func (m *Manager) AddServer(profileName, templateName, serverName string, envOverrides map[string]string) error {
+	overrides := ServerOverrides{}
+	if len(envOverrides) > 0 {
+		overrides.Env = envOverrides
+	}
+	return m.AddServerWithOverrides(profileName, templateName, serverName, overrides, false)
+}
+
+// AddServerWithOverrides adds a server reference with per-profile overrides.
+// When update is true and the server already exists in the profile, its
+// template is replaced and the given overrides are merged into the existing ones.
+func (m *Manager) AddServerWithOverrides(profileName, templateName, serverName string, overrides ServerOverrides, update bool) error {
+	unlock, err := m.lockStore()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	profile, err := m.Load(profileName)
 	if err != nil {
 		return err
@@ -484,19 +859,30 @@ func (m *Manager) AddServer(profileName, templateName, serverName string, envOve
 		serverName = templateName
 	}
 
-	for _, server := range profile.Servers {
-		if server.Name == serverName {
-			return fmt.Errorf("サーバー名 '%s' は既にプロファイル '%s' に存在します。別の名前を指定してください（--as オプションを使用）", serverName, profileName)
+	for i, server := range profile.Servers {
+		if server.Name != serverName {
+			continue
+		}
+		if !update {
+			return i18n.Errorf("profile.server_exists", serverName, profileName)
 		}
-	}
 
-	serverRef := ServerRef{
-		Name:     serverName,
-		Template: templateName,
+		profile.Servers[i].Template = templateName
+		profile.Servers[i].Overrides = server.Overrides.Merge(overrides)
+		profile.UpdatedAt = time.Now()
+
+		if err := m.saveProfile(profile); err != nil {
+			return err
+		}
+
+		fmt.Println(i18n.T("profile.server_updated", profileName, serverName))
+		return nil
 	}
 
-	if len(envOverrides) > 0 {
-		serverRef.Overrides.Env = envOverrides
+	serverRef := ServerRef{
+		Name:      serverName,
+		Template:  templateName,
+		Overrides: ServerOverrides{}.Merge(overrides),
 	}
 
 	profile.Servers = append(profile.Servers, serverRef)
@@ -506,11 +892,17 @@ func (m *Manager) AddServer(profileName, templateName, serverName string, envOve
 		return err
 	}
 
-	fmt.Printf("サーバー '%s' をプロファイル '%s' に追加しました\n", serverName, profileName)
+	fmt.Println(i18n.T("profile.server_added", serverName, profileName))
 	return nil
 }
 
 func (m *Manager) RemoveServer(profileName, serverName string) error {
+	unlock, err := m.lockStore()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	profile, err := m.Load(profileName)
 	if err != nil {
 		return err
@@ -519,7 +911,7 @@ func (m *Manager) RemoveServer(profileName, serverName string) error {
 	newServers, removedCount := m.filterServersByName(profile.Servers, serverName)
 
 	if removedCount == 0 {
-		return fmt.Errorf("サーバー '%s' がプロファイル '%s' に見つかりません", serverName, profileName)
+		return i18n.Errorf("profile.server_not_found", serverName, profileName)
 	}
 
 	profile.Servers = newServers
@@ -529,7 +921,7 @@ func (m *Manager) RemoveServer(profileName, serverName string) error {
 		return err
 	}
 
-	fmt.Printf("サーバー '%s' をプロファイル '%s' から削除しました\n", serverName, profileName)
+	fmt.Println(i18n.T("profile.server_removed", serverName, profileName))
 	return nil
 }
 
@@ -549,24 +941,26 @@ func (m *Manager) filterServersByName(servers []ServerRef, serverName string) ([
 }
 
 func (m *Manager) GetProfilePath(name string) (string, error) {
-	profilePath := m.getProfilePath(name)
+	profilePath := m.lookupPath(name)
 
 	if _, err := os.Stat(profilePath); os.IsNotExist(err) {
-		return "", fmt.Errorf("プロファイル '%s' が見つかりません", name)
+		return "", i18n.Errorf("profile.not_found", name)
 	}
 
 	return profilePath, nil
 }
 
+// Reset deletes all profiles. Like Delete, it asks before locking the
+// store; profiles created while the user answered are kept.
 func (m *Manager) Reset(force bool) error {
 	if _, err := os.Stat(m.profilesDir); os.IsNotExist(err) {
-		fmt.Println("プロファイルディレクトリが存在しません")
+		fmt.Println(i18n.T("profile.no_dir"))
 		return nil
 	}
 
 	files, err := os.ReadDir(m.profilesDir)
 	if err != nil {
-		return fmt.Errorf("プロファイルディレクトリの読み込みに失敗しました: %w", err)
+		return i18n.Errorf("profile.read_dir_failed", err)
 	}
 
 	profileFiles := []string{}
@@ -577,35 +971,41 @@ func (m *Manager) Reset(force bool) error {
 	}
 
 	if len(profileFiles) == 0 {
-		fmt.Println("削除するプロファイルが存在しません")
+		fmt.Println(i18n.T("profile.reset_none"))
 		return nil
 	}
 
 	if !force {
-		fmt.Printf("以下の%d個のプロファイルを削除します:\n", len(profileFiles))
+		fmt.Println(i18n.T("profile.reset_list", len(profileFiles)))
 		for _, file := range profileFiles {
 			name := strings.TrimSuffix(file, config.FileExtension)
 			fmt.Printf("  - %s\n", name)
 		}
 		fmt.Println()
 
-		if !interaction.Confirm("すべてのプロファイルを削除しますか？") {
-			fmt.Println("リセットをキャンセルしました")
+		if !interaction.Confirm(i18n.T("profile.confirm_reset")) {
+			fmt.Println(i18n.T("common.reset_cancelled"))
 			return nil
 		}
 	}
 
+	unlock, err := m.lockStore()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	deletedCount := 0
 	for _, file := range profileFiles {
 		profilePath := filepath.Join(m.profilesDir, file)
-		if err := os.Remove(profilePath); err != nil {
-			fmt.Printf("警告: %s の削除に失敗しました: %v\n", file, err)
-		} else {
+		if err := os.Remove(profilePath); err == nil {
 			deletedCount++
+		} else if !os.IsNotExist(err) {
+			fmt.Println(i18n.T("common.remove_failed_warning", file, err))
 		}
 	}
 
-	fmt.Printf("プロファイルを%d個削除しました\n", deletedCount)
+	fmt.Println(i18n.T("profile.reset_done", deletedCount))
 	return nil
 }
 
@@ -613,7 +1013,7 @@ func (m *Manager) Reset(force bool) error {
 func (m *Manager) FindProfilesUsingTemplate(templateName string) ([]string, error) {
 	files, err := os.ReadDir(m.profilesDir)
 	if err != nil {
-		return nil, fmt.Errorf("プロファイルディレクトリの読み込みに失敗しました: %w", err)
+		return nil, i18n.Errorf("profile.read_dir_failed", err)
 	}
 
 	var usingProfiles []string
@@ -643,26 +1043,48 @@ func (m *Manager) FindProfilesUsingTemplate(templateName string) ([]string, erro
 
 // RemoveTemplateReferencesFromProfile は指定されたプロファイルから特定のサーバーテンプレート参照を削除します
 func (m *Manager) RemoveTemplateReferencesFromProfile(profileName, templateName string) error {
-	profile, err := m.Load(profileName)
+	unlock, err := m.lockStore()
 	if err != nil {
 		return err
 	}
+	defer unlock()
+
+	tx := utils.NewTransaction()
+	defer tx.Rollback()
+
+	removedCount, err := m.removeTemplateReferences(tx, profileName, templateName)
+	if err != nil {
+		return err
+	}
+	tx.Commit()
+
+	if removedCount > 0 {
+		fmt.Println(i18n.T("profile.refs_removed", profileName, templateName, removedCount))
+	}
+	return nil
+}
+
+// removeTemplateReferences drops references to templateName from a profile
+// within tx and returns how many were removed
+func (m *Manager) removeTemplateReferences(tx *utils.Transaction, profileName, templateName string) (int, error) {
+	profile, err := m.Load(profileName)
+	if err != nil {
+		return 0, err
+	}
 
 	newServers, removedCount := m.filterServersByTemplate(profile.Servers, templateName)
 
 	if removedCount == 0 {
-		return nil
+		return 0, nil
 	}
 
 	profile.Servers = newServers
 	profile.UpdatedAt = time.Now()
 
-	if err := m.saveProfile(profile); err != nil {
-		return err
+	if err := tx.SaveJSON(m.getProfilePath(profile.Name), profile); err != nil {
+		return 0, err
 	}
-
-	fmt.Printf("プロファイル '%s' からサーバーテンプレート '%s' の参照を%d個削除しました\n", profileName, templateName, removedCount)
-	return nil
+	return removedCount, nil
 }
 
 func (m *Manager) filterServersByTemplate(servers []ServerRef, templateName string) ([]ServerRef, int) {
@@ -680,24 +1102,46 @@ func (m *Manager) filterServersByTemplate(servers []ServerRef, templateName stri
 	return newServers, removedCount
 }
 
-// RemoveTemplateReferencesFromAllProfiles はすべてのプロファイルから特定のサーバーテンプレート参照を削除します
+// RemoveTemplateReferencesFromAllProfiles はすべてのプロファイルから特定のサーバーテンプレート参照を削除します。
+// いずれかのプロファイルの更新に失敗した場合は、すべての変更を取り消します。
 func (m *Manager) RemoveTemplateReferencesFromAllProfiles(templateName string) error {
+	unlock, err := m.lockStore()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	usingProfiles, err := m.FindProfilesUsingTemplate(templateName)
 	if err != nil {
 		return err
 	}
 
+	tx := utils.NewTransaction()
+	defer tx.Rollback()
+
+	removedCounts := make([]int, len(usingProfiles))
+	for i, profileName := range usingProfiles {
+		removedCount, err := m.removeTemplateReferences(tx, profileName, templateName)
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				return i18n.Errorf("profile.remove_refs_failed", profileName, err, rollbackErr)
+			}
+			return i18n.Errorf("profile.remove_refs_rolled_back", profileName, err)
+		}
+		removedCounts[i] = removedCount
+	}
+	tx.Commit()
+
 	totalRemoved := 0
-	for _, profileName := range usingProfiles {
-		if err := m.RemoveTemplateReferencesFromProfile(profileName, templateName); err != nil {
-			fmt.Printf("警告: プロファイル '%s' からの参照削除に失敗しました: %v\n", profileName, err)
-		} else {
+	for i, profileName := range usingProfiles {
+		if removedCounts[i] > 0 {
+			fmt.Println(i18n.T("profile.refs_removed", profileName, templateName, removedCounts[i]))
 			totalRemoved++
 		}
 	}
 
 	if totalRemoved > 0 {
-		fmt.Printf("合計%d個のプロファイルからサーバーテンプレート '%s' の参照を削除しました\n", totalRemoved, templateName)
+		fmt.Println(i18n.T("profile.refs_removed_total", totalRemoved, templateName))
 	}
 
 	return nil
@@ -1,12 +1,22 @@
 package profile
 
 import (
+	"bytes"
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/filelock"
+	"github.com/naoto24kawa/mcpjson/internal/interaction"
+	"github.com/naoto24kawa/mcpjson/internal/mcpjson"
+	"github.com/naoto24kawa/mcpjson/internal/output"
+	"github.com/naoto24kawa/mcpjson/internal/provenance"
 	"github.com/naoto24kawa/mcpjson/internal/server"
 )
 
@@ -183,6 +193,63 @@ func TestManager_Delete(t *testing.T) {
 	}
 }
 
+// lockWatcher answers yes to every prompt and records whether the store
+// lock was held while a prompt waited for the answer
+type lockWatcher struct {
+	lockPath string
+	prompts  int
+	locked   bool
+}
+
+func (w *lockWatcher) Read(p []byte) (int, error) {
+	w.prompts++
+	if _, err := os.Stat(w.lockPath); err == nil {
+		w.locked = true
+	}
+	return copy(p, "y\n"), nil
+}
+
+func TestManager_PromptsWithoutLock(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(m *Manager) error
+	}{
+		{name: "削除", run: func(m *Manager) error { return m.Delete("test-profile", false) }},
+		{name: "リセット", run: func(m *Manager) error { return m.Reset(false) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			profilesDir := filepath.Join(tempDir, config.ProfilesDir)
+			if err := os.MkdirAll(profilesDir, 0755); err != nil {
+				t.Fatal(err)
+			}
+			manager := NewManager(profilesDir)
+			if err := manager.Create("test-profile", "テスト用"); err != nil {
+				t.Fatal(err)
+			}
+
+			watcher := &lockWatcher{lockPath: filepath.Join(tempDir, filelock.StoreLockName)}
+			interaction.Input = watcher
+			defer func() { interaction.Input = nil }()
+
+			if err := tt.run(manager); err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if watcher.prompts == 0 {
+				t.Fatal("no prompt was shown")
+			}
+			if watcher.locked {
+				t.Error("the store was locked while the prompt waited for an answer")
+			}
+			if _, err := os.Stat(filepath.Join(profilesDir, "test-profile"+config.FileExtension)); !os.IsNotExist(err) {
+				t.Error("the profile was not deleted")
+			}
+		})
+	}
+}
+
 func TestManager_AddServer(t *testing.T) {
 	tempDir := t.TempDir()
 	manager := NewManager(tempDir)
@@ -229,6 +296,56 @@ func TestManager_AddServer(t *testing.T) {
 	}
 }
 
+func TestManager_AddServerWithOverrides(t *testing.T) {
+	tempDir := t.TempDir()
+	manager := NewManager(tempDir)
+
+	if err := manager.Create(testProfileName, testDescription); err != nil {
+		t.Fatalf("テストプロファイル作成に失敗: %v", err)
+	}
+
+	timeout := 120
+	disabled := false
+	overrides := ServerOverrides{
+		ArgsAppend: []string{"/work"},
+		Timeout:    &timeout,
+		Enabled:    &disabled,
+	}
+	if err := manager.AddServerWithOverrides(testProfileName, testTemplateName, testServerName, overrides, false); err != nil {
+		t.Fatalf("AddServerWithOverrides() failed: %v", err)
+	}
+
+	// --update なしの重複追加はエラー
+	if err := manager.AddServerWithOverrides(testProfileName, testTemplateName, testServerName, ServerOverrides{}, false); err == nil {
+		t.Error("AddServerWithOverrides() expected error for duplicate server without update")
+	}
+
+	enabled := true
+	update := ServerOverrides{Command: "bunx", Enabled: &enabled}
+	if err := manager.AddServerWithOverrides(testProfileName, "other-template", testServerName, update, true); err != nil {
+		t.Fatalf("AddServerWithOverrides() with update failed: %v", err)
+	}
+
+	profile, err := manager.Load(testProfileName)
+	if err != nil {
+		t.Fatalf("プロファイルの読み込みに失敗: %v", err)
+	}
+	if len(profile.Servers) != 1 {
+		t.Fatalf("サーバー数 = %d, want 1", len(profile.Servers))
+	}
+
+	ref := profile.Servers[0]
+	if ref.Template != "other-template" {
+		t.Errorf("テンプレート = %s, want other-template", ref.Template)
+	}
+	if ref.Overrides.Command != "bunx" || ref.Overrides.Timeout == nil || *ref.Overrides.Timeout != 120 {
+		t.Errorf("上書き設定がマージされていません: %+v", ref.Overrides)
+	}
+	if len(ref.Overrides.ArgsAppend) != 1 || !ref.IsEnabled() {
+		t.Errorf("上書き設定が正しくありません: %+v", ref.Overrides)
+	}
+}
+
 func TestManager_RemoveServer(t *testing.T) {
 	tempDir := t.TempDir()
 	manager := NewManager(tempDir)
@@ -403,6 +520,42 @@ func TestManager_Rename_Success(t *testing.T) {
 	verifyRenameSuccess(t, manager, tempDir, oldProfileName, newProfileName)
 }
 
+func TestManager_Rename_PreservesComments(t *testing.T) {
+	tempDir := t.TempDir()
+	manager := NewManager(tempDir)
+
+	content := `{
+  // チームで共有する設定
+  "name": "old-profile",
+  "description": "古いプロファイル", // 説明
+  "servers": [],
+  "createdAt": "2025-01-01T00:00:00Z",
+  "updatedAt": "2025-01-01T00:00:00Z",
+}
+`
+	if err := os.WriteFile(filepath.Join(tempDir, oldProfileName+".jsonc"), []byte(content), 0644); err != nil {
+		t.Fatalf("テストプロファイル作成に失敗: %v", err)
+	}
+
+	if err := manager.Rename(oldProfileName, newProfileName, false); err != nil {
+		t.Fatalf("Manager.Rename() failed: %v", err)
+	}
+	if err := manager.AddServer(newProfileName, testTemplateName, testServerName, nil); err != nil {
+		t.Fatalf("Manager.AddServer() failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tempDir, newProfileName+".jsonc"))
+	if err != nil {
+		t.Fatalf("リネーム後のプロファイルの読み込みに失敗: %v", err)
+	}
+	for _, comment := range []string{"// チームで共有する設定", "// 説明"} {
+		if !strings.Contains(string(data), comment) {
+			t.Errorf("コメント %q が失われています:\n%s", comment, data)
+		}
+	}
+	verifyRenameSuccess(t, manager, tempDir, oldProfileName, newProfileName)
+}
+
 func TestManager_Rename_NonexistentProfile(t *testing.T) {
 	// Arrange
 	tempDir := t.TempDir()
@@ -532,6 +685,41 @@ func TestManager_List_NonexistentDirectory(t *testing.T) {
 	}
 }
 
+func TestManager_ListWithFormat_JSON(t *testing.T) {
+	// Arrange
+	manager := NewManager(t.TempDir())
+	for _, name := range []string{"profile1", "profile2"} {
+		if err := manager.Create(name, testDescription); err != nil {
+			t.Fatalf("テストプロファイル '%s' の作成に失敗: %v", name, err)
+		}
+	}
+	if err := manager.AddServer("profile2", testTemplateName, testServerName, nil); err != nil {
+		t.Fatalf("サーバーの追加に失敗: %v", err)
+	}
+
+	// Act
+	var buf bytes.Buffer
+	err := manager.ListWithFormat(&buf, false, output.FormatJSON)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Manager.ListWithFormat() error = %v", err)
+	}
+	var summaries []Summary
+	if err := json.Unmarshal(buf.Bytes(), &summaries); err != nil {
+		t.Fatalf("JSONの解析に失敗: %v\n%s", err, buf.String())
+	}
+	if len(summaries) != 2 {
+		t.Fatalf("len(summaries) = %d, want 2", len(summaries))
+	}
+	if summaries[1].Name != "profile2" || summaries[1].ServerCount != 1 || summaries[1].Description != testDescription {
+		t.Errorf("summaries[1] = %+v", summaries[1])
+	}
+	if !strings.Contains(buf.String(), `"extends": []`) {
+		t.Errorf("extends は空の配列で出力される必要があります:\n%s", buf.String())
+	}
+}
+
 func TestManager_AddServer_Duplicate(t *testing.T) {
 	// Arrange
 	tempDir := t.TempDir()
@@ -863,6 +1051,106 @@ func TestManager_Apply_BasicFlow(t *testing.T) {
 	}
 }
 
+func TestManager_ApplyWithOptions_Merge(t *testing.T) {
+	tempDir := t.TempDir()
+	profilesDir := filepath.Join(tempDir, "profiles")
+	serversDir := filepath.Join(tempDir, "servers")
+	for _, dir := range []string{profilesDir, serversDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+	}
+
+	manager := NewManager(profilesDir)
+	serverManager := server.NewManager(serversDir)
+	for _, name := range []string{"a", "b"} {
+		if err := serverManager.SaveFromConfig(name, server.MCPServer{Command: "npx", Args: []string{name}}); err != nil {
+			t.Fatalf("Failed to create server template: %v", err)
+		}
+	}
+	for _, p := range []*Profile{
+		{Name: "first", Servers: []ServerRef{{Name: "a", Template: "a"}}},
+		{Name: "second", Servers: []ServerRef{{Name: "b", Template: "b"}}},
+	} {
+		if err := manager.saveProfile(p); err != nil {
+			t.Fatalf("Failed to save test profile: %v", err)
+		}
+	}
+
+	targetPath := filepath.Join(tempDir, ".mcp.json")
+	createTestMCPConfig(t, targetPath, &server.MCPConfig{McpServers: map[string]server.MCPServer{
+		"hand": {Command: "node", Args: []string{"hand.js"}},
+	}})
+
+	opts := ApplyOptions{Mode: mcpjson.ModeMerge, State: provenance.NewStore(filepath.Join(tempDir, "state"))}
+	if err := manager.ApplyWithOptions("first", targetPath, serverManager, opts); err != nil {
+		t.Fatalf("Manager.ApplyWithOptions() failed: %v", err)
+	}
+	// 2つ目のプロファイルを適用すると、以前書き込んだ a だけが削除される
+	if err := manager.ApplyWithOptions("second", targetPath, serverManager, opts); err != nil {
+		t.Fatalf("Manager.ApplyWithOptions() failed: %v", err)
+	}
+
+	mcpConfig, err := mcpjson.NewMCPConfigManager().Load(targetPath)
+	if err != nil {
+		t.Fatalf("Failed to load output file: %v", err)
+	}
+	var names []string
+	for name := range mcpConfig.McpServers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if strings.Join(names, ",") != "b,hand" {
+		t.Errorf("servers = %v, want [b hand]", names)
+	}
+}
+
+func TestManager_ApplyWithOptions_ViaProxy(t *testing.T) {
+	tempDir := t.TempDir()
+	profilesDir := filepath.Join(tempDir, "profiles")
+	serversDir := filepath.Join(tempDir, "servers")
+	for _, dir := range []string{profilesDir, serversDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+	}
+
+	manager := NewManager(profilesDir)
+	serverManager := server.NewManager(serversDir)
+	for _, name := range []string{"a", "b"} {
+		if err := serverManager.SaveFromConfig(name, server.MCPServer{Command: "npx", Args: []string{name}}); err != nil {
+			t.Fatalf("Failed to create server template: %v", err)
+		}
+	}
+	profile := &Profile{Name: "work", Servers: []ServerRef{{Name: "a", Template: "a"}, {Name: "b", Template: "b"}}}
+	if err := manager.saveProfile(profile); err != nil {
+		t.Fatalf("Failed to save test profile: %v", err)
+	}
+
+	targetPath := filepath.Join(tempDir, ".mcp.json")
+	if err := manager.ApplyWithOptions("work", targetPath, serverManager, ApplyOptions{ViaProxy: true}); err != nil {
+		t.Fatalf("Manager.ApplyWithOptions() failed: %v", err)
+	}
+
+	mcpConfig, err := mcpjson.NewMCPConfigManager().Load(targetPath)
+	if err != nil {
+		t.Fatalf("Failed to load output file: %v", err)
+	}
+	want := map[string]server.MCPServer{"mcpjson-proxy": {Command: "mcpjson", Args: []string{"proxy", "work"}}}
+	if !reflect.DeepEqual(mcpConfig.McpServers, want) {
+		t.Errorf("servers = %+v, want only the proxy entry", mcpConfig.McpServers)
+	}
+
+	// 壊れたプロファイルはプロキシ経由でも適用時にエラーになる
+	profile.Servers = append(profile.Servers, ServerRef{Name: "c", Template: "missing"})
+	if err := manager.saveProfile(profile); err != nil {
+		t.Fatalf("Failed to save test profile: %v", err)
+	}
+	if err := manager.ApplyWithOptions("work", targetPath, serverManager, ApplyOptions{ViaProxy: true}); err == nil {
+		t.Error("Manager.ApplyWithOptions() should fail for a profile that does not build")
+	}
+}
+
 func TestManager_Apply_ProfileNotFound(t *testing.T) {
 	// Arrange
 	tempDir := t.TempDir()
@@ -892,14 +1180,17 @@ func TestManager_Apply_ProfileNotFound(t *testing.T) {
 
 func TestManager_Copy(t *testing.T) {
 	tests := []struct {
-		name          string
-		sourceName    string
-		destName      string
-		force         bool
-		setupSource   bool
-		setupDest     bool
-		expectError   bool
-		errorContains string
+		name        string
+		sourceName  string
+		destName    string
+		force       bool
+		setupSource bool
+		setupDest   bool
+		// sourceInOtherStore puts the source in a store read before the
+		// manager's own directory
+		sourceInOtherStore bool
+		expectError        bool
+		errorContains      string
 	}{
 		{
 			name:        "正常なプロファイルコピー",
@@ -910,6 +1201,13 @@ func TestManager_Copy(t *testing.T) {
 			setupDest:   false,
 			expectError: false,
 		},
+		{
+			name:               "他のストアのプロファイルをコピー",
+			sourceName:         "team-profile",
+			destName:           "dest-profile",
+			setupSource:        true,
+			sourceInOtherStore: true,
+		},
 		{
 			name:          "存在しないソースプロファイル",
 			sourceName:    "nonexistent",
@@ -946,6 +1244,11 @@ func TestManager_Copy(t *testing.T) {
 			// Arrange
 			tempDir := t.TempDir()
 			manager := NewManager(tempDir)
+			sourceDir := tempDir
+			if tt.sourceInOtherStore {
+				sourceDir = t.TempDir()
+				manager.readDirs = []string{sourceDir, tempDir}
+			}
 
 			// Setup source profile if needed
 			if tt.setupSource {
@@ -958,7 +1261,7 @@ func TestManager_Copy(t *testing.T) {
 						{Name: "test-server", Template: "test-template"},
 					},
 				}
-				sourcePath := filepath.Join(tempDir, tt.sourceName+".jsonc")
+				sourcePath := filepath.Join(sourceDir, tt.sourceName+".jsonc")
 				if err := createTestProfile(t, sourcePath, sourceProfile); err != nil {
 					t.Fatalf("Failed to create source profile: %v", err)
 				}
@@ -1006,7 +1309,7 @@ func TestManager_Copy(t *testing.T) {
 				}
 
 				// Verify source profile still exists
-				sourcePath := filepath.Join(tempDir, tt.sourceName+".jsonc")
+				sourcePath := filepath.Join(sourceDir, tt.sourceName+".jsonc")
 				if _, err := os.Stat(sourcePath); os.IsNotExist(err) {
 					t.Error("Source profile should still exist after copy")
 				}
@@ -1295,3 +1598,164 @@ func findInString(s, substr string) bool {
 	}
 	return false
 }
+
+func TestManager_Rename_UpdatesExtends(t *testing.T) {
+	tempDir := t.TempDir()
+	manager := NewManager(tempDir)
+
+	for _, p := range []*Profile{
+		{Name: "base"},
+		{Name: "frontend", Extends: []string{"base"}},
+		{Name: "other"},
+	} {
+		if err := createTestProfile(t, filepath.Join(tempDir, p.Name+".jsonc"), p); err != nil {
+			t.Fatalf("テストプロファイル作成に失敗: %v", err)
+		}
+	}
+
+	if err := manager.Rename("base", "common", false); err != nil {
+		t.Fatalf("Manager.Rename() failed: %v", err)
+	}
+
+	frontend, err := manager.Load("frontend")
+	if err != nil {
+		t.Fatalf("Manager.Load() failed: %v", err)
+	}
+	if len(frontend.Extends) != 1 || frontend.Extends[0] != "common" {
+		t.Errorf("Extends = %v, want [common]", frontend.Extends)
+	}
+	if _, err := manager.Resolve("frontend"); err != nil {
+		t.Errorf("Manager.Resolve() failed after rename: %v", err)
+	}
+}
+
+func TestManager_UseConfig(t *testing.T) {
+	teamStore := config.Store{Name: "team", Dir: t.TempDir()}
+	personalStore := config.Store{Name: config.StorePersonal, Dir: t.TempDir()}
+	team := NewManager(teamStore.ProfilesDir())
+	personal := NewManager(personalStore.ProfilesDir())
+	for _, m := range []*Manager{team, personal} {
+		if err := os.MkdirAll(m.profilesDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = team.Create("shared", "team")
+	_ = team.Create("team-only", "team")
+	_ = personal.Create("shared", "personal")
+
+	manager := NewManager(personalStore.ProfilesDir())
+	manager.UseConfig(&config.Config{
+		ProfilesDir: personalStore.ProfilesDir(),
+		Stores:      []config.Store{teamStore, personalStore},
+	})
+
+	shared, err := manager.Load("shared")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if shared.Description != "team" {
+		t.Errorf("Load() read %q, want the profile of the first store", shared.Description)
+	}
+
+	var buf bytes.Buffer
+	if err := manager.ListWithFormat(&buf, false, output.FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	var summaries []Summary
+	if err := json.Unmarshal(buf.Bytes(), &summaries); err != nil {
+		t.Fatal(err)
+	}
+	if len(summaries) != 2 || summaries[0].Name != "shared" || summaries[1].Name != "team-only" {
+		t.Errorf("ListWithFormat() = %+v, want shared and team-only once each", summaries)
+	}
+
+	if err := manager.Delete("team-only", true); err == nil {
+		t.Error("Delete() should not remove a profile of another store")
+	}
+	if err := manager.AddServer("team-only", "github", "github", nil); err != nil {
+		t.Fatalf("AddServer() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(personalStore.ProfilesDir(), "team-only.jsonc")); err != nil {
+		t.Errorf("AddServer() should write to the manager's store: %v", err)
+	}
+	if original, _ := team.Load("team-only"); len(original.Servers) != 0 {
+		t.Errorf("AddServer() modified the team store: %+v", original.Servers)
+	}
+}
+
+func TestManager_Tools(t *testing.T) {
+	tempDir := t.TempDir()
+	profilesDir := filepath.Join(tempDir, "profiles")
+	serversDir := filepath.Join(tempDir, "servers")
+	for _, dir := range []string{profilesDir, serversDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	manager := NewManager(profilesDir)
+	serverManager := server.NewManager(serversDir)
+
+	inspected := map[string][]string{
+		"github": {"search", "create_issue"},
+		"gitlab": {"search", "create_merge_request"},
+		"slack":  {"post_message"},
+	}
+	for _, name := range []string{"github", "gitlab", "slack", "filesystem"} {
+		if err := serverManager.SaveFromConfig(name, server.MCPServer{Command: "npx", Args: []string{name}}); err != nil {
+			t.Fatal(err)
+		}
+		tools, ok := inspected[name]
+		if !ok {
+			continue
+		}
+		capabilities := &server.Capabilities{InspectedAt: time.Now()}
+		for _, tool := range tools {
+			capabilities.Tools = append(capabilities.Tools, server.Tool{Name: tool, Description: name + " " + tool})
+		}
+		if err := serverManager.SaveCapabilities(name, capabilities); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	disabled := false
+	profile := &Profile{
+		Name: testProfileName,
+		Servers: []ServerRef{
+			{Name: "github", Template: "github"},
+			{Name: "gitlab", Template: "gitlab"},
+			{Name: "slack", Template: "slack", Overrides: ServerOverrides{Enabled: &disabled}},
+			{Name: "fs", Template: "filesystem"},
+		},
+	}
+	if err := manager.saveProfile(profile); err != nil {
+		t.Fatal(err)
+	}
+
+	surface, err := manager.Tools(testProfileName, serverManager)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, tool := range surface.Tools {
+		names = append(names, tool.Server+"/"+tool.Name)
+	}
+	want := "github/create_issue,gitlab/create_merge_request,github/search,gitlab/search"
+	if strings.Join(names, ",") != want {
+		t.Errorf("Tools = %s, want %s (disabled servers left out)", strings.Join(names, ","), want)
+	}
+	if len(surface.Collisions) != 1 || surface.Collisions[0].Name != "search" || strings.Join(surface.Collisions[0].Servers, ",") != "github,gitlab" {
+		t.Errorf("Collisions = %+v, want search on github and gitlab", surface.Collisions)
+	}
+	if strings.Join(surface.Uninspected, ",") != "fs" {
+		t.Errorf("Uninspected = %v, want [fs]", surface.Uninspected)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteTools(&buf, surface); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "'search'") || !strings.Contains(buf.String(), "fs") {
+		t.Errorf("output does not flag the collision and the uninspected server:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,119 @@
+package profile
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/output"
+	"github.com/naoto24kawa/mcpjson/internal/server"
+)
+
+// ToolSurface is the combined set of tools the servers of a profile
+// expose, taken from the capabilities cached by 'server inspect'
+type ToolSurface struct {
+	Profile string `json:"profile"`
+	Tools   []Tool `json:"tools"`
+	// Collisions lists the tool names exposed by more than one server
+	Collisions []Collision `json:"collisions"`
+	// Uninspected lists the servers whose template was never inspected
+	Uninspected []string `json:"uninspected"`
+}
+
+// Tool is a tool of one server in a profile
+type Tool struct {
+	Server      string `json:"server"`
+	Template    string `json:"template"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// Collision is a tool name exposed by several servers of a profile
+type Collision struct {
+	Name    string   `json:"name"`
+	Servers []string `json:"servers"`
+}
+
+// Tools returns the tool surface of the enabled servers of the profile
+func (m *Manager) Tools(name string, serverManager *server.Manager) (*ToolSurface, error) {
+	resolved, err := m.Resolve(name)
+	if err != nil {
+		return nil, err
+	}
+
+	surface := &ToolSurface{Profile: name, Tools: []Tool{}, Collisions: []Collision{}, Uninspected: []string{}}
+	servers := map[string][]string{}
+	for _, ref := range resolved.ServerRefs() {
+		if !ref.IsEnabled() {
+			continue
+		}
+		template, err := serverManager.Load(ref.Template)
+		if err != nil {
+			return nil, i18n.Errorf("mcpjson.template_load_failed", ref.Template, err)
+		}
+		if template.Capabilities == nil {
+			surface.Uninspected = append(surface.Uninspected, ref.Name)
+			continue
+		}
+		for _, tool := range template.Capabilities.Tools {
+			surface.Tools = append(surface.Tools, Tool{Server: ref.Name, Template: ref.Template, Name: tool.Name, Description: tool.Description})
+			servers[tool.Name] = append(servers[tool.Name], ref.Name)
+		}
+	}
+
+	sort.SliceStable(surface.Tools, func(i, j int) bool {
+		if surface.Tools[i].Name != surface.Tools[j].Name {
+			return surface.Tools[i].Name < surface.Tools[j].Name
+		}
+		return surface.Tools[i].Server < surface.Tools[j].Server
+	})
+	for toolName, names := range servers {
+		if len(names) > 1 {
+			sort.Strings(names)
+			surface.Collisions = append(surface.Collisions, Collision{Name: toolName, Servers: names})
+		}
+	}
+	sort.Slice(surface.Collisions, func(i, j int) bool {
+		return surface.Collisions[i].Name < surface.Collisions[j].Name
+	})
+	return surface, nil
+}
+
+// WriteTools renders the tool surface as a table followed by the
+// collisions and the servers that were never inspected
+func WriteTools(w io.Writer, surface *ToolSurface) error {
+	colliding := make(map[string]bool, len(surface.Collisions))
+	for _, collision := range surface.Collisions {
+		colliding[collision.Name] = true
+	}
+
+	table := output.NewTable(i18n.T("profile.column_tool"), i18n.T("profile.column_server"), i18n.T("profile.column_description"))
+	for _, tool := range surface.Tools {
+		name := tool.Name
+		if colliding[name] {
+			name += " " + i18n.T("profile.tool_collision_mark")
+		}
+		table.AddRow(name, tool.Server, firstLine(tool.Description))
+	}
+	if err := table.Write(w); err != nil {
+		return err
+	}
+
+	for _, collision := range surface.Collisions {
+		fmt.Fprintln(w, i18n.T("profile.tool_collision", collision.Name, strings.Join(collision.Servers, ", ")))
+	}
+	if len(surface.Uninspected) > 0 {
+		fmt.Fprintln(w, i18n.T("profile.tools_uninspected", strings.Join(surface.Uninspected, ", ")))
+	}
+	return nil
+}
+
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
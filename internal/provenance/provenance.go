@@ -0,0 +1,139 @@
+// Package provenance records which servers mcpjson wrote to each MCP
+// config file, so later applies can tell them apart from servers that
+// were added to the file by hand.
+package provenance
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/naoto24kawa/mcpjson/internal/filelock"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/server"
+	"github.com/naoto24kawa/mcpjson/internal/utils"
+)
+
+const (
+	FileName      = "provenance.json"
+	formatVersion = 1
+)
+
+// Record describes the last apply to one MCP config file
+type Record struct {
+	Profile   string    `json:"profile"`
+	AppliedAt time.Time `json:"appliedAt"`
+	// Servers maps each server mcpjson manages in the file to the
+	// fingerprint of the configuration it wrote
+	Servers map[string]string `json:"servers"`
+}
+
+type stateFile struct {
+	Version int                `json:"version"`
+	Targets map[string]*Record `json:"targets"`
+}
+
+// Store keeps records in a JSON file in the state directory
+type Store struct {
+	dir string
+}
+
+// NewStore returns a store that keeps its file in stateDir
+func NewStore(stateDir string) *Store {
+	return &Store{dir: stateDir}
+}
+
+func (s *Store) path() string {
+	return filepath.Join(s.dir, FileName)
+}
+
+// Get returns the record for targetPath, or nil when mcpjson has not
+// applied a profile to it
+func (s *Store) Get(targetPath string) (*Record, error) {
+	state, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	return state.Targets[key(targetPath)], nil
+}
+
+// Put stores the record for targetPath
+func (s *Store) Put(targetPath string, record *Record) error {
+	return s.update(func(state *stateFile) {
+		state.Targets[key(targetPath)] = record
+	})
+}
+
+// Delete forgets targetPath
+func (s *Store) Delete(targetPath string) error {
+	return s.update(func(state *stateFile) {
+		delete(state.Targets, key(targetPath))
+	})
+}
+
+func (s *Store) update(fn func(state *stateFile)) error {
+	unlock, err := filelock.LockStore(s.dir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	state, err := s.load()
+	if err != nil {
+		return err
+	}
+	fn(state)
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return i18n.Errorf("provenance.save_failed", err)
+	}
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return i18n.Errorf("provenance.mkdir_failed", err)
+	}
+	if err := utils.WriteFileAtomic(s.path(), append(data, '\n'), 0644); err != nil {
+		return i18n.Errorf("provenance.save_failed", err)
+	}
+	return nil
+}
+
+func (s *Store) load() (*stateFile, error) {
+	state := &stateFile{Version: formatVersion, Targets: make(map[string]*Record)}
+
+	data, err := os.ReadFile(s.path())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, i18n.Errorf("provenance.read_failed", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, i18n.Errorf("provenance.parse_failed", s.path(), err)
+	}
+	if state.Targets == nil {
+		state.Targets = make(map[string]*Record)
+	}
+	return state, nil
+}
+
+// Fingerprint returns a stable hash of a server configuration
+func Fingerprint(mcpServer server.MCPServer) string {
+	data, _ := json.Marshal(mcpServer)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// key identifies a target file independently of how its path was written
+func key(targetPath string) string {
+	path, err := filepath.Abs(targetPath)
+	if err != nil {
+		path = targetPath
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	return filepath.Clean(path)
+}
@@ -0,0 +1,70 @@
+package provenance
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/naoto24kawa/mcpjson/internal/server"
+)
+
+func TestStore(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(filepath.Join(dir, "state"))
+	targetPath := filepath.Join(dir, ".mcp.json")
+
+	record, err := store.Get(targetPath)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if record != nil {
+		t.Fatalf("Get() = %v, want nil", record)
+	}
+
+	want := &Record{
+		Profile:   "work",
+		AppliedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Servers:   map[string]string{"a": "fingerprint"},
+	}
+	if err := store.Put(targetPath, want); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	// 相対パスで指定しても同じ対象として扱う
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	relPath, err := filepath.Rel(wd, targetPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.Get(relPath)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got == nil || got.Profile != want.Profile || got.Servers["a"] != "fingerprint" || !got.AppliedAt.Equal(want.AppliedAt) {
+		t.Errorf("Get() = %+v, want %+v", got, want)
+	}
+
+	if err := store.Delete(targetPath); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if got, _ := store.Get(targetPath); got != nil {
+		t.Errorf("Delete() 後の Get() = %+v, want nil", got)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	a := server.MCPServer{Command: "npx", Args: []string{"a"}, Env: map[string]string{"X": "1", "Y": "2"}}
+	b := server.MCPServer{Command: "npx", Args: []string{"a"}, Env: map[string]string{"Y": "2", "X": "1"}}
+	c := server.MCPServer{Command: "npx", Args: []string{"b"}}
+
+	if Fingerprint(a) != Fingerprint(b) {
+		t.Error("同じ設定のフィンガープリントが一致しません")
+	}
+	if Fingerprint(a) == Fingerprint(c) {
+		t.Error("異なる設定のフィンガープリントが一致しました")
+	}
+}
@@ -0,0 +1,215 @@
+// Package proxy serves every server of a profile behind a single stdio MCP
+// server, so that a client needs one entry instead of one per server.
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"sync"
+
+	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/mcpclient"
+	"github.com/naoto24kawa/mcpjson/internal/mcpjson"
+	"github.com/naoto24kawa/mcpjson/internal/mcpserve"
+	"github.com/naoto24kawa/mcpjson/internal/server"
+)
+
+const (
+	// ServerName is the name of the entry apply --via-proxy writes
+	ServerName = "mcpjson-proxy"
+	// Separator joins the server name and the tool name
+	Separator = "__"
+)
+
+// invalidToolChars are the characters not allowed in MCP tool names
+var invalidToolChars = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+// Entry returns the MCP server entry that runs the proxy for the profile.
+// The store and the project directory the profile was applied from are
+// passed on, since the client starts the proxy elsewhere; empty values and
+// the personal store are left out.
+func Entry(profileName, store, projectDir string) server.MCPServer {
+	args := []string{"proxy", profileName}
+	if store != "" && store != config.StorePersonal {
+		args = append(args, "--store", store)
+	}
+	if projectDir != "" {
+		args = append(args, "--project", projectDir)
+	}
+	return server.MCPServer{Command: "mcpjson", Args: args}
+}
+
+// Upstream is a server of the profile together with its tool filter
+type Upstream struct {
+	Name   string
+	Server server.MCPServer
+	Tools  *mcpjson.ToolFilter
+}
+
+type upstreamTool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	InputSchema map[string]interface{} `json:"inputSchema"`
+	Annotations json.RawMessage        `json:"annotations"`
+}
+
+// Proxy holds the connections to the upstream servers
+type Proxy struct {
+	clients []*mcpclient.Client
+	tools   []mcpserve.Tool
+}
+
+// Start launches the upstream servers concurrently and lists their tools.
+// Servers that are remote or fail to start are reported on log and left
+// out, so that one broken server does not take the others down.
+func Start(ctx context.Context, upstreams []Upstream, version string, log io.Writer) *Proxy {
+	type started struct {
+		client *mcpclient.Client
+		tools  []mcpserve.Tool
+	}
+	results := make([]started, len(upstreams))
+
+	// ツール名の接頭辞が同じになるサーバーは、どちらのツールか区別できないため後の方を除外する
+	prefixes := make(map[string]string, len(upstreams))
+	var wg sync.WaitGroup
+	for i, upstream := range upstreams {
+		if upstream.Server.IsRemote() {
+			fmt.Fprintln(log, i18n.T("proxy.remote_skipped", upstream.Name, upstream.Server.ResolvedType()))
+			continue
+		}
+		prefix := toolPrefix(upstream.Name)
+		if other, ok := prefixes[prefix]; ok {
+			fmt.Fprintln(log, i18n.T("proxy.prefix_collision", upstream.Name, other, prefix))
+			continue
+		}
+		prefixes[prefix] = upstream.Name
+		wg.Add(1)
+		go func(i int, upstream Upstream) {
+			defer wg.Done()
+			c, tools, err := connect(ctx, upstream, version, log)
+			if err != nil {
+				fmt.Fprintln(log, i18n.T("proxy.start_failed", upstream.Name, err))
+				return
+			}
+			results[i] = started{client: c, tools: tools}
+		}(i, upstream)
+	}
+	wg.Wait()
+
+	p := &Proxy{}
+	for _, result := range results {
+		if result.client != nil {
+			p.clients = append(p.clients, result.client)
+			p.tools = append(p.tools, result.tools...)
+		}
+	}
+	return p
+}
+
+// connect starts one upstream server and returns its filtered tools,
+// renamed to <server>__<tool>
+func connect(ctx context.Context, upstream Upstream, version string, log io.Writer) (*mcpclient.Client, []mcpserve.Tool, error) {
+	if err := upstream.Server.Validate(); err != nil {
+		return nil, nil, err
+	}
+	c, err := mcpclient.Start(upstream.Server, &prefixWriter{prefix: "[" + upstream.Name + "] ", w: log})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	timeout := mcpclient.Timeout(upstream.Server)
+	initCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	upstreamTools, err := listTools(initCtx, c, version)
+	if err != nil {
+		c.Close()
+		if initCtx.Err() != nil {
+			err = mcpclient.TimeoutError(timeout)
+		}
+		return nil, nil, err
+	}
+
+	prefix := toolPrefix(upstream.Name)
+	tools := make([]mcpserve.Tool, 0, len(upstreamTools))
+	for _, tool := range upstreamTools {
+		if !upstream.Tools.Allows(tool.Name) {
+			continue
+		}
+		name := tool.Name
+		tools = append(tools, mcpserve.Tool{
+			Name:        prefix + name,
+			Description: tool.Description,
+			InputSchema: tool.InputSchema,
+			Annotations: tool.Annotations,
+			Forward: func(ctx context.Context, args json.RawMessage) (json.RawMessage, error) {
+				result, err := c.Call(ctx, "tools/call", map[string]interface{}{"name": name, "arguments": args})
+				if err != nil {
+					return nil, callError(upstream.Name, err)
+				}
+				return result, nil
+			},
+		})
+	}
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	return c, tools, nil
+}
+
+// listTools performs the MCP handshake and lists the server's tools
+func listTools(ctx context.Context, c *mcpclient.Client, version string) ([]upstreamTool, error) {
+	if _, err := c.Initialize(ctx, "mcpjson-proxy", version); err != nil {
+		return nil, err
+	}
+	items, err := c.List(ctx, "tools/list", "tools")
+	if err != nil {
+		return nil, err
+	}
+
+	tools := make([]upstreamTool, 0, len(items))
+	for _, item := range items {
+		var tool upstreamTool
+		if err := json.Unmarshal(item, &tool); err != nil {
+			return nil, i18n.Errorf("proxy.invalid_tool", err)
+		}
+		tools = append(tools, tool)
+	}
+	return tools, nil
+}
+
+// callError names the upstream server in an error of a relayed call
+func callError(serverName string, err error) error {
+	var responseErr *mcpclient.ResponseError
+	if errors.As(err, &responseErr) {
+		return i18n.Errorf("proxy.error_response", serverName, responseErr.Message, responseErr.Code)
+	}
+	return i18n.Errorf("proxy.call_failed", serverName, err)
+}
+
+// toolPrefix returns the prefix of the tools of the named server
+func toolPrefix(serverName string) string {
+	return invalidToolChars.ReplaceAllString(serverName, "_") + Separator
+}
+
+// Tools returns the tools of every upstream server
+func (p *Proxy) Tools() []mcpserve.Tool {
+	return p.tools
+}
+
+// Close stops the upstream servers
+func (p *Proxy) Close() {
+	var wg sync.WaitGroup
+	for _, c := range p.clients {
+		wg.Add(1)
+		go func(c *mcpclient.Client) {
+			defer wg.Done()
+			c.Close()
+		}(c)
+	}
+	wg.Wait()
+}
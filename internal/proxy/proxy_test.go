@@ -0,0 +1,126 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/naoto24kawa/mcpjson/internal/mcpjson"
+	"github.com/naoto24kawa/mcpjson/internal/server"
+	"github.com/naoto24kawa/mcpjson/internal/testutil"
+)
+
+func TestMain(m *testing.M) {
+	testutil.RunFakeServerIfRequested()
+	os.Exit(m.Run())
+}
+
+// syncBuffer is written to by the stderr copiers of several servers
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestEntry(t *testing.T) {
+	tests := []struct {
+		name       string
+		store      string
+		projectDir string
+		want       []string
+	}{
+		{name: "個人ストア", store: "personal", want: []string{"proxy", "work"}},
+		{name: "選択したストア", store: "team", want: []string{"proxy", "work", "--store", "team"}},
+		{name: "プロジェクト", store: "project", projectDir: "/src/app", want: []string{"proxy", "work", "--store", "project", "--project", "/src/app"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := Entry("work", tt.store, tt.projectDir)
+			if entry.Command != "mcpjson" || !reflect.DeepEqual(entry.Args, tt.want) {
+				t.Errorf("Entry() = %s %v, want mcpjson %v", entry.Command, entry.Args, tt.want)
+			}
+		})
+	}
+}
+
+func TestStart(t *testing.T) {
+	upstreams := []Upstream{
+		{Name: "github", Server: testutil.FakeServer("github"), Tools: &mcpjson.ToolFilter{Deny: []string{"delete"}}},
+		{Name: "my.db", Server: testutil.FakeServer("db"), Tools: &mcpjson.ToolFilter{Allow: []string{"search", "f*"}}},
+		{Name: "my_db", Server: testutil.FakeServer("clash")},
+		{Name: "broken", Server: testutil.FakeServer("crash")},
+		{Name: "remote", Server: server.MCPServer{URL: "https://example.com/mcp"}},
+	}
+
+	var log syncBuffer
+	p := Start(context.Background(), upstreams, "v1.0.0", &log)
+	defer p.Close()
+
+	tools := map[string]func(context.Context, json.RawMessage) (json.RawMessage, error){}
+	var names []string
+	for _, tool := range p.Tools() {
+		names = append(names, tool.Name)
+		tools[tool.Name] = tool.Forward
+	}
+	want := "github__fail,github__fetch,github__search,my_db__fail,my_db__fetch,my_db__search"
+	if got := strings.Join(names, ","); got != want {
+		t.Fatalf("Tools() = %s, want %s\nlog:\n%s", got, want, log.String())
+	}
+
+	for _, wantLog := range []string{"[broken] fatal: API_KEY is not set", "'broken'", "'remote'", "'my_db'"} {
+		if !strings.Contains(log.String(), wantLog) {
+			t.Errorf("log = %q, want it to mention %q", log.String(), wantLog)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		tool     string
+		wantText string
+		wantErr  string
+	}{
+		{name: "元の名前で転送", tool: "github__search", wantText: `github search {"query":"mcp"}`},
+		{name: "別のサーバーへ転送", tool: "my_db__search", wantText: `db search {"query":"mcp"}`},
+		{name: "エラー応答", tool: "my_db__fail", wantErr: "database unavailable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tools[tt.tool](context.Background(), json.RawMessage(`{"query":"mcp"}`))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var decoded struct {
+				Content []struct {
+					Text string `json:"text"`
+				} `json:"content"`
+			}
+			if err := json.Unmarshal(result, &decoded); err != nil || len(decoded.Content) != 1 || decoded.Content[0].Text != tt.wantText {
+				t.Errorf("result = %s, want the text %s", result, tt.wantText)
+			}
+		})
+	}
+}
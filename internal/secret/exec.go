@@ -0,0 +1,62 @@
+package secret
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+)
+
+// NamePlaceholder is replaced with the secret name in an exec provider command
+const NamePlaceholder = "{name}"
+
+// ExecProvider resolves secrets by running an external command such as
+// `pass show {name}` and reading its standard output. The command is run
+// directly, without a shell. When no argument contains {name}, the name
+// is passed as the last argument.
+type ExecProvider struct {
+	command []string
+}
+
+// NewExecProvider returns a provider that runs command
+func NewExecProvider(command []string) (*ExecProvider, error) {
+	if len(command) == 0 || command[0] == "" {
+		return nil, i18n.Errorf("secret.no_command")
+	}
+	return &ExecProvider{command: append([]string(nil), command...)}, nil
+}
+
+// Get runs the command for name and returns its output without the
+// trailing newline
+func (p *ExecProvider) Get(name string) (string, error) {
+	if err := ValidateName(name); err != nil {
+		return "", err
+	}
+
+	args := make([]string, 0, len(p.command)+1)
+	replaced := false
+	for _, arg := range p.command[1:] {
+		if strings.Contains(arg, NamePlaceholder) {
+			arg = strings.ReplaceAll(arg, NamePlaceholder, name)
+			replaced = true
+		}
+		args = append(args, arg)
+	}
+	if !replaced {
+		args = append(args, name)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(p.command[0], args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", i18n.Errorf("secret.command_failed_output", err, msg)
+		}
+		return "", i18n.Errorf("secret.command_failed", err)
+	}
+
+	return strings.TrimRight(stdout.String(), "\r\n"), nil
+}
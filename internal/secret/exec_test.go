@@ -0,0 +1,51 @@
+//go:build !windows
+
+package secret
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExecProvider_Get(t *testing.T) {
+	tests := []struct {
+		name    string
+		command []string
+		want    string
+	}{
+		{"名前を置換", []string{"printf", "%s\n", "value-of-{name}"}, "value-of-token"},
+		{"名前を末尾に追加", []string{"echo"}, "token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := NewExecProvider(tt.command)
+			if err != nil {
+				t.Fatalf("NewExecProvider() failed: %v", err)
+			}
+			got, err := provider.Get("token")
+			if err != nil || got != tt.want {
+				t.Errorf("Get() = %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecProvider_Errors(t *testing.T) {
+	if _, err := NewExecProvider(nil); err == nil {
+		t.Error("NewExecProvider(nil) expected error")
+	}
+
+	provider, err := NewExecProvider([]string{"sh", "-c", "echo 'not in store' >&2; exit 1"})
+	if err != nil {
+		t.Fatalf("NewExecProvider() failed: %v", err)
+	}
+	if _, err := provider.Get("token"); err == nil || !strings.Contains(err.Error(), "not in store") {
+		t.Errorf("Get() error = %v, want command stderr", err)
+	}
+
+	// 名前はシェルを経由せずに渡されるが、不正な名前はそもそも拒否する
+	if _, err := provider.Get("a;rm"); err == nil {
+		t.Error("Get() with an invalid name expected error")
+	}
+}
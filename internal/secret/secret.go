@@ -0,0 +1,81 @@
+// Package secret provides the backends that resolve ${secret:name}
+// placeholders at apply time, so secret values never have to be stored
+// in server templates.
+package secret
+
+import (
+	"regexp"
+
+	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+)
+
+// ErrNotFound is returned when a secret is not defined in the backend
+var ErrNotFound = i18n.Error("secret.not_found")
+
+// ErrReadOnly is returned by OpenStore when the configured backend
+// cannot be written to
+var ErrReadOnly = i18n.Error("secret.read_only")
+
+const MaxNameLength = 100
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_./-]*$`)
+
+// SecretProvider resolves secret values by name
+type SecretProvider interface {
+	Get(name string) (string, error)
+}
+
+// Store is a SecretProvider that can also be written to
+type Store interface {
+	SecretProvider
+	Set(name, value string) error
+	Delete(name string) (bool, error)
+	List() ([]string, error)
+}
+
+// Open returns the provider selected in the settings file
+func Open(cfg *config.Config) (SecretProvider, error) {
+	settings, err := cfg.LoadSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	switch settings.Secrets.Provider {
+	case config.SecretProviderVault:
+		return NewVault(cfg.SecretsDir()), nil
+	case config.SecretProviderExec:
+		return NewExecProvider(settings.Secrets.Command)
+	default:
+		return nil, i18n.Errorf("secret.unknown_provider",
+			settings.Secrets.Provider, config.SecretProviderVault, config.SecretProviderExec)
+	}
+}
+
+// OpenStore is like Open but fails with ErrReadOnly when the configured
+// provider cannot store secrets
+func OpenStore(cfg *config.Config) (Store, error) {
+	provider, err := Open(cfg)
+	if err != nil {
+		return nil, err
+	}
+	store, ok := provider.(Store)
+	if !ok {
+		return nil, ErrReadOnly
+	}
+	return store, nil
+}
+
+// ValidateName checks that name can be used as a secret name
+func ValidateName(name string) error {
+	if name == "" {
+		return i18n.Errorf("secret.no_name")
+	}
+	if len(name) > MaxNameLength {
+		return i18n.Errorf("secret.name_too_long", MaxNameLength)
+	}
+	if !namePattern.MatchString(name) {
+		return i18n.Errorf("secret.invalid_name")
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package secret
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/naoto24kawa/mcpjson/internal/config"
+)
+
+func TestOpen(t *testing.T) {
+	tests := []struct {
+		name      string
+		settings  string
+		wantStore bool
+		wantErr   bool
+	}{
+		{"設定ファイルなしはvault", "", true, false},
+		{"vault", `{"secrets": {"provider": "vault"}}`, true, false},
+		{"exec", "{\n  // pass を使う\n  \"secrets\": {\"provider\": \"exec\", \"command\": [\"pass\", \"show\", \"{name}\"]},\n}", false, false},
+		{"execでコマンド未設定", `{"secrets": {"provider": "exec"}}`, false, true},
+		{"不明なプロバイダー", `{"secrets": {"provider": "keychain"}}`, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{BaseDir: t.TempDir()}
+			if tt.settings != "" {
+				if err := os.WriteFile(cfg.SettingsPath(), []byte(tt.settings), 0644); err != nil {
+					t.Fatalf("failed to write settings: %v", err)
+				}
+			}
+
+			provider, err := Open(cfg)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Open() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Open() failed: %v", err)
+			}
+
+			_, isStore := provider.(Store)
+			if isStore != tt.wantStore {
+				t.Errorf("provider is Store = %v, want %v", isStore, tt.wantStore)
+			}
+			if _, err := OpenStore(cfg); !tt.wantStore && !errors.Is(err, ErrReadOnly) {
+				t.Errorf("OpenStore() error = %v, want ErrReadOnly", err)
+			}
+		})
+	}
+}
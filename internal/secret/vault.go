@@ -0,0 +1,245 @@
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/naoto24kawa/mcpjson/internal/filelock"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/utils"
+)
+
+const (
+	VaultKeyFile  = "vault.key"
+	VaultFile     = "vault.json"
+	vaultVersion  = 1
+	vaultKeySize  = 32
+	vaultFilePerm = 0600
+	vaultDirPerm  = 0700
+)
+
+// Vault stores secrets encrypted with AES-256-GCM in a local file.
+// The key lives in a separate keyfile next to it that is created on
+// first use; both files are readable by the owner only.
+type Vault struct {
+	dir string
+}
+
+type vaultData struct {
+	Version int               `json:"version"`
+	Secrets map[string]string `json:"secrets"`
+}
+
+// NewVault returns a vault stored in dir
+func NewVault(dir string) *Vault {
+	return &Vault{dir: dir}
+}
+
+func (v *Vault) keyPath() string {
+	return filepath.Join(v.dir, VaultKeyFile)
+}
+
+func (v *Vault) dataPath() string {
+	return filepath.Join(v.dir, VaultFile)
+}
+
+// Get decrypts the secret called name
+func (v *Vault) Get(name string) (string, error) {
+	data, err := v.load()
+	if err != nil {
+		return "", err
+	}
+
+	sealed, ok := data.Secrets[name]
+	if !ok {
+		return "", ErrNotFound
+	}
+
+	key, err := v.loadKey(false)
+	if err != nil {
+		return "", err
+	}
+	return open(key, name, sealed)
+}
+
+// Set encrypts value and stores it as name, replacing any previous value
+func (v *Vault) Set(name, value string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+
+	return v.update(func(data *vaultData) error {
+		key, err := v.loadKey(true)
+		if err != nil {
+			return err
+		}
+		sealed, err := seal(key, name, value)
+		if err != nil {
+			return err
+		}
+		data.Secrets[name] = sealed
+		return nil
+	})
+}
+
+// Delete removes the secret called name and reports whether it existed
+func (v *Vault) Delete(name string) (bool, error) {
+	deleted := false
+	err := v.update(func(data *vaultData) error {
+		if _, ok := data.Secrets[name]; ok {
+			delete(data.Secrets, name)
+			deleted = true
+		}
+		return nil
+	})
+	return deleted, err
+}
+
+// List returns the names of all stored secrets in sorted order
+func (v *Vault) List() ([]string, error) {
+	data, err := v.load()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(data.Secrets))
+	for name := range data.Secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (v *Vault) update(fn func(data *vaultData) error) error {
+	unlock, err := filelock.LockStore(v.dir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	data, err := v.load()
+	if err != nil {
+		return err
+	}
+	if err := fn(data); err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return i18n.Errorf("secret.save_failed", err)
+	}
+	if err := os.MkdirAll(v.dir, vaultDirPerm); err != nil {
+		return i18n.Errorf("secret.mkdir_failed", err)
+	}
+	if err := utils.WriteFileAtomic(v.dataPath(), append(content, '\n'), vaultFilePerm); err != nil {
+		return i18n.Errorf("secret.save_failed", err)
+	}
+	return nil
+}
+
+func (v *Vault) load() (*vaultData, error) {
+	data := &vaultData{Version: vaultVersion, Secrets: make(map[string]string)}
+
+	content, err := os.ReadFile(v.dataPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return data, nil
+		}
+		return nil, i18n.Errorf("secret.load_failed", err)
+	}
+
+	if err := json.Unmarshal(content, data); err != nil {
+		return nil, i18n.Errorf("secret.parse_failed", v.dataPath(), err)
+	}
+	if data.Version != vaultVersion {
+		return nil, i18n.Errorf("secret.unsupported_version", data.Version)
+	}
+	if data.Secrets == nil {
+		data.Secrets = make(map[string]string)
+	}
+	return data, nil
+}
+
+// loadKey reads the vault key, generating it when create is set and no
+// key exists yet
+func (v *Vault) loadKey(create bool) ([]byte, error) {
+	content, err := os.ReadFile(v.keyPath())
+	if err == nil {
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(content)))
+		if err != nil || len(key) != vaultKeySize {
+			return nil, i18n.Errorf("secret.key_invalid", v.keyPath())
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, i18n.Errorf("secret.key_read_failed", err)
+	}
+	if !create {
+		return nil, i18n.Errorf("secret.key_not_found", v.keyPath())
+	}
+
+	key := make([]byte, vaultKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, i18n.Errorf("secret.keygen_failed", err)
+	}
+	if err := os.MkdirAll(v.dir, vaultDirPerm); err != nil {
+		return nil, i18n.Errorf("secret.mkdir_failed", err)
+	}
+	encoded := base64.StdEncoding.EncodeToString(key) + "\n"
+	if err := utils.WriteFileAtomic(v.keyPath(), []byte(encoded), vaultFilePerm); err != nil {
+		return nil, i18n.Errorf("secret.key_save_failed", err)
+	}
+	return key, nil
+}
+
+// seal encrypts value, binding it to name so entries cannot be swapped
+func seal(key []byte, name, value string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", i18n.Errorf("secret.encrypt_failed", err)
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(value), []byte(name))
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func open(key []byte, name, sealed string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil || len(raw) < gcm.NonceSize() {
+		return "", i18n.Errorf("secret.corrupted", name)
+	}
+	plain, err := gcm.Open(nil, raw[:gcm.NonceSize()], raw[gcm.NonceSize():], []byte(name))
+	if err != nil {
+		return "", i18n.Errorf("secret.decrypt_failed", name)
+	}
+	return string(plain), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, i18n.Errorf("secret.cipher_failed", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, i18n.Errorf("secret.cipher_failed", err)
+	}
+	return gcm, nil
+}
@@ -0,0 +1,122 @@
+package secret
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVault_RoundTrip(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "secrets")
+	vault := NewVault(dir)
+
+	if err := vault.Set("github-token", "ghp_123"); err != nil {
+		t.Fatalf("Set() failed: %v", err)
+	}
+	if err := vault.Set("openai/key", "sk-456"); err != nil {
+		t.Fatalf("Set() failed: %v", err)
+	}
+
+	value, err := vault.Get("github-token")
+	if err != nil || value != "ghp_123" {
+		t.Errorf("Get() = %q, %v; want ghp_123", value, err)
+	}
+
+	names, err := vault.List()
+	if err != nil {
+		t.Fatalf("List() failed: %v", err)
+	}
+	if strings.Join(names, ",") != "github-token,openai/key" {
+		t.Errorf("List() = %v", names)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, VaultFile))
+	if err != nil {
+		t.Fatalf("failed to read vault: %v", err)
+	}
+	if strings.Contains(string(content), "ghp_123") {
+		t.Error("vault file must not contain plaintext values")
+	}
+	for _, name := range []string{VaultFile, VaultKeyFile} {
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("Stat(%s) failed: %v", name, err)
+		}
+		if perm := info.Mode().Perm(); perm != 0600 {
+			t.Errorf("%s permissions = %o, want 600", name, perm)
+		}
+	}
+
+	deleted, err := vault.Delete("github-token")
+	if err != nil || !deleted {
+		t.Errorf("Delete() = %v, %v; want true, nil", deleted, err)
+	}
+	if _, err := vault.Get("github-token"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() after Delete error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestVault_WrongKey(t *testing.T) {
+	dir := t.TempDir()
+	vault := NewVault(dir)
+	if err := vault.Set("token", "value"); err != nil {
+		t.Fatalf("Set() failed: %v", err)
+	}
+
+	if err := os.Remove(filepath.Join(dir, VaultKeyFile)); err != nil {
+		t.Fatalf("failed to remove key: %v", err)
+	}
+	if _, err := vault.Get("token"); err == nil {
+		t.Error("Get() without a key file should fail")
+	}
+
+	other := NewVault(t.TempDir())
+	if err := other.Set("unused", "x"); err != nil {
+		t.Fatalf("Set() failed: %v", err)
+	}
+	key, _ := os.ReadFile(filepath.Join(other.dir, VaultKeyFile))
+	if err := os.WriteFile(filepath.Join(dir, VaultKeyFile), key, 0600); err != nil {
+		t.Fatalf("failed to write key: %v", err)
+	}
+	if _, err := vault.Get("token"); err == nil || !strings.Contains(err.Error(), "復号できません") {
+		t.Errorf("Get() with another key error = %v, want decryption failure", err)
+	}
+}
+
+func TestVault_ValuesAreBoundToNames(t *testing.T) {
+	dir := t.TempDir()
+	vault := NewVault(dir)
+	if err := vault.Set("a", "value-a"); err != nil {
+		t.Fatalf("Set() failed: %v", err)
+	}
+
+	data, err := vault.load()
+	if err != nil {
+		t.Fatalf("load() failed: %v", err)
+	}
+	key, err := vault.loadKey(false)
+	if err != nil {
+		t.Fatalf("loadKey() failed: %v", err)
+	}
+	if _, err := open(key, "b", data.Secrets["a"]); err == nil {
+		t.Error("a value sealed for one name must not decrypt under another")
+	}
+}
+
+func TestVault_EmptyAndInvalidNames(t *testing.T) {
+	vault := NewVault(filepath.Join(t.TempDir(), "secrets"))
+
+	if names, err := vault.List(); err != nil || len(names) != 0 {
+		t.Errorf("List() on a new vault = %v, %v", names, err)
+	}
+	if deleted, err := vault.Delete("missing"); err != nil || deleted {
+		t.Errorf("Delete(missing) = %v, %v; want false, nil", deleted, err)
+	}
+	for _, name := range []string{"", "-leading", "has space", "${x}"} {
+		if err := vault.Set(name, "v"); err == nil {
+			t.Errorf("Set(%q) expected error", name)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+// Package sensitive finds values in server settings that are likely to be
+// credentials: env vars and headers with sensitive names, the values of
+// sensitive command-line flags and the password and sensitive query
+// parameters of URLs. Export replaces them with secret references, and
+// diff output hides them.
+package sensitive
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// keyPattern matches env, header, flag and variable names whose values
+// are likely to be credentials
+var keyPattern = regexp.MustCompile(`(?i)(token|secret|passw(or)?d|api[_-]?key|access[_-]?key|private[_-]?key|credential|auth)`)
+
+// PasswordName is the name Replacer is called with for the password of
+// the user info of a URL
+const PasswordName = "password"
+
+// Replacer returns the text that takes the place of value, the value of
+// the sensitive entry name, or false to keep value as it is
+type Replacer func(name, value string) (string, bool)
+
+// IsKey reports whether name looks like the name of a credential
+func IsKey(name string) bool {
+	return keyPattern.MatchString(name)
+}
+
+// Values replaces the values of sensitive keys in values and reports
+// whether it replaced any
+func Values(values map[string]string, replace Replacer) bool {
+	changed := false
+	for key, value := range values {
+		if value == "" || !IsKey(key) {
+			continue
+		}
+		if text, ok := replace(key, value); ok {
+			values[key] = text
+			changed = true
+		}
+	}
+	return changed
+}
+
+// Args replaces the values of sensitive flags, given either as
+// --flag=value or as --flag value, and reports whether it replaced any.
+// The flag name without leading dashes is passed to replace.
+func Args(args []string, replace Replacer) bool {
+	changed := false
+	for i := 0; i < len(args); i++ {
+		if !strings.HasPrefix(args[i], "-") {
+			continue
+		}
+		flag := strings.TrimLeft(args[i], "-")
+		if name, value, ok := strings.Cut(flag, "="); ok {
+			if value != "" && IsKey(name) {
+				if text, ok := replace(name, value); ok {
+					args[i] = strings.TrimSuffix(args[i], value) + text
+					changed = true
+				}
+			}
+			continue
+		}
+		if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") && args[i+1] != "" && IsKey(flag) {
+			i++
+			if text, ok := replace(flag, args[i]); ok {
+				args[i] = text
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// CommandLine replaces the values of sensitive flags in a command line
+// whose words are separated by spaces, and reports whether it replaced any
+func CommandLine(commandLine *string, replace Replacer) bool {
+	words := strings.Fields(*commandLine)
+	if !Args(words, replace) {
+		return false
+	}
+	*commandLine = strings.Join(words, " ")
+	return true
+}
+
+// URL replaces the password of the user info and the values of sensitive
+// query parameters in *rawURL, and reports whether it replaced any. The
+// URL is edited as text so that the replacements are not escaped.
+func URL(rawURL *string, replace Replacer) bool {
+	changed := false
+	rest, fragment, hasFragment := strings.Cut(*rawURL, "#")
+	base, query, hasQuery := strings.Cut(rest, "?")
+
+	if scheme, authority, ok := strings.Cut(base, "://"); ok {
+		host, path, hasPath := strings.Cut(authority, "/")
+		if userInfo, hostPort, ok := cutLast(host, "@"); ok {
+			if user, password, ok := strings.Cut(userInfo, ":"); ok && password != "" {
+				if text, ok := replace(PasswordName, password); ok {
+					host = user + ":" + text + "@" + hostPort
+					changed = true
+				}
+			}
+		}
+		base = scheme + "://" + host
+		if hasPath {
+			base += "/" + path
+		}
+	}
+
+	if hasQuery {
+		params := strings.Split(query, "&")
+		for i, param := range params {
+			key, value, ok := strings.Cut(param, "=")
+			name, err := url.QueryUnescape(key)
+			if !ok || err != nil || value == "" || !IsKey(name) {
+				continue
+			}
+			if text, ok := replace(name, value); ok {
+				params[i] = key + "=" + text
+				changed = true
+			}
+		}
+		base += "?" + strings.Join(params, "&")
+	}
+	if hasFragment {
+		base += "#" + fragment
+	}
+
+	if changed {
+		*rawURL = base
+	}
+	return changed
+}
+
+// cutLast is strings.Cut around the last instance of sep
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
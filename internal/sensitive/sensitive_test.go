@@ -0,0 +1,85 @@
+package sensitive
+
+import (
+	"reflect"
+	"testing"
+)
+
+func mark(name, value string) (string, bool) {
+	return "<" + name + ">", true
+}
+
+func TestArgs(t *testing.T) {
+	args := []string{"server", "--token=abc", "--api-key", "xyz", "--repo", "/src", "-v"}
+	if !Args(args, mark) {
+		t.Fatal("Args() reported no change")
+	}
+
+	want := []string{"server", "--token=<token>", "--api-key", "<api-key>", "--repo", "/src", "-v"}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("Args() = %v, want %v", args, want)
+	}
+}
+
+func TestArgs_Keep(t *testing.T) {
+	args := []string{"--token=${secret:token}"}
+	keep := func(name, value string) (string, bool) { return "", false }
+	if Args(args, keep) {
+		t.Error("Args() reported a change when the replacer kept every value")
+	}
+	if args[0] != "--token=${secret:token}" {
+		t.Errorf("Args() = %v", args)
+	}
+}
+
+func TestURL(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{
+			name: "パスワードとクエリ",
+			url:  "https://user:pw@example.com/mcp?access_token=abc&region=eu#top",
+			want: "https://user:<password>@example.com/mcp?access_token=<access_token>&region=eu#top",
+		},
+		{
+			name: "機密情報なし",
+			url:  "https://example.com/mcp?region=eu",
+			want: "https://example.com/mcp?region=eu",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.url
+			changed := URL(&got, mark)
+			if got != tt.want {
+				t.Errorf("URL() = %q, want %q", got, tt.want)
+			}
+			if changed != (tt.url != tt.want) {
+				t.Errorf("URL() changed = %v", changed)
+			}
+		})
+	}
+}
+
+func TestCommandLine(t *testing.T) {
+	commandLine := "run-server --password pw --port 80"
+	if !CommandLine(&commandLine, mark) {
+		t.Fatal("CommandLine() reported no change")
+	}
+	if want := "run-server --password <password> --port 80"; commandLine != want {
+		t.Errorf("CommandLine() = %q, want %q", commandLine, want)
+	}
+}
+
+func TestValues(t *testing.T) {
+	values := map[string]string{"GITHUB_TOKEN": "abc", "LOG_LEVEL": "debug", "API_KEY": ""}
+	Values(values, mark)
+
+	want := map[string]string{"GITHUB_TOKEN": "<GITHUB_TOKEN>", "LOG_LEVEL": "debug", "API_KEY": ""}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("Values() = %v, want %v", values, want)
+	}
+}
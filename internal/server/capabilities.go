@@ -0,0 +1,171 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/jsonedit"
+	"github.com/naoto24kawa/mcpjson/internal/utils"
+)
+
+// Capabilities is what a server exposed when it was last inspected. It is
+// cached in the template so that profiles can be examined without
+// launching their servers.
+type Capabilities struct {
+	InspectedAt   time.Time  `json:"inspectedAt"`
+	ServerName    string     `json:"serverName,omitempty"`
+	ServerVersion string     `json:"serverVersion,omitempty"`
+	Tools         []Tool     `json:"tools"`
+	Prompts       []Prompt   `json:"prompts"`
+	Resources     []Resource `json:"resources"`
+}
+
+// Tool is a tool offered by a server
+type Tool struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// InputSchema is the JSON Schema of the tool's arguments
+	InputSchema json.RawMessage `json:"inputSchema,omitempty"`
+}
+
+// Prompt is a prompt template offered by a server
+type Prompt struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Arguments   []PromptArgument `json:"arguments,omitempty"`
+}
+
+// PromptArgument is an argument of a prompt
+type PromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// Resource is a resource offered by a server
+type Resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+// SaveCapabilities records what the template's server exposed in the
+// template file of the selected store. Only the capabilities member is
+// rewritten, so the rest of the file keeps its comments and layout.
+// A template read from another store is refused, since commands only
+// write to the selected store.
+func (m *Manager) SaveCapabilities(templateName string, capabilities *Capabilities) error {
+	return m.withStoreLock(func() error {
+		path := m.templateManager.getTemplatePath(templateName)
+		if lookupPath := m.templateManager.lookupPath(templateName); lookupPath != path {
+			return i18n.Errorf("server.capabilities_other_store", templateName, lookupPath)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return i18n.Errorf("common.template_not_found", templateName)
+			}
+			return i18n.Errorf("server.capabilities_save_failed", templateName, err)
+		}
+		doc, err := jsonedit.Parse(data)
+		if err != nil {
+			return i18n.Errorf("server.capabilities_save_failed", templateName, err)
+		}
+		if err := doc.Set(capabilities, "capabilities"); err != nil {
+			return i18n.Errorf("server.capabilities_save_failed", templateName, err)
+		}
+		if err := utils.WriteFileAtomic(path, doc.Bytes(), 0644); err != nil {
+			return i18n.Errorf("server.capabilities_save_failed", templateName, err)
+		}
+		return nil
+	})
+}
+
+// WriteCapabilities renders the capabilities for people
+func WriteCapabilities(w io.Writer, templateName string, capabilities *Capabilities) {
+	fmt.Fprintln(w, i18n.T("server.inspect_header", templateName,
+		strings.TrimSpace(capabilities.ServerName+" "+capabilities.ServerVersion),
+		capabilities.InspectedAt.Format("2006-01-02 15:04:05")))
+
+	fmt.Fprintln(w, i18n.T("server.inspect_tools", len(capabilities.Tools)))
+	for _, tool := range capabilities.Tools {
+		writeItem(w, tool.Name+"("+strings.Join(schemaParams(tool.InputSchema), ", ")+")", tool.Description)
+	}
+
+	fmt.Fprintln(w, i18n.T("server.inspect_prompts", len(capabilities.Prompts)))
+	for _, prompt := range capabilities.Prompts {
+		args := make([]string, 0, len(prompt.Arguments))
+		for _, arg := range prompt.Arguments {
+			args = append(args, paramLabel(arg.Name, arg.Required))
+		}
+		writeItem(w, prompt.Name+"("+strings.Join(args, ", ")+")", prompt.Description)
+	}
+
+	fmt.Fprintln(w, i18n.T("server.inspect_resources", len(capabilities.Resources)))
+	for _, resource := range capabilities.Resources {
+		label := resource.URI
+		if resource.Name != "" {
+			label += " (" + resource.Name + ")"
+		}
+		writeItem(w, label, resource.Description)
+	}
+}
+
+func writeItem(w io.Writer, label, description string) {
+	if description == "" {
+		fmt.Fprintf(w, "  - %s\n", label)
+		return
+	}
+	fmt.Fprintf(w, "  - %s: %s\n", label, firstLine(description))
+}
+
+// schemaParams lists the properties of an object schema, required ones
+// marked with *
+func schemaParams(schema json.RawMessage) []string {
+	var parsed struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+		Required   []string                   `json:"required"`
+	}
+	if len(schema) == 0 || json.Unmarshal(schema, &parsed) != nil {
+		return nil
+	}
+
+	required := make(map[string]bool, len(parsed.Required))
+	for _, name := range parsed.Required {
+		required[name] = true
+	}
+	names := make([]string, 0, len(parsed.Properties))
+	for name := range parsed.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	params := make([]string, 0, len(names))
+	for _, name := range names {
+		params = append(params, paramLabel(name, required[name]))
+	}
+	return params
+}
+
+func paramLabel(name string, required bool) string {
+	if required {
+		return name + "*"
+	}
+	return name
+}
+
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
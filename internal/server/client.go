@@ -0,0 +1,337 @@
+package server
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/utils"
+)
+
+// Client names accepted by --client and --from-client
+const (
+	ClientClaudeDesktop = "claude-desktop"
+	ClientClaudeCode    = "claude-code"
+	ClientCursor        = "cursor"
+	ClientVSCode        = "vscode"
+	ClientWindsurf      = "windsurf"
+)
+
+// ClientAdapter describes the MCP config file of one client: where it
+// lives, which key holds the servers and how a server entry is spelled.
+// Decode is the read adapter and Encode the write adapter.
+type ClientAdapter interface {
+	// Name is the client name used on the command line
+	Name() string
+	// DefaultPath returns the file the client reads when no path is given
+	DefaultPath() (string, error)
+	// ServersKey is the top-level key holding the server entries
+	ServersKey() string
+	// Fields lists the entry fields Encode owns. Other fields of an
+	// existing entry are left untouched when it is rewritten.
+	Fields() []string
+	// Decode converts an entry of the client's file into a server
+	Decode(name string, raw json.RawMessage) (MCPServer, error)
+	// Encode converts a server into an entry of the client's file
+	Encode(name string, server MCPServer) (interface{}, error)
+}
+
+var clients = map[string]ClientAdapter{
+	ClientClaudeDesktop: claudeDesktopClient{},
+	ClientClaudeCode:    claudeCodeClient{},
+	ClientCursor:        cursorClient{},
+	ClientVSCode:        vscodeClient{},
+	ClientWindsurf:      windsurfClient{},
+}
+
+// DefaultClient is the adapter for plain .mcp.json files, used when no
+// client is selected
+var DefaultClient ClientAdapter = claudeCodeClient{}
+
+// LookupClient returns the adapter of the named client
+func LookupClient(name string) (ClientAdapter, error) {
+	client, ok := clients[strings.ToLower(name)]
+	if !ok {
+		return nil, i18n.Errorf("client.unknown", name, strings.Join(ClientNames(), ", "))
+	}
+	return client, nil
+}
+
+// ClientNames returns the names of the supported clients in sorted order
+func ClientNames() []string {
+	names := make([]string, 0, len(clients))
+	for name := range clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ConvertServer returns the server as the client reads it back after
+// writing it, dropping whatever the client's format cannot express
+func ConvertServer(client ClientAdapter, name string, server MCPServer) (MCPServer, error) {
+	entry, err := client.Encode(name, server)
+	if err != nil {
+		return MCPServer{}, err
+	}
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		return MCPServer{}, err
+	}
+	return client.Decode(name, raw)
+}
+
+// DroppedFields lists the fields of server that were lost when it was
+// converted for a client (see ConvertServer)
+func DroppedFields(server, converted MCPServer) []string {
+	var dropped []string
+	if server.EnvFile != nil && converted.EnvFile == nil {
+		dropped = append(dropped, "envFile")
+	}
+	if server.Timeout != nil && converted.Timeout == nil {
+		dropped = append(dropped, "timeout")
+	}
+	return dropped
+}
+
+// inlineEnv returns the server's env with the variables of its env file
+// merged in, for clients that cannot read an env file themselves. The
+// server's own variables win, as when mcpjson launches it (see Environ).
+func inlineEnv(server MCPServer) (map[string]string, error) {
+	if server.EnvFile == nil || *server.EnvFile == "" {
+		return server.Env, nil
+	}
+	fileEnv, err := utils.LoadEnvFile(*server.EnvFile)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range server.Env {
+		fileEnv[key] = value
+	}
+	return fileEnv, nil
+}
+
+func decodeEntry(name string, raw json.RawMessage, v interface{}) error {
+	if err := json.Unmarshal(raw, v); err != nil {
+		return i18n.Errorf("server.server_parse_failed", name, err)
+	}
+	return nil
+}
+
+func homePath(elem ...string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", i18n.Errorf("config.home_dir_failed", err)
+	}
+	return filepath.Join(append([]string{home}, elem...)...), nil
+}
+
+// claudeCodeClient writes .mcp.json as Claude Code reads it. This is the
+// format mcpjson has always written, so every field is kept.
+type claudeCodeClient struct{}
+
+func (claudeCodeClient) Name() string                 { return ClientClaudeCode }
+func (claudeCodeClient) DefaultPath() (string, error) { return "./.mcp.json", nil }
+func (claudeCodeClient) ServersKey() string           { return MCPServersKey }
+func (claudeCodeClient) Fields() []string             { return mcpServerFields() }
+
+func (claudeCodeClient) Decode(name string, raw json.RawMessage) (MCPServer, error) {
+	var server MCPServer
+	err := decodeEntry(name, raw, &server)
+	return server, err
+}
+
+func (claudeCodeClient) Encode(name string, server MCPServer) (interface{}, error) {
+	return server, nil
+}
+
+// claudeDesktopClient writes claude_desktop_config.json, which only
+// launches local stdio servers
+type claudeDesktopClient struct{}
+
+type claudeDesktopEntry struct {
+	Command string            `json:"command"`
+	Args    []string          `json:"args,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+}
+
+func (claudeDesktopClient) Name() string       { return ClientClaudeDesktop }
+func (claudeDesktopClient) ServersKey() string { return MCPServersKey }
+func (claudeDesktopClient) Fields() []string   { return []string{"command", "args", "env"} }
+
+func (claudeDesktopClient) DefaultPath() (string, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		return homePath("Library", "Application Support", "Claude", "claude_desktop_config.json")
+	case "windows":
+		if appData := os.Getenv("APPDATA"); appData != "" {
+			return filepath.Join(appData, "Claude", "claude_desktop_config.json"), nil
+		}
+		return homePath("AppData", "Roaming", "Claude", "claude_desktop_config.json")
+	}
+	if configHome := os.Getenv("XDG_CONFIG_HOME"); configHome != "" {
+		return filepath.Join(configHome, "Claude", "claude_desktop_config.json"), nil
+	}
+	return homePath(".config", "Claude", "claude_desktop_config.json")
+}
+
+func (claudeDesktopClient) Decode(name string, raw json.RawMessage) (MCPServer, error) {
+	var entry claudeDesktopEntry
+	if err := decodeEntry(name, raw, &entry); err != nil {
+		return MCPServer{}, err
+	}
+	return MCPServer{Command: entry.Command, Args: entry.Args, Env: entry.Env}, nil
+}
+
+func (claudeDesktopClient) Encode(name string, server MCPServer) (interface{}, error) {
+	if server.IsRemote() {
+		return nil, i18n.Errorf("client.remote_unsupported", ClientClaudeDesktop, server.ResolvedType(), name)
+	}
+	env, err := inlineEnv(server)
+	if err != nil {
+		return nil, err
+	}
+	return claudeDesktopEntry{Command: server.Command, Args: server.Args, Env: env}, nil
+}
+
+// cursorClient writes .cursor/mcp.json. Cursor tells remote servers from
+// local ones by the presence of url, so no type is written.
+type cursorClient struct{}
+
+type cursorEntry struct {
+	Command string            `json:"command,omitempty"`
+	Args    []string          `json:"args,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+	EnvFile *string           `json:"envFile,omitempty"`
+	URL     string            `json:"url,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+func (cursorClient) Name() string       { return ClientCursor }
+func (cursorClient) ServersKey() string { return MCPServersKey }
+
+func (cursorClient) DefaultPath() (string, error) {
+	return filepath.Join(".", ".cursor", "mcp.json"), nil
+}
+
+func (cursorClient) Fields() []string {
+	return []string{"type", "command", "args", "env", "envFile", "url", "headers"}
+}
+
+func (cursorClient) Decode(name string, raw json.RawMessage) (MCPServer, error) {
+	var server MCPServer
+	if err := decodeEntry(name, raw, &server); err != nil {
+		return MCPServer{}, err
+	}
+	return MCPServer{
+		Type: server.Type, Command: server.Command, Args: server.Args, Env: server.Env,
+		EnvFile: server.EnvFile, URL: server.URL, Headers: server.Headers,
+	}, nil
+}
+
+func (cursorClient) Encode(name string, server MCPServer) (interface{}, error) {
+	if server.IsRemote() {
+		return cursorEntry{URL: server.URL, Headers: server.Headers}, nil
+	}
+	return cursorEntry{Command: server.Command, Args: server.Args, Env: server.Env, EnvFile: server.EnvFile}, nil
+}
+
+// vscodeClient writes .vscode/mcp.json, which keeps its servers under
+// "servers" and always states the type
+type vscodeClient struct{}
+
+type vscodeEntry struct {
+	Type    string            `json:"type"`
+	Command string            `json:"command,omitempty"`
+	Args    []string          `json:"args,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+	EnvFile *string           `json:"envFile,omitempty"`
+	URL     string            `json:"url,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+func (vscodeClient) Name() string       { return ClientVSCode }
+func (vscodeClient) ServersKey() string { return "servers" }
+
+func (vscodeClient) DefaultPath() (string, error) {
+	return filepath.Join(".", ".vscode", "mcp.json"), nil
+}
+
+func (vscodeClient) Fields() []string {
+	return []string{"type", "command", "args", "env", "envFile", "url", "headers"}
+}
+
+func (vscodeClient) Decode(name string, raw json.RawMessage) (MCPServer, error) {
+	var entry vscodeEntry
+	if err := decodeEntry(name, raw, &entry); err != nil {
+		return MCPServer{}, err
+	}
+	return MCPServer{
+		Type: entry.Type, Command: entry.Command, Args: entry.Args, Env: entry.Env,
+		EnvFile: entry.EnvFile, URL: entry.URL, Headers: entry.Headers,
+	}, nil
+}
+
+func (vscodeClient) Encode(name string, server MCPServer) (interface{}, error) {
+	if server.IsRemote() {
+		return vscodeEntry{Type: server.ResolvedType(), URL: server.URL, Headers: server.Headers}, nil
+	}
+	return vscodeEntry{
+		Type: ServerTypeStdio, Command: server.Command, Args: server.Args, Env: server.Env, EnvFile: server.EnvFile,
+	}, nil
+}
+
+// windsurfClient writes ~/.codeium/windsurf/mcp_config.json, where remote
+// servers are given by serverUrl
+type windsurfClient struct{}
+
+type windsurfEntry struct {
+	Command   string            `json:"command,omitempty"`
+	Args      []string          `json:"args,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+	ServerURL string            `json:"serverUrl,omitempty"`
+	URL       string            `json:"url,omitempty"`
+	Headers   map[string]string `json:"headers,omitempty"`
+}
+
+func (windsurfClient) Name() string       { return ClientWindsurf }
+func (windsurfClient) ServersKey() string { return MCPServersKey }
+
+func (windsurfClient) Fields() []string {
+	return []string{"command", "args", "env", "serverUrl", "url", "headers"}
+}
+
+func (windsurfClient) DefaultPath() (string, error) {
+	return homePath(".codeium", "windsurf", "mcp_config.json")
+}
+
+func (windsurfClient) Decode(name string, raw json.RawMessage) (MCPServer, error) {
+	var entry windsurfEntry
+	if err := decodeEntry(name, raw, &entry); err != nil {
+		return MCPServer{}, err
+	}
+	server := MCPServer{Command: entry.Command, Args: entry.Args, Env: entry.Env, Headers: entry.Headers}
+	if url := entry.ServerURL; url != "" || entry.URL != "" {
+		if url == "" {
+			url = entry.URL
+		}
+		server.Type = ServerTypeHTTP
+		server.URL = url
+	}
+	return server, nil
+}
+
+func (windsurfClient) Encode(name string, server MCPServer) (interface{}, error) {
+	if server.IsRemote() {
+		return windsurfEntry{ServerURL: server.URL, Headers: server.Headers}, nil
+	}
+	env, err := inlineEnv(server)
+	if err != nil {
+		return nil, err
+	}
+	return windsurfEntry{Command: server.Command, Args: server.Args, Env: env}, nil
+}
@@ -0,0 +1,193 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func clientTestServers(stdioType, remoteType string) map[string]MCPServer {
+	servers := map[string]MCPServer{
+		"filesystem": {
+			Type:    stdioType,
+			Command: "npx",
+			Args:    []string{"-y", "@modelcontextprotocol/server-filesystem", "."},
+			Env:     map[string]string{"LOG_LEVEL": "info"},
+		},
+	}
+	if remoteType != "-" {
+		servers["remote"] = MCPServer{
+			Type:    remoteType,
+			URL:     "https://example.com/mcp",
+			Headers: map[string]string{"Authorization": "Bearer token"},
+		}
+	}
+	return servers
+}
+
+func TestClientAdapters(t *testing.T) {
+	tests := []struct {
+		client string
+		want   map[string]MCPServer
+	}{
+		{client: ClientClaudeCode, want: clientTestServers(ServerTypeStdio, ServerTypeHTTP)},
+		{client: ClientClaudeDesktop, want: clientTestServers("", "-")},
+		{client: ClientCursor, want: clientTestServers("", "")},
+		{client: ClientVSCode, want: clientTestServers(ServerTypeStdio, ServerTypeHTTP)},
+		{client: ClientWindsurf, want: clientTestServers("", ServerTypeHTTP)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.client, func(t *testing.T) {
+			client, err := LookupClient(tt.client)
+			if err != nil {
+				t.Fatalf("LookupClient() error = %v", err)
+			}
+			fixture := filepath.Join("testdata", "clients", tt.client+".json")
+			data, err := os.ReadFile(fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// 読み込み
+			doc, err := LoadMCPDocumentFor(fixture, client)
+			if err != nil {
+				t.Fatalf("LoadMCPDocumentFor() error = %v", err)
+			}
+			got, err := doc.Config()
+			if err != nil {
+				t.Fatalf("Config() error = %v", err)
+			}
+			if !reflect.DeepEqual(got.McpServers, tt.want) {
+				t.Errorf("read servers = %+v, want %+v", got.McpServers, tt.want)
+			}
+
+			// 同じ内容の書き戻しはファイルを変えない
+			if err := doc.ReplaceServers(tt.want); err != nil {
+				t.Fatalf("ReplaceServers() error = %v", err)
+			}
+			rewritten, err := doc.Bytes()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(rewritten) != string(data) {
+				t.Errorf("rewritten file differs from the fixture:\n%s", rewritten)
+			}
+
+			// 新規作成したファイルを読み戻す
+			created := NewMCPDocumentFor(client)
+			if err := created.ReplaceServers(tt.want); err != nil {
+				t.Fatalf("ReplaceServers() error = %v", err)
+			}
+			createdData, err := created.Bytes()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(createdData), `"`+client.ServersKey()+`"`) {
+				t.Errorf("created file has no %q key:\n%s", client.ServersKey(), createdData)
+			}
+			reread, err := ParseMCPDocumentFor(createdData, client)
+			if err != nil {
+				t.Fatalf("ParseMCPDocumentFor() error = %v", err)
+			}
+			if got, _ := reread.Config(); !reflect.DeepEqual(got.McpServers, tt.want) {
+				t.Errorf("created servers = %+v, want %+v", got.McpServers, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertServer(t *testing.T) {
+	timeout := 30
+	remote := MCPServer{Type: ServerTypeSSE, URL: "https://example.com/sse", Timeout: &timeout}
+
+	tests := []struct {
+		client  string
+		want    MCPServer
+		wantErr bool
+	}{
+		{client: ClientClaudeCode, want: remote},
+		{client: ClientClaudeDesktop, wantErr: true},
+		{client: ClientCursor, want: MCPServer{URL: "https://example.com/sse"}},
+		{client: ClientVSCode, want: MCPServer{Type: ServerTypeSSE, URL: "https://example.com/sse"}},
+		{client: ClientWindsurf, want: MCPServer{Type: ServerTypeHTTP, URL: "https://example.com/sse"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.client, func(t *testing.T) {
+			client, err := LookupClient(tt.client)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := ConvertServer(client, "remote", remote)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ConvertServer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ConvertServer() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertServer_EnvFile(t *testing.T) {
+	envFile := filepath.Join("testdata", "clients", "filesystem.env")
+	timeout := 30
+	local := MCPServer{
+		Command: "npx",
+		Env:     map[string]string{"LOG_LEVEL": "info"},
+		EnvFile: &envFile,
+		Timeout: &timeout,
+	}
+	// env ファイルを読めないクライアントには変数を展開して書き込む
+	inlined := MCPServer{Command: "npx", Env: map[string]string{"LOG_LEVEL": "info", "API_KEY": "secret"}}
+
+	tests := []struct {
+		client      string
+		want        MCPServer
+		wantDropped []string
+	}{
+		{client: ClientClaudeCode, want: local},
+		{client: ClientClaudeDesktop, want: inlined, wantDropped: []string{"envFile", "timeout"}},
+		{client: ClientCursor, want: MCPServer{Command: "npx", Env: local.Env, EnvFile: &envFile}, wantDropped: []string{"timeout"}},
+		{client: ClientVSCode, want: MCPServer{Type: ServerTypeStdio, Command: "npx", Env: local.Env, EnvFile: &envFile}, wantDropped: []string{"timeout"}},
+		{client: ClientWindsurf, want: inlined, wantDropped: []string{"envFile", "timeout"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.client, func(t *testing.T) {
+			client, err := LookupClient(tt.client)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := ConvertServer(client, "filesystem", local)
+			if err != nil {
+				t.Fatalf("ConvertServer() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ConvertServer() = %+v, want %+v", got, tt.want)
+			}
+			if dropped := DroppedFields(local, got); !reflect.DeepEqual(dropped, tt.wantDropped) {
+				t.Errorf("DroppedFields() = %v, want %v", dropped, tt.wantDropped)
+			}
+		})
+	}
+
+	// env ファイルが無ければ書き込まずにエラーにする
+	missing := filepath.Join(t.TempDir(), "missing.env")
+	broken := MCPServer{Command: "npx", EnvFile: &missing}
+	if _, err := ConvertServer(clients[ClientClaudeDesktop], "filesystem", broken); err == nil {
+		t.Error("ConvertServer() with a missing env file error = nil")
+	}
+}
+
+func TestLookupClient(t *testing.T) {
+	if client, err := LookupClient("VSCode"); err != nil || client.Name() != ClientVSCode {
+		t.Errorf("LookupClient(VSCode) = %v, %v", client, err)
+	}
+	if _, err := LookupClient("emacs"); err == nil || !strings.Contains(err.Error(), ClientCursor) {
+		t.Errorf("LookupClient(emacs) error = %v, want the list of clients", err)
+	}
+}
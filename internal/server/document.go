@@ -0,0 +1,239 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/jsonedit"
+	"github.com/naoto24kawa/mcpjson/internal/utils"
+)
+
+// MCPServersKey is the top-level key holding server definitions in MCP config files
+const MCPServersKey = "mcpServers"
+
+// MCPDocument is a lossless view of an MCP config file.
+// Only the servers subtree is edited. Comments, every other top-level
+// key and every per-server field the client adapter does not own are
+// carried through as-is, so files such as Claude's settings.json can be
+// rewritten safely.
+type MCPDocument struct {
+	doc    *jsonedit.Document
+	client ClientAdapter
+}
+
+// NewMCPDocument creates an empty MCP document
+func NewMCPDocument() *MCPDocument {
+	return NewMCPDocumentFor(DefaultClient)
+}
+
+// NewMCPDocumentFor creates an empty MCP document in the client's format
+func NewMCPDocumentFor(client ClientAdapter) *MCPDocument {
+	return &MCPDocument{doc: jsonedit.New(), client: client}
+}
+
+// LoadMCPDocument reads an MCP config file into a document.
+// The returned error satisfies os.IsNotExist when the file is missing.
+func LoadMCPDocument(path string) (*MCPDocument, error) {
+	return LoadMCPDocumentFor(path, DefaultClient)
+}
+
+// LoadMCPDocumentFor reads a client's MCP config file into a document
+func LoadMCPDocumentFor(path string, client ClientAdapter) (*MCPDocument, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseMCPDocumentFor(data, client)
+}
+
+// ParseMCPDocument parses MCP config file contents (JSON or JSONC) into a document
+func ParseMCPDocument(data []byte) (*MCPDocument, error) {
+	return ParseMCPDocumentFor(data, DefaultClient)
+}
+
+// ParseMCPDocumentFor parses the contents of a client's MCP config file
+func ParseMCPDocumentFor(data []byte, client ClientAdapter) (*MCPDocument, error) {
+	doc, err := jsonedit.Parse(data)
+	if err != nil {
+		return nil, i18n.Errorf("server.mcp_parse_failed", err)
+	}
+	if doc.IsEmpty() {
+		return NewMCPDocumentFor(client), nil
+	}
+
+	if root, _ := doc.Get(); !isJSONObject(root) {
+		return nil, i18n.Errorf("server.mcp_not_object")
+	}
+	key := client.ServersKey()
+	if raw, exists := doc.Get(key); exists && string(raw) != "null" && !isJSONObject(raw) {
+		return nil, i18n.Errorf("server.section_not_object", key)
+	}
+
+	return &MCPDocument{doc: doc, client: client}, nil
+}
+
+// ServerNames returns the server names in file order
+func (d *MCPDocument) ServerNames() []string {
+	return d.doc.Keys(d.client.ServersKey())
+}
+
+// HasServer reports whether a server with the given name exists
+func (d *MCPDocument) HasServer(name string) bool {
+	_, exists := d.doc.Get(d.client.ServersKey(), name)
+	return exists
+}
+
+// Server decodes a single server entry
+func (d *MCPDocument) Server(name string) (MCPServer, bool, error) {
+	raw, exists := d.doc.Get(d.client.ServersKey(), name)
+	if !exists {
+		return MCPServer{}, false, nil
+	}
+
+	server, err := d.client.Decode(name, raw)
+	if err != nil {
+		return MCPServer{}, true, err
+	}
+	return server, true, nil
+}
+
+// Config decodes all server entries into an MCPConfig
+func (d *MCPDocument) Config() (*MCPConfig, error) {
+	mcpConfig := &MCPConfig{McpServers: make(map[string]MCPServer)}
+	for _, name := range d.ServerNames() {
+		server, _, err := d.Server(name)
+		if err != nil {
+			return nil, err
+		}
+		mcpConfig.McpServers[name] = server
+	}
+	return mcpConfig, nil
+}
+
+// SetServer adds or updates a server entry.
+// Fields of an existing entry that the client adapter does not own are
+// preserved.
+func (d *MCPDocument) SetServer(name string, server MCPServer) error {
+	entry, err := d.client.Encode(name, server)
+	if err != nil {
+		return err
+	}
+	if err := d.ensureServers(); err != nil {
+		return err
+	}
+
+	key := d.client.ServersKey()
+	raw, exists := d.doc.Get(key, name)
+	if !exists || !isJSONObject(raw) {
+		return d.doc.Set(entry, key, name)
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(entry); err != nil {
+		return err
+	}
+	var known map[string]json.RawMessage
+	if err := json.Unmarshal(buf.Bytes(), &known); err != nil {
+		return err
+	}
+
+	for _, field := range d.client.Fields() {
+		if value, exists := known[field]; exists {
+			if err := d.doc.Patch(value, key, name, field); err != nil {
+				return err
+			}
+		} else if _, err := d.doc.Delete(key, name, field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveServer deletes a server entry and reports whether it existed
+func (d *MCPDocument) RemoveServer(name string) (bool, error) {
+	return d.doc.Delete(d.client.ServersKey(), name)
+}
+
+// ReplaceServers makes the document contain exactly the given servers.
+// Servers kept across the replacement retain their position and unknown
+// fields; new servers are appended in name order.
+func (d *MCPDocument) ReplaceServers(servers map[string]MCPServer) error {
+	for _, name := range d.ServerNames() {
+		if _, keep := servers[name]; !keep {
+			if _, err := d.RemoveServer(name); err != nil {
+				return err
+			}
+		}
+	}
+
+	names := make([]string, 0, len(servers))
+	for name := range servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := d.SetServer(name, servers[name]); err != nil {
+			return err
+		}
+	}
+	return d.ensureServers()
+}
+
+// Bytes renders the document source
+func (d *MCPDocument) Bytes() ([]byte, error) {
+	if err := d.ensureServers(); err != nil {
+		return nil, err
+	}
+	return d.doc.Bytes(), nil
+}
+
+// Save writes the document to path
+func (d *MCPDocument) Save(path string) error {
+	data, err := d.Bytes()
+	if err != nil {
+		return err
+	}
+	return utils.WriteFileAtomic(path, data, 0644)
+}
+
+// ensureServers makes sure the document has a servers object
+func (d *MCPDocument) ensureServers() error {
+	key := d.client.ServersKey()
+	if raw, exists := d.doc.Get(key); exists && isJSONObject(raw) {
+		return nil
+	}
+	return d.doc.Set(map[string]MCPServer{}, key)
+}
+
+func isJSONObject(raw json.RawMessage) bool {
+	return len(raw) > 0 && raw[0] == '{'
+}
+
+// knownServerFields are the JSON field names modelled by MCPServer
+var knownServerFields = jsonFieldNames(reflect.TypeOf(MCPServer{}))
+
+// mcpServerFields returns the JSON field names modelled by MCPServer
+func mcpServerFields() []string {
+	return knownServerFields
+}
+
+// jsonFieldNames returns the JSON names of the fields of struct type t
+func jsonFieldNames(t reflect.Type) []string {
+	fields := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("json")
+		name := strings.Split(tag, ",")[0]
+		if name != "" && name != "-" {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
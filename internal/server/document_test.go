@@ -0,0 +1,223 @@
+package server
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tidwall/jsonc"
+)
+
+const documentTestContent = `{
+  // Claude settings
+  "theme": "dark",
+  "mcpServers": {
+    "git": {
+      "command": "uvx",
+      "args": ["mcp-server-git"],
+      "alwaysAllow": ["git_status"]
+    },
+    "remote": {"type": "http", "url": "https://example.com/mcp?a=1&b=2"}
+  },
+  "permissions": {"allow": ["Bash(ls:*)"]}
+}`
+
+func parseTestDocument(t *testing.T) *MCPDocument {
+	t.Helper()
+
+	doc, err := ParseMCPDocument([]byte(documentTestContent))
+	if err != nil {
+		t.Fatalf("ParseMCPDocument() failed: %v", err)
+	}
+	return doc
+}
+
+func decodeDocument(t *testing.T, doc *MCPDocument) map[string]interface{} {
+	t.Helper()
+
+	data, err := doc.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes() failed: %v", err)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(jsonc.ToJSON(data), &result); err != nil {
+		t.Fatalf("rendered document is not valid JSONC: %v\n%s", err, data)
+	}
+	return result
+}
+
+func TestParseMCPDocument_ServerNamesKeepOrder(t *testing.T) {
+	doc := parseTestDocument(t)
+
+	names := doc.ServerNames()
+	if len(names) != 2 || names[0] != "git" || names[1] != "remote" {
+		t.Errorf("ServerNames() = %v, want [git remote]", names)
+	}
+}
+
+func TestParseMCPDocument_EmptyAndInvalid(t *testing.T) {
+	doc, err := ParseMCPDocument([]byte("  \n"))
+	if err != nil {
+		t.Fatalf("ParseMCPDocument() failed for empty input: %v", err)
+	}
+	if len(doc.ServerNames()) != 0 {
+		t.Errorf("empty document should have no servers, got %v", doc.ServerNames())
+	}
+
+	if _, err := ParseMCPDocument([]byte(`["not", "an", "object"]`)); err == nil {
+		t.Error("ParseMCPDocument() expected error for non-object input")
+	}
+	if _, err := ParseMCPDocument([]byte(`{"mcpServers": []}`)); err == nil {
+		t.Error("ParseMCPDocument() expected error for non-object mcpServers")
+	}
+}
+
+func TestMCPDocument_PreservesOtherKeys(t *testing.T) {
+	doc := parseTestDocument(t)
+
+	if removed, err := doc.RemoveServer("remote"); err != nil || !removed {
+		t.Fatal("RemoveServer() should report existing server")
+	}
+
+	result := decodeDocument(t, doc)
+	if result["theme"] != "dark" {
+		t.Errorf("top-level key 'theme' lost: %v", result)
+	}
+	permissions, ok := result["permissions"].(map[string]interface{})
+	if !ok || len(permissions["allow"].([]interface{})) != 1 {
+		t.Errorf("top-level key 'permissions' lost: %v", result)
+	}
+
+	data, _ := doc.Bytes()
+	if strings.Index(string(data), `"theme"`) > strings.Index(string(data), `"permissions"`) {
+		t.Errorf("top-level key order not preserved:\n%s", data)
+	}
+}
+
+func TestMCPDocument_SetServerPreservesUnknownFields(t *testing.T) {
+	doc := parseTestDocument(t)
+
+	err := doc.SetServer("git", MCPServer{Command: "uvx", Args: []string{"mcp-server-git", "--verbose"}})
+	if err != nil {
+		t.Fatalf("SetServer() failed: %v", err)
+	}
+
+	servers := decodeDocument(t, doc)["mcpServers"].(map[string]interface{})
+	git := servers["git"].(map[string]interface{})
+	if _, exists := git["alwaysAllow"]; !exists {
+		t.Errorf("unknown field 'alwaysAllow' lost: %v", git)
+	}
+	if len(git["args"].([]interface{})) != 2 {
+		t.Errorf("args not updated: %v", git["args"])
+	}
+}
+
+func TestMCPDocument_KeepsCommentsAndLayout(t *testing.T) {
+	doc := parseTestDocument(t)
+
+	if err := doc.SetServer("fetch", MCPServer{Command: "uvx", Args: []string{"mcp-server-fetch"}}); err != nil {
+		t.Fatalf("SetServer() failed: %v", err)
+	}
+	if _, err := doc.RemoveServer("remote"); err != nil {
+		t.Fatalf("RemoveServer() failed: %v", err)
+	}
+
+	data, _ := doc.Bytes()
+	for _, text := range []string{"// Claude settings", `"args": ["mcp-server-git"],`, `"permissions": {"allow": ["Bash(ls:*)"]}`} {
+		if !strings.Contains(string(data), text) {
+			t.Errorf("%q not preserved:\n%s", text, data)
+		}
+	}
+}
+
+func TestMCPDocument_SetServerRemovesClearedFields(t *testing.T) {
+	doc := parseTestDocument(t)
+
+	if err := doc.SetServer("git", MCPServer{Command: "uvx"}); err != nil {
+		t.Fatalf("SetServer() failed: %v", err)
+	}
+
+	server, exists, err := doc.Server("git")
+	if err != nil || !exists {
+		t.Fatalf("Server() = %v, %v", exists, err)
+	}
+	if len(server.Args) != 0 {
+		t.Errorf("cleared args should be removed, got %v", server.Args)
+	}
+}
+
+func TestMCPDocument_ReplaceServers(t *testing.T) {
+	doc := parseTestDocument(t)
+
+	err := doc.ReplaceServers(map[string]MCPServer{
+		"git":   {Command: "uvx", Args: []string{"mcp-server-git"}},
+		"fetch": {Command: "uvx", Args: []string{"mcp-server-fetch"}},
+	})
+	if err != nil {
+		t.Fatalf("ReplaceServers() failed: %v", err)
+	}
+
+	names := doc.ServerNames()
+	if len(names) != 2 || names[0] != "git" || names[1] != "fetch" {
+		t.Errorf("ServerNames() = %v, want [git fetch]", names)
+	}
+
+	servers := decodeDocument(t, doc)["mcpServers"].(map[string]interface{})
+	if _, exists := servers["git"].(map[string]interface{})["alwaysAllow"]; !exists {
+		t.Error("unknown field of kept server should be preserved")
+	}
+}
+
+func TestMCPDocument_DoesNotEscapeHTML(t *testing.T) {
+	doc := parseTestDocument(t)
+
+	if err := doc.SetServer("remote", MCPServer{Type: ServerTypeHTTP, URL: "https://example.com/mcp?a=1&b=2"}); err != nil {
+		t.Fatalf("SetServer() failed: %v", err)
+	}
+
+	data, _ := doc.Bytes()
+	if !strings.Contains(string(data), "a=1&b=2") {
+		t.Errorf("url should be written verbatim:\n%s", data)
+	}
+}
+
+func TestManager_AddToMCPConfig_PreservesOtherKeys(t *testing.T) {
+	tempDir := t.TempDir()
+	manager := NewManager(tempDir)
+
+	if err := manager.SaveManual("fetch", "uvx", []string{"mcp-server-fetch"}, nil, false); err != nil {
+		t.Fatalf("SaveManual() failed: %v", err)
+	}
+
+	mcpConfigPath := filepath.Join(tempDir, "settings.json")
+	if err := os.WriteFile(mcpConfigPath, []byte(documentTestContent), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	if err := manager.AddToMCPConfig(mcpConfigPath, "fetch", "", nil); err != nil {
+		t.Fatalf("AddToMCPConfig() failed: %v", err)
+	}
+
+	doc, err := LoadMCPDocument(mcpConfigPath)
+	if err != nil {
+		t.Fatalf("LoadMCPDocument() failed: %v", err)
+	}
+	result := decodeDocument(t, doc)
+	if result["theme"] != "dark" || result["permissions"] == nil {
+		t.Errorf("other top-level keys lost: %v", result)
+	}
+	if len(doc.ServerNames()) != 3 {
+		t.Errorf("expected 3 servers, got %v", doc.ServerNames())
+	}
+
+	if err := manager.RemoveFromMCPConfig(mcpConfigPath, "remote"); err != nil {
+		t.Fatalf("RemoveFromMCPConfig() failed: %v", err)
+	}
+	doc, _ = LoadMCPDocument(mcpConfigPath)
+	if decodeDocument(t, doc)["theme"] != "dark" {
+		t.Error("other top-level keys lost after remove")
+	}
+}
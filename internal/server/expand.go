@@ -0,0 +1,56 @@
+package server
+
+import (
+	"sort"
+
+	"github.com/naoto24kawa/mcpjson/internal/interpolate"
+)
+
+// MapStrings returns a copy of the server with fn applied to the command,
+// each argument, the url and every env and header value
+func (s MCPServer) MapStrings(fn func(string) string) MCPServer {
+	s.Command = fn(s.Command)
+	s.URL = fn(s.URL)
+
+	if s.Args != nil {
+		args := make([]string, len(s.Args))
+		for i, arg := range s.Args {
+			args[i] = fn(arg)
+		}
+		s.Args = args
+	}
+
+	s.Env = mapValues(s.Env, fn)
+	s.Headers = mapValues(s.Headers, fn)
+	return s
+}
+
+func mapValues(src map[string]string, fn func(string) string) map[string]string {
+	if src == nil {
+		return nil
+	}
+	dst := make(map[string]string, len(src))
+	for k, v := range src {
+		dst[k] = fn(v)
+	}
+	return dst
+}
+
+// UnresolvedSecrets returns the ${secret:name} references left in the
+// server's strings, sorted and without duplicates
+func (s MCPServer) UnresolvedSecrets() []string {
+	seen := make(map[string]bool)
+	s.MapStrings(func(value string) string {
+		for _, ref := range interpolate.References(value, interpolate.SecretScope) {
+			seen[ref] = true
+		}
+		return value
+	})
+
+	refs := make([]string, 0, len(seen))
+	for ref := range seen {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+	return refs
+}
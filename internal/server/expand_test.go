@@ -0,0 +1,140 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/naoto24kawa/mcpjson/internal/secret"
+)
+
+func TestMCPServer_MapStrings(t *testing.T) {
+	original := MCPServer{
+		Command: "a",
+		Args:    []string{"b"},
+		Env:     map[string]string{"K": "c"},
+		URL:     "d",
+		Headers: map[string]string{"H": "e"},
+	}
+
+	mapped := original.MapStrings(strings.ToUpper)
+
+	if mapped.Command != "A" || mapped.Args[0] != "B" || mapped.Env["K"] != "C" || mapped.URL != "D" || mapped.Headers["H"] != "E" {
+		t.Errorf("MapStrings() = %+v", mapped)
+	}
+	if original.Args[0] != "b" || original.Env["K"] != "c" || original.Headers["H"] != "e" {
+		t.Errorf("MapStrings() must not modify the receiver: %+v", original)
+	}
+}
+
+func TestManager_AddToMCPConfig_ResolvesPlaceholders(t *testing.T) {
+	tempDir := t.TempDir()
+	manager := NewManager(tempDir)
+	mcpConfigPath := filepath.Join(tempDir, ".mcp.json")
+
+	t.Setenv("MCPJSON_TEST_GITHUB_TOKEN", "ghp_test")
+	err := manager.SaveManual("github", "npx", []string{"-y", "@modelcontextprotocol/server-github"},
+		map[string]string{"GITHUB_TOKEN": "${env:MCPJSON_TEST_GITHUB_TOKEN}"}, false)
+	if err != nil {
+		t.Fatalf("SaveManual() failed: %v", err)
+	}
+
+	if err := manager.AddToMCPConfig(mcpConfigPath, "github", "", nil); err != nil {
+		t.Fatalf("AddToMCPConfig() failed: %v", err)
+	}
+
+	doc, err := LoadMCPDocument(mcpConfigPath)
+	if err != nil {
+		t.Fatalf("LoadMCPDocument() failed: %v", err)
+	}
+	server, _, _ := doc.Server("github")
+	if server.Env["GITHUB_TOKEN"] != "ghp_test" {
+		t.Errorf("GITHUB_TOKEN = %q, want resolved value", server.Env["GITHUB_TOKEN"])
+	}
+
+	template, _ := manager.Load("github")
+	if template.ServerConfig.Env["GITHUB_TOKEN"] != "${env:MCPJSON_TEST_GITHUB_TOKEN}" {
+		t.Errorf("template should keep the placeholder, got %q", template.ServerConfig.Env["GITHUB_TOKEN"])
+	}
+}
+
+func TestManager_AddToMCPConfig_MissingVariable(t *testing.T) {
+	tempDir := t.TempDir()
+	manager := NewManager(tempDir)
+	mcpConfigPath := filepath.Join(tempDir, ".mcp.json")
+
+	os.Unsetenv("MCPJSON_TEST_UNSET")
+	err := manager.SaveManual("github", "npx", nil, map[string]string{"TOKEN": "${env:MCPJSON_TEST_UNSET}"}, false)
+	if err != nil {
+		t.Fatalf("SaveManual() failed: %v", err)
+	}
+
+	err = manager.AddToMCPConfig(mcpConfigPath, "github", "", nil)
+	if err == nil || !strings.Contains(err.Error(), "${env:MCPJSON_TEST_UNSET}") {
+		t.Fatalf("AddToMCPConfig() error = %v, want missing variable error", err)
+	}
+	if _, err := os.Stat(mcpConfigPath); !os.IsNotExist(err) {
+		t.Error("MCP config file should not be written when variables are missing")
+	}
+}
+
+type mapSecrets map[string]string
+
+func (m mapSecrets) Get(name string) (string, error) {
+	if value, ok := m[name]; ok {
+		return value, nil
+	}
+	return "", secret.ErrNotFound
+}
+
+func TestManager_AddToMCPConfig_Secrets(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      string
+		provider secret.SecretProvider
+		want     string
+		wantErr  string
+	}{
+		{"プロバイダーで解決", "${secret:github-token}", mapSecrets{"github-token": "ghp_vault"}, "ghp_vault", ""},
+		{"プロバイダー未設定", "${secret:github-token}", nil, "", "シークレットプロバイダーが設定されていません"},
+		{"未登録のシークレット", "${secret:missing}", mapSecrets{}, "", "${secret:missing}"},
+		{"エスケープされた参照も書き込まない", "$${secret:github-token}", mapSecrets{}, "", "未解決のシークレット参照"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			manager := NewManager(tempDir)
+			manager.SetSecretProvider(tt.provider)
+			mcpConfigPath := filepath.Join(tempDir, ".mcp.json")
+
+			if err := manager.SaveManual("github", "npx", nil, map[string]string{"TOKEN": tt.env}, false); err != nil {
+				t.Fatalf("SaveManual() failed: %v", err)
+			}
+
+			err := manager.AddToMCPConfig(mcpConfigPath, "github", "", nil)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("AddToMCPConfig() error = %v, want %q", err, tt.wantErr)
+				}
+				if _, err := os.Stat(mcpConfigPath); !os.IsNotExist(err) {
+					t.Error("MCP config file should not be written with unresolved secrets")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("AddToMCPConfig() failed: %v", err)
+			}
+
+			doc, err := LoadMCPDocument(mcpConfigPath)
+			if err != nil {
+				t.Fatalf("LoadMCPDocument() failed: %v", err)
+			}
+			server, _, _ := doc.Server("github")
+			if server.Env["TOKEN"] != tt.want {
+				t.Errorf("TOKEN = %q, want %q", server.Env["TOKEN"], tt.want)
+			}
+		})
+	}
+}
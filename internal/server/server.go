@@ -1,14 +1,22 @@
 package server
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"strings"
 	"time"
 
+	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/filelock"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/interpolate"
+	"github.com/naoto24kawa/mcpjson/internal/output"
+	"github.com/naoto24kawa/mcpjson/internal/secret"
 	"github.com/naoto24kawa/mcpjson/internal/utils"
 )
 
 const (
-	ListColumnWidth = 20
 	TimestampFormat = "2006-01-02 15:04:05"
 )
 
@@ -17,13 +25,20 @@ type ServerTemplate struct {
 	Description  *string      `json:"description"`
 	CreatedAt    time.Time    `json:"createdAt"`
 	ServerConfig ServerConfig `json:"serverConfig"`
+	// Capabilities is filled in by 'server inspect'
+	Capabilities *Capabilities `json:"capabilities,omitempty"`
 }
 
-// MCPServer represents a server configuration for MCP settings
+// MCPServer represents a server configuration for MCP settings.
+// Stdio servers are described by Command/Args/Env, remote (http/sse)
+// servers by URL/Headers. Type selects between them.
 type MCPServer struct {
-	Command       string            `json:"command"`
+	Type          string            `json:"type,omitempty"`
+	Command       string            `json:"command,omitempty"`
 	Args          []string          `json:"args,omitempty"`
 	Env           map[string]string `json:"env,omitempty"`
+	URL           string            `json:"url,omitempty"`
+	Headers       map[string]string `json:"headers,omitempty"`
 	Timeout       *int              `json:"timeout,omitempty"`
 	EnvFile       *string           `json:"envFile,omitempty"`
 	TransportType *string           `json:"transportType,omitempty"`
@@ -41,6 +56,8 @@ type Manager struct {
 	templateManager *TemplateManager
 	templateUpdater *TemplateUpdater
 	templateDisplay *TemplateDisplay
+	secretProvider  secret.SecretProvider
+	projectVars     map[string]string
 }
 
 // NewManager creates a new unified Manager instance
@@ -49,18 +66,45 @@ func NewManager(serversDir string) *Manager {
 	return &Manager{
 		templateManager: templateManager,
 		templateUpdater: NewTemplateUpdater(templateManager),
-		templateDisplay: NewTemplateDisplay(serversDir),
+		templateDisplay: &TemplateDisplay{templateManager: templateManager},
 	}
 }
 
 // SaveFromFile saves a server template from an MCP config file
 func (m *Manager) SaveFromFile(templateName, serverName, mcpConfigPath string, force bool) error {
-	return m.templateManager.SaveFromFile(templateName, serverName, mcpConfigPath, force)
+	overwrite, err := m.templateManager.confirmOverwrite(m.templateManager.exists(templateName), templateName, force)
+	if err != nil {
+		return err
+	}
+	return m.withStoreLock(func() error {
+		return m.templateManager.saveFromFile(templateName, serverName, mcpConfigPath, overwrite)
+	})
 }
 
 // SaveManual saves or updates a server template manually
 func (m *Manager) SaveManual(templateName, command string, args []string, env map[string]string, force bool) error {
-	return m.templateUpdater.SaveManual(templateName, command, args, env, force)
+	overwrite, err := m.templateUpdater.confirmOverwrite(templateName, force)
+	if err != nil {
+		return err
+	}
+	return m.withStoreLock(func() error {
+		return m.templateUpdater.saveManual(templateName, command, args, env, overwrite)
+	})
+}
+
+// SaveRemote saves or updates a remote (http/sse) server template manually
+func (m *Manager) SaveRemote(templateName, serverType, url string, headers map[string]string, force bool) error {
+	if serverType != "" && !IsValidServerType(serverType) {
+		return i18n.Errorf("server.unknown_type", serverType)
+	}
+
+	overwrite, err := m.templateUpdater.confirmOverwrite(templateName, force)
+	if err != nil {
+		return err
+	}
+	return m.withStoreLock(func() error {
+		return m.templateUpdater.saveRemote(templateName, serverType, url, headers, overwrite)
+	})
 }
 
 // List displays all server templates
@@ -68,19 +112,113 @@ func (m *Manager) List(detail bool) error {
 	return m.templateDisplay.List(detail)
 }
 
+// ListWithFormat writes all server templates to w in the given format
+func (m *Manager) ListWithFormat(w io.Writer, detail bool, format output.Format) error {
+	return m.templateDisplay.ListWithFormat(w, detail, format)
+}
+
 // Delete deletes a server template
 func (m *Manager) Delete(name string, force bool, profileManager ProfileManager) error {
-	return m.templateManager.Delete(name, force, profileManager)
+	removal, err := m.templateManager.confirmDelete(name, force, profileManager)
+	if err != nil || removal == nil {
+		return err
+	}
+	return m.withStoreLock(func() error {
+		return m.templateManager.remove(removal, profileManager)
+	})
 }
 
 // Copy copies a server template
 func (m *Manager) Copy(srcName, destName string, force bool) error {
-	return m.templateManager.Copy(srcName, destName, force)
+	return m.withStoreLock(func() error {
+		return m.templateManager.Copy(srcName, destName, force)
+	})
 }
 
 // Rename renames a server template
 func (m *Manager) Rename(oldName, newName string, force bool) error {
-	return m.templateManager.Rename(oldName, newName, force)
+	return m.withStoreLock(func() error {
+		return m.templateManager.Rename(oldName, newName, force)
+	})
+}
+
+// SetSecretProvider sets the provider that resolves ${secret:name}
+// placeholders when servers are written to an MCP config file
+func (m *Manager) SetSecretProvider(provider secret.SecretProvider) {
+	m.secretProvider = provider
+}
+
+// UseConfig makes template reads fall through the stores of cfg and the
+// variables of its project file override profile variables
+func (m *Manager) UseConfig(cfg *config.Config) {
+	m.templateManager.SetReadDirs(cfg.ServerDirs())
+	if cfg.Project != nil {
+		m.projectVars = cfg.Project.Vars
+	}
+}
+
+// NewResolver returns a placeholder resolver for the given profile
+// variables that also resolves ${secret:name} through the secret provider.
+// Project variables override profile variables of the same name.
+func (m *Manager) NewResolver(vars map[string]string) *interpolate.Resolver {
+	if len(m.projectVars) > 0 {
+		merged := make(map[string]string, len(vars)+len(m.projectVars))
+		for k, v := range vars {
+			merged[k] = v
+		}
+		for k, v := range m.projectVars {
+			merged[k] = v
+		}
+		vars = merged
+	}
+	resolver := interpolate.New(vars)
+	if m.secretProvider == nil {
+		return resolver
+	}
+
+	provider := m.secretProvider
+	resolver.Register(interpolate.SecretScope, ":", func(name string) (string, error) {
+		value, err := provider.Get(name)
+		if errors.Is(err, secret.ErrNotFound) {
+			return "", interpolate.ErrNotFound
+		}
+		return value, err
+	})
+	return resolver
+}
+
+// CheckNoSecretReferences fails when a server still contains a
+// ${secret:name} reference, which must never reach an MCP config file
+func CheckNoSecretReferences(serverName string, mcpServer MCPServer) error {
+	if refs := mcpServer.UnresolvedSecrets(); len(refs) > 0 {
+		return i18n.Errorf("server.unresolved_secret",
+			serverName, strings.Join(refs, ", "))
+	}
+	return nil
+}
+
+// Build returns the server the template describes with its placeholders
+// resolved, as it would be written to an MCP config file
+func (m *Manager) Build(templateName string) (MCPServer, error) {
+	template, err := m.loadTemplate(templateName)
+	if err != nil {
+		return MCPServer{}, err
+	}
+	return m.resolveServer(templateName, templateName, template, nil)
+}
+
+// resolveServer builds the server from the template and expands its
+// placeholders, failing when a secret reference is left
+func (m *Manager) resolveServer(templateName, serverName string, template *ServerTemplate, envOverrides map[string]string) (MCPServer, error) {
+	resolver := m.NewResolver(nil)
+	mcpServer := m.buildMCPServer(template, envOverrides).MapStrings(resolver.Expand)
+	if err := resolver.Err(); err != nil {
+		return MCPServer{}, i18n.Errorf("server.expand_failed", templateName, err)
+	}
+	if err := CheckNoSecretReferences(serverName, mcpServer); err != nil {
+		return MCPServer{}, err
+	}
+	return mcpServer, nil
 }
 
 // AddToMCPConfig adds a server from template to an MCP config file
@@ -90,21 +228,35 @@ func (m *Manager) AddToMCPConfig(mcpConfigPath, templateName, serverName string,
 		return err
 	}
 
-	mcpConfig, err := m.loadOrCreateMCPConfig(mcpConfigPath)
+	unlock, err := filelock.LockFile(mcpConfigPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	doc, err := m.loadOrCreateMCPDocument(mcpConfigPath)
 	if err != nil {
 		return err
 	}
 
 	serverName = m.resolveServerName(serverName, templateName)
 
-	if err := m.validateServerNotExists(mcpConfig, serverName); err != nil {
+	if err := m.validateServerNotExists(doc, serverName); err != nil {
 		return err
 	}
 
-	mcpServer := m.buildMCPServer(template, envOverrides)
-	mcpConfig.McpServers[serverName] = mcpServer
+	mcpServer, err := m.resolveServer(templateName, serverName, template, envOverrides)
+	if err != nil {
+		return err
+	}
+	if err := mcpServer.Validate(); err != nil {
+		return i18n.Errorf("server.template_invalid", templateName, err)
+	}
+	if err := doc.SetServer(serverName, mcpServer); err != nil {
+		return i18n.Errorf("common.mcp_update_failed", err)
+	}
 
-	if err := m.saveMCPConfig(mcpConfig, mcpConfigPath); err != nil {
+	if err := m.saveMCPDocument(doc, mcpConfigPath); err != nil {
 		return err
 	}
 
@@ -115,25 +267,21 @@ func (m *Manager) AddToMCPConfig(mcpConfigPath, templateName, serverName string,
 func (m *Manager) loadTemplate(templateName string) (*ServerTemplate, error) {
 	template, err := m.templateManager.Load(templateName)
 	if err != nil {
-		return nil, fmt.Errorf("テンプレート '%s' の読み込みに失敗しました: %w", templateName, err)
+		return nil, i18n.Errorf("server.template_load_failed_short", templateName, err)
 	}
 	return template, nil
 }
 
-func (m *Manager) loadOrCreateMCPConfig(mcpConfigPath string) (*MCPConfig, error) {
-	mcpConfig := &MCPConfig{McpServers: make(map[string]MCPServer)}
-
-	if utils.FileExists(mcpConfigPath) {
-		if err := utils.LoadJSON(mcpConfigPath, mcpConfig); err != nil {
-			return nil, fmt.Errorf("MCP設定ファイルの読み込みに失敗しました: %w", err)
-		}
+func (m *Manager) loadOrCreateMCPDocument(mcpConfigPath string) (*MCPDocument, error) {
+	if !utils.FileExists(mcpConfigPath) {
+		return NewMCPDocument(), nil
 	}
 
-	if mcpConfig.McpServers == nil {
-		mcpConfig.McpServers = make(map[string]MCPServer)
+	doc, err := LoadMCPDocument(mcpConfigPath)
+	if err != nil {
+		return nil, i18n.Errorf("common.mcp_read_failed", err)
 	}
-
-	return mcpConfig, nil
+	return doc, nil
 }
 
 func (m *Manager) resolveServerName(serverName, templateName string) string {
@@ -143,9 +291,9 @@ func (m *Manager) resolveServerName(serverName, templateName string) string {
 	return serverName
 }
 
-func (m *Manager) validateServerNotExists(mcpConfig *MCPConfig, serverName string) error {
-	if _, exists := mcpConfig.McpServers[serverName]; exists {
-		return fmt.Errorf("サーバー '%s' は既にMCP設定ファイルに存在します", serverName)
+func (m *Manager) validateServerNotExists(doc *MCPDocument, serverName string) error {
+	if doc.HasServer(serverName) {
+		return i18n.Errorf("server.already_in_mcp", serverName)
 	}
 	return nil
 }
@@ -154,9 +302,12 @@ func (m *Manager) buildMCPServer(template *ServerTemplate, envOverrides map[stri
 	env := m.mergeEnvironmentVariables(template.ServerConfig.Env, envOverrides)
 
 	return MCPServer{
+		Type:          template.ServerConfig.Type,
 		Command:       template.ServerConfig.Command,
 		Args:          template.ServerConfig.Args,
 		Env:           env,
+		URL:           template.ServerConfig.URL,
+		Headers:       template.ServerConfig.Headers,
 		Timeout:       template.ServerConfig.Timeout,
 		EnvFile:       template.ServerConfig.EnvFile,
 		TransportType: template.ServerConfig.TransportType,
@@ -176,44 +327,47 @@ func (m *Manager) mergeEnvironmentVariables(templateEnv, overrides map[string]st
 	return env
 }
 
-func (m *Manager) saveMCPConfig(mcpConfig *MCPConfig, mcpConfigPath string) error {
-	if err := utils.SaveJSON(mcpConfigPath, mcpConfig); err != nil {
-		return fmt.Errorf("MCP設定ファイルの保存に失敗しました: %w", err)
+func (m *Manager) saveMCPDocument(doc *MCPDocument, mcpConfigPath string) error {
+	if err := doc.Save(mcpConfigPath); err != nil {
+		return i18n.Errorf("common.mcp_save_failed", err)
 	}
 	return nil
 }
 
 func (m *Manager) printAddSuccess(serverName, mcpConfigPath string) {
-	fmt.Printf("サーバー '%s' をMCP設定ファイルに追加しました: %s\n", serverName, mcpConfigPath)
+	fmt.Println(i18n.T("server.added_to_mcp", serverName, mcpConfigPath))
 }
 
 // RemoveFromMCPConfig removes a server from an MCP config file
 func (m *Manager) RemoveFromMCPConfig(mcpConfigPath, serverName string) error {
-	// MCP設定ファイルを読み込む
-	mcpConfig := &MCPConfig{}
-	if err := utils.LoadJSON(mcpConfigPath, mcpConfig); err != nil {
-		return fmt.Errorf("MCP設定ファイルの読み込みに失敗しました: %w", err)
+	unlock, err := filelock.LockFile(mcpConfigPath)
+	if err != nil {
+		return err
 	}
+	defer unlock()
 
-	// サーバーが存在するかチェック
-	if _, exists := mcpConfig.McpServers[serverName]; !exists {
-		availableServers := make([]string, 0, len(mcpConfig.McpServers))
-		for name := range mcpConfig.McpServers {
-			availableServers = append(availableServers, name)
-		}
-		return fmt.Errorf("サーバー '%s' がMCP設定ファイルに見つかりません\nファイル: %s\n利用可能なサーバー: %v",
-			serverName, mcpConfigPath, availableServers)
+	// MCP設定ファイルを読み込む
+	doc, err := LoadMCPDocument(mcpConfigPath)
+	if err != nil {
+		return i18n.Errorf("common.mcp_read_failed", err)
 	}
 
 	// サーバーを削除
-	delete(mcpConfig.McpServers, serverName)
+	removed, err := doc.RemoveServer(serverName)
+	if err != nil {
+		return i18n.Errorf("server.remove_failed", serverName, err)
+	}
+	if !removed {
+		return i18n.Errorf("server.not_in_mcp",
+			serverName, mcpConfigPath, doc.ServerNames())
+	}
 
 	// ファイルに保存
-	if err := utils.SaveJSON(mcpConfigPath, mcpConfig); err != nil {
-		return fmt.Errorf("MCP設定ファイルの保存に失敗しました: %w", err)
+	if err := m.saveMCPDocument(doc, mcpConfigPath); err != nil {
+		return err
 	}
 
-	fmt.Printf("サーバー '%s' をMCP設定ファイルから削除しました: %s\n", serverName, mcpConfigPath)
+	fmt.Println(i18n.T("server.removed_from_mcp", serverName, mcpConfigPath))
 	return nil
 }
 
@@ -229,15 +383,44 @@ func (m *Manager) Exists(name string) (bool, error) {
 
 // SaveFromConfig saves a server template from MCPServer config
 func (m *Manager) SaveFromConfig(name string, server MCPServer) error {
-	return m.templateManager.SaveFromConfig(name, server)
+	return m.withStoreLock(func() error {
+		return m.templateManager.SaveFromConfig(name, server)
+	})
+}
+
+// SaveWithDescription saves a server template with the given description,
+// replacing any template of the same name
+func (m *Manager) SaveWithDescription(name string, description *string, server MCPServer) error {
+	return m.withStoreLock(func() error {
+		return m.templateManager.SaveWithDescription(name, description, server)
+	})
 }
 
 // Reset deletes all server templates
 func (m *Manager) Reset(force bool) error {
-	return m.templateManager.Reset(force)
+	files, err := m.templateManager.confirmReset(force)
+	if err != nil || files == nil {
+		return err
+	}
+	return m.withStoreLock(func() error {
+		return m.templateManager.removeFiles(files)
+	})
 }
 
 // GetTemplatePath returns the file path for a server template
 func (m *Manager) GetTemplatePath(name string) (string, error) {
 	return m.templateManager.GetTemplatePath(name)
 }
+
+// withStoreLock runs fn while holding the store lock. Confirmations are
+// asked before it is taken, so that other commands are not held up while
+// the user answers; fn then checks again what they were asked about.
+func (m *Manager) withStoreLock(fn func() error) error {
+	unlock, err := filelock.LockStore(m.templateManager.serversDir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return fn()
+}
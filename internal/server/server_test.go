@@ -4,8 +4,13 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/filelock"
+	"github.com/naoto24kawa/mcpjson/internal/interaction"
 )
 
 func TestManager_SaveManual(t *testing.T) {
@@ -160,6 +165,120 @@ func TestManager_Delete(t *testing.T) {
 	}
 }
 
+// racingProfileManager deletes the template while the usage is checked,
+// as another command would while the user answers the prompts
+type racingProfileManager struct {
+	templatePath      string
+	lockPath          string
+	lockedDuringCheck bool
+	refsRemoved       bool
+}
+
+func (m *racingProfileManager) FindProfilesUsingTemplate(templateName string) ([]string, error) {
+	_, err := os.Stat(m.lockPath)
+	m.lockedDuringCheck = err == nil
+	return []string{"work"}, os.Remove(m.templatePath)
+}
+
+func (m *racingProfileManager) RemoveTemplateReferencesFromAllProfiles(templateName string) error {
+	m.refsRemoved = true
+	return nil
+}
+
+func TestManager_Delete_ChecksAgainUnderLock(t *testing.T) {
+	tempDir := t.TempDir()
+	serversDir := filepath.Join(tempDir, config.ServersDir)
+	if err := os.MkdirAll(serversDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	manager := NewManager(serversDir)
+	if err := manager.SaveManual("test-template", "test-command", nil, nil, false); err != nil {
+		t.Fatal(err)
+	}
+
+	profileManager := &racingProfileManager{
+		templatePath: filepath.Join(serversDir, "test-template"+config.FileExtension),
+		lockPath:     filepath.Join(tempDir, filelock.StoreLockName),
+	}
+	err := manager.Delete("test-template", true, profileManager)
+	if err == nil || !strings.Contains(err.Error(), "test-template") {
+		t.Errorf("Manager.Delete() error = %v, want template not found", err)
+	}
+	if profileManager.lockedDuringCheck {
+		t.Error("the store was locked while the usage was checked")
+	}
+	if profileManager.refsRemoved {
+		t.Error("references were removed although the template was already gone")
+	}
+}
+
+// lockWatcher answers yes to every prompt and records whether the store
+// lock was held while a prompt waited for the answer
+type lockWatcher struct {
+	lockPath string
+	prompts  int
+	locked   bool
+}
+
+func (w *lockWatcher) Read(p []byte) (int, error) {
+	w.prompts++
+	if _, err := os.Stat(w.lockPath); err == nil {
+		w.locked = true
+	}
+	return copy(p, "y\n"), nil
+}
+
+func TestManager_PromptsWithoutLock(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(m *Manager, dir string) error
+	}{
+		{name: "ファイルから上書き保存", run: func(m *Manager, dir string) error {
+			mcpConfigPath := filepath.Join(dir, ".mcp.json")
+			if err := os.WriteFile(mcpConfigPath, []byte(`{"mcpServers": {"s": {"command": "new-command"}}}`), 0644); err != nil {
+				return err
+			}
+			return m.SaveFromFile("test-template", "s", mcpConfigPath, false)
+		}},
+		{name: "上書き保存", run: func(m *Manager, dir string) error {
+			return m.SaveManual("test-template", "new-command", nil, nil, false)
+		}},
+		{name: "リモートの上書き保存", run: func(m *Manager, dir string) error {
+			return m.SaveRemote("test-template", ServerTypeHTTP, "https://example.com/mcp", nil, false)
+		}},
+		{name: "削除", run: func(m *Manager, dir string) error { return m.Delete("test-template", false, nil) }},
+		{name: "リセット", run: func(m *Manager, dir string) error { return m.Reset(false) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			serversDir := filepath.Join(tempDir, config.ServersDir)
+			if err := os.MkdirAll(serversDir, 0755); err != nil {
+				t.Fatal(err)
+			}
+			manager := NewManager(serversDir)
+			if err := manager.SaveManual("test-template", "test-command", nil, nil, false); err != nil {
+				t.Fatal(err)
+			}
+
+			watcher := &lockWatcher{lockPath: filepath.Join(tempDir, filelock.StoreLockName)}
+			interaction.Input = watcher
+			defer func() { interaction.Input = nil }()
+
+			if err := tt.run(manager, tempDir); err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if watcher.prompts == 0 {
+				t.Fatal("no prompt was shown")
+			}
+			if watcher.locked {
+				t.Error("the store was locked while the prompt waited for an answer")
+			}
+		})
+	}
+}
+
 func TestManager_Exists(t *testing.T) {
 	tempDir := t.TempDir()
 	manager := NewManager(tempDir)
@@ -402,3 +521,99 @@ func TestManager_Reset_WithNonTemplateFiles(t *testing.T) {
 		t.Error("Non-template file was unexpectedly deleted")
 	}
 }
+
+func TestManager_UseConfig(t *testing.T) {
+	projectDir := t.TempDir()
+	serversDir := t.TempDir()
+	manager := NewManager(serversDir)
+	manager.UseConfig(&config.Config{
+		ServersDir: serversDir,
+		Project: &config.Project{
+			Path: filepath.Join(projectDir, config.ProjectFileName),
+			Vars: map[string]string{"db": "project-db"},
+		},
+	})
+
+	resolver := manager.NewResolver(map[string]string{"db": "profile-db", "user": "alice"})
+	if got := resolver.Expand("${profile.db} ${profile.user}"); got != "project-db alice" {
+		t.Errorf("Expand() = %q, want %q", got, "project-db alice")
+	}
+	if err := resolver.Err(); err != nil {
+		t.Errorf("Err() = %v", err)
+	}
+}
+
+func TestManager_SaveCapabilities(t *testing.T) {
+	serversDir := t.TempDir()
+	manager := NewManager(serversDir)
+
+	source := `{
+  // 社内向けのファイルサーバー
+  "name": "files",
+  "description": null,
+  "createdAt": "2024-01-01T00:00:00Z",
+  "serverConfig": {
+    "command": "npx", // ローカルで起動する
+    "args": ["files-server"]
+  }
+}
+`
+	path := filepath.Join(serversDir, "files"+config.FileExtension)
+	if err := os.WriteFile(path, []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	capabilities := &Capabilities{
+		InspectedAt: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		Tools:       []Tool{{Name: "read_file"}},
+	}
+	if err := manager.SaveCapabilities("files", capabilities); err != nil {
+		t.Fatalf("SaveCapabilities() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, comment := range []string{"// 社内向けのファイルサーバー", "// ローカルで起動する"} {
+		if !strings.Contains(string(data), comment) {
+			t.Errorf("comment %q was dropped:\n%s", comment, data)
+		}
+	}
+
+	template, err := manager.Load("files")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if template.Capabilities == nil || len(template.Capabilities.Tools) != 1 || template.Capabilities.Tools[0].Name != "read_file" {
+		t.Errorf("Capabilities = %+v, want the saved tools", template.Capabilities)
+	}
+}
+
+func TestManager_SaveCapabilities_OtherStore(t *testing.T) {
+	teamDir := t.TempDir()
+	serversDir := t.TempDir()
+	manager := NewManager(serversDir)
+	manager.templateManager.SetReadDirs([]string{teamDir, serversDir})
+
+	path := filepath.Join(teamDir, "files"+config.FileExtension)
+	source := `{"name": "files", "serverConfig": {"command": "npx"}}`
+	if err := os.WriteFile(path, []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := manager.SaveCapabilities("files", &Capabilities{}); err == nil {
+		t.Fatal("SaveCapabilities() succeeded for a template of another store")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != source {
+		t.Errorf("template of another store was rewritten:\n%s", data)
+	}
+	if _, err := os.Stat(filepath.Join(serversDir, "files"+config.FileExtension)); !os.IsNotExist(err) {
+		t.Errorf("template was created in the selected store: %v", err)
+	}
+}
@@ -2,98 +2,158 @@ package server
 
 import (
 	"fmt"
+	"io"
 	"os"
-	"strings"
+	"sort"
+	"time"
 
-	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/output"
 )
 
 // TemplateDisplay handles template listing and display operations
 type TemplateDisplay struct {
-	serversDir string
+	templateManager *TemplateManager
 }
 
 // NewTemplateDisplay creates a new TemplateDisplay instance
 func NewTemplateDisplay(serversDir string) *TemplateDisplay {
 	return &TemplateDisplay{
-		serversDir: serversDir,
+		templateManager: NewTemplateManager(serversDir),
+	}
+}
+
+// TemplateSummary is one row of 'server list' in the json and yaml output
+// formats. Target is the command of stdio servers and the url of remote
+// servers.
+type TemplateSummary struct {
+	Name        string    `json:"name"`
+	Description *string   `json:"description"`
+	CreatedAt   time.Time `json:"createdAt"`
+	Type        string    `json:"type"`
+	Target      string    `json:"target"`
+}
+
+// NewTemplateSummary returns the summary of a template
+func NewTemplateSummary(template *ServerTemplate) TemplateSummary {
+	target := template.ServerConfig.Command
+	if template.ServerConfig.IsRemote() {
+		target = template.ServerConfig.URL
+	}
+	return TemplateSummary{
+		Name:        template.Name,
+		Description: template.Description,
+		CreatedAt:   template.CreatedAt,
+		Type:        template.ServerConfig.ResolvedType(),
+		Target:      target,
 	}
 }
 
 // List displays all server templates
 func (td *TemplateDisplay) List(detail bool) error {
-	files, err := os.ReadDir(td.serversDir)
+	return td.ListWithFormat(os.Stdout, detail, output.FormatTable)
+}
+
+// ListWithFormat writes all server templates to w. The json and yaml
+// formats write a list of TemplateSummary, or of ServerTemplate with detail.
+func (td *TemplateDisplay) ListWithFormat(w io.Writer, detail bool, format output.Format) error {
+	templates, err := td.loadAll()
 	if err != nil {
-		return fmt.Errorf("サーバーディレクトリの読み込みに失敗しました: %w", err)
+		return err
 	}
 
-	if len(files) == 0 {
-		fmt.Println("サーバーテンプレートが存在しません")
+	if format.IsStructured() {
+		if detail {
+			return output.Write(w, format, templates)
+		}
+		summaries := make([]TemplateSummary, 0, len(templates))
+		for _, template := range templates {
+			summaries = append(summaries, NewTemplateSummary(template))
+		}
+		return output.Write(w, format, summaries)
+	}
+
+	if len(templates) == 0 {
+		fmt.Fprintln(w, i18n.T("server.no_templates"))
 		return nil
 	}
 
 	if detail {
-		return td.listDetailed(files)
+		for _, template := range templates {
+			td.WriteDetail(w, template)
+		}
+		return nil
 	}
 
-	return td.listSummary(files)
-}
-
-func (td *TemplateDisplay) listDetailed(files []os.DirEntry) error {
-	templateManager := NewTemplateManager(td.serversDir)
-
-	for _, file := range files {
-		if strings.HasSuffix(file.Name(), config.FileExtension) {
-			name := strings.TrimSuffix(file.Name(), config.FileExtension)
-			template, err := templateManager.Load(name)
-			if err != nil {
-				fmt.Printf("エラー: %s の読み込みに失敗しました: %v\n", name, err)
-				continue
-			}
-
-			td.displayTemplateDetail(template)
-		}
+	table := output.NewTable(i18n.T("server.column_name"), i18n.T("common.created_at"), i18n.T("server.column_command"))
+	for _, template := range templates {
+		table.AddRow(template.Name, template.CreatedAt.Format(TimestampFormat), td.summaryTarget(template))
 	}
-	return nil
+	return table.Write(w)
 }
 
-func (td *TemplateDisplay) listSummary(files []os.DirEntry) error {
-	templateManager := NewTemplateManager(td.serversDir)
-
-	fmt.Printf("%-20s %-20s %s\n", "テンプレート名", "作成日時", "コマンド")
-	fmt.Println(strings.Repeat("-", 60))
-
-	for _, file := range files {
-		if strings.HasSuffix(file.Name(), config.FileExtension) {
-			name := strings.TrimSuffix(file.Name(), config.FileExtension)
-			template, err := templateManager.Load(name)
-			if err != nil {
-				continue
-			}
+// loadAll loads every template that can be read. Templates that cannot be
+// read are reported on stderr and skipped.
+func (td *TemplateDisplay) loadAll() ([]*ServerTemplate, error) {
+	if _, err := os.ReadDir(td.templateManager.serversDir); err != nil {
+		return nil, i18n.Errorf("server.read_dir_failed", err)
+	}
 
-			fmt.Printf("%-*s %-*s %s\n",
-				ListColumnWidth, template.Name,
-				ListColumnWidth, template.CreatedAt.Format(TimestampFormat),
-				template.ServerConfig.Command)
+	templates := []*ServerTemplate{}
+	for _, name := range td.templateManager.names() {
+		template, err := td.templateManager.Load(name)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, i18n.T("common.load_failed_skip", name, err))
+			continue
 		}
+		templates = append(templates, template)
 	}
-	return nil
+	return templates, nil
 }
 
-func (td *TemplateDisplay) displayTemplateDetail(template *ServerTemplate) {
-	fmt.Printf("\nテンプレート: %s\n", template.Name)
+// WriteDetail writes the human-readable details of a template to w
+func (td *TemplateDisplay) WriteDetail(w io.Writer, template *ServerTemplate) {
+	fmt.Fprintln(w, i18n.T("server.detail_header", template.Name))
 	if template.Description != nil {
-		fmt.Printf("  説明: %s\n", *template.Description)
+		fmt.Fprintln(w, i18n.T("common.detail_description", *template.Description))
+	}
+	fmt.Fprintln(w, i18n.T("common.detail_created_at", template.CreatedAt.Format(TimestampFormat)))
+	if template.ServerConfig.IsRemote() {
+		fmt.Fprintln(w, i18n.T("server.detail_type", template.ServerConfig.ResolvedType()))
+		fmt.Fprintf(w, "  URL: %s\n", template.ServerConfig.URL)
+		if len(template.ServerConfig.Headers) > 0 {
+			fmt.Fprintln(w, i18n.T("server.detail_headers"))
+			for _, k := range sortedKeys(template.ServerConfig.Headers) {
+				fmt.Fprintf(w, "    %s: %s\n", k, template.ServerConfig.Headers[k])
+			}
+		}
+		return
 	}
-	fmt.Printf("  作成日時: %s\n", template.CreatedAt.Format("2006-01-02 15:04:05"))
-	fmt.Printf("  コマンド: %s\n", template.ServerConfig.Command)
+	fmt.Fprintln(w, i18n.T("server.detail_command", template.ServerConfig.Command))
 	if len(template.ServerConfig.Args) > 0 {
-		fmt.Printf("  引数: %v\n", template.ServerConfig.Args)
+		fmt.Fprintln(w, i18n.T("server.detail_args", template.ServerConfig.Args))
 	}
 	if len(template.ServerConfig.Env) > 0 {
-		fmt.Println("  環境変数:")
-		for k, v := range template.ServerConfig.Env {
-			fmt.Printf("    %s=%s\n", k, v)
+		fmt.Fprintln(w, i18n.T("server.detail_env"))
+		for _, k := range sortedKeys(template.ServerConfig.Env) {
+			fmt.Fprintf(w, "    %s=%s\n", k, template.ServerConfig.Env[k])
 		}
 	}
 }
+
+// summaryTarget returns the command for stdio servers and the url for remote servers
+func (td *TemplateDisplay) summaryTarget(template *ServerTemplate) string {
+	if template.ServerConfig.IsRemote() {
+		return fmt.Sprintf("[%s] %s", template.ServerConfig.ResolvedType(), template.ServerConfig.URL)
+	}
+	return template.ServerConfig.Command
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
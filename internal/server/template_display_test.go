@@ -1,7 +1,10 @@
 package server
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -9,6 +12,7 @@ import (
 	"time"
 
 	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/output"
 )
 
 const (
@@ -123,56 +127,55 @@ func TestTemplateDisplay_List_WithNonTemplateFiles(t *testing.T) {
 	}
 }
 
-func TestTemplateDisplay_listSummary(t *testing.T) {
+func TestTemplateDisplay_ListWithFormat_Table(t *testing.T) {
 	// Arrange
 	display, manager, _ := createTemplateDisplayForTest(t)
 	templateNames := createMultipleTestTemplates(t, manager, 2)
 
-	files, err := os.ReadDir(display.serversDir)
-	if err != nil {
-		t.Fatalf("Failed to read directory: %v", err)
-	}
-
 	// Act
-	err = display.listSummary(files)
+	var buf bytes.Buffer
+	err := display.ListWithFormat(&buf, false, output.FormatTable)
 
 	// Assert
 	if err != nil {
-		t.Errorf("listSummary() failed: %v", err)
+		t.Errorf("ListWithFormat() failed: %v", err)
 	}
 
-	// ファイルが正しく読み込まれているか確認
-	for _, name := range templateNames {
-		template, err := manager.Load(name)
-		if err != nil {
-			t.Errorf("Failed to load template %s: %v", name, err)
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != len(templateNames)+2 {
+		t.Fatalf("ListWithFormat() lines = %d, want %d:\n%s", len(lines), len(templateNames)+2, buf.String())
+	}
+	for i, name := range templateNames {
+		if !strings.HasPrefix(lines[i+2], name+"  ") {
+			t.Errorf("line %d = %q, want template %s", i+2, lines[i+2], name)
 		}
-		if template == nil {
-			t.Errorf("Template %s is nil", name)
+		if !strings.HasSuffix(lines[i+2], fmt.Sprintf("command-%d", i+1)) {
+			t.Errorf("line %d = %q, want command-%d", i+2, lines[i+2], i+1)
 		}
 	}
 }
 
-func TestTemplateDisplay_listDetailed(t *testing.T) {
+func TestTemplateDisplay_ListWithFormat_Detailed(t *testing.T) {
 	// Arrange
 	display, manager, _ := createTemplateDisplayForTest(t)
 	createMultipleTestTemplates(t, manager, 2)
 
-	files, err := os.ReadDir(display.serversDir)
-	if err != nil {
-		t.Fatalf("Failed to read directory: %v", err)
-	}
-
 	// Act
-	err = display.listDetailed(files)
+	var buf bytes.Buffer
+	err := display.ListWithFormat(&buf, true, output.FormatTable)
 
 	// Assert
 	if err != nil {
-		t.Errorf("listDetailed() failed: %v", err)
+		t.Errorf("ListWithFormat() detailed failed: %v", err)
+	}
+	for _, want := range []string{"テンプレート: template-1", "テンプレート: template-2", "ENV_2=value-2"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("ListWithFormat() output does not contain %q:\n%s", want, buf.String())
+		}
 	}
 }
 
-func TestTemplateDisplay_listDetailed_WithCorruptedFile(t *testing.T) {
+func TestTemplateDisplay_ListWithFormat_WithCorruptedFile(t *testing.T) {
 	// Arrange
 	display, manager, tempDir := createTemplateDisplayForTest(t)
 
@@ -185,21 +188,16 @@ func TestTemplateDisplay_listDetailed_WithCorruptedFile(t *testing.T) {
 		t.Fatalf("Failed to create corrupted file: %v", err)
 	}
 
-	files, err := os.ReadDir(display.serversDir)
-	if err != nil {
-		t.Fatalf("Failed to read directory: %v", err)
-	}
-
 	// Act
-	err = display.listDetailed(files)
+	err := display.ListWithFormat(io.Discard, true, output.FormatTable)
 
 	// Assert
 	if err != nil {
-		t.Errorf("listDetailed() failed with corrupted file: %v", err)
+		t.Errorf("ListWithFormat() failed with corrupted file: %v", err)
 	}
 }
 
-func TestTemplateDisplay_displayTemplateDetail_Complete(t *testing.T) {
+func TestTemplateDisplay_WriteDetail_Complete(t *testing.T) {
 	// Arrange
 	display, _, _ := createTemplateDisplayForTest(t)
 
@@ -216,7 +214,7 @@ func TestTemplateDisplay_displayTemplateDetail_Complete(t *testing.T) {
 	}
 
 	// Act - この関数は出力をテストするのが難しいので、エラーが出ないことを確認
-	display.displayTemplateDetail(template)
+	display.WriteDetail(io.Discard, template)
 
 	// Assert - パニックしないことを確認
 	if template.Name != displayTestTemplateName {
@@ -224,7 +222,7 @@ func TestTemplateDisplay_displayTemplateDetail_Complete(t *testing.T) {
 	}
 }
 
-func TestTemplateDisplay_displayTemplateDetail_Minimal(t *testing.T) {
+func TestTemplateDisplay_WriteDetail_Minimal(t *testing.T) {
 	// Arrange
 	display, _, _ := createTemplateDisplayForTest(t)
 
@@ -237,7 +235,7 @@ func TestTemplateDisplay_displayTemplateDetail_Minimal(t *testing.T) {
 	}
 
 	// Act
-	display.displayTemplateDetail(template)
+	display.WriteDetail(io.Discard, template)
 
 	// Assert - パニックしないことを確認
 	if template.Name != displayTestTemplateName {
@@ -245,7 +243,7 @@ func TestTemplateDisplay_displayTemplateDetail_Minimal(t *testing.T) {
 	}
 }
 
-func TestTemplateDisplay_displayTemplateDetail_EmptyEnv(t *testing.T) {
+func TestTemplateDisplay_WriteDetail_EmptyEnv(t *testing.T) {
 	// Arrange
 	display, _, _ := createTemplateDisplayForTest(t)
 
@@ -260,7 +258,7 @@ func TestTemplateDisplay_displayTemplateDetail_EmptyEnv(t *testing.T) {
 	}
 
 	// Act
-	display.displayTemplateDetail(template)
+	display.WriteDetail(io.Discard, template)
 
 	// Assert - パニックしないことを確認
 	if template.Name != displayTestTemplateName {
@@ -268,7 +266,7 @@ func TestTemplateDisplay_displayTemplateDetail_EmptyEnv(t *testing.T) {
 	}
 }
 
-func TestTemplateDisplay_displayTemplateDetail_EmptyArgs(t *testing.T) {
+func TestTemplateDisplay_WriteDetail_EmptyArgs(t *testing.T) {
 	// Arrange
 	display, _, _ := createTemplateDisplayForTest(t)
 
@@ -283,7 +281,7 @@ func TestTemplateDisplay_displayTemplateDetail_EmptyArgs(t *testing.T) {
 	}
 
 	// Act
-	display.displayTemplateDetail(template)
+	display.WriteDetail(io.Discard, template)
 
 	// Assert - パニックしないことを確認
 	if template.Name != displayTestTemplateName {
@@ -291,7 +289,7 @@ func TestTemplateDisplay_displayTemplateDetail_EmptyArgs(t *testing.T) {
 	}
 }
 
-func TestTemplateDisplay_listSummary_WithBrokenTemplate(t *testing.T) {
+func TestTemplateDisplay_ListWithFormat_WithBrokenTemplate(t *testing.T) {
 	// Arrange
 	display, manager, tempDir := createTemplateDisplayForTest(t)
 
@@ -304,17 +302,20 @@ func TestTemplateDisplay_listSummary_WithBrokenTemplate(t *testing.T) {
 		t.Fatalf("Failed to create broken file: %v", err)
 	}
 
-	files, err := os.ReadDir(display.serversDir)
-	if err != nil {
-		t.Fatalf("Failed to read directory: %v", err)
-	}
-
 	// Act
-	err = display.listSummary(files)
+	var buf bytes.Buffer
+	err := display.ListWithFormat(&buf, false, output.FormatJSON)
 
 	// Assert - 破損したファイルがあってもエラーにならないことを確認
 	if err != nil {
-		t.Errorf("listSummary() failed with broken template: %v", err)
+		t.Errorf("ListWithFormat() failed with broken template: %v", err)
+	}
+	var summaries []TemplateSummary
+	if err := json.Unmarshal(buf.Bytes(), &summaries); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, buf.String())
+	}
+	if len(summaries) != 1 || summaries[0].Name != "template-1" {
+		t.Errorf("summaries = %+v, want only template-1", summaries)
 	}
 }
 
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/naoto24kawa/mcpjson/internal/config"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
 	"github.com/naoto24kawa/mcpjson/internal/interaction"
 	"github.com/naoto24kawa/mcpjson/internal/utils"
 )
@@ -21,6 +22,7 @@ type ProfileManager interface {
 // TemplateManager handles server template CRUD operations
 type TemplateManager struct {
 	serversDir string
+	readDirs   []string
 }
 
 // NewTemplateManager creates a new TemplateManager instance
@@ -30,22 +32,55 @@ func NewTemplateManager(serversDir string) *TemplateManager {
 	}
 }
 
+// SetReadDirs sets the directories templates are read from, in lookup
+// order. Writes always go to the directory the manager was created with.
+func (tm *TemplateManager) SetReadDirs(dirs []string) {
+	tm.readDirs = dirs
+}
+
 // SaveFromFile saves a server template from an MCP config file
 func (tm *TemplateManager) SaveFromFile(templateName, serverName, mcpConfigPath string, force bool) error {
-	if !force && tm.exists(templateName) {
-		if !interaction.ConfirmOverwrite("サーバーテンプレート", templateName) {
-			return fmt.Errorf("上書きをキャンセルしました")
-		}
+	overwrite, err := tm.confirmOverwrite(tm.exists(templateName), templateName, force)
+	if err != nil {
+		return err
+	}
+	return tm.saveFromFile(templateName, serverName, mcpConfigPath, overwrite)
+}
+
+// confirmOverwrite asks whether the template name may be replaced when it
+// already exists, and reports whether the save may replace a template
+func (tm *TemplateManager) confirmOverwrite(exists bool, name string, force bool) (bool, error) {
+	if force {
+		return true, nil
+	}
+	if !exists {
+		return false, nil
+	}
+	if !interaction.ConfirmOverwrite(i18n.T("kind.template"), name) {
+		return false, i18n.Errorf("common.overwrite_cancelled")
+	}
+	return true, nil
+}
+
+// saveFromFile is SaveFromFile after the confirmation. It fails when the
+// template has appeared since, unless overwrite is set.
+func (tm *TemplateManager) saveFromFile(templateName, serverName, mcpConfigPath string, overwrite bool) error {
+	if !overwrite && tm.exists(templateName) {
+		return i18n.Errorf("server.already_exists", templateName)
 	}
 
 	mcpConfig := &MCPConfig{}
 	if err := utils.LoadJSON(mcpConfigPath, mcpConfig); err != nil {
-		return fmt.Errorf("MCP設定ファイルの読み込みに失敗しました: %w", err)
+		return i18n.Errorf("common.mcp_read_failed", err)
 	}
 
 	server, exists := mcpConfig.McpServers[serverName]
 	if !exists {
-		return fmt.Errorf("MCPサーバー '%s' がMCP設定ファイルに見つかりません", serverName)
+		return i18n.Errorf("server.mcp_server_not_found", serverName)
+	}
+
+	if err := server.Validate(); err != nil {
+		return i18n.Errorf("server.mcp_server_invalid", serverName, err)
 	}
 
 	template := &ServerTemplate{
@@ -59,10 +94,15 @@ func (tm *TemplateManager) SaveFromFile(templateName, serverName, mcpConfigPath
 		return err
 	}
 
-	fmt.Printf("サーバーテンプレート '%s' を保存しました\n", templateName)
-	fmt.Printf("コマンド: %s\n", server.Command)
+	fmt.Println(i18n.T("server.template_saved", templateName))
+	if server.IsRemote() {
+		fmt.Println(i18n.T("server.saved_type", server.ResolvedType()))
+		fmt.Printf("URL: %s\n", server.URL)
+		return nil
+	}
+	fmt.Println(i18n.T("server.saved_command", server.Command))
 	if len(server.Args) > 0 {
-		fmt.Printf("引数: %v\n", server.Args)
+		fmt.Println(i18n.T("server.saved_args", server.Args))
 	}
 
 	return nil
@@ -70,9 +110,19 @@ func (tm *TemplateManager) SaveFromFile(templateName, serverName, mcpConfigPath
 
 // SaveFromConfig saves a server template from MCPServer config
 func (tm *TemplateManager) SaveFromConfig(name string, server MCPServer) error {
+	return tm.SaveWithDescription(name, nil, server)
+}
+
+// SaveWithDescription saves a server template with the given description,
+// replacing any template of the same name
+func (tm *TemplateManager) SaveWithDescription(name string, description *string, server MCPServer) error {
+	if err := server.Validate(); err != nil {
+		return i18n.Errorf("common.server_invalid", name, err)
+	}
+
 	template := &ServerTemplate{
 		Name:         name,
-		Description:  nil,
+		Description:  description,
 		CreatedAt:    time.Now(),
 		ServerConfig: ServerConfig(server),
 	}
@@ -83,11 +133,11 @@ func (tm *TemplateManager) SaveFromConfig(name string, server MCPServer) error {
 // Load loads a server template by name
 func (tm *TemplateManager) Load(name string) (*ServerTemplate, error) {
 	template := &ServerTemplate{}
-	if err := utils.LoadJSON(tm.getTemplatePath(name), template); err != nil {
+	if err := utils.LoadJSON(tm.lookupPath(name), template); err != nil {
 		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("サーバーテンプレート '%s' が見つかりません", name)
+			return nil, i18n.Errorf("common.template_not_found", name)
 		}
-		return nil, fmt.Errorf("サーバーテンプレートの読み込みに失敗しました: %w", err)
+		return nil, i18n.Errorf("server.template_load_failed", err)
 	}
 
 	return template, nil
@@ -95,15 +145,32 @@ func (tm *TemplateManager) Load(name string) (*ServerTemplate, error) {
 
 // Exists checks if a server template exists
 func (tm *TemplateManager) Exists(name string) (bool, error) {
-	return tm.exists(name), nil
+	_, err := os.Stat(tm.lookupPath(name))
+	return err == nil, nil
 }
 
 // Delete deletes a server template
 func (tm *TemplateManager) Delete(name string, force bool, profileManager ProfileManager) error {
-	templatePath := tm.getTemplatePath(name)
+	removal, err := tm.confirmDelete(name, force, profileManager)
+	if err != nil || removal == nil {
+		return err
+	}
+	return tm.remove(removal, profileManager)
+}
 
-	if _, err := os.Stat(templatePath); os.IsNotExist(err) {
-		return fmt.Errorf("サーバーテンプレート '%s' が見つかりません", name)
+// removal is a deletion of a template the user has confirmed
+type removal struct {
+	name  string
+	force bool
+	// removeRefs is set when the references in profiles are removed too
+	removeRefs bool
+}
+
+// confirmDelete checks which profiles use the template and asks for the
+// confirmations Delete needs. It returns nil when the user cancelled.
+func (tm *TemplateManager) confirmDelete(name string, force bool, profileManager ProfileManager) (*removal, error) {
+	if _, err := os.Stat(tm.getTemplatePath(name)); os.IsNotExist(err) {
+		return nil, i18n.Errorf("common.template_not_found", name)
 	}
 
 	// プロファイルでの使用状況をチェック
@@ -112,13 +179,13 @@ func (tm *TemplateManager) Delete(name string, force bool, profileManager Profil
 		var err error
 		usingProfiles, err = profileManager.FindProfilesUsingTemplate(name)
 		if err != nil {
-			return fmt.Errorf("プロファイルでの使用状況確認に失敗しました: %w", err)
+			return nil, i18n.Errorf("server.usage_check_failed", err)
 		}
 	}
 
 	// 使用中のプロファイルがある場合の警告と削除確認
 	if len(usingProfiles) > 0 {
-		fmt.Printf("警告: サーバーテンプレート '%s' は以下のプロファイルで使用されています:\n", name)
+		fmt.Println(i18n.T("server.used_by_profiles", name))
 		for _, profileName := range usingProfiles {
 			fmt.Printf("  - %s\n", profileName)
 		}
@@ -127,35 +194,41 @@ func (tm *TemplateManager) Delete(name string, force bool, profileManager Profil
 
 	// 削除確認
 	if !force {
-		if !interaction.Confirm(fmt.Sprintf("サーバーテンプレート '%s' を削除しますか？", name)) {
-			fmt.Println("削除をキャンセルしました")
-			return nil
+		if !interaction.Confirm(i18n.T("server.confirm_delete", name)) {
+			fmt.Println(i18n.T("common.delete_cancelled"))
+			return nil, nil
 		}
 	}
 
-	// プロファイルからの参照削除処理
-	if len(usingProfiles) > 0 && profileManager != nil {
-		if !force {
-			// 通常削除の場合、プロファイルからの参照も削除するか確認
-			if interaction.Confirm("プロファイルからの参照も削除しますか？") {
-				if err := profileManager.RemoveTemplateReferencesFromAllProfiles(name); err != nil {
-					fmt.Printf("警告: プロファイルからの参照削除に失敗しました: %v\n", err)
-				}
-			}
-		} else {
-			// 強制削除の場合、プロファイルからの参照も自動削除
-			fmt.Println("強制削除: プロファイルからの参照も削除します")
-			if err := profileManager.RemoveTemplateReferencesFromAllProfiles(name); err != nil {
-				fmt.Printf("警告: プロファイルからの参照削除に失敗しました: %v\n", err)
-			}
+	// 強制削除の場合、プロファイルからの参照も自動削除する。
+	// 通常削除の場合は参照も削除するか確認する
+	removeRefs := len(usingProfiles) > 0 && profileManager != nil &&
+		(force || interaction.Confirm(i18n.T("server.confirm_delete_refs")))
+	return &removal{name: name, force: force, removeRefs: removeRefs}, nil
+}
+
+// remove carries out a confirmed deletion. The template is checked again,
+// since the store may have changed while the user was answering.
+func (tm *TemplateManager) remove(r *removal, profileManager ProfileManager) error {
+	templatePath := tm.getTemplatePath(r.name)
+	if _, err := os.Stat(templatePath); os.IsNotExist(err) {
+		return i18n.Errorf("common.template_not_found", r.name)
+	}
+
+	if r.removeRefs {
+		if r.force {
+			fmt.Println(i18n.T("server.force_remove_refs"))
+		}
+		if err := profileManager.RemoveTemplateReferencesFromAllProfiles(r.name); err != nil {
+			fmt.Println(i18n.T("server.remove_refs_failed", err))
 		}
 	}
 
 	if err := os.Remove(templatePath); err != nil {
-		return fmt.Errorf("サーバーテンプレートの削除に失敗しました: %w", err)
+		return i18n.Errorf("server.delete_failed", err)
 	}
 
-	fmt.Printf("サーバーテンプレート '%s' を削除しました\n", name)
+	fmt.Println(i18n.T("server.deleted", r.name))
 	return nil
 }
 
@@ -174,33 +247,33 @@ func (tm *TemplateManager) Copy(srcName, destName string, force bool) error {
 		return err
 	}
 
-	fmt.Printf("サーバーテンプレート '%s' を '%s' にコピーしました\n", srcName, destName)
+	fmt.Println(i18n.T("server.copied", srcName, destName))
 	return nil
 }
 
 func (tm *TemplateManager) validateCopy(srcName, destName string, force bool) error {
 	// 空の名前をチェック
 	if srcName == "" {
-		return fmt.Errorf("コピー元のサーバーテンプレート名が指定されていません")
+		return i18n.Errorf("server.copy_no_source")
 	}
 	if destName == "" {
-		return fmt.Errorf("コピー先のサーバーテンプレート名が指定されていません")
+		return i18n.Errorf("server.copy_no_dest")
 	}
 
 	// 同じ名前をチェック
 	if srcName == destName {
-		return fmt.Errorf("コピー元とコピー先が同じ名前です")
+		return i18n.Errorf("common.copy_same_name")
 	}
 
-	srcPath := tm.getTemplatePath(srcName)
+	srcPath := tm.lookupPath(srcName)
 	destPath := tm.getTemplatePath(destName)
 
 	if _, err := os.Stat(srcPath); os.IsNotExist(err) {
-		return fmt.Errorf("サーバーテンプレート '%s' が見つかりません", srcName)
+		return i18n.Errorf("common.template_not_found", srcName)
 	}
 
 	if _, err := os.Stat(destPath); err == nil && !force {
-		return fmt.Errorf("サーバーテンプレート '%s' は既に存在します\n別の名前を指定するか、--force オプションで上書きしてください", destName)
+		return i18n.Errorf("server.already_exists", destName)
 	}
 
 	return nil
@@ -233,7 +306,7 @@ func (tm *TemplateManager) Rename(oldName, newName string, force bool) error {
 		return err
 	}
 
-	fmt.Printf("サーバーテンプレート '%s' を '%s' に変更しました\n", oldName, newName)
+	fmt.Println(i18n.T("server.renamed", oldName, newName))
 	return nil
 }
 
@@ -242,11 +315,11 @@ func (tm *TemplateManager) validateRename(oldName, newName string, force bool) e
 	newPath := tm.getTemplatePath(newName)
 
 	if _, err := os.Stat(oldPath); os.IsNotExist(err) {
-		return fmt.Errorf("サーバーテンプレート '%s' が見つかりません", oldName)
+		return i18n.Errorf("common.template_not_found", oldName)
 	}
 
 	if _, err := os.Stat(newPath); err == nil && !force {
-		return fmt.Errorf("サーバーテンプレート '%s' は既に存在します\n別の名前を指定するか、--force オプションで上書きしてください", newName)
+		return i18n.Errorf("server.already_exists", newName)
 	}
 
 	return nil
@@ -255,23 +328,27 @@ func (tm *TemplateManager) validateRename(oldName, newName string, force bool) e
 func (tm *TemplateManager) performRename(template *ServerTemplate, oldName, newName string) error {
 	template.Name = newName
 
-	if err := tm.save(template); err != nil {
+	tx := utils.NewTransaction()
+	defer tx.Rollback()
+
+	if err := tx.SaveJSONFrom(tm.getTemplatePath(oldName), tm.getTemplatePath(newName), template); err != nil {
 		return err
 	}
 
-	if err := os.Remove(tm.getTemplatePath(oldName)); err != nil {
-		return fmt.Errorf("古いサーバーテンプレートの削除に失敗しました: %w", err)
+	if err := tx.Remove(tm.getTemplatePath(oldName)); err != nil {
+		return i18n.Errorf("server.remove_old_failed", err)
 	}
 
+	tx.Commit()
 	return nil
 }
 
 // GetTemplatePath returns the file path for a server template
 func (tm *TemplateManager) GetTemplatePath(name string) (string, error) {
-	templatePath := tm.getTemplatePath(name)
+	templatePath := tm.lookupPath(name)
 
 	if _, err := os.Stat(templatePath); os.IsNotExist(err) {
-		return "", fmt.Errorf("サーバーテンプレート '%s' が見つかりません", name)
+		return "", i18n.Errorf("common.template_not_found", name)
 	}
 
 	return templatePath, nil
@@ -281,6 +358,20 @@ func (tm *TemplateManager) getTemplatePath(name string) string {
 	return filepath.Join(tm.serversDir, name+config.FileExtension)
 }
 
+// lookupPath returns the path a template is read from: the first read
+// directory that has it, otherwise its path in the manager's directory
+func (tm *TemplateManager) lookupPath(name string) string {
+	return config.LookupPath(tm.readDirs, tm.serversDir, name)
+}
+
+// names returns the names of the templates that can be read
+func (tm *TemplateManager) names() []string {
+	if len(tm.readDirs) == 0 {
+		return config.ListNames(tm.serversDir)
+	}
+	return config.ListNames(tm.readDirs...)
+}
+
 func (tm *TemplateManager) exists(name string) bool {
 	_, err := os.Stat(tm.getTemplatePath(name))
 	return err == nil
@@ -292,14 +383,25 @@ func (tm *TemplateManager) save(template *ServerTemplate) error {
 
 // Reset deletes all server templates
 func (tm *TemplateManager) Reset(force bool) error {
+	files, err := tm.confirmReset(force)
+	if err != nil || files == nil {
+		return err
+	}
+	return tm.removeFiles(files)
+}
+
+// confirmReset lists the templates and asks whether to delete them. It
+// returns the template files to delete, or nil when there are none or the
+// user cancelled.
+func (tm *TemplateManager) confirmReset(force bool) ([]string, error) {
 	if _, err := os.Stat(tm.serversDir); os.IsNotExist(err) {
-		fmt.Println("サーバーテンプレートディレクトリが存在しません")
-		return nil
+		fmt.Println(i18n.T("server.no_dir"))
+		return nil, nil
 	}
 
 	files, err := os.ReadDir(tm.serversDir)
 	if err != nil {
-		return fmt.Errorf("サーバーテンプレートディレクトリの読み込みに失敗しました: %w", err)
+		return nil, i18n.Errorf("server.read_template_dir_failed", err)
 	}
 
 	templateFiles := []string{}
@@ -310,34 +412,39 @@ func (tm *TemplateManager) Reset(force bool) error {
 	}
 
 	if len(templateFiles) == 0 {
-		fmt.Println("削除するサーバーテンプレートが存在しません")
-		return nil
+		fmt.Println(i18n.T("server.reset_none"))
+		return nil, nil
 	}
 
 	if !force {
-		fmt.Printf("以下の%d個のサーバーテンプレートを削除します:\n", len(templateFiles))
+		fmt.Println(i18n.T("server.reset_list", len(templateFiles)))
 		for _, file := range templateFiles {
 			name := strings.TrimSuffix(file, config.FileExtension)
 			fmt.Printf("  - %s\n", name)
 		}
 		fmt.Println()
 
-		if !interaction.Confirm("すべてのサーバーテンプレートを削除しますか？") {
-			fmt.Println("リセットをキャンセルしました")
-			return nil
+		if !interaction.Confirm(i18n.T("server.confirm_reset")) {
+			fmt.Println(i18n.T("common.reset_cancelled"))
+			return nil, nil
 		}
 	}
+	return templateFiles, nil
+}
 
+// removeFiles deletes the template files confirmReset listed. Templates
+// created since are kept, and ones already removed are skipped.
+func (tm *TemplateManager) removeFiles(files []string) error {
 	deletedCount := 0
-	for _, file := range templateFiles {
+	for _, file := range files {
 		templatePath := filepath.Join(tm.serversDir, file)
-		if err := os.Remove(templatePath); err != nil {
-			fmt.Printf("警告: %s の削除に失敗しました: %v\n", file, err)
-		} else {
+		if err := os.Remove(templatePath); err == nil {
 			deletedCount++
+		} else if !os.IsNotExist(err) {
+			fmt.Println(i18n.T("common.remove_failed_warning", file, err))
 		}
 	}
 
-	fmt.Printf("サーバーテンプレートを%d個削除しました\n", deletedCount)
+	fmt.Println(i18n.T("server.reset_done", deletedCount))
 	return nil
 }
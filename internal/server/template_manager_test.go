@@ -610,6 +610,30 @@ func TestTemplateManager_Copy_Success(t *testing.T) {
 	}
 }
 
+func TestTemplateManager_Copy_FromOtherStore(t *testing.T) {
+	// Arrange
+	teamDir := t.TempDir()
+	createTestTemplate(t, NewTemplateManager(teamDir), testTemplateNameOld)
+	tempDir := t.TempDir()
+	manager := NewTemplateManager(tempDir)
+	manager.SetReadDirs([]string{teamDir, tempDir})
+
+	// Act
+	err := manager.Copy(testTemplateNameOld, testTemplateNameNew, false)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Copy() failed: %v", err)
+	}
+	// コピーは自分のストアに書き込まれる
+	if _, err := os.Stat(filepath.Join(tempDir, testTemplateNameNew+config.FileExtension)); err != nil {
+		t.Errorf("Copied template was not written to the manager's directory: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, testTemplateNameOld+config.FileExtension)); !os.IsNotExist(err) {
+		t.Error("Source template should stay in its own store")
+	}
+}
+
 func TestTemplateManager_Copy_SourceNotFound(t *testing.T) {
 	// Arrange
 	tempDir := t.TempDir()
@@ -850,3 +874,112 @@ func TestTemplateManager_Copy_PreservesAllFields(t *testing.T) {
 		}
 	}
 }
+
+func TestTemplateManager_SaveFromFile_RemoteServer(t *testing.T) {
+	// Arrange
+	tempDir := t.TempDir()
+	manager := NewTemplateManager(tempDir)
+
+	mcpConfigPath := filepath.Join(tempDir, "test_config.json")
+	content := `{
+  "mcpServers": {
+    "remote": {
+      "type": "http",
+      "url": "https://example.com/mcp",
+      "headers": {"Authorization": "Bearer token"}
+    }
+  }
+}`
+	if err := os.WriteFile(mcpConfigPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test MCP config: %v", err)
+	}
+
+	// Act
+	err := manager.SaveFromFile(testTemplateName, "remote", mcpConfigPath, false)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("SaveFromFile() failed: %v", err)
+	}
+
+	template, err := manager.Load(testTemplateName)
+	if err != nil {
+		t.Fatalf("Failed to load saved template: %v", err)
+	}
+
+	if template.ServerConfig.Type != ServerTypeHTTP {
+		t.Errorf("Type mismatch: got %s, want %s", template.ServerConfig.Type, ServerTypeHTTP)
+	}
+	if template.ServerConfig.URL != "https://example.com/mcp" {
+		t.Errorf("URL mismatch: got %s", template.ServerConfig.URL)
+	}
+	if template.ServerConfig.Headers["Authorization"] != "Bearer token" {
+		t.Errorf("Headers mismatch: got %v", template.ServerConfig.Headers)
+	}
+}
+
+func TestTemplateManager_SaveFromFile_InvalidServer(t *testing.T) {
+	// Arrange
+	tempDir := t.TempDir()
+	manager := NewTemplateManager(tempDir)
+
+	mcpConfigPath := filepath.Join(tempDir, "test_config.json")
+	content := `{"mcpServers": {"broken": {"type": "sse"}}}`
+	if err := os.WriteFile(mcpConfigPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test MCP config: %v", err)
+	}
+
+	// Act
+	err := manager.SaveFromFile(testTemplateName, "broken", mcpConfigPath, false)
+
+	// Assert
+	if err == nil {
+		t.Error("SaveFromFile() expected error for remote server without url, got nil")
+	}
+	if manager.exists(testTemplateName) {
+		t.Error("Template should not be saved when validation fails")
+	}
+}
+
+func TestTemplateManager_ReadDirs(t *testing.T) {
+	globalDir := t.TempDir()
+	projectDir := t.TempDir()
+	global := NewTemplateManager(globalDir)
+	createTestTemplate(t, global, "shared")
+	createTestTemplate(t, global, "global-only")
+	if err := NewTemplateManager(projectDir).SaveFromConfig("shared", MCPServer{Command: "node"}); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := NewTemplateManager(globalDir)
+	manager.SetReadDirs([]string{projectDir, globalDir})
+
+	tests := []struct {
+		name        string
+		template    string
+		wantCommand string
+		wantDir     string
+	}{
+		{name: "先のディレクトリを優先", template: "shared", wantCommand: "node", wantDir: projectDir},
+		{name: "後のディレクトリへのフォールスルー", template: "global-only", wantCommand: testCommand, wantDir: globalDir},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template, err := manager.Load(tt.template)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if template.ServerConfig.Command != tt.wantCommand {
+				t.Errorf("Command = %q, want %q", template.ServerConfig.Command, tt.wantCommand)
+			}
+			path, err := manager.GetTemplatePath(tt.template)
+			if err != nil {
+				t.Fatalf("GetTemplatePath() error = %v", err)
+			}
+			if filepath.Dir(path) != tt.wantDir {
+				t.Errorf("GetTemplatePath() = %q, want it in %q", path, tt.wantDir)
+			}
+		})
+	}
+}
@@ -3,7 +3,7 @@ package server
 import (
 	"fmt"
 
-	"github.com/naoto24kawa/mcpjson/internal/interaction"
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
 )
 
 // TemplateUpdater handles template update operations
@@ -20,12 +20,25 @@ func NewTemplateUpdater(templateManager *TemplateManager) *TemplateUpdater {
 
 // SaveManual saves or updates a server template manually
 func (tu *TemplateUpdater) SaveManual(templateName, command string, args []string, env map[string]string, force bool) error {
-	existing := tu.templateExists(templateName)
+	overwrite, err := tu.confirmOverwrite(templateName, force)
+	if err != nil {
+		return err
+	}
+	return tu.saveManual(templateName, command, args, env, overwrite)
+}
 
-	if existing && !force {
-		if !interaction.ConfirmOverwrite("サーバーテンプレート", templateName) {
-			return fmt.Errorf("上書きをキャンセルしました")
-		}
+// confirmOverwrite asks whether an existing template may be updated, and
+// reports whether the save may update one
+func (tu *TemplateUpdater) confirmOverwrite(templateName string, force bool) (bool, error) {
+	return tu.templateManager.confirmOverwrite(tu.templateExists(templateName), templateName, force)
+}
+
+// saveManual is SaveManual after the confirmation. It fails when the
+// template has appeared since, unless overwrite is set.
+func (tu *TemplateUpdater) saveManual(templateName, command string, args []string, env map[string]string, overwrite bool) error {
+	existing := tu.templateExists(templateName)
+	if existing && !overwrite {
+		return i18n.Errorf("server.already_exists", templateName)
 	}
 
 	var template *ServerTemplate
@@ -33,19 +46,117 @@ func (tu *TemplateUpdater) SaveManual(templateName, command string, args []strin
 
 	if existing {
 		template, err = tu.updateExistingTemplate(templateName, command, args, env)
-		if err != nil {
-			return err
-		}
-		fmt.Printf("サーバーテンプレート '%s' を更新しました\n", templateName)
 	} else {
 		template, err = tu.createNewTemplate(templateName, command, args, env)
-		if err != nil {
-			return err
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := tu.templateManager.save(template); err != nil {
+		return err
+	}
+	if existing {
+		fmt.Println(i18n.T("server.template_updated", templateName))
+	} else {
+		fmt.Println(i18n.T("server.template_created", templateName))
+	}
+	return nil
+}
+
+// SaveRemote saves or updates a remote (http/sse) server template manually
+func (tu *TemplateUpdater) SaveRemote(templateName, serverType, url string, headers map[string]string, force bool) error {
+	if serverType != "" && !IsValidServerType(serverType) {
+		return i18n.Errorf("server.unknown_type", serverType)
+	}
+
+	overwrite, err := tu.confirmOverwrite(templateName, force)
+	if err != nil {
+		return err
+	}
+	return tu.saveRemote(templateName, serverType, url, headers, overwrite)
+}
+
+// saveRemote is SaveRemote after the confirmation. It fails when the
+// template has appeared since, unless overwrite is set.
+func (tu *TemplateUpdater) saveRemote(templateName, serverType, url string, headers map[string]string, overwrite bool) error {
+	existing := tu.templateExists(templateName)
+	if existing && !overwrite {
+		return i18n.Errorf("server.already_exists", templateName)
+	}
+
+	var template *ServerTemplate
+	var err error
+
+	if existing {
+		template, err = tu.updateExistingRemoteTemplate(templateName, serverType, url, headers)
+	} else {
+		template, err = tu.createNewRemoteTemplate(templateName, serverType, url, headers)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := template.ServerConfig.Validate(); err != nil {
+		return err
+	}
+
+	if err := tu.templateManager.save(template); err != nil {
+		return err
+	}
+	if existing {
+		fmt.Println(i18n.T("server.template_updated", templateName))
+	} else {
+		fmt.Println(i18n.T("server.template_created", templateName))
+	}
+	return nil
+}
+
+func (tu *TemplateUpdater) updateExistingRemoteTemplate(templateName, serverType, url string, headers map[string]string) (*ServerTemplate, error) {
+	template, err := tu.templateManager.Load(templateName)
+	if err != nil {
+		return nil, err
+	}
+
+	wasRemote := template.ServerConfig.IsRemote()
+
+	if serverType != "" {
+		template.ServerConfig.Type = serverType
+	}
+	if url != "" {
+		template.ServerConfig.URL = url
+		if template.ServerConfig.Type == "" {
+			template.ServerConfig.Type = ServerTypeHTTP
 		}
-		fmt.Printf("サーバーテンプレート '%s' を作成しました\n", templateName)
 	}
 
-	return tu.templateManager.save(template)
+	// stdioからリモートに切り替えた場合、ローカル実行用の設定は不要になる
+	if !wasRemote && template.ServerConfig.IsRemote() {
+		template.ServerConfig.Command = ""
+		template.ServerConfig.Args = nil
+	}
+
+	tu.UpdateTemplateHeaders(template, headers)
+
+	return template, nil
+}
+
+func (tu *TemplateUpdater) createNewRemoteTemplate(templateName, serverType, url string, headers map[string]string) (*ServerTemplate, error) {
+	if url == "" {
+		return nil, i18n.Errorf("server.no_url")
+	}
+	if serverType == "" {
+		serverType = ServerTypeHTTP
+	}
+
+	template := CreateServerTemplate(templateName, "", nil, nil)
+	template.ServerConfig.Type = serverType
+	template.ServerConfig.URL = url
+	if len(headers) > 0 {
+		template.ServerConfig.Headers = headers
+	}
+
+	return template, nil
 }
 
 func (tu *TemplateUpdater) updateExistingTemplate(templateName, command string, args []string, env map[string]string) (*ServerTemplate, error) {
@@ -56,6 +167,12 @@ func (tu *TemplateUpdater) updateExistingTemplate(templateName, command string,
 
 	if command != "" {
 		template.ServerConfig.Command = command
+		// リモートからstdioに切り替えた場合、接続先の設定は不要になる
+		if template.ServerConfig.IsRemote() {
+			template.ServerConfig.Type = ""
+			template.ServerConfig.URL = ""
+			template.ServerConfig.Headers = nil
+		}
 	}
 
 	tu.UpdateTemplateArgs(template, args)
@@ -66,7 +183,7 @@ func (tu *TemplateUpdater) updateExistingTemplate(templateName, command string,
 
 func (tu *TemplateUpdater) createNewTemplate(templateName, command string, args []string, env map[string]string) (*ServerTemplate, error) {
 	if command == "" {
-		return nil, fmt.Errorf("コマンドが指定されていません")
+		return nil, i18n.Errorf("server.no_command")
 	}
 
 	return CreateServerTemplate(templateName, command, args, env), nil
@@ -107,6 +224,29 @@ func (tu *TemplateUpdater) UpdateTemplateEnv(template *ServerTemplate, env map[s
 	}
 }
 
+func (tu *TemplateUpdater) UpdateTemplateHeaders(template *ServerTemplate, headers map[string]string) {
+	if headers == nil {
+		return
+	}
+
+	if len(headers) == 0 {
+		template.ServerConfig.Headers = nil
+		return
+	}
+
+	if template.ServerConfig.Headers == nil {
+		template.ServerConfig.Headers = make(map[string]string)
+	}
+
+	for k, v := range headers {
+		if v == "" {
+			delete(template.ServerConfig.Headers, k)
+		} else {
+			template.ServerConfig.Headers[k] = v
+		}
+	}
+}
+
 func (tu *TemplateUpdater) templateExists(name string) bool {
 	exists, _ := tu.templateManager.Exists(name)
 	return exists
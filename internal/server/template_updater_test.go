@@ -461,3 +461,79 @@ func TestTemplateUpdater_updateExistingTemplate(t *testing.T) {
 		t.Errorf("Command not updated: got %s, want %s", template.ServerConfig.Command, newCommand)
 	}
 }
+
+func TestTemplateUpdater_SaveRemote_CreateNew(t *testing.T) {
+	// Arrange
+	updater, manager, _ := createTemplateUpdaterForTest(t)
+
+	headers := map[string]string{"Authorization": "Bearer token"}
+
+	// Act
+	err := updater.SaveRemote(updaterTestTemplateName, "", "https://example.com/mcp", headers, false)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("SaveRemote() failed to create new template: %v", err)
+	}
+
+	template, err := manager.Load(updaterTestTemplateName)
+	if err != nil {
+		t.Fatalf("Failed to load created template: %v", err)
+	}
+
+	if template.ServerConfig.Type != ServerTypeHTTP {
+		t.Errorf("Type mismatch: got %s, want %s", template.ServerConfig.Type, ServerTypeHTTP)
+	}
+	if template.ServerConfig.URL != "https://example.com/mcp" {
+		t.Errorf("URL mismatch: got %s", template.ServerConfig.URL)
+	}
+	if template.ServerConfig.Headers["Authorization"] != "Bearer token" {
+		t.Errorf("Headers mismatch: got %v", template.ServerConfig.Headers)
+	}
+}
+
+func TestTemplateUpdater_SaveRemote_WithoutURL(t *testing.T) {
+	// Arrange
+	updater, _, _ := createTemplateUpdaterForTest(t)
+
+	// Act
+	err := updater.SaveRemote(updaterTestTemplateName, ServerTypeSSE, "", nil, false)
+
+	// Assert
+	if err == nil {
+		t.Error("SaveRemote() expected error when url is missing, got nil")
+	}
+}
+
+func TestTemplateUpdater_SaveRemote_ConvertFromStdio(t *testing.T) {
+	// Arrange
+	updater, manager, _ := createTemplateUpdaterForTest(t)
+
+	err := manager.SaveFromConfig(updaterTestTemplateName, MCPServer{
+		Command: updaterTestCommand,
+		Args:    []string{"server.js"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create initial template: %v", err)
+	}
+
+	// Act
+	err = updater.SaveRemote(updaterTestTemplateName, ServerTypeSSE, "https://example.com/sse", nil, true)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("SaveRemote() failed to update template: %v", err)
+	}
+
+	template, err := manager.Load(updaterTestTemplateName)
+	if err != nil {
+		t.Fatalf("Failed to load updated template: %v", err)
+	}
+
+	if template.ServerConfig.ResolvedType() != ServerTypeSSE {
+		t.Errorf("Type mismatch: got %s, want %s", template.ServerConfig.ResolvedType(), ServerTypeSSE)
+	}
+	if template.ServerConfig.Command != "" || len(template.ServerConfig.Args) != 0 {
+		t.Errorf("stdio fields should be cleared: command=%q args=%v", template.ServerConfig.Command, template.ServerConfig.Args)
+	}
+}
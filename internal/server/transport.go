@@ -0,0 +1,94 @@
+package server
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/utils"
+)
+
+const (
+	// ServerTypeStdio は標準入出力で通信するローカルサーバーを表します
+	ServerTypeStdio = "stdio"
+	// ServerTypeHTTP はStreamable HTTPで通信するリモートサーバーを表します
+	ServerTypeHTTP = "http"
+	// ServerTypeSSE はServer-Sent Eventsで通信するリモートサーバーを表します
+	ServerTypeSSE = "sse"
+)
+
+// ResolvedType returns the transport type of the server.
+// Entries without an explicit type are treated as remote http servers
+// when they only carry a url, and as stdio servers otherwise.
+func (s MCPServer) ResolvedType() string {
+	if s.Type != "" {
+		return strings.ToLower(s.Type)
+	}
+	if s.URL != "" && s.Command == "" {
+		return ServerTypeHTTP
+	}
+	return ServerTypeStdio
+}
+
+// IsRemote reports whether the server is reached over the network
+func (s MCPServer) IsRemote() bool {
+	switch s.ResolvedType() {
+	case ServerTypeHTTP, ServerTypeSSE:
+		return true
+	}
+	return false
+}
+
+// Environ returns the environment to launch a stdio server with: ours,
+// then the env file, then the server's own variables
+func (s MCPServer) Environ() ([]string, error) {
+	env := os.Environ()
+	if s.EnvFile != nil && *s.EnvFile != "" {
+		fileEnv, err := utils.LoadEnvFile(*s.EnvFile)
+		if err != nil {
+			return nil, err
+		}
+		env = appendEnv(env, fileEnv)
+	}
+	return appendEnv(env, s.Env), nil
+}
+
+func appendEnv(env []string, vars map[string]string) []string {
+	keys := make([]string, 0, len(vars))
+	for key := range vars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		env = append(env, key+"="+vars[key])
+	}
+	return env
+}
+
+// Validate checks that the server has the fields required by its transport type
+func (s MCPServer) Validate() error {
+	switch s.ResolvedType() {
+	case ServerTypeStdio:
+		if s.Command == "" {
+			return i18n.Errorf("server.stdio_needs_command")
+		}
+	case ServerTypeHTTP, ServerTypeSSE:
+		if s.URL == "" {
+			return i18n.Errorf("server.remote_needs_url", s.ResolvedType())
+		}
+	default:
+		return i18n.Errorf("server.unknown_type_available",
+			s.Type, ServerTypeStdio, ServerTypeHTTP, ServerTypeSSE)
+	}
+	return nil
+}
+
+// IsValidServerType reports whether serverType is a supported transport type
+func IsValidServerType(serverType string) bool {
+	switch strings.ToLower(serverType) {
+	case ServerTypeStdio, ServerTypeHTTP, ServerTypeSSE:
+		return true
+	}
+	return false
+}
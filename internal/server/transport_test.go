@@ -0,0 +1,51 @@
+package server
+
+import (
+	"testing"
+)
+
+func TestMCPServer_ResolvedType(t *testing.T) {
+	tests := []struct {
+		name   string
+		server MCPServer
+		want   string
+	}{
+		{"コマンドのみ", MCPServer{Command: "node"}, ServerTypeStdio},
+		{"明示的なhttp", MCPServer{Type: "http", URL: "https://example.com/mcp"}, ServerTypeHTTP},
+		{"大文字のSSE", MCPServer{Type: "SSE", URL: "https://example.com/sse"}, ServerTypeSSE},
+		{"タイプなしのURL", MCPServer{URL: "https://example.com/mcp"}, ServerTypeHTTP},
+		{"空の設定", MCPServer{}, ServerTypeStdio},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.server.ResolvedType(); got != tt.want {
+				t.Errorf("ResolvedType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMCPServer_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		server  MCPServer
+		wantErr bool
+	}{
+		{"正常なstdioサーバー", MCPServer{Command: "node", Args: []string{"server.js"}}, false},
+		{"コマンドのないstdioサーバー", MCPServer{Type: "stdio"}, true},
+		{"正常なhttpサーバー", MCPServer{Type: "http", URL: "https://example.com/mcp"}, false},
+		{"URLのないsseサーバー", MCPServer{Type: "sse", Headers: map[string]string{"X-Key": "v"}}, true},
+		{"不明なタイプ", MCPServer{Type: "websocket", URL: "wss://example.com"}, true},
+		{"空の設定", MCPServer{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.server.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
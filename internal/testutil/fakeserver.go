@@ -0,0 +1,112 @@
+package testutil
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/naoto24kawa/mcpjson/internal/server"
+)
+
+// FakeServerEnv makes the test binary act as an MCP server. Its value is
+// the mode passed to FakeServer.
+const FakeServerEnv = "MCPJSON_FAKE_SERVER"
+
+// RunFakeServerIfRequested turns the test binary into the fake MCP server
+// when it was started by FakeServer. Call it first in TestMain.
+func RunFakeServerIfRequested() {
+	if mode := os.Getenv(FakeServerEnv); mode != "" {
+		os.Exit(runFakeServer(mode))
+	}
+}
+
+// FakeServer returns a server that runs the test binary as a fake MCP
+// server on stdio. The mode selects how it misbehaves:
+//
+//	crash  writes to stderr and exits with status 3
+//	noise  writes a log line to stdout before answering
+//	hang   never answers
+//	error  answers every request with an error
+//
+// Any other mode answers normally. It offers the tools search, delete and
+// fail on the first page of tools/list and fetch on the second; calling a
+// tool answers with the mode, the tool name and the arguments, except
+// that fail answers with an error. The server info is read from FAKE_NAME
+// and FAKE_VERSION.
+func FakeServer(mode string) server.MCPServer {
+	return server.MCPServer{
+		Command: os.Args[0],
+		Args:    []string{"-test.run=^$"},
+		Env:     map[string]string{FakeServerEnv: mode},
+	}
+}
+
+func runFakeServer(mode string) int {
+	switch mode {
+	case "crash":
+		fmt.Fprintln(os.Stderr, "fatal: API_KEY is not set")
+		return 3
+	case "noise":
+		fmt.Println("starting server...")
+	}
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		var request struct {
+			ID     *int   `json:"id"`
+			Method string `json:"method"`
+			Params struct {
+				Name      string          `json:"name"`
+				Arguments json.RawMessage `json:"arguments"`
+				Cursor    string          `json:"cursor"`
+			} `json:"params"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &request); err != nil || request.ID == nil {
+			continue
+		}
+
+		var result interface{}
+		switch {
+		case mode == "hang":
+			continue
+		case mode == "error", request.Method == "tools/call" && request.Params.Name == "fail":
+			fakeReply(map[string]interface{}{"jsonrpc": "2.0", "id": *request.ID, "error": map[string]interface{}{"code": -32603, "message": "database unavailable"}})
+			continue
+		case request.Method == "initialize":
+			fakeReply(map[string]interface{}{"jsonrpc": "2.0", "method": "notifications/message", "params": map[string]string{"data": "hello"}})
+			result = map[string]interface{}{
+				"protocolVersion": "2025-03-26",
+				"capabilities":    map[string]interface{}{"tools": map[string]interface{}{}, "prompts": map[string]interface{}{}, "resources": map[string]interface{}{}},
+				"serverInfo":      map[string]string{"name": os.Getenv("FAKE_NAME"), "version": os.Getenv("FAKE_VERSION")},
+			}
+		case request.Method == "tools/list" && request.Params.Cursor == "page-2":
+			result = map[string]interface{}{"tools": []map[string]string{{"name": "fetch"}}}
+		case request.Method == "tools/list":
+			result = map[string]interface{}{"tools": []map[string]interface{}{
+				{"name": "search", "description": "Searches " + mode + "\nin detail", "inputSchema": map[string]interface{}{
+					"type":       "object",
+					"properties": map[string]interface{}{"query": map[string]string{"type": "string"}, "limit": map[string]string{"type": "number"}},
+					"required":   []string{"query"},
+				}},
+				{"name": "delete", "annotations": map[string]bool{"destructiveHint": true}},
+				{"name": "fail"},
+			}, "nextCursor": "page-2"}
+		case request.Method == "tools/call":
+			text := strings.Join([]string{mode, request.Params.Name, string(request.Params.Arguments)}, " ")
+			result = map[string]interface{}{"content": []map[string]string{{"type": "text", "text": text}}}
+		case request.Method == "prompts/list":
+			result = map[string]interface{}{"prompts": []map[string]interface{}{{"name": "review", "arguments": []map[string]interface{}{{"name": "code", "required": true}}}}}
+		case request.Method == "resources/list":
+			result = map[string]interface{}{"resources": []map[string]string{{"uri": "file:///readme.md", "name": "readme", "mimeType": "text/markdown"}}}
+		}
+		fakeReply(map[string]interface{}{"jsonrpc": "2.0", "id": *request.ID, "result": result})
+	}
+	return 0
+}
+
+func fakeReply(message interface{}) {
+	data, _ := json.Marshal(message)
+	fmt.Println(string(data))
+}
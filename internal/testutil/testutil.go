@@ -58,15 +58,20 @@ func SetupIsolatedTestEnvironment(t *testing.T) (tempDir string, cfg *config.Con
 	// Backup original environment variables
 	origXDGConfigHome := os.Getenv("XDG_CONFIG_HOME")
 	origHome := os.Getenv("HOME")
+	origMCPJSONHome := os.Getenv(config.EnvHome)
 
 	// Set temporary environment
 	_ = os.Setenv("XDG_CONFIG_HOME", tempDir)
 	_ = os.Setenv("HOME", tempDir)
+	_ = os.Setenv(config.EnvHome, filepath.Join(tempDir, config.ConfigDirName))
+	config.UseStore("")
 
 	cleanup = func() {
 		// Restore original environment
 		_ = os.Setenv("XDG_CONFIG_HOME", origXDGConfigHome)
 		_ = os.Setenv("HOME", origHome)
+		_ = os.Setenv(config.EnvHome, origMCPJSONHome)
+		config.UseStore("")
 
 		// Remove temporary directory
 		_ = os.RemoveAll(tempDir)
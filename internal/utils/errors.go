@@ -1,8 +1,10 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
-	"os"
+
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
 )
 
 const (
@@ -17,38 +19,61 @@ const (
 	ExitReferenceError = 8
 )
 
-// HandleError handles errors with standardized error reporting and exit codes
-func HandleError(err error, exitCode int) {
-	if err != nil {
-		fmt.Fprintln(os.Stderr, "エラー:", err)
-		os.Exit(exitCode)
+// ExitError is an error that carries the exit code the process should
+// end with when it reaches the top of the command tree
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// WithExitCode attaches an exit code to err. A nil err stays nil.
+func WithExitCode(err error, code int) error {
+	if err == nil {
+		return nil
 	}
+	return &ExitError{Code: code, Err: err}
 }
 
-// HandleArgumentError handles argument validation errors
-func HandleArgumentError(err error) {
-	HandleError(err, ExitArgumentError)
+// ArgumentError marks err as an argument validation error
+func ArgumentError(err error) error {
+	return WithExitCode(err, ExitArgumentError)
 }
 
-// HandleGeneralError handles general errors
-func HandleGeneralError(err error) {
-	HandleError(err, ExitGeneralError)
+// EnvironmentError marks err as an environment setup error
+func EnvironmentError(err error) error {
+	return WithExitCode(err, ExitEnvironment)
 }
 
-// HandleEnvironmentError handles environment setup errors
-func HandleEnvironmentError(err error) {
-	HandleError(err, ExitEnvironment)
+// ExitCode returns the exit code for err. Errors without an attached code
+// are general errors.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitSuccess
+	}
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	return ExitGeneralError
 }
 
 // ParseProfileName extracts profile name from args, using default if not provided
 func ParseProfileName(args []string, defaultName string) (profileName string, consumedArgs int) {
 	if len(args) < 1 {
-		fmt.Printf("プロファイル名が指定されていないため、デフォルト '%s' を使用します\n", defaultName)
+		fmt.Println(i18n.T("utils.default_profile", defaultName))
 		return defaultName, 0
 	}
 	// Check if the first argument is an option (starts with -)
 	if len(args[0]) > 0 && args[0][0] == '-' {
-		fmt.Printf("プロファイル名が指定されていないため、デフォルト '%s' を使用します\n", defaultName)
+		fmt.Println(i18n.T("utils.default_profile", defaultName))
 		return defaultName, 0
 	}
 	return args[0], 1
@@ -57,7 +82,7 @@ func ParseProfileName(args []string, defaultName string) (profileName string, co
 // ParseFlag parses a flag value from args at the given index
 func ParseFlag(args []string, index int, flag string) (string, int, error) {
 	if index+1 >= len(args) {
-		return "", index, fmt.Errorf("%s オプションに値が指定されていません", flag)
+		return "", index, i18n.Errorf("utils.flag_missing_value", flag)
 	}
 	return args[index+1], index + 1, nil
 }
@@ -65,13 +90,13 @@ func ParseFlag(args []string, index int, flag string) (string, int, error) {
 // ParseRenameArgs parses rename command arguments (special case)
 func ParseRenameArgs(args []string, defaultName string) (oldName, newName string, consumedArgs int, err error) {
 	if len(args) < 1 {
-		return "", "", 0, fmt.Errorf("新しいプロファイル名が指定されていません\n使用方法: mcpconfig rename [現在のプロファイル名] <新しいプロファイル名>")
+		return "", "", 0, i18n.Errorf("utils.rename_missing_name")
 	}
 
 	if len(args) < 2 {
 		oldName = defaultName
 		newName = args[0]
-		fmt.Printf("元のプロファイル名が指定されていないため、デフォルト '%s' を使用します\n", oldName)
+		fmt.Println(i18n.T("utils.default_source_profile", oldName))
 		return oldName, newName, 1, nil
 	}
 
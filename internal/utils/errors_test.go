@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"errors"
+	"fmt"
 	"io"
 	"os"
 	"testing"
@@ -227,3 +229,33 @@ func TestParseRenameArgs(t *testing.T) {
 		})
 	}
 }
+
+func TestExitCode(t *testing.T) {
+	base := errors.New("failed")
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "エラーなし", err: nil, want: ExitSuccess},
+		{name: "終了コードなし", err: base, want: ExitGeneralError},
+		{name: "引数エラー", err: ArgumentError(base), want: ExitArgumentError},
+		{name: "環境エラー", err: EnvironmentError(base), want: ExitEnvironment},
+		{name: "ラップされた終了コード", err: fmt.Errorf("context: %w", WithExitCode(base, ExitFileError)), want: ExitFileError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	if WithExitCode(nil, ExitArgumentError) != nil {
+		t.Error("WithExitCode(nil) should be nil")
+	}
+	if !errors.Is(ArgumentError(base), base) {
+		t.Error("ArgumentError should wrap the original error")
+	}
+}
@@ -3,10 +3,12 @@ package utils
 import (
 	"bufio"
 	"encoding/json"
-	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+	"github.com/naoto24kawa/mcpjson/internal/jsonedit"
 	"github.com/tidwall/jsonc"
 )
 
@@ -27,19 +29,90 @@ func LoadJSON(path string, v interface{}) error {
 	return json.Unmarshal(jsonData, v)
 }
 
+// SaveJSON writes v to path as indented JSON. When path already holds a
+// JSONC document, only the values that changed are rewritten, so comments,
+// key order and trailing commas are kept.
 func SaveJSON(path string, v interface{}) error {
-	data, err := json.MarshalIndent(v, "", "  ")
+	return SaveJSONFrom(path, path, v)
+}
+
+// SaveJSONFrom writes v to path using the JSONC document at srcPath as the
+// starting point. Renames and copies use it to carry comments over.
+func SaveJSONFrom(srcPath, path string, v interface{}) error {
+	data, err := MarshalJSONFrom(srcPath, v)
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(path, data, 0644)
+	return WriteFileAtomic(path, data, 0644)
+}
+
+// MarshalJSONFrom renders v as an edit of the JSONC document at srcPath.
+// A missing or unparsable source yields freshly indented JSON.
+func MarshalJSONFrom(srcPath string, v interface{}) ([]byte, error) {
+	doc := jsonedit.New()
+	if data, err := os.ReadFile(srcPath); err == nil {
+		// 解析できない既存ファイルは従来どおり新規に書き出す
+		if existing, err := jsonedit.Parse(data); err == nil {
+			doc = existing
+		}
+	}
+
+	if err := doc.Patch(v); err != nil {
+		return nil, err
+	}
+
+	return doc.Bytes(), nil
+}
+
+// WriteFileAtomic writes data to a temporary file next to path and renames
+// it into place, so readers never observe a partially written file.
+// An existing file keeps its permissions, and a symlink keeps pointing
+// at the rewritten target.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
 }
 
 func LoadEnvFile(path string) (map[string]string, error) {
 	file, err := os.Open(path)
 	if err != nil {
-		return nil, fmt.Errorf("環境変数ファイルが見つかりません: '%s'", path)
+		return nil, i18n.Errorf("utils.env_file_not_found", path)
 	}
 	defer file.Close()
 
@@ -57,7 +130,7 @@ func LoadEnvFile(path string) (map[string]string, error) {
 
 		parts := strings.SplitN(line, "=", 2)
 		if len(parts) != 2 {
-			return nil, fmt.Errorf("環境変数ファイルの形式が不正です: '%s' 行%d", path, lineNum)
+			return nil, i18n.Errorf("utils.env_file_invalid", path, lineNum)
 		}
 
 		key := strings.TrimSpace(parts[0])
@@ -69,7 +142,7 @@ func LoadEnvFile(path string) (map[string]string, error) {
 	}
 
 	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("環境変数ファイルの読み込みに失敗しました: '%s'", path)
+		return nil, i18n.Errorf("utils.env_file_read_failed", path)
 	}
 
 	return envMap, nil
@@ -153,6 +153,63 @@ func TestSaveJSON(t *testing.T) {
 	}
 }
 
+func TestSaveJSON_PreservesComments(t *testing.T) {
+	tempDir := t.TempDir()
+	path := filepath.Join(tempDir, "profile.jsonc")
+
+	content := `{
+  // 名前
+  "name": "test",
+  "value": 1, // 値
+  "extra": true,
+}
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("テストファイル作成に失敗: %v", err)
+	}
+
+	if err := SaveJSON(path, map[string]interface{}{"name": "test", "value": 2}); err != nil {
+		t.Fatalf("SaveJSON() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("保存されたファイルの読み込みに失敗: %v", err)
+	}
+
+	want := `{
+  // 名前
+  "name": "test",
+  "value": 2, // 値
+}
+`
+	if string(data) != want {
+		t.Errorf("SaveJSON() wrote\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestSaveJSONFrom(t *testing.T) {
+	tempDir := t.TempDir()
+	srcPath := filepath.Join(tempDir, "old.jsonc")
+	dstPath := filepath.Join(tempDir, "new.jsonc")
+
+	if err := os.WriteFile(srcPath, []byte("{\n  // コメント\n  \"name\": \"old\"\n}\n"), 0644); err != nil {
+		t.Fatalf("テストファイル作成に失敗: %v", err)
+	}
+
+	if err := SaveJSONFrom(srcPath, dstPath, map[string]string{"name": "new"}); err != nil {
+		t.Fatalf("SaveJSONFrom() error = %v", err)
+	}
+
+	data, err := os.ReadFile(dstPath)
+	if err != nil {
+		t.Fatalf("保存されたファイルの読み込みに失敗: %v", err)
+	}
+	if string(data) != "{\n  // コメント\n  \"name\": \"new\"\n}\n" {
+		t.Errorf("SaveJSONFrom() wrote\n%s", data)
+	}
+}
+
 func TestLoadEnvFile(t *testing.T) {
 	tempDir := t.TempDir()
 
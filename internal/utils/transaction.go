@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"errors"
+	"os"
+
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
+)
+
+// Transaction groups file writes and removals so that a multi-file
+// operation either completes or leaves every file as it was.
+//
+//	tx := utils.NewTransaction()
+//	defer tx.Rollback()
+//	... tx.WriteFile / tx.SaveJSON / tx.Remove ...
+//	tx.Commit()
+type Transaction struct {
+	backups  []fileBackup
+	recorded map[string]bool
+	done     bool
+}
+
+type fileBackup struct {
+	path    string
+	data    []byte
+	mode    os.FileMode
+	existed bool
+}
+
+// NewTransaction starts a new transaction
+func NewTransaction() *Transaction {
+	return &Transaction{recorded: make(map[string]bool)}
+}
+
+// WriteFile atomically writes data to path, remembering the previous content
+func (tx *Transaction) WriteFile(path string, data []byte, perm os.FileMode) error {
+	if err := tx.record(path); err != nil {
+		return err
+	}
+	return WriteFileAtomic(path, data, perm)
+}
+
+// SaveJSON is the transactional counterpart of SaveJSON
+func (tx *Transaction) SaveJSON(path string, v interface{}) error {
+	return tx.SaveJSONFrom(path, path, v)
+}
+
+// SaveJSONFrom is the transactional counterpart of SaveJSONFrom
+func (tx *Transaction) SaveJSONFrom(srcPath, path string, v interface{}) error {
+	data, err := MarshalJSONFrom(srcPath, v)
+	if err != nil {
+		return err
+	}
+	return tx.WriteFile(path, data, 0644)
+}
+
+// Remove deletes path, remembering its content
+func (tx *Transaction) Remove(path string) error {
+	if err := tx.record(path); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// Commit ends the transaction and keeps every change
+func (tx *Transaction) Commit() {
+	tx.done = true
+	tx.backups = nil
+}
+
+// Rollback restores every file touched by the transaction.
+// It does nothing after Commit, so it can be deferred unconditionally.
+func (tx *Transaction) Rollback() error {
+	if tx.done {
+		return nil
+	}
+	tx.done = true
+
+	var errs []error
+	for i := len(tx.backups) - 1; i >= 0; i-- {
+		b := tx.backups[i]
+		if !b.existed {
+			if err := os.Remove(b.path); err != nil && !os.IsNotExist(err) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		if err := WriteFileAtomic(b.path, b.data, b.mode); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return i18n.Errorf("utils.rollback_failed", errors.Join(errs...))
+	}
+	return nil
+}
+
+// record saves the current state of path the first time it is touched
+func (tx *Transaction) record(path string) error {
+	if tx.done {
+		return i18n.Errorf("utils.transaction_closed")
+	}
+	if tx.recorded[path] {
+		return nil
+	}
+
+	b := fileBackup{path: path}
+	if info, err := os.Stat(path); err == nil {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return i18n.Errorf("utils.backup_failed", err)
+		}
+		b.data = data
+		b.mode = info.Mode().Perm()
+		b.existed = true
+	} else if !os.IsNotExist(err) {
+		return i18n.Errorf("utils.backup_failed", err)
+	}
+
+	tx.recorded[path] = true
+	tx.backups = append(tx.backups, b)
+	return nil
+}
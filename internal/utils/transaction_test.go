@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTransaction_RollbackRestoresFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	existing := filepath.Join(tempDir, "existing.jsonc")
+	created := filepath.Join(tempDir, "created.jsonc")
+	removed := filepath.Join(tempDir, "removed.jsonc")
+
+	if err := os.WriteFile(existing, []byte("// 元の内容\n{}\n"), 0600); err != nil {
+		t.Fatalf("テストファイル作成に失敗: %v", err)
+	}
+	if err := os.WriteFile(removed, []byte(`{"name": "removed"}`), 0644); err != nil {
+		t.Fatalf("テストファイル作成に失敗: %v", err)
+	}
+
+	tx := NewTransaction()
+	if err := tx.SaveJSON(existing, map[string]string{"name": "changed"}); err != nil {
+		t.Fatalf("SaveJSON() error = %v", err)
+	}
+	if err := tx.WriteFile(created, []byte("{}"), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if err := tx.Remove(removed); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+
+	data, err := os.ReadFile(existing)
+	if err != nil || string(data) != "// 元の内容\n{}\n" {
+		t.Errorf("既存ファイルが復元されていません: %q, %v", data, err)
+	}
+	if info, err := os.Stat(existing); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("既存ファイルのパーミッションが復元されていません: %v", info.Mode())
+	}
+	if FileExists(created) {
+		t.Error("作成したファイルが削除されていません")
+	}
+	if !FileExists(removed) {
+		t.Error("削除したファイルが復元されていません")
+	}
+}
+
+func TestTransaction_CommitKeepsChanges(t *testing.T) {
+	tempDir := t.TempDir()
+	path := filepath.Join(tempDir, "file.json")
+
+	tx := NewTransaction()
+	if err := tx.WriteFile(path, []byte("{}"), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	tx.Commit()
+
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("Rollback() after Commit() error = %v", err)
+	}
+	if !FileExists(path) {
+		t.Error("コミット後のRollback()で変更が取り消されました")
+	}
+	if err := tx.WriteFile(path, []byte("{}"), 0644); err == nil {
+		t.Error("終了したトランザクションへの書き込みはエラーになるべきです")
+	}
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	tempDir := t.TempDir()
+	target := filepath.Join(tempDir, "target.json")
+	link := filepath.Join(tempDir, "link.json")
+
+	if err := os.WriteFile(target, []byte("old"), 0600); err != nil {
+		t.Fatalf("テストファイル作成に失敗: %v", err)
+	}
+	if err := os.Symlink(target, link); err != nil {
+		t.Skipf("シンボリックリンクを作成できません: %v", err)
+	}
+
+	if err := WriteFileAtomic(link, []byte("new"), 0644); err != nil {
+		t.Fatalf("WriteFileAtomic() error = %v", err)
+	}
+
+	if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Error("シンボリックリンクが置き換えられました")
+	}
+	data, _ := os.ReadFile(target)
+	if string(data) != "new" {
+		t.Errorf("リンク先が更新されていません: %q", data)
+	}
+	if info, _ := os.Stat(target); info.Mode().Perm() != 0600 {
+		t.Errorf("パーミッションが保持されていません: %v", info.Mode().Perm())
+	}
+
+	entries, _ := os.ReadDir(tempDir)
+	if len(entries) != 2 {
+		t.Errorf("一時ファイルが残っています: %v", entries)
+	}
+}
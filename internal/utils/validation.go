@@ -1,35 +1,38 @@
 package utils
 
 import (
-	"fmt"
 	"regexp"
 	"strings"
+
+	"github.com/naoto24kawa/mcpjson/internal/i18n"
 )
 
 var (
 	namePattern   = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
 	reservedWords = []string{"help", "version", "list", "server", "apply", "save", "create", "delete", "rename", "add", "remove", "show"}
 	envKeyPattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*$`)
+	// RFC 7230 のトークン文字
+	headerNamePattern = regexp.MustCompile("^[!#$%&'*+.^_`|~0-9A-Za-z-]+$")
 )
 
 const MaxNameLength = 50
 
 func ValidateName(name string, resourceType string) error {
 	if name == "" {
-		return fmt.Errorf("%s名が指定されていません", resourceType)
+		return i18n.Errorf("utils.name_empty", resourceType)
 	}
 
 	if len(name) > MaxNameLength {
-		return fmt.Errorf("%s名は%d文字以内で指定してください", resourceType, MaxNameLength)
+		return i18n.Errorf("utils.name_too_long", resourceType, MaxNameLength)
 	}
 
 	if !namePattern.MatchString(name) {
-		return fmt.Errorf("%s名に使用できない文字が含まれています（使用可能: 英数字、ハイフン、アンダースコア）", resourceType)
+		return i18n.Errorf("utils.name_invalid_chars", resourceType)
 	}
 
 	for _, reserved := range reservedWords {
 		if name == reserved {
-			return fmt.Errorf("%s名に予約語 '%s' は使用できません", resourceType, reserved)
+			return i18n.Errorf("utils.name_reserved", resourceType, reserved)
 		}
 	}
 
@@ -47,14 +50,14 @@ func ParseEnvVars(envStr string) (map[string]string, error) {
 	for _, pair := range pairs {
 		parts := strings.SplitN(pair, "=", 2)
 		if len(parts) != 2 {
-			return nil, fmt.Errorf("環境変数の形式が不正です: '%s'", pair)
+			return nil, i18n.Errorf("utils.env_invalid", pair)
 		}
 
 		key := strings.TrimSpace(parts[0])
 		value := strings.TrimSpace(parts[1])
 
 		if !envKeyPattern.MatchString(key) {
-			return nil, fmt.Errorf("環境変数名が不正です: '%s'", key)
+			return nil, i18n.Errorf("utils.env_name_invalid", key)
 		}
 
 		envMap[key] = value
@@ -80,3 +83,38 @@ func ParseArgs(argsStr string) []string {
 
 	return result
 }
+
+// ParseHeader parses a single HTTP header in "Name: value" form
+func ParseHeader(headerStr string) (string, string, error) {
+	parts := strings.SplitN(headerStr, ":", 2)
+	if len(parts) != 2 {
+		return "", "", i18n.Errorf("utils.header_invalid", headerStr)
+	}
+
+	name := strings.TrimSpace(parts[0])
+	value := strings.TrimSpace(parts[1])
+
+	if !headerNamePattern.MatchString(name) {
+		return "", "", i18n.Errorf("utils.header_name_invalid", name)
+	}
+
+	return name, value, nil
+}
+
+// ParseHeaders parses multiple "Name: value" headers into a map
+func ParseHeaders(headerStrs []string) (map[string]string, error) {
+	if len(headerStrs) == 0 {
+		return nil, nil
+	}
+
+	headers := make(map[string]string)
+	for _, headerStr := range headerStrs {
+		name, value, err := ParseHeader(headerStr)
+		if err != nil {
+			return nil, err
+		}
+		headers[name] = value
+	}
+
+	return headers, nil
+}